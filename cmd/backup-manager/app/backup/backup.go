@@ -130,8 +130,12 @@ func constructOptions(backup *v1alpha1.Backup) ([]string, error) {
 	if config.Concurrency != nil {
 		args = append(args, fmt.Sprintf("--concurrency=%d", *config.Concurrency))
 	}
-	if config.RateLimit != nil {
-		args = append(args, fmt.Sprintf("--ratelimit=%d", *config.RateLimit))
+	rateLimit, err := config.GetRateLimit(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if rateLimit != nil {
+		args = append(args, fmt.Sprintf("--ratelimit=%d", *rateLimit))
 	}
 	if config.TimeAgo != "" {
 		args = append(args, fmt.Sprintf("--timeago=%s", config.TimeAgo))
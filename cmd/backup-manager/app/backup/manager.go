@@ -24,6 +24,7 @@ import (
 	"github.com/Masterminds/semver"
 	"github.com/dustin/go-humanize"
 	"github.com/pingcap/errors"
+	kvbackup "github.com/pingcap/kvproto/pkg/backup"
 	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/clean"
 	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
 	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
@@ -36,18 +37,23 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
 
 const (
 	gcPausedKeyword          = "GC is paused"
 	pdSchedulesPausedKeyword = "Schedulers are paused"
+
+	// maxCorruptedFilesInMessage is the max number of corrupted files shown in the condition message
+	maxCorruptedFilesInMessage = 10
 )
 
 // Manager mainly used to manage backup related work
 type Manager struct {
 	backupLister  listers.BackupLister
 	StatusUpdater controller.BackupConditionUpdaterInterface
+	kubeCli       kubernetes.Interface
 	Options
 }
 
@@ -55,10 +61,12 @@ type Manager struct {
 func NewManager(
 	backupLister listers.BackupLister,
 	statusUpdater controller.BackupConditionUpdaterInterface,
+	kubeCli kubernetes.Interface,
 	backupOpts Options) *Manager {
 	return &Manager{
 		backupLister,
 		statusUpdater,
+		kubeCli,
 		backupOpts,
 	}
 }
@@ -384,7 +392,10 @@ func (bm *Manager) performBackup(ctx context.Context, backup *v1alpha1.Backup, d
 	}
 	klog.Infof("backup cluster %s data to %s success", bm, backupFullPath)
 
-	var updateStatus *controller.BackupUpdateStatus
+	var (
+		updateStatus *controller.BackupUpdateStatus
+		backupMeta   *kvbackup.BackupMeta
+	)
 	completeCondition := v1alpha1.BackupComplete
 	switch bm.Mode {
 	case string(v1alpha1.BackupModeVolumeSnapshot):
@@ -398,7 +409,7 @@ func (bm *Manager) performBackup(ctx context.Context, backup *v1alpha1.Backup, d
 			}
 		}
 	default:
-		backupMeta, err := util.GetBRMetaData(ctx, backup.Spec.StorageProvider)
+		backupMeta, err = util.GetBRMetaData(ctx, backup.Spec.StorageProvider, backup.Spec.Encryption)
 		if err != nil {
 			errs = append(errs, err)
 			klog.Errorf("Get backup metadata for backup files in %s of cluster %s failed, err: %s", backupFullPath, bm, err)
@@ -427,10 +438,81 @@ func (bm *Manager) performBackup(ctx context.Context, backup *v1alpha1.Backup, d
 		}
 	}
 
-	return bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
+	err = bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
 		Type:   completeCondition,
 		Status: corev1.ConditionTrue,
 	}, updateStatus)
+	if err != nil || backupMeta == nil {
+		return err
+	}
+	if backup.Spec.Verify {
+		if err := bm.verifyBackup(ctx, backup, backupMeta); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(backup.Spec.SecondaryStorages) > 0 {
+		bm.replicateBackup(ctx, backup)
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+// verifyBackup verifies the backup files referenced by the backup meta and records the result
+// as the Verified or Corrupted condition, the backup is still complete even if it is corrupted.
+func (bm *Manager) verifyBackup(ctx context.Context, backup *v1alpha1.Backup, backupMeta *kvbackup.BackupMeta) error {
+	result, err := util.VerifyBRBackupFiles(ctx, backup.Spec.StorageProvider, backup.Spec.Encryption, backupMeta)
+	if err != nil {
+		klog.Errorf("Verify backup files of cluster %s failed, err: %s", bm, err)
+		uerr := bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
+			Type:    v1alpha1.BackupVerified,
+			Status:  corev1.ConditionFalse,
+			Reason:  "VerifyBackupFilesFailed",
+			Message: err.Error(),
+		}, nil)
+		return errorutils.NewAggregate([]error{err, uerr})
+	}
+
+	if len(result.Corrupted) > 0 {
+		var details []string
+		for i, file := range result.Corrupted {
+			if i >= maxCorruptedFilesInMessage {
+				details = append(details, "...")
+				break
+			}
+			details = append(details, fmt.Sprintf("%s: %s", file.Name, file.Reason))
+		}
+		message := fmt.Sprintf("%d of %d backup files are missing or corrupted: %s",
+			len(result.Corrupted), result.Verified+len(result.Corrupted), strings.Join(details, "; "))
+		klog.Errorf("Verify backup files of cluster %s, %s", bm, message)
+		return bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
+			Type:    v1alpha1.BackupCorrupted,
+			Status:  corev1.ConditionTrue,
+			Reason:  "BackupFilesCorrupted",
+			Message: message,
+		}, nil)
+	}
+
+	if result.Verified == 0 {
+		// the backup is not marked as verified without checking any file
+		message := "no backup files are recorded in the backup meta"
+		klog.Warningf("Verify backup files of cluster %s, %s", bm, message)
+		return bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
+			Type:    v1alpha1.BackupVerified,
+			Status:  corev1.ConditionFalse,
+			Reason:  "NoBackupFilesVerified",
+			Message: message,
+		}, nil)
+	}
+
+	message := fmt.Sprintf("%d backup files are verified", result.Verified)
+	if backup.Spec.Encryption != nil {
+		message += ", the checksum is not verified as the backup is encrypted"
+	}
+	klog.Infof("Verify %d backup files of cluster %s success", result.Verified, bm)
+	return bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
+		Type:    v1alpha1.BackupVerified,
+		Status:  corev1.ConditionTrue,
+		Message: message,
+	}, nil)
 }
 
 // performLogBackup execute log backup commands according to backup cr.
@@ -517,7 +599,7 @@ func (bm *Manager) startLogBackup(ctx context.Context, backup *v1alpha1.Backup)
 	klog.Infof("Start log backup of cluster %s to %s success", bm, backupFullPath)
 
 	// get Meta info
-	backupMeta, err := util.GetBRMetaData(ctx, backup.Spec.StorageProvider)
+	backupMeta, err := util.GetBRMetaData(ctx, backup.Spec.StorageProvider, backup.Spec.Encryption)
 	if err != nil {
 		klog.Errorf("Get log backup metadata for backup files in %s of cluster %s failed, err: %s", backupFullPath, bm, err)
 		return nil, "GetLogBackupMetadataFailed", err
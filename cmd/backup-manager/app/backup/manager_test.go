@@ -0,0 +1,110 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	kvbackup "github.com/pingcap/kvproto/pkg/backup"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestVerifyBackup(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	content := []byte("the content of the sst")
+	g.Expect(os.WriteFile(filepath.Join(dir, "1_2_3.sst"), content, 0644)).To(Succeed())
+	checksum := sha256.Sum256(content)
+
+	backup := &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backup"},
+		Spec: v1alpha1.BackupSpec{
+			StorageProvider: v1alpha1.StorageProvider{
+				Local: &v1alpha1.LocalStorageProvider{
+					Volume:      corev1.Volume{Name: "local"},
+					VolumeMount: corev1.VolumeMount{Name: "local", MountPath: dir},
+				},
+			},
+			Verify: true,
+		},
+	}
+	cli := fake.NewSimpleClientset(backup)
+	backupInformer := informers.NewSharedInformerFactory(cli, 0).Pingcap().V1alpha1().Backups()
+	g.Expect(backupInformer.Informer().GetIndexer().Add(backup)).To(Succeed())
+	bm := NewManager(backupInformer.Lister(),
+		controller.NewRealBackupConditionUpdater(cli, backupInformer.Lister(), record.NewFakeRecorder(10)),
+		nil, Options{})
+	getVerifiedCondition := func() *v1alpha1.BackupCondition {
+		got, err := cli.PingcapV1alpha1().Backups("ns").Get(context.TODO(), backup.Name, metav1.GetOptions{})
+		g.Expect(err).To(Succeed())
+		g.Expect(backupInformer.Informer().GetIndexer().Update(got)).To(Succeed())
+		_, cond := v1alpha1.GetBackupCondition(&got.Status, v1alpha1.BackupVerified)
+		return cond
+	}
+
+	// no backup files are recorded in the backup meta
+	g.Expect(bm.verifyBackup(context.TODO(), backup.DeepCopy(), &kvbackup.BackupMeta{EndVersion: 1})).To(Succeed())
+	cond := getVerifiedCondition()
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(cond.Reason).To(Equal("NoBackupFilesVerified"))
+
+	backupMeta := &kvbackup.BackupMeta{
+		EndVersion: 1,
+		Files:      []*kvbackup.File{{Name: "1_2_3.sst", Sha256: checksum[:], Size_: uint64(len(content))}},
+	}
+	g.Expect(bm.verifyBackup(context.TODO(), backup.DeepCopy(), backupMeta)).To(Succeed())
+	cond = getVerifiedCondition()
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Message).To(Equal("1 backup files are verified"))
+
+	// the checksum computed over the plaintext is not verified for the encrypted backup
+	encrypted := backup.DeepCopy()
+	encrypted.Spec.Encryption = &v1alpha1.BackupEncryption{
+		Method:     v1alpha1.BackupEncryptionMethodAES256CTR,
+		SecretName: "backup-key",
+	}
+	checksum = sha256.Sum256([]byte("the plaintext of the sst"))
+	backupMeta.Files[0].Sha256 = checksum[:]
+	g.Expect(bm.verifyBackup(context.TODO(), encrypted, backupMeta)).To(Succeed())
+	cond = getVerifiedCondition()
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(cond.Message).To(Equal("1 backup files are verified, the checksum is not verified as the backup is encrypted"))
+}
+
+func TestGetCopyRetryDuration(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(getCopyRetryDuration(time.Second, 1)).To(Equal(time.Second))
+	g.Expect(getCopyRetryDuration(time.Second, 3)).To(Equal(4 * time.Second))
+	// the duration is capped instead of overflowing
+	g.Expect(getCopyRetryDuration(time.Second, 13)).To(Equal(maxCopyRetryDuration))
+	g.Expect(getCopyRetryDuration(time.Second, 100)).To(Equal(maxCopyRetryDuration))
+	g.Expect(getCopyRetryDuration(2*time.Hour, 1)).To(Equal(maxCopyRetryDuration))
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	pkgutil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// copyBackupConcurrency is the number of the backup files copied concurrently
+	copyBackupConcurrency = 16
+	// maxCopyRetryDuration is the max duration between the retries of copying the backup, which keeps
+	// MinRetryDuration << (n - 1) from overflowing
+	maxCopyRetryDuration = time.Hour
+)
+
+// replicateBackup copies the complete backup to the secondary storages one by one.
+// The failures are recorded in the statuses of the secondary storages and don't fail the backup.
+func (bm *Manager) replicateBackup(ctx context.Context, backup *v1alpha1.Backup) {
+	src, err := pkgutil.NewStorageBackend(backup.Spec.StorageProvider, &pkgutil.StorageCredential{})
+	if err != nil {
+		klog.Errorf("Create storage backend of backup %s failed, err: %s", bm, err)
+		for _, storage := range backup.Spec.SecondaryStorages {
+			bm.updateSecondaryStorageStatus(backup, &v1alpha1.SecondaryStorageStatus{
+				Name:    storage.Name,
+				Phase:   v1alpha1.SecondaryStorageFailed,
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+	defer src.Close()
+
+	for _, storage := range backup.Spec.SecondaryStorages {
+		bm.replicateBackupTo(ctx, backup, src, storage)
+	}
+}
+
+// replicateBackupTo copies the backup to the secondary storage, and retries according to BackoffRetryPolicy:
+// the n-th retry is after MinRetryDuration << (n - 1), and the copy fails if the retries exceed MaxRetryTimes
+// or the time from the first failure exceeds RetryTimeout.
+func (bm *Manager) replicateBackupTo(ctx context.Context, backup *v1alpha1.Backup, src *pkgutil.StorageBackend, storage v1alpha1.SecondaryStorage) {
+	status := &v1alpha1.SecondaryStorageStatus{
+		Name:  storage.Name,
+		Phase: v1alpha1.SecondaryStorageCopying,
+	}
+	status.BackupPath, _ = pkgutil.GetStoragePath(storage.StorageProvider)
+	bm.updateSecondaryStorageStatus(backup, status)
+
+	policy := backup.Spec.BackoffRetryPolicy
+	minRetryDuration, _ := time.ParseDuration(policy.MinRetryDuration)
+	retryTimeout, _ := time.ParseDuration(policy.RetryTimeout)
+	var firstFailedAt time.Time
+	for {
+		count, err := bm.copyBackupTo(ctx, backup, src, storage)
+		if err == nil {
+			klog.Infof("Copy %d backup files of %s to secondary storage %s success", count, bm, storage.Name)
+			status.Phase = v1alpha1.SecondaryStorageComplete
+			status.TimeCompleted = &metav1.Time{Time: time.Now()}
+			status.Message = ""
+			bm.updateSecondaryStorageStatus(backup, status)
+			return
+		}
+		klog.Errorf("Copy backup %s to secondary storage %s failed, retry num %d, err: %s", bm, storage.Name, status.RetryNum, err)
+		status.Message = err.Error()
+
+		if firstFailedAt.IsZero() {
+			firstFailedAt = time.Now()
+		}
+		retryNum := status.RetryNum + 1
+		if minRetryDuration <= 0 || retryNum > policy.MaxRetryTimes ||
+			(retryTimeout > 0 && time.Since(firstFailedAt) > retryTimeout) {
+			status.Phase = v1alpha1.SecondaryStorageFailed
+			bm.updateSecondaryStorageStatus(backup, status)
+			return
+		}
+
+		status.RetryNum = retryNum
+		bm.updateSecondaryStorageStatus(backup, status)
+		select {
+		case <-ctx.Done():
+			status.Phase = v1alpha1.SecondaryStorageFailed
+			status.Message = ctx.Err().Error()
+			bm.updateSecondaryStorageStatus(backup, status)
+			return
+		case <-time.After(getCopyRetryDuration(minRetryDuration, retryNum)):
+		}
+	}
+}
+
+// getCopyRetryDuration returns MinRetryDuration << (retryNum - 1), which is capped by maxCopyRetryDuration
+func getCopyRetryDuration(minRetryDuration time.Duration, retryNum int) time.Duration {
+	duration := minRetryDuration
+	for i := 1; i < retryNum && duration < maxCopyRetryDuration; i++ {
+		duration <<= 1
+	}
+	if duration > maxCopyRetryDuration {
+		return maxCopyRetryDuration
+	}
+	return duration
+}
+
+// copyBackupTo copies all the files of the backup to the secondary storage
+func (bm *Manager) copyBackupTo(ctx context.Context, backup *v1alpha1.Backup, src *pkgutil.StorageBackend, storage v1alpha1.SecondaryStorage) (int, error) {
+	var cred *pkgutil.StorageCredential
+	if bm.kubeCli != nil {
+		cred = pkgutil.GetStorageCredentialByClient(backup.Namespace, storage.StorageProvider, bm.kubeCli)
+	} else {
+		cred = &pkgutil.StorageCredential{}
+	}
+	// the local bucket can't be opened if the directory doesn't exist
+	if local := storage.Local; local != nil {
+		if err := os.MkdirAll(path.Join(local.VolumeMount.MountPath, local.Prefix), 0755); err != nil {
+			return 0, fmt.Errorf("create directory of secondary storage %s failed: %v", storage.Name, err)
+		}
+	}
+	dst, err := pkgutil.NewStorageBackend(storage.StorageProvider, cred)
+	if err != nil {
+		return 0, fmt.Errorf("create storage backend of secondary storage %s failed: %v", storage.Name, err)
+	}
+	defer dst.Close()
+
+	return src.CopyObjects(ctx, dst, copyBackupConcurrency)
+}
+
+func (bm *Manager) updateSecondaryStorageStatus(backup *v1alpha1.Backup, status *v1alpha1.SecondaryStorageStatus) {
+	err := bm.StatusUpdater.Update(backup, nil, &controller.BackupUpdateStatus{
+		SecondaryStorageStatus: status.DeepCopy(),
+	})
+	if err != nil {
+		klog.Errorf("Update status of secondary storage %s of backup %s failed, err: %s", status.Name, bm, err)
+	}
+}
@@ -145,6 +145,11 @@ func (bo *Options) CleanBRRemoteBackupData(ctx context.Context, backup *v1alpha1
 	}
 	defer backend.Close()
 
+	return bo.cleanBRRemoteBackupData(ctx, backend, opt)
+}
+
+// cleanBRRemoteBackupData cleans all the objects in the storage backend, and retries according to the clean option
+func (bo *Options) cleanBRRemoteBackupData(ctx context.Context, backend *bkutil.StorageBackend, opt v1alpha1.CleanOption) error {
 	round := 0
 	return util.RetryOnError(ctx, opt.RetryCount, 0, util.RetriableOnAnyError, func() error {
 		round++
@@ -20,11 +20,13 @@ import (
 
 	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	bkutil "github.com/pingcap/tidb-operator/pkg/backup/util"
 	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
 
@@ -32,6 +34,7 @@ import (
 type Manager struct {
 	backupLister  listers.BackupLister
 	StatusUpdater controller.BackupConditionUpdaterInterface
+	kubeCli       kubernetes.Interface
 	Options
 }
 
@@ -39,10 +42,12 @@ type Manager struct {
 func NewManager(
 	backupLister listers.BackupLister,
 	statusUpdater controller.BackupConditionUpdaterInterface,
+	kubeCli kubernetes.Interface,
 	backupOpts Options) *Manager {
 	return &Manager{
 		backupLister,
 		statusUpdater,
+		kubeCli,
 		backupOpts,
 	}
 }
@@ -91,6 +96,9 @@ func (bm *Manager) performCleanBackup(ctx context.Context, backup *v1alpha1.Back
 	} else {
 		if backup.Spec.BR != nil {
 			err = bm.CleanBRRemoteBackupData(ctx, backup)
+			if err == nil {
+				err = bm.cleanSecondaryStorages(ctx, backup)
+			}
 		} else {
 			opts := util.GetOptions(backup.Spec.StorageProvider)
 			err = bm.cleanRemoteBackupData(ctx, backup.Status.BackupPath, opts)
@@ -117,6 +125,30 @@ func (bm *Manager) performCleanBackup(ctx context.Context, backup *v1alpha1.Back
 	}, nil)
 }
 
+// cleanSecondaryStorages cleans the copies of the backup in the secondary storages
+func (bm *Manager) cleanSecondaryStorages(ctx context.Context, backup *v1alpha1.Backup) error {
+	opt := backup.GetCleanOption()
+	for _, storage := range backup.Spec.SecondaryStorages {
+		var cred *bkutil.StorageCredential
+		if bm.kubeCli != nil {
+			cred = bkutil.GetStorageCredentialByClient(backup.Namespace, storage.StorageProvider, bm.kubeCli)
+		} else {
+			cred = &bkutil.StorageCredential{}
+		}
+		backend, err := bkutil.NewStorageBackend(storage.StorageProvider, cred)
+		if err != nil {
+			return fmt.Errorf("create storage backend of secondary storage %s failed: %v", storage.Name, err)
+		}
+		err = bm.cleanBRRemoteBackupData(ctx, backend, opt)
+		backend.Close()
+		if err != nil {
+			return fmt.Errorf("clean backup data in secondary storage %s failed: %v", storage.Name, err)
+		}
+		klog.Infof("clean cluster %s backup in secondary storage %s success", bm, storage.Name)
+	}
+	return nil
+}
+
 // getNextBackup to get next backup sorted by start time
 func (bm *Manager) getNextBackup(ctx context.Context, backup *v1alpha1.Backup) *v1alpha1.Backup {
 	var err error
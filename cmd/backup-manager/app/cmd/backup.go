@@ -76,6 +76,6 @@ func runBackup(backupOpts backup.Options, kubecfg string) error {
 	cache.WaitForCacheSync(ctx.Done(), backupInformer.Informer().HasSynced)
 
 	klog.Infof("start to process backup %s", backupOpts.String())
-	bm := backup.NewManager(backupInformer.Lister(), statusUpdater, backupOpts)
+	bm := backup.NewManager(backupInformer.Lister(), statusUpdater, kubeCli, backupOpts)
 	return bm.ProcessBackup()
 }
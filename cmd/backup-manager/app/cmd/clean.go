@@ -67,6 +67,6 @@ func runClean(backupOpts clean.Options, kubecfg string) error {
 	cache.WaitForCacheSync(ctx.Done(), backupInformer.Informer().HasSynced)
 
 	klog.Infof("start to clean backup %s", backupOpts.String())
-	bm := clean.NewManager(backupInformer.Lister(), statusUpdater, backupOpts)
+	bm := clean.NewManager(backupInformer.Lister(), statusUpdater, kubeCli, backupOpts)
 	return bm.ProcessCleanBackup()
 }
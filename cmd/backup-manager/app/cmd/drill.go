@@ -0,0 +1,55 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	// registry mysql drive
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/drill"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// NewDrillCommand implements the drill command
+func NewDrillCommand() *cobra.Command {
+	do := drill.Options{}
+
+	cmd := &cobra.Command{
+		Use:   "drill",
+		Short: "Run the checks of restore drill against the restored tidb cluster.",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.ValidCmdFlags(cmd.CommandPath(), cmd.LocalFlags())
+			cmdutil.CheckErr(runDrill(do, kubecfg))
+		},
+	}
+
+	cmd.Flags().StringVar(&do.Namespace, "namespace", "", "RestoreDrill CR's namespace")
+	cmd.Flags().StringVar(&do.ResourceName, "drillName", "", "RestoreDrill CRD object name")
+	cmd.Flags().BoolVar(&do.TLSClient, "client-tls", false, "Whether client tls is enabled")
+	cmd.Flags().BoolVar(&do.SkipClientCA, "skipClientCA", false, "Whether to skip tidb server's certificates validation")
+	return cmd
+}
+
+func runDrill(drillOpts drill.Options, kubecfg string) error {
+	_, cli, err := util.NewKubeAndCRCli(kubecfg)
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("start to process restore drill %s", drillOpts.String())
+	dm := drill.NewManager(cli, drillOpts)
+	return dm.ProcessDrill()
+}
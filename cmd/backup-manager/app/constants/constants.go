@@ -57,6 +57,9 @@ const (
 	// MetaFile is the file name for meta data of backup with BR
 	MetaFile = "backupmeta"
 
+	// VerifyBackupConcurrency is the number of the backup files verified concurrently
+	VerifyBackupConcurrency = 16
+
 	// BR certificate storage path
 	BRCertPath = "/var/lib/br-tls"
 
@@ -69,3 +72,19 @@ const (
 	// DefaultTableFilter is the default table filter 'db.table' matching
 	DefaultTableFilter = "!/^(mysql|test|INFORMATION_SCHEMA|PERFORMANCE_SCHEMA|METRICS_SCHEMA|INSPECTION_SCHEMA)$/.*"
 )
+
+// the progress steps of the logical export with dumpling and the logical import with lightning
+const (
+	// ProgressStepDump is the step to export the data with dumpling
+	ProgressStepDump = "Dump"
+	// ProgressStepArchive is the step to archive the exported data
+	ProgressStepArchive = "Archive"
+	// ProgressStepUpload is the step to upload the archived data to the remote storage
+	ProgressStepUpload = "Upload"
+	// ProgressStepDownload is the step to download the archived data from the remote storage
+	ProgressStepDownload = "Download"
+	// ProgressStepUnarchive is the step to unarchive the downloaded data
+	ProgressStepUnarchive = "Unarchive"
+	// ProgressStepImport is the step to import the data with lightning
+	ProgressStepImport = "Import"
+)
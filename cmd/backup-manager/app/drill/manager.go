@@ -0,0 +1,221 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drill
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	bkconstants "github.com/pingcap/tidb-operator/pkg/backup/constants"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	pkgutil "github.com/pingcap/tidb-operator/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// Options contains the input arguments to the drill command
+type Options struct {
+	util.GenericOptions
+}
+
+// Manager runs the checks of the restore drill against the restored TidbCluster
+type Manager struct {
+	cli versioned.Interface
+	Options
+}
+
+// NewManager return a Manager
+func NewManager(cli versioned.Interface, opts Options) *Manager {
+	return &Manager{
+		cli,
+		opts,
+	}
+}
+
+func (m *Manager) setOptions(rd *v1alpha1.RestoreDrill) {
+	run := rd.Status.CurrentRun
+	m.Options.Host = fmt.Sprintf("%s-tidb.%s", run.Cluster, rd.Namespace)
+	m.Options.Port = v1alpha1.DefaultTiDBServerPort
+	m.Options.User = v1alpha1.DefaultTidbUser
+
+	if access := rd.Spec.Access; access != nil {
+		if access.Host != "" {
+			m.Options.Host = access.Host
+		}
+		if access.Port != 0 {
+			m.Options.Port = access.Port
+		}
+		if access.User != "" {
+			m.Options.User = access.User
+		}
+	}
+	m.Options.Password = util.GetOptionValueFromEnv(bkconstants.TidbPasswordKey, bkconstants.BackupManagerEnvVarPrefix)
+}
+
+// ProcessDrill runs the checks of the running drill and records the results
+func (m *Manager) ProcessDrill() error {
+	ctx, cancel := util.GetContextForTerminationSignals(m.ResourceName)
+	defer cancel()
+
+	rd, err := m.cli.PingcapV1alpha1().RestoreDrills(m.Namespace).Get(ctx, m.ResourceName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get restore drill %s failed, err: %v", m, err)
+	}
+	run := rd.Status.CurrentRun
+	if run == nil || run.Phase != v1alpha1.RestoreDrillChecking {
+		klog.Infof("restore drill %s is not checking, skip", m)
+		return nil
+	}
+	m.setOptions(rd)
+
+	var db *sql.DB
+	err = wait.PollImmediate(constants.PollInterval, constants.CheckTimeout, func() (done bool, err error) {
+		dsn, err := m.GetDSN(m.TLSClient)
+		if err != nil {
+			klog.Errorf("can't get dsn of tidb cluster %s, err: %s", m.Host, err)
+			return false, err
+		}
+
+		db, err = pkgutil.OpenDB(ctx, dsn)
+		if err != nil {
+			klog.Warningf("can't connect to tidb cluster %s, err: %s", m.Host, err)
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		klog.Errorf("restore drill %s connect to tidb cluster %s failed, err: %s", m, m.Host, err)
+		return m.updateResult(ctx, run.Restore, nil, fmt.Sprintf("connect to tidb cluster %s failed, err: %v", m.Host, err))
+	}
+	defer db.Close()
+
+	results := make([]v1alpha1.RestoreDrillCheckResult, 0, len(rd.Spec.Checks))
+	for i := range rd.Spec.Checks {
+		result := runCheck(ctx, db, &rd.Spec.Checks[i])
+		klog.Infof("restore drill %s check %s passed: %t, value: %s, message: %s", m, result.Name, result.Passed, result.Value, result.Message)
+		results = append(results, result)
+	}
+	return m.updateResult(ctx, run.Restore, results, "")
+}
+
+// updateResult records the results of the checks in the running drill and moves it to CleaningUp,
+// the drill fails with the message if it is not empty.
+func (m *Manager) updateResult(ctx context.Context, restoreName string, results []v1alpha1.RestoreDrillCheckResult, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		rd, err := m.cli.PingcapV1alpha1().RestoreDrills(m.Namespace).Get(ctx, m.ResourceName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		run := rd.Status.CurrentRun
+		if run == nil || run.Restore != restoreName || run.Phase != v1alpha1.RestoreDrillChecking {
+			klog.Infof("restore drill %s is not checking restore %s any more, skip updating the results", m, restoreName)
+			return nil
+		}
+
+		run.Checks = results
+		run.Result = v1alpha1.RestoreDrillPassed
+		run.Message = message
+		if message != "" {
+			run.Result = v1alpha1.RestoreDrillFailed
+		}
+		for _, r := range results {
+			if !r.Passed {
+				run.Result = v1alpha1.RestoreDrillFailed
+				if run.Message == "" {
+					run.Message = fmt.Sprintf("check %s failed: %s", r.Name, r.Message)
+				}
+			}
+		}
+		run.Phase = v1alpha1.RestoreDrillCleaningUp
+		_, err = m.cli.PingcapV1alpha1().RestoreDrills(m.Namespace).Update(ctx, rd, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// runCheck runs the check and returns its result
+func runCheck(ctx context.Context, db *sql.DB, check *v1alpha1.RestoreDrillCheck) v1alpha1.RestoreDrillCheckResult {
+	started := time.Now()
+	result := v1alpha1.RestoreDrillCheckResult{Name: check.Name}
+	defer func() {
+		result.Duration = time.Since(started).Round(time.Millisecond).String()
+	}()
+
+	table, err := quoteTable(check.Table)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	switch check.Type {
+	case v1alpha1.RestoreDrillCheckRowCount:
+		var count int64
+		if err := db.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&count); err != nil { // nolint: gosec
+			result.Message = fmt.Sprintf("count rows failed, err: %v", err)
+			return result
+		}
+		result.Value = strconv.FormatInt(count, 10)
+		result.Passed, result.Message = evaluateRowCount(check, count)
+	case v1alpha1.RestoreDrillCheckChecksum:
+		var dbName, tableName, checksum, totalKvs, totalBytes string
+		if err := db.QueryRowContext(ctx, fmt.Sprintf("ADMIN CHECKSUM TABLE %s", table)).Scan(&dbName, &tableName, &checksum, &totalKvs, &totalBytes); err != nil {
+			result.Message = fmt.Sprintf("checksum table failed, err: %v", err)
+			return result
+		}
+		result.Value = checksum
+		result.Passed, result.Message = evaluateChecksum(check, checksum)
+	default:
+		result.Message = fmt.Sprintf("unknown check type %s", check.Type)
+	}
+	return result
+}
+
+// quoteTable quotes the table in the format of `db.table`
+func quoteTable(table string) (string, error) {
+	parts := strings.SplitN(table, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("table %q is not in the format of db.table", table)
+	}
+	quote := func(name string) string {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return quote(parts[0]) + "." + quote(parts[1]), nil
+}
+
+// evaluateRowCount returns whether the row count check passes and the reason if it fails
+func evaluateRowCount(check *v1alpha1.RestoreDrillCheck, count int64) (bool, string) {
+	if minRows := check.GetMinRows(); count < minRows {
+		return false, fmt.Sprintf("row count %d is less than %d", count, minRows)
+	}
+	return true, ""
+}
+
+// evaluateChecksum returns whether the checksum check passes and the reason if it fails
+func evaluateChecksum(check *v1alpha1.RestoreDrillCheck, checksum string) (bool, string) {
+	if check.ExpectedChecksum != "" && check.ExpectedChecksum != checksum {
+		return false, fmt.Sprintf("checksum %s is not the expected %s", checksum, check.ExpectedChecksum)
+	}
+	return true, ""
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drill
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestQuoteTable(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	table, err := quoteTable("test.t")
+	g.Expect(err).Should(BeNil())
+	g.Expect(table).Should(Equal("`test`.`t`"))
+	table, err = quoteTable("test.t.1`")
+	g.Expect(err).Should(BeNil())
+	g.Expect(table).Should(Equal("`test`.`t.1```"))
+	for _, invalid := range []string{"test", ".t", "test."} {
+		_, err = quoteTable(invalid)
+		g.Expect(err).ShouldNot(BeNil())
+	}
+}
+
+func TestEvaluateChecks(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	check := &v1alpha1.RestoreDrillCheck{Type: v1alpha1.RestoreDrillCheckRowCount}
+	passed, _ := evaluateRowCount(check, 1)
+	g.Expect(passed).Should(BeTrue())
+	passed, msg := evaluateRowCount(check, 0)
+	g.Expect(passed).Should(BeFalse())
+	g.Expect(msg).Should(Equal("row count 0 is less than 1"))
+	check.MinRows = pointer.Int64Ptr(0)
+	passed, _ = evaluateRowCount(check, 0)
+	g.Expect(passed).Should(BeTrue())
+
+	check = &v1alpha1.RestoreDrillCheck{Type: v1alpha1.RestoreDrillCheckChecksum}
+	passed, _ = evaluateChecksum(check, "123")
+	g.Expect(passed).Should(BeTrue())
+	check.ExpectedChecksum = "123"
+	passed, _ = evaluateChecksum(check, "123")
+	g.Expect(passed).Should(BeTrue())
+	passed, msg = evaluateChecksum(check, "456")
+	g.Expect(passed).Should(BeFalse())
+	g.Expect(msg).Should(Equal("checksum 456 is not the expected 123"))
+}
+
+func TestUpdateResult(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rd := &v1alpha1.RestoreDrill{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "drill"},
+		Status: v1alpha1.RestoreDrillStatus{
+			CurrentRun: &v1alpha1.RestoreDrillRun{Phase: v1alpha1.RestoreDrillChecking, Restore: "drill-1", Cluster: "drill-1"},
+		},
+	}
+	cli := fake.NewSimpleClientset(rd)
+	m := NewManager(cli, Options{util.GenericOptions{Namespace: "ns", ResourceName: "drill"}})
+	getRun := func() *v1alpha1.RestoreDrillRun {
+		got, err := cli.PingcapV1alpha1().RestoreDrills("ns").Get(context.TODO(), "drill", metav1.GetOptions{})
+		g.Expect(err).Should(BeNil())
+		return got.Status.CurrentRun
+	}
+
+	// the results of another run are not recorded
+	g.Expect(m.updateResult(context.TODO(), "drill-0", nil, "")).Should(Succeed())
+	g.Expect(getRun().Phase).Should(Equal(v1alpha1.RestoreDrillChecking))
+
+	results := []v1alpha1.RestoreDrillCheckResult{
+		{Name: "rows", Passed: true, Value: "10"},
+		{Name: "checksum", Passed: false, Value: "456", Message: "checksum 456 is not the expected 123"},
+	}
+	g.Expect(m.updateResult(context.TODO(), "drill-1", results, "")).Should(Succeed())
+	run := getRun()
+	g.Expect(run.Phase).Should(Equal(v1alpha1.RestoreDrillCleaningUp))
+	g.Expect(run.Result).Should(Equal(v1alpha1.RestoreDrillFailed))
+	g.Expect(run.Message).Should(Equal("check checksum failed: checksum 456 is not the expected 123"))
+	g.Expect(run.Checks).Should(Equal(results))
+
+	m.setOptions(rd)
+	g.Expect(m.Host).Should(Equal("drill-1-tidb.ns"))
+	g.Expect(m.Port).Should(Equal(v1alpha1.DefaultTiDBServerPort))
+	g.Expect(m.User).Should(Equal(v1alpha1.DefaultTidbUser))
+}
@@ -59,7 +59,7 @@ func (bo *Options) getDestBucketURI(remotePath string) string {
 	return fmt.Sprintf("%s://%s", bo.StorageType, remotePath)
 }
 
-func (bo *Options) dumpTidbClusterData(ctx context.Context, bfPath string, backup *v1alpha1.Backup) error {
+func (bo *Options) dumpTidbClusterData(ctx context.Context, bfPath string, backup *v1alpha1.Backup, onProgress func(progress float64)) error {
 	err := backupUtil.EnsureDirectoryExist(bfPath)
 	if err != nil {
 		return err
@@ -96,9 +96,9 @@ func (bo *Options) dumpTidbClusterData(ctx context.Context, bfPath string, backu
 
 	klog.Infof("The dump process is ready, command \"%s %s\"", binPath, strings.Join(args_redacted, " "))
 
-	output, err := exec.CommandContext(ctx, binPath, args...).CombinedOutput()
+	output, err := backupUtil.RunCommandWithProgress(exec.CommandContext(ctx, binPath, args...), backupUtil.ParseDumplingProgress, onProgress)
 	if err != nil {
-		return fmt.Errorf("cluster %s, execute dumpling command %v failed, output: %s, err: %v", bo, args, string(output), err)
+		return fmt.Errorf("cluster %s, execute dumpling command %v failed, output: %s, err: %v", bo, args_redacted, output, err)
 	}
 	return nil
 }
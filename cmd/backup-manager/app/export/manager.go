@@ -259,7 +259,10 @@ func (bm *BackupManager) performBackup(ctx context.Context, backup *v1alpha1.Bac
 		return err
 	}
 
-	backupErr := bm.dumpTidbClusterData(ctx, backupFullPath, backup)
+	bm.updateProgress(backup, constants.ProgressStepDump, 0)
+	backupErr := bm.dumpTidbClusterData(ctx, backupFullPath, backup, func(progress float64) {
+		bm.updateProgress(backup, constants.ProgressStepDump, progress)
+	})
 	if oldTikvGCTimeDuration < tikvGCTimeDuration {
 		// use another context to revert `tikv_gc_life_time` back.
 		// `DefaultTerminationGracePeriodSeconds` for a pod is 30, so we use a smaller timeout value here.
@@ -313,6 +316,7 @@ func (bm *BackupManager) performBackup(ctx context.Context, backup *v1alpha1.Bac
 	}
 	klog.Infof("get cluster %s commitTs %s success", bm, commitTs)
 
+	bm.updateProgress(backup, constants.ProgressStepArchive, 0)
 	err = archiveBackupData(backupFullPath, archiveBackupPath)
 	if err != nil {
 		errs = append(errs, err)
@@ -347,6 +351,7 @@ func (bm *BackupManager) performBackup(ctx context.Context, backup *v1alpha1.Bac
 	// archive backup data successfully, origin dir can be deleted safely
 	os.RemoveAll(backupFullPath)
 
+	bm.updateProgress(backup, constants.ProgressStepUpload, 0)
 	err = bm.backupDataToRemote(ctx, archiveBackupPath, bucketURI, opts)
 	if err != nil {
 		errs = append(errs, err)
@@ -367,12 +372,16 @@ func (bm *BackupManager) performBackup(ctx context.Context, backup *v1alpha1.Bac
 	finish := time.Now()
 
 	backupSizeReadable := humanize.Bytes(uint64(size))
+	progressStep, progress := constants.ProgressStepUpload, float64(100)
 	updateStatus := &controller.BackupUpdateStatus{
 		TimeStarted:        &metav1.Time{Time: started},
 		TimeCompleted:      &metav1.Time{Time: finish},
 		BackupSize:         &size,
 		BackupSizeReadable: &backupSizeReadable,
 		CommitTs:           &commitTs,
+		ProgressStep:       &progressStep,
+		Progress:           &progress,
+		ProgressUpdateTime: &metav1.Time{Time: finish},
 	}
 
 	return bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
@@ -380,3 +389,14 @@ func (bm *BackupManager) performBackup(ctx context.Context, backup *v1alpha1.Bac
 		Status: corev1.ConditionTrue,
 	}, updateStatus)
 }
+
+// updateProgress updates the progress of the step, the previous step is marked as complete when a new step starts
+func (bm *BackupManager) updateProgress(backup *v1alpha1.Backup, step string, progress float64) {
+	if err := bm.StatusUpdater.Update(backup, nil, &controller.BackupUpdateStatus{
+		ProgressStep:       &step,
+		Progress:           &progress,
+		ProgressUpdateTime: &metav1.Time{Time: time.Now()},
+	}); err != nil {
+		klog.Errorf("Failed to update the progress of step %s for cluster %s, %v", step, bm, err)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
@@ -83,7 +84,7 @@ func (ro *Options) downloadBackupData(ctx context.Context, localPath string, opt
 	return nil
 }
 
-func (ro *Options) loadTidbClusterData(ctx context.Context, restorePath string, restore *v1alpha1.Restore) error {
+func (ro *Options) loadTidbClusterData(ctx context.Context, restorePath string, restore *v1alpha1.Restore, onProgress func(progress float64)) error {
 	tableFilter := restore.Spec.TableFilter
 
 	if exist := backupUtil.IsDirExist(restorePath); !exist {
@@ -92,7 +93,6 @@ func (ro *Options) loadTidbClusterData(ctx context.Context, restorePath string,
 	// args for restore
 	args := []string{
 		"--status-addr=0.0.0.0:8289",
-		"--backend=tidb",
 		"--server-mode=false",
 		"--log-file=-", // "-" to stdout
 		fmt.Sprintf("--tidb-user=%s", ro.User),
@@ -101,6 +101,11 @@ func (ro *Options) loadTidbClusterData(ctx context.Context, restorePath string,
 		fmt.Sprintf("--d=%s", restorePath),
 		fmt.Sprintf("--tidb-port=%d", ro.Port),
 	}
+	lightningArgs, err := constructLightningOptions(restore.Spec.Lightning, lightningConfigFile)
+	if err != nil {
+		return err
+	}
+	args = append(args, lightningArgs...)
 
 	for _, filter := range tableFilter {
 		args = append(args, "-f", filter)
@@ -119,15 +124,81 @@ func (ro *Options) loadTidbClusterData(ctx context.Context, restorePath string,
 		binPath = path.Join(util.LightningBinPath, "tidb-lightning")
 	}
 
-	klog.Infof("The lightning process is ready, command \"%s %s\"", binPath, strings.Join(args, " "))
+	argsRedacted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--tidb-password=") {
+			arg = "--tidb-password=******"
+		}
+		argsRedacted = append(argsRedacted, arg)
+	}
+	klog.Infof("The lightning process is ready, command \"%s %s\"", binPath, strings.Join(argsRedacted, " "))
 
-	output, err := exec.CommandContext(ctx, binPath, args...).CombinedOutput()
+	output, err := backupUtil.RunCommandWithProgress(exec.CommandContext(ctx, binPath, args...), backupUtil.ParseLightningProgress, onProgress)
 	if err != nil {
-		return fmt.Errorf("cluster %s, execute loader command %v failed, output: %s, err: %v", ro, args, string(output), err)
+		return fmt.Errorf("cluster %s, execute loader command %v failed, output: %s, err: %v", ro, argsRedacted, output, err)
 	}
 	return nil
 }
 
+var (
+	// lightningConfigFile is the config file of lightning for the options without command line flags
+	lightningConfigFile = filepath.Join(constants.BackupRootPath, "tidb-lightning.toml")
+	// lightningSortedKVDir is the directory to store the sorted key-value pairs for the local backend
+	lightningSortedKVDir = filepath.Join(constants.BackupRootPath, "lightning-sorted-kv")
+	// lightningCheckpointFile is the checkpoint file for the file driver, it is in the volume of the restore job,
+	// so the import can be resumed from the checkpoint when the job is retried
+	lightningCheckpointFile = filepath.Join(constants.BackupRootPath, "lightning-checkpoint.pb")
+)
+
+// constructLightningOptions constructs the options of lightning, and writes the config file if some options
+// can't be set by the command line flags.
+func constructLightningOptions(config *v1alpha1.LightningConfig, configFile string) ([]string, error) {
+	if config == nil {
+		return []string{fmt.Sprintf("--backend=%s", v1alpha1.LightningBackendTiDB)}, nil
+	}
+
+	backend := config.Backend
+	if backend == "" {
+		backend = v1alpha1.LightningBackendTiDB
+	}
+	args := []string{fmt.Sprintf("--backend=%s", backend)}
+	if backend == v1alpha1.LightningBackendLocal {
+		args = append(args, fmt.Sprintf("--sorted-kv-dir=%s", lightningSortedKVDir))
+		if config.PDAddress != "" {
+			args = append(args, fmt.Sprintf("--pd-urls=%s", config.PDAddress))
+		}
+	}
+
+	var content strings.Builder
+	if config.DuplicateResolution != "" {
+		content.WriteString("[tikv-importer]\n")
+		if backend == v1alpha1.LightningBackendLocal {
+			fmt.Fprintf(&content, "duplicate-resolution = %q\n", config.DuplicateResolution)
+		} else {
+			fmt.Fprintf(&content, "on-duplicate = %q\n", config.DuplicateResolution)
+		}
+	}
+	if checkpoint := config.Checkpoint; checkpoint != nil {
+		content.WriteString("[checkpoint]\nenable = true\nkeep-after-success = \"remove\"\n")
+		if checkpoint.Driver == v1alpha1.LightningCheckpointDriverMySQL {
+			fmt.Fprintf(&content, "driver = %q\n", checkpoint.Driver)
+			if checkpoint.Schema != "" {
+				fmt.Fprintf(&content, "schema = %q\n", checkpoint.Schema)
+			}
+		} else {
+			fmt.Fprintf(&content, "driver = %q\ndsn = %q\n", v1alpha1.LightningCheckpointDriverFile, lightningCheckpointFile)
+		}
+	}
+	if content.Len() == 0 {
+		return args, nil
+	}
+
+	if err := os.WriteFile(configFile, []byte(content.String()), 0644); err != nil {
+		return nil, fmt.Errorf("write lightning config file %s failed, err: %v", configFile, err)
+	}
+	return append(args, fmt.Sprintf("--config=%s", configFile)), nil
+}
+
 // unarchiveBackupData unarchive backup data to dest dir
 // NOTE: no context/timeout supported for `tarGz.Unarchive`, this may cause to be KILLed when blocking.
 func unarchiveBackupData(backupFile, destDir string) (string, error) {
@@ -0,0 +1,81 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package _import
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+func TestConstructLightningOptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+	configFile := filepath.Join(t.TempDir(), "tidb-lightning.toml")
+
+	// use the tidb backend by default
+	args, err := constructLightningOptions(nil, configFile)
+	g.Expect(err).Should(BeNil())
+	g.Expect(args).Should(Equal([]string{"--backend=tidb"}))
+	args, err = constructLightningOptions(&v1alpha1.LightningConfig{}, configFile)
+	g.Expect(err).Should(BeNil())
+	g.Expect(args).Should(Equal([]string{"--backend=tidb"}))
+	g.Expect(configFile).ShouldNot(BeAnExistingFile())
+
+	args, err = constructLightningOptions(&v1alpha1.LightningConfig{
+		DuplicateResolution: v1alpha1.LightningDuplicateIgnore,
+		Checkpoint:          &v1alpha1.LightningCheckpoint{},
+	}, configFile)
+	g.Expect(err).Should(BeNil())
+	g.Expect(args).Should(Equal([]string{"--backend=tidb", "--config=" + configFile}))
+	content, err := os.ReadFile(configFile)
+	g.Expect(err).Should(BeNil())
+	g.Expect(string(content)).Should(Equal(`[tikv-importer]
+on-duplicate = "ignore"
+[checkpoint]
+enable = true
+keep-after-success = "remove"
+driver = "file"
+dsn = "/backup/lightning-checkpoint.pb"
+`))
+
+	args, err = constructLightningOptions(&v1alpha1.LightningConfig{
+		Backend:             v1alpha1.LightningBackendLocal,
+		DuplicateResolution: v1alpha1.LightningDuplicateRemove,
+		PDAddress:           "basic-pd:2379",
+		Checkpoint: &v1alpha1.LightningCheckpoint{
+			Driver: v1alpha1.LightningCheckpointDriverMySQL,
+			Schema: "checkpoint",
+		},
+	}, configFile)
+	g.Expect(err).Should(BeNil())
+	g.Expect(args).Should(Equal([]string{
+		"--backend=local",
+		"--sorted-kv-dir=/backup/lightning-sorted-kv",
+		"--pd-urls=basic-pd:2379",
+		"--config=" + configFile,
+	}))
+	content, err = os.ReadFile(configFile)
+	g.Expect(err).Should(BeNil())
+	g.Expect(string(content)).Should(Equal(`[tikv-importer]
+duplicate-resolution = "remove"
+[checkpoint]
+enable = true
+keep-after-success = "remove"
+driver = "mysql"
+schema = "checkpoint"
+`))
+}
@@ -19,6 +19,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
 	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	bkconstants "github.com/pingcap/tidb-operator/pkg/backup/constants"
@@ -106,6 +107,7 @@ func (rm *RestoreManager) performRestore(ctx context.Context, restore *v1alpha1.
 	var errs []error
 	restoreDataPath := rm.getRestoreDataPath()
 	opts := util.GetOptions(restore.Spec.StorageProvider)
+	rm.updateProgress(restore, constants.ProgressStepDownload, 0)
 	if err := rm.downloadBackupData(ctx, restoreDataPath, opts); err != nil {
 		errs = append(errs, err)
 		klog.Errorf("download cluster %s backup %s data failed, err: %s", rm, rm.BackupPath, err)
@@ -121,6 +123,7 @@ func (rm *RestoreManager) performRestore(ctx context.Context, restore *v1alpha1.
 	klog.Infof("download cluster %s backup %s data success", rm, rm.BackupPath)
 
 	restoreDataDir := filepath.Dir(restoreDataPath)
+	rm.updateProgress(restore, constants.ProgressStepUnarchive, 0)
 	unarchiveDataPath, err := unarchiveBackupData(restoreDataPath, restoreDataDir)
 	if err != nil {
 		errs = append(errs, err)
@@ -151,7 +154,10 @@ func (rm *RestoreManager) performRestore(ctx context.Context, restore *v1alpha1.
 	}
 	klog.Infof("get cluster %s commitTs %s success", rm, commitTs)
 
-	err = rm.loadTidbClusterData(ctx, unarchiveDataPath, restore)
+	rm.updateProgress(restore, constants.ProgressStepImport, 0)
+	err = rm.loadTidbClusterData(ctx, unarchiveDataPath, restore, func(progress float64) {
+		rm.updateProgress(restore, constants.ProgressStepImport, progress)
+	})
 	if err != nil {
 		errs = append(errs, err)
 		klog.Errorf("restore cluster %s from backup %s failed, err: %s", rm, rm.BackupPath, err)
@@ -168,13 +174,28 @@ func (rm *RestoreManager) performRestore(ctx context.Context, restore *v1alpha1.
 
 	finish := time.Now()
 
+	progressStep, progress := constants.ProgressStepImport, float64(100)
 	updateStatus := &controller.RestoreUpdateStatus{
-		TimeStarted:   &metav1.Time{Time: started},
-		TimeCompleted: &metav1.Time{Time: finish},
-		CommitTs:      &commitTs,
+		TimeStarted:        &metav1.Time{Time: started},
+		TimeCompleted:      &metav1.Time{Time: finish},
+		CommitTs:           &commitTs,
+		ProgressStep:       &progressStep,
+		Progress:           &progress,
+		ProgressUpdateTime: &metav1.Time{Time: finish},
 	}
 	return rm.StatusUpdater.Update(restore, &v1alpha1.RestoreCondition{
 		Type:   v1alpha1.RestoreComplete,
 		Status: corev1.ConditionTrue,
 	}, updateStatus)
 }
+
+// updateProgress updates the progress of the step, the previous step is marked as complete when a new step starts
+func (rm *RestoreManager) updateProgress(restore *v1alpha1.Restore, step string, progress float64) {
+	if err := rm.StatusUpdater.Update(restore, nil, &controller.RestoreUpdateStatus{
+		ProgressStep:       &step,
+		Progress:           &progress,
+		ProgressUpdateTime: &metav1.Time{Time: time.Now()},
+	}); err != nil {
+		klog.Errorf("Failed to update the progress of step %s for cluster %s, %v", step, rm, err)
+	}
+}
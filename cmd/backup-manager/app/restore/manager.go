@@ -294,7 +294,7 @@ func (rm *Manager) performRestore(ctx context.Context, restore *v1alpha1.Restore
 			allFinished = true
 		}
 	default:
-		ts, err := util.GetCommitTsFromBRMetaData(ctx, restore.Spec.StorageProvider)
+		ts, err := util.GetCommitTsFromBRMetaData(ctx, restore.Spec.StorageProvider, getBRMetaEncryption(restore))
 		if err != nil {
 			errs = append(errs, err)
 			klog.Errorf("get cluster %s commitTs failed, err: %s", rm, err)
@@ -325,3 +325,12 @@ func (rm *Manager) performRestore(ctx context.Context, restore *v1alpha1.Restore
 		Status: corev1.ConditionTrue,
 	}, updateStatus)
 }
+
+// getBRMetaEncryption returns the encryption of the backup meta read from the storage of the restore. The storage
+// of PiTR is the log backup which is not encrypted, while the encryption is resolved from the full backup.
+func getBRMetaEncryption(restore *v1alpha1.Restore) *v1alpha1.BackupEncryption {
+	if restore.Spec.Mode == v1alpha1.RestoreModePiTR {
+		return nil
+	}
+	return restore.Spec.Encryption
+}
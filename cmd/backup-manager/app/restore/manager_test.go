@@ -0,0 +1,68 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	. "github.com/onsi/gomega"
+	kvbackup "github.com/pingcap/kvproto/pkg/backup"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetBRMetaEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	prefix := "log-backup"
+	metaData, err := proto.Marshal(&kvbackup.BackupMeta{EndVersion: 431234567890123456})
+	g.Expect(err).To(Succeed())
+	g.Expect(os.MkdirAll(filepath.Join(dir, prefix), 0755)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, prefix, constants.MetaFile), metaData, 0644)).To(Succeed())
+
+	encryption := &v1alpha1.BackupEncryption{
+		Method:     v1alpha1.BackupEncryptionMethodAES256CTR,
+		SecretName: "backup-key",
+	}
+	restore := &v1alpha1.Restore{
+		Spec: v1alpha1.RestoreSpec{
+			Mode: v1alpha1.RestoreModePiTR,
+			StorageProvider: v1alpha1.StorageProvider{
+				Local: &v1alpha1.LocalStorageProvider{
+					Volume:      corev1.Volume{Name: "local"},
+					VolumeMount: corev1.VolumeMount{Name: "local", MountPath: dir},
+					Prefix:      prefix,
+				},
+			},
+			// the encryption is resolved from the full backup of PiTR
+			Encryption: encryption,
+		},
+	}
+
+	// the plaintext backup meta of the log backup is read without decryption
+	g.Expect(getBRMetaEncryption(restore)).To(BeNil())
+	ts, err := util.GetCommitTsFromBRMetaData(context.Background(), restore.Spec.StorageProvider, getBRMetaEncryption(restore))
+	g.Expect(err).To(Succeed())
+	g.Expect(ts).To(Equal(uint64(431234567890123456)))
+
+	restore.Spec.Mode = v1alpha1.RestoreModeSnapshot
+	g.Expect(getBRMetaEncryption(restore)).To(Equal(encryption))
+}
@@ -248,8 +248,12 @@ func constructBROptions(restore *v1alpha1.Restore) ([]string, error) {
 	if config.CheckRequirements != nil {
 		args = append(args, fmt.Sprintf("--check-requirements=%t", *config.CheckRequirements))
 	}
-	if config.RateLimit != nil {
-		args = append(args, fmt.Sprintf("--ratelimit=%d", *config.RateLimit))
+	rateLimit, err := config.GetRateLimit(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if rateLimit != nil {
+		args = append(args, fmt.Sprintf("--ratelimit=%d", *rateLimit))
 	}
 	if config.OnLine != nil {
 		args = append(args, fmt.Sprintf("--online=%t", *config.OnLine))
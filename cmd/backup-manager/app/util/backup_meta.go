@@ -0,0 +1,100 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pingcap/errors"
+	kvbackup "github.com/pingcap/kvproto/pkg/backup"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/util"
+)
+
+// brBackupMetaV2 is the part of BR backup meta v2 which is not defined in the vendored kvproto, the data files
+// of backup meta v2 are recorded in the meta files referenced by the file index instead of the backup meta.
+type brBackupMetaV2 struct {
+	FileIndex *brMetaFile `protobuf:"bytes,13,opt,name=file_index,json=fileIndex" json:"file_index,omitempty"`
+}
+
+func (m *brBackupMetaV2) Reset()         { *m = brBackupMetaV2{} }
+func (m *brBackupMetaV2) String() string { return proto.CompactTextString(m) }
+func (*brBackupMetaV2) ProtoMessage()    {}
+
+// brMetaFile is the MetaFile of BR backup meta v2, the meta files referenced by it are the next level of the index.
+type brMetaFile struct {
+	MetaFiles []*brMetaFileRef `protobuf:"bytes,1,rep,name=meta_files,json=metaFiles" json:"meta_files,omitempty"`
+	DataFiles []*kvbackup.File `protobuf:"bytes,2,rep,name=data_files,json=dataFiles" json:"data_files,omitempty"`
+}
+
+func (m *brMetaFile) Reset()         { *m = brMetaFile{} }
+func (m *brMetaFile) String() string { return proto.CompactTextString(m) }
+func (*brMetaFile) ProtoMessage()    {}
+
+// brMetaFileRef is the File referencing a meta file, BR encrypts the meta file with the cipher IV in it.
+type brMetaFileRef struct {
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	CipherIv []byte `protobuf:"bytes,12,opt,name=cipher_iv,json=cipherIv,proto3" json:"cipher_iv,omitempty"`
+}
+
+func (m *brMetaFileRef) Reset()         { *m = brMetaFileRef{} }
+func (m *brMetaFileRef) String() string { return proto.CompactTextString(m) }
+func (*brMetaFileRef) ProtoMessage()    {}
+
+// getBRBackupFiles returns the data files of the BR backup meta. The data files of backup meta v2 are read from
+// the meta files referenced by the file index, which are decrypted if the backup is encrypted.
+func getBRBackupFiles(ctx context.Context, s *util.StorageBackend, encryption *v1alpha1.BackupEncryption, backupMeta *kvbackup.BackupMeta) ([]*kvbackup.File, error) {
+	if len(backupMeta.Files) > 0 || len(backupMeta.XXX_unrecognized) == 0 {
+		return backupMeta.Files, nil
+	}
+	metaV2 := &brBackupMetaV2{}
+	if err := proto.Unmarshal(backupMeta.XXX_unrecognized, metaV2); err != nil {
+		return nil, errors.Annotate(err, "unmarshal the file index of backup meta v2")
+	}
+	if metaV2.FileIndex == nil {
+		return nil, nil
+	}
+	return readBRMetaFiles(ctx, s, encryption, metaV2.FileIndex)
+}
+
+// readBRMetaFiles returns the data files of the meta file and the meta files referenced by it recursively
+func readBRMetaFiles(ctx context.Context, s *util.StorageBackend, encryption *v1alpha1.BackupEncryption, metaFile *brMetaFile) ([]*kvbackup.File, error) {
+	files := metaFile.DataFiles
+	for _, ref := range metaFile.MetaFiles {
+		data, err := s.ReadAll(ctx, ref.Name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "read meta file %s", ref.Name)
+		}
+		if encryption != nil {
+			// the IV is recorded in the reference instead of prefixing the meta file
+			encrypted := make([]byte, 0, len(ref.CipherIv)+len(data))
+			encrypted = append(append(encrypted, ref.CipherIv...), data...)
+			data, err = decryptBRMetaData(encrypted, util.GetEncryptionKeyFile(encryption))
+			if err != nil {
+				return nil, errors.Annotatef(err, "decrypt meta file %s", ref.Name)
+			}
+		}
+		next := &brMetaFile{}
+		if err := proto.Unmarshal(data, next); err != nil {
+			return nil, errors.Annotatef(err, "unmarshal meta file %s", ref.Name)
+		}
+		nextFiles, err := readBRMetaFiles(ctx, s, encryption, next)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, nextFiles...)
+	}
+	return files, nil
+}
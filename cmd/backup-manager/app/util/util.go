@@ -14,14 +14,21 @@
 package util
 
 import (
+	"bufio"
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -168,6 +175,7 @@ func ConstructBRGlobalOptionsForBackup(backup *v1alpha1.Backup) ([]string, error
 		return nil, err
 	}
 	args = append(args, storageArgs...)
+	args = append(args, constructBRCrypterOptions(backup.Spec.Encryption)...)
 
 	if spec.TableFilter != nil && len(spec.TableFilter) > 0 {
 		for _, tableFilter := range spec.TableFilter {
@@ -221,6 +229,23 @@ func ConstructDumplingOptionsForBackup(backup *v1alpha1.Backup) []string {
 		args = append(args, defaultOptions...)
 	}
 
+	// the typed fields are appended at last to override the default options
+	if config.Dumpling.FileType != "" {
+		args = append(args, fmt.Sprintf("--filetype=%s", config.Dumpling.FileType))
+	}
+	if config.Dumpling.Compress != "" {
+		args = append(args, fmt.Sprintf("--compress=%s", config.Dumpling.Compress))
+	}
+	if config.Dumpling.Rows != nil {
+		args = append(args, fmt.Sprintf("--rows=%d", *config.Dumpling.Rows))
+	}
+	if config.Dumpling.Consistency != "" {
+		args = append(args, fmt.Sprintf("--consistency=%s", config.Dumpling.Consistency))
+	}
+	if config.Dumpling.Snapshot != "" {
+		args = append(args, fmt.Sprintf("--snapshot=%s", config.Dumpling.Snapshot))
+	}
+
 	return args
 }
 
@@ -237,6 +262,7 @@ func ConstructBRGlobalOptionsForRestore(restore *v1alpha1.Restore) ([]string, er
 		return nil, err
 	}
 	args = append(args, storageArgs...)
+	args = append(args, constructBRCrypterOptions(restore.Spec.Encryption)...)
 
 	if config.TableFilter != nil && len(config.TableFilter) > 0 {
 		for _, tableFilter := range config.TableFilter {
@@ -277,6 +303,17 @@ func constructBRGlobalOptions(config *v1alpha1.BRConfig) []string {
 	return args
 }
 
+// constructBRCrypterOptions constructs BR options to encrypt or decrypt the backup data.
+func constructBRCrypterOptions(encryption *v1alpha1.BackupEncryption) []string {
+	if encryption == nil {
+		return nil
+	}
+	return []string{
+		fmt.Sprintf("--crypter.method=%s", encryption.Method),
+		fmt.Sprintf("--crypter.key-file=%s", util.GetEncryptionKeyFile(encryption)),
+	}
+}
+
 // Suffix parses the major and minor version from the string and return the suffix
 func Suffix(version string) string {
 	numS := strings.Split(DefaultVersion, ".")
@@ -353,8 +390,8 @@ func GetBRArchiveSize(meta *kvbackup.BackupMeta) uint64 {
 	return total
 }
 
-// GetBRMetaData get backup metadata from cloud storage
-func GetBRMetaData(ctx context.Context, provider v1alpha1.StorageProvider) (*kvbackup.BackupMeta, error) {
+// GetBRMetaData get backup metadata from cloud storage, the backup metadata is decrypted if the backup is encrypted
+func GetBRMetaData(ctx context.Context, provider v1alpha1.StorageProvider, encryption *v1alpha1.BackupEncryption) (*kvbackup.BackupMeta, error) {
 	s, err := util.NewStorageBackend(provider, &util.StorageCredential{})
 	if err != nil {
 		return nil, err
@@ -391,6 +428,13 @@ func GetBRMetaData(ctx context.Context, provider v1alpha1.StorageProvider) (*kvb
 		return nil, errors.Annotatef(err, "read backup meta from bucket %s and prefix %s", s.GetBucket(), s.GetPrefix())
 	}
 
+	if encryption != nil {
+		metaData, err = decryptBRMetaData(metaData, util.GetEncryptionKeyFile(encryption))
+		if err != nil {
+			return nil, errors.Annotatef(err, "decrypt backup meta from bucket %s and prefix %s", s.GetBucket(), s.GetPrefix())
+		}
+	}
+
 	backupMeta := &kvbackup.BackupMeta{}
 	err = proto.Unmarshal(metaData, backupMeta)
 	if err != nil {
@@ -399,9 +443,58 @@ func GetBRMetaData(ctx context.Context, provider v1alpha1.StorageProvider) (*kvb
 	return backupMeta, nil
 }
 
+// decryptBRMetaData decrypts the backup meta encrypted by BR with the hex encoded key in the key file,
+// BR encrypts the backup meta in AES-CTR mode and prefixes it with the IV.
+func decryptBRMetaData(metaData []byte, keyFile string) ([]byte, error) {
+	if len(metaData) == 0 {
+		return metaData, nil
+	}
+	if len(metaData) < aes.BlockSize {
+		return nil, fmt.Errorf("encrypted backup meta is too short, length %d", len(metaData))
+	}
+	content, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read encryption key file %s failed, err: %v", keyFile, err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, fmt.Errorf("decode encryption key in %s failed, err: %v", keyFile, err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	iv := metaData[:aes.BlockSize]
+	decrypted := make([]byte, len(metaData)-aes.BlockSize)
+	cipher.NewCTR(block, iv).XORKeyStream(decrypted, metaData[aes.BlockSize:])
+	return decrypted, nil
+}
+
+// VerifyBRBackupFiles verifies the backup files referenced by the BR backup meta in cloud storage, the files of
+// backup meta v2 are read from the meta files which are decrypted by the encryption of the backup.
+func VerifyBRBackupFiles(ctx context.Context, provider v1alpha1.StorageProvider, encryption *v1alpha1.BackupEncryption, backupMeta *kvbackup.BackupMeta) (*util.VerifyBackupFilesResult, error) {
+	s, err := util.NewStorageBackend(provider, &util.StorageCredential{})
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	files, err := getBRBackupFiles(ctx, s, encryption, backupMeta)
+	if err != nil {
+		return nil, errors.Annotatef(err, "get backup files in bucket %s and prefix %s", s.GetBucket(), s.GetPrefix())
+	}
+	// TiKV computes the sha256 checksum over the plaintext of the files, so only the existence
+	// and size of the encrypted files are checked
+	result, err := s.VerifyBackupFiles(ctx, files, constants.VerifyBackupConcurrency, encryption != nil)
+	if err != nil {
+		return nil, errors.Annotatef(err, "verify backup files in bucket %s and prefix %s", s.GetBucket(), s.GetPrefix())
+	}
+	return result, nil
+}
+
 // GetCommitTsFromBRMetaData get backup position from `EndVersion` in BR backup meta
-func GetCommitTsFromBRMetaData(ctx context.Context, provider v1alpha1.StorageProvider) (uint64, error) {
-	backupMeta, err := GetBRMetaData(ctx, provider)
+func GetCommitTsFromBRMetaData(ctx context.Context, provider v1alpha1.StorageProvider, encryption *v1alpha1.BackupEncryption) (uint64, error) {
+	backupMeta, err := GetBRMetaData(ctx, provider, encryption)
 	if err != nil {
 		return 0, err
 	}
@@ -510,6 +603,72 @@ func ParseRestoreProgress(line string) (step, progress string) {
 	return
 }
 
+// ParseDumplingProgress parse the progress of the exported tables from the dumpling log
+func ParseDumplingProgress(line string) (progress string) {
+	matchs := dumplingProgressRegex.FindStringSubmatch(line)
+	if len(matchs) < 2 {
+		return
+	}
+	return matchs[1]
+}
+
+// ParseLightningProgress parse the total progress from the lightning log
+func ParseLightningProgress(line string) (progress string) {
+	matchs := lightningProgressRegex.FindStringSubmatch(line)
+	if len(matchs) < 2 {
+		return
+	}
+	return matchs[1]
+}
+
+var (
+	dumplingProgressRegex  = regexp.MustCompile(`\["?progress"?\].*\[tables="\d+/\d+ \((.*?)%\)"\]`)
+	lightningProgressRegex = regexp.MustCompile(`\["?progress"?\] \[total=(.*?)%\]`)
+)
+
+// maxOutputLines is the max number of the last output lines kept for the error message
+const maxOutputLines = 20
+
+// RunCommandWithProgress runs the command and logs its output line by line, parseProgress parses the progress
+// from each line and onProgress is called when a progress is parsed. The last lines of the output are returned.
+func RunCommandWithProgress(cmd *exec.Cmd, parseProgress func(line string) string, onProgress func(progress float64)) (string, error) {
+	stdOut, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("create stdout pipe failed, err: %v", err)
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("start command failed, err: %v", err)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(stdOut)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		klog.Info(line)
+		if len(lines) == maxOutputLines {
+			lines = lines[1:]
+		}
+		lines = append(lines, line)
+
+		if progressStr := parseProgress(line); progressStr != "" {
+			progress, err := strconv.ParseFloat(progressStr, 64)
+			if err != nil {
+				klog.Warningf("Failed to parse progress %s, err: %v", progressStr, err)
+				continue
+			}
+			onProgress(progress)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		klog.Warningf("Failed to read the output, err: %v", err)
+		// drain the output to avoid blocking the command
+		_, _ = io.Copy(io.Discard, stdOut)
+	}
+	return strings.Join(lines, "\n"), cmd.Wait()
+}
+
 const (
 	e2eBackupEnv                string = "E2E_TEST_ENV"
 	e2eExtendBackupTime         string = "Extend_BACKUP_TIME"
@@ -15,15 +15,22 @@ package util
 
 import (
 	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	. "github.com/onsi/gomega"
+	kvbackup "github.com/pingcap/kvproto/pkg/backup"
 	appconstant "github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -131,6 +138,31 @@ func TestConstructDumplingOptionsForBackup(t *testing.T) {
 	}
 }
 
+func TestConstructDumplingTypedOptionsForBackup(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rows := uint64(100000)
+	backup := newBackup()
+	backup.Spec.Dumpling = &v1alpha1.DumplingConfig{
+		FileType:    v1alpha1.DumplingFileTypeCSV,
+		Compress:    v1alpha1.DumplingCompressTypeGzip,
+		Rows:        &rows,
+		Consistency: v1alpha1.DumplingConsistencySnapshot,
+		Snapshot:    "2023-01-01 00:00:00",
+	}
+	var expectArgs []string
+	expectArgs = append(expectArgs, defaultTableFilterOptions...)
+	expectArgs = append(expectArgs, defaultOptions...)
+	expectArgs = append(expectArgs,
+		"--filetype=csv",
+		"--compress=gzip",
+		"--rows=100000",
+		"--consistency=snapshot",
+		"--snapshot=2023-01-01 00:00:00",
+	)
+	g.Expect(ConstructDumplingOptionsForBackup(backup)).To(Equal(expectArgs))
+}
+
 func TestConstructBRGlobalOptionsForBackup(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -208,6 +240,36 @@ func TestConstructBRGlobalOptionsForBackup(t *testing.T) {
 	}
 }
 
+func TestConstructBRGlobalOptionsWithEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	encryption := &v1alpha1.BackupEncryption{
+		Method:     v1alpha1.BackupEncryptionMethodAES256CTR,
+		SecretName: "backup-key",
+	}
+	expectArgs := []string{
+		"--storage=s3://test1-demo1",
+		"--s3.provider=ceph",
+		"--s3.endpoint=http://10.0.0.1",
+		"--crypter.method=aes256-ctr",
+		"--crypter.key-file=/var/lib/br-encryption/encryption-key",
+	}
+
+	backup := newBackup()
+	backup.Spec.BR = &v1alpha1.BRConfig{Cluster: "cluster-1", ClusterNamespace: "default"}
+	backup.Spec.Encryption = encryption
+	args, err := ConstructBRGlobalOptionsForBackup(backup)
+	g.Expect(err).To(Succeed())
+	g.Expect(args).To(Equal(expectArgs))
+
+	restore := newRestore()
+	restore.Spec.BR = &v1alpha1.BRConfig{Cluster: "cluster-1", ClusterNamespace: "default"}
+	restore.Spec.Encryption = encryption
+	args, err = ConstructBRGlobalOptionsForRestore(restore)
+	g.Expect(err).To(Succeed())
+	g.Expect(args).To(Equal(expectArgs))
+}
+
 func TestGetRemotePath(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -629,6 +691,33 @@ func TestParseRestoreProgress(t *testing.T) {
 	}
 }
 
+func TestParseLogicalProgress(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(ParseDumplingProgress("")).To(Equal(""))
+	g.Expect(ParseDumplingProgress(`[2023/01/01 00:00:00.000 +00:00] [INFO] [status.go:37] ["progress"] [tables="2/8 (25.0%)"] [finished-rows=10000]`)).To(Equal("25.0"))
+	g.Expect(ParseLightningProgress("")).To(Equal(""))
+	g.Expect(ParseLightningProgress(`[2023/01/01 00:00:00.000 +00:00] [INFO] [restore.go:1080] [progress] [total=12.5%] [tables="0/3 (0.0%)"]`)).To(Equal("12.5"))
+}
+
+func TestRunCommandWithProgress(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var progresses []float64
+	cmd := exec.Command("sh", "-c", `echo '["progress"] [total=10%]'; echo '["progress"] [total=abc%]' >&2; echo '["progress"] [total=50.5%]'`)
+	output, err := RunCommandWithProgress(cmd, ParseLightningProgress, func(progress float64) {
+		progresses = append(progresses, progress)
+	})
+	g.Expect(err).To(Succeed())
+	g.Expect(progresses).To(Equal([]float64{10, 50.5}))
+	g.Expect(output).To(ContainSubstring("[total=abc%]"))
+
+	cmd = exec.Command("sh", "-c", "echo failed; exit 1")
+	output, err = RunCommandWithProgress(cmd, ParseLightningProgress, func(float64) {})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(output).To(Equal("failed"))
+}
+
 func newBackup() *v1alpha1.Backup {
 	return &v1alpha1.Backup{
 		TypeMeta: metav1.TypeMeta{
@@ -737,3 +826,115 @@ func TestGetSliceExcludeOneString(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBRMetaDataWithEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	backupMeta := &kvbackup.BackupMeta{
+		EndVersion: 431234567890123456,
+		Files:      []*kvbackup.File{{Name: "1_2_3.sst", Size_: 1024}},
+	}
+	metaData, err := proto.Marshal(backupMeta)
+	g.Expect(err).To(Succeed())
+
+	// encrypt the backup meta as BR does
+	key := []byte("0123456789abcdef0123456789abcdef")
+	keyFile := filepath.Join(dir, "encryption-key")
+	g.Expect(ioutil.WriteFile(keyFile, []byte(hex.EncodeToString(key)+"\n"), 0600)).To(Succeed())
+	iv := []byte("fedcba9876543210")
+	block, err := aes.NewCipher(key)
+	g.Expect(err).To(Succeed())
+	encrypted := make([]byte, len(metaData))
+	cipher.NewCTR(block, iv).XORKeyStream(encrypted, metaData)
+	encrypted = append(iv, encrypted...)
+	g.Expect(encrypted[aes.BlockSize:]).NotTo(Equal(metaData))
+
+	decrypted, err := decryptBRMetaData(encrypted, keyFile)
+	g.Expect(err).To(Succeed())
+	g.Expect(decrypted).To(Equal(metaData))
+
+	// the backup meta can't be decrypted with a wrong key
+	g.Expect(ioutil.WriteFile(keyFile, []byte("not-hex"), 0600)).To(Succeed())
+	_, err = decryptBRMetaData(encrypted, keyFile)
+	g.Expect(err).To(HaveOccurred())
+	_, err = decryptBRMetaData(iv[:8], keyFile)
+	g.Expect(err).To(HaveOccurred())
+
+	// the backup meta is read without decryption if the backup is not encrypted
+	prefix := "backup"
+	g.Expect(os.MkdirAll(filepath.Join(dir, prefix), 0755)).To(Succeed())
+	g.Expect(ioutil.WriteFile(filepath.Join(dir, prefix, appconstant.MetaFile), metaData, 0644)).To(Succeed())
+	provider := v1alpha1.StorageProvider{
+		Local: &v1alpha1.LocalStorageProvider{
+			Volume:      corev1.Volume{Name: "local"},
+			VolumeMount: corev1.VolumeMount{Name: "local", MountPath: dir},
+			Prefix:      prefix,
+		},
+	}
+	got, err := GetBRMetaData(context.Background(), provider, nil)
+	g.Expect(err).To(Succeed())
+	g.Expect(got.EndVersion).To(Equal(backupMeta.EndVersion))
+	g.Expect(got.Files).To(HaveLen(1))
+
+	// the encrypted backup meta is decrypted with the key file mounted in the backup job
+	g.Expect(ioutil.WriteFile(filepath.Join(dir, prefix, appconstant.MetaFile), encrypted, 0644)).To(Succeed())
+	_, err = GetBRMetaData(context.Background(), provider, &v1alpha1.BackupEncryption{
+		Method:     v1alpha1.BackupEncryptionMethodAES256CTR,
+		SecretName: "backup-key",
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("decrypt backup meta"))
+}
+
+func TestVerifyBRBackupFilesOfBackupMetaV2(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	content := []byte("the content of the sst")
+	g.Expect(ioutil.WriteFile(filepath.Join(dir, "1_2_3.sst"), content, 0644)).To(Succeed())
+	checksum := sha256.Sum256(content)
+	provider := v1alpha1.StorageProvider{
+		Local: &v1alpha1.LocalStorageProvider{
+			Volume:      corev1.Volume{Name: "local"},
+			VolumeMount: corev1.VolumeMount{Name: "local", MountPath: dir},
+		},
+	}
+
+	// the data files are recorded in the meta file referenced by another meta file in the file index
+	dataFiles, err := proto.Marshal(&brMetaFile{
+		DataFiles: []*kvbackup.File{
+			{Name: "1_2_3.sst", Sha256: checksum[:], Size_: uint64(len(content))},
+			{Name: "4_5_6.sst", Size_: 1024},
+		},
+	})
+	g.Expect(err).To(Succeed())
+	g.Expect(ioutil.WriteFile(filepath.Join(dir, "backupmeta.datafile.000000002"), dataFiles, 0644)).To(Succeed())
+	index, err := proto.Marshal(&brMetaFile{
+		MetaFiles: []*brMetaFileRef{{Name: "backupmeta.datafile.000000002"}},
+	})
+	g.Expect(err).To(Succeed())
+	g.Expect(ioutil.WriteFile(filepath.Join(dir, "backupmeta.datafile.000000001"), index, 0644)).To(Succeed())
+
+	metaData, err := proto.Marshal(&kvbackup.BackupMeta{EndVersion: 1})
+	g.Expect(err).To(Succeed())
+	fileIndex, err := proto.Marshal(&brBackupMetaV2{
+		FileIndex: &brMetaFile{MetaFiles: []*brMetaFileRef{{Name: "backupmeta.datafile.000000001"}}},
+	})
+	g.Expect(err).To(Succeed())
+	backupMeta := &kvbackup.BackupMeta{}
+	g.Expect(proto.Unmarshal(append(metaData, fileIndex...), backupMeta)).To(Succeed())
+	g.Expect(backupMeta.Files).To(BeEmpty())
+
+	result, err := VerifyBRBackupFiles(context.Background(), provider, nil, backupMeta)
+	g.Expect(err).To(Succeed())
+	g.Expect(result.Verified).To(Equal(1))
+	g.Expect(result.Corrupted).To(HaveLen(1))
+	g.Expect(result.Corrupted[0].Name).To(Equal("4_5_6.sst"))
+
+	// the meta file referenced by the file index doesn't exist
+	g.Expect(os.Remove(filepath.Join(dir, "backupmeta.datafile.000000002"))).To(Succeed())
+	_, err = VerifyBRBackupFiles(context.Background(), provider, nil, backupMeta)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("backupmeta.datafile.000000002"))
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller/backupschedule"
 	"github.com/pingcap/tidb-operator/pkg/controller/dmcluster"
 	"github.com/pingcap/tidb-operator/pkg/controller/restore"
+	"github.com/pingcap/tidb-operator/pkg/controller/restoredrill"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbcluster"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbdashboard"
 	"github.com/pingcap/tidb-operator/pkg/controller/tidbinitializer"
@@ -175,6 +176,7 @@ func main() {
 			backup.NewController(deps),
 			restore.NewController(deps),
 			backupschedule.NewController(deps),
+			restoredrill.NewController(deps),
 			tidbinitializer.NewController(deps),
 			tidbmonitor.NewController(deps),
 			tidbngmonitoring.NewController(deps),
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	"github.com/pingcap/tidb-operator/pkg/discovery"
 	"github.com/pingcap/tidb-operator/pkg/discovery/server"
 	"github.com/pingcap/tidb-operator/pkg/dmapi"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
@@ -104,11 +105,20 @@ func main() {
 	// waiting for the shared informer's store has synced.
 	cache.WaitForCacheSync(ctx.Done(), secretInformer.HasSynced)
 
+	// The discovery state is persisted in the ConfigMap created by the operator, so that
+	// the PD/dm-master bootstrap is not affected by the restart of the discovery service
+	var store discovery.ClusterStore
+	if stateConfigMap := os.Getenv("DISCOVERY_STATE_CONFIGMAP"); len(stateConfigMap) > 0 {
+		store = discovery.NewConfigMapStore(kubeCli, os.Getenv("MY_POD_NAMESPACE"), stateConfigMap)
+	} else {
+		klog.Warning("ENV DISCOVERY_STATE_CONFIGMAP is not set, the discovery state is only kept in memory")
+	}
+
 	go wait.Forever(func() {
 		addr := fmt.Sprintf("0.0.0.0:%d", port)
 		klog.Infof("starting TiDB Discovery server, listening on %s", addr)
 		lister := kubeInformerFactory.Core().V1().Secrets().Lister()
-		discoveryServer := server.NewServer(pdapi.NewDefaultPDControl(lister), dmapi.NewDefaultMasterControl(lister), cli, kubeCli)
+		discoveryServer := server.NewServer(pdapi.NewDefaultPDControl(lister), dmapi.NewDefaultMasterControl(lister), cli, kubeCli, store)
 		discoveryServer.ListenAndServe(addr)
 	}, 5*time.Second)
 	go wait.Forever(func() {
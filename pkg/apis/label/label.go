@@ -59,8 +59,13 @@ const (
 	// RestoreLabelKey is restore key
 	RestoreLabelKey string = "tidb.pingcap.com/restore"
 
+	// RestoreDrillLabelKey is restore drill key
+	RestoreDrillLabelKey string = "tidb.pingcap.com/restore-drill"
+
 	// BackupProtectionFinalizer is the name of finalizer on backups or federation backups
 	BackupProtectionFinalizer string = "tidb.pingcap.com/backup-protection"
+	// RestoreDrillProtectionFinalizer is the name of finalizer on restore drills
+	RestoreDrillProtectionFinalizer string = "tidb.pingcap.com/restore-drill-protection"
 
 	// AutoScalingGroupLabelKey describes the autoscaling group of the TiDB
 	AutoScalingGroupLabelKey = "tidb.pingcap.com/autoscaling-group"
@@ -89,6 +94,8 @@ const (
 	AnnTiKVPartition string = "tidb.pingcap.com/tikv-partition"
 	// AnnForceUpgradeKey is tc annotation key to indicate whether force upgrade should be done
 	AnnForceUpgradeKey = "tidb.pingcap.com/force-upgrade"
+	// AnnUpgradeApprove is tc annotation key of the comma separated names of the approved upgrade pause points
+	AnnUpgradeApprove = "tidb.pingcap.com/upgrade-approve"
 	// AnnPDDeferDeleting is pd pod annotation key  in pod for defer for deleting pod
 	AnnPDDeferDeleting = "tidb.pingcap.com/pd-defer-deleting"
 	// AnnSysctlInit is pod annotation key to indicate whether configuring sysctls with init container
@@ -164,8 +171,12 @@ const (
 	BackupJobLabelVal string = "backup"
 	// BackupScheduleJobLabelVal is backup schedule job label value
 	BackupScheduleJobLabelVal string = "backup-schedule"
+	// RestoreDrillJobLabelVal is restore drill job label value
+	RestoreDrillJobLabelVal string = "restore-drill"
 	// InitJobLabelVal is TiDB initializer job label value
 	InitJobLabelVal string = "initializer"
+	// UpgradeHookJobLabelVal is upgrade hook job label value
+	UpgradeHookJobLabelVal string = "upgrade-hook"
 	// TiDBOperator is ManagedByLabelKey label value
 	TiDBOperator string = "tidb-operator"
 
@@ -247,6 +258,14 @@ func NewBackupSchedule() Label {
 	}
 }
 
+// NewRestoreDrill initialize a new Label for restores and jobs of restore drill
+func NewRestoreDrill() Label {
+	return Label{
+		NameLabelKey:      RestoreDrillJobLabelVal,
+		ManagedByLabelKey: "restore-drill-operator",
+	}
+}
+
 func NewMonitor() Label {
 	return Label{
 		// NameLabelKey is used to be compatible with helm monitor
@@ -362,6 +381,12 @@ func (l Label) Restore(val string) Label {
 	return l
 }
 
+// RestoreDrill assigns specific value to restore drill key in label
+func (l Label) RestoreDrill(val string) Label {
+	l[RestoreDrillLabelKey] = val
+	return l
+}
+
 // PD assigns pd to component key in label
 func (l Label) PD() Label {
 	return l.Component(PDLabelVal)
@@ -15,6 +15,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
@@ -133,8 +134,8 @@ func UpdateBackupCondition(status *BackupStatus, condition *BackupCondition) boo
 
 	isDiffPhase := status.Phase != condition.Type
 
-	// restart condition no need to update to phase
-	if isDiffPhase && condition.Type != BackupRestart {
+	// restart and verification conditions no need to update to phase
+	if isDiffPhase && condition.Type != BackupRestart && condition.Type != BackupVerified && condition.Type != BackupCorrupted {
 		status.Phase = condition.Type
 	}
 
@@ -174,6 +175,18 @@ func IsBackupComplete(backup *Backup) bool {
 	return condition != nil && condition.Status == corev1.ConditionTrue
 }
 
+// IsBackupVerified returns true if the backup files of a Backup are verified
+func IsBackupVerified(backup *Backup) bool {
+	_, condition := GetBackupCondition(&backup.Status, BackupVerified)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// IsBackupCorrupted returns true if some backup files of a Backup are missing or corrupted
+func IsBackupCorrupted(backup *Backup) bool {
+	_, condition := GetBackupCondition(&backup.Status, BackupCorrupted)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
 // IsBackupInvalid returns true if a Backup has invalid condition set
 func IsBackupInvalid(backup *Backup) bool {
 	if backup.Spec.Mode == BackupModeLog {
@@ -370,3 +383,67 @@ func IsLogBackupAlreadyTruncate(backup *Backup) bool {
 func IsLogBackupAlreadyStop(backup *Backup) bool {
 	return backup.Spec.Mode == BackupModeLog && backup.Status.Phase == BackupStopped
 }
+
+// timeWindowLayout is the layout of the start and the end of TimeWindow
+const timeWindowLayout = "15:04"
+
+// Contains returns whether the time is in the daily time window
+func (w TimeWindow) Contains(t time.Time) (bool, error) {
+	start, err := time.Parse(timeWindowLayout, w.Start)
+	if err != nil {
+		return false, fmt.Errorf("invalid start %q of time window: %v", w.Start, err)
+	}
+	end, err := time.Parse(timeWindowLayout, w.End)
+	if err != nil {
+		return false, fmt.Errorf("invalid end %q of time window: %v", w.End, err)
+	}
+	t = t.UTC()
+	minutes := t.Hour()*60 + t.Minute()
+	startMinutes := start.Hour()*60 + start.Minute()
+	endMinutes := end.Hour()*60 + end.Minute()
+	if startMinutes < endMinutes {
+		return minutes >= startMinutes && minutes < endMinutes, nil
+	}
+	// the window crosses midnight
+	return minutes >= startMinutes || minutes < endMinutes, nil
+}
+
+// IsInTimeWindows returns whether the time is in any of the time windows, it returns true if there is no window
+func IsInTimeWindows(windows []TimeWindow, t time.Time) (bool, error) {
+	if len(windows) == 0 {
+		return true, nil
+	}
+	for _, w := range windows {
+		contains, err := w.Contains(t)
+		if err != nil {
+			return false, err
+		}
+		if contains {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetRateLimit returns the rate limit of the first rate limit window containing the time,
+// and returns RateLimit if no window contains it.
+func (c *BRConfig) GetRateLimit(t time.Time) (*uint, error) {
+	for i := range c.RateLimitWindows {
+		contains, err := c.RateLimitWindows[i].Contains(t)
+		if err != nil {
+			return nil, err
+		}
+		if contains {
+			return &c.RateLimitWindows[i].RateLimit, nil
+		}
+	}
+	return c.RateLimit, nil
+}
+
+// GetSecretKey returns the key of the encryption key in the secret
+func (e *BackupEncryption) GetSecretKey() string {
+	if e.SecretKey == "" {
+		return DefaultBackupEncryptionSecretKey
+	}
+	return e.SecretKey
+}
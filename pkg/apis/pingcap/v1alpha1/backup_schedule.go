@@ -16,6 +16,9 @@ package v1alpha1
 import (
 	"fmt"
 	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (bs *BackupSchedule) GetBackupCRDName(timestamp time.Time) string {
@@ -25,3 +28,46 @@ func (bs *BackupSchedule) GetBackupCRDName(timestamp time.Time) string {
 func (bs *BackupSchedule) GetLogBackupCRDName() string {
 	return fmt.Sprintf("%s-%s", "log", bs.GetName())
 }
+
+// GetBackupScheduleCondition returns the condition with the provided type.
+func GetBackupScheduleCondition(status *BackupScheduleStatus, conditionType BackupScheduleConditionType) (int, *BackupScheduleCondition) {
+	if status == nil {
+		return -1, nil
+	}
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			return i, &status.Conditions[i]
+		}
+	}
+	return -1, nil
+}
+
+// UpdateBackupScheduleCondition updates existing BackupSchedule condition or creates a new
+// one. Sets LastTransitionTime to now if the status has changed.
+// Returns true if BackupSchedule condition has changed or has been added.
+func UpdateBackupScheduleCondition(status *BackupScheduleStatus, condition *BackupScheduleCondition) bool {
+	if condition == nil {
+		return false
+	}
+	condition.LastTransitionTime = metav1.Now()
+	conditionIndex, oldCondition := GetBackupScheduleCondition(status, condition.Type)
+	if oldCondition == nil {
+		status.Conditions = append(status.Conditions, *condition)
+		return true
+	}
+
+	if condition.Status == oldCondition.Status {
+		condition.LastTransitionTime = oldCondition.LastTransitionTime
+	}
+	isUpdate := condition.Status == oldCondition.Status &&
+		condition.Reason == oldCondition.Reason &&
+		condition.Message == oldCondition.Message
+	status.Conditions[conditionIndex] = *condition
+	return !isUpdate
+}
+
+// IsBackupScheduleRPOViolated returns true if the latest restorable time of BackupSchedule falls behind the recovery point objective
+func IsBackupScheduleRPOViolated(bs *BackupSchedule) bool {
+	_, condition := GetBackupScheduleCondition(&bs.Status, BackupScheduleRPOViolated)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
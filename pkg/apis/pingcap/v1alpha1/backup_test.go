@@ -0,0 +1,86 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTimeWindowContains(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2023, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		window   TimeWindow
+		time     time.Time
+		contains bool
+	}{
+		{window: TimeWindow{Start: "01:00", End: "06:00"}, time: at(0, 59), contains: false},
+		{window: TimeWindow{Start: "01:00", End: "06:00"}, time: at(1, 0), contains: true},
+		{window: TimeWindow{Start: "01:00", End: "06:00"}, time: at(5, 59), contains: true},
+		{window: TimeWindow{Start: "01:00", End: "06:00"}, time: at(6, 0), contains: false},
+		// the window crosses midnight
+		{window: TimeWindow{Start: "22:00", End: "02:00"}, time: at(23, 0), contains: true},
+		{window: TimeWindow{Start: "22:00", End: "02:00"}, time: at(1, 0), contains: true},
+		{window: TimeWindow{Start: "22:00", End: "02:00"}, time: at(12, 0), contains: false},
+		// the time is converted to UTC
+		{window: TimeWindow{Start: "01:00", End: "06:00"}, time: at(2, 0).In(time.FixedZone("UTC+8", 8*3600)), contains: true},
+	}
+	for _, tt := range tests {
+		contains, err := tt.window.Contains(tt.time)
+		g.Expect(err).Should(BeNil())
+		g.Expect(contains).Should(Equal(tt.contains), "window %v, time %s", tt.window, tt.time)
+	}
+
+	_, err := TimeWindow{Start: "1am", End: "06:00"}.Contains(at(1, 0))
+	g.Expect(err).Should(HaveOccurred())
+
+	inWindows, err := IsInTimeWindows(nil, at(1, 0))
+	g.Expect(err).Should(BeNil())
+	g.Expect(inWindows).Should(BeTrue())
+	inWindows, err = IsInTimeWindows([]TimeWindow{{Start: "01:00", End: "02:00"}, {Start: "03:00", End: "04:00"}}, at(3, 30))
+	g.Expect(err).Should(BeNil())
+	g.Expect(inWindows).Should(BeTrue())
+	inWindows, err = IsInTimeWindows([]TimeWindow{{Start: "01:00", End: "02:00"}, {Start: "03:00", End: "04:00"}}, at(2, 30))
+	g.Expect(err).Should(BeNil())
+	g.Expect(inWindows).Should(BeFalse())
+}
+
+func TestBRConfigGetRateLimit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rateLimit := uint(100)
+	config := &BRConfig{
+		RateLimit: &rateLimit,
+		RateLimitWindows: []BRRateLimitWindow{
+			{TimeWindow: TimeWindow{Start: "09:00", End: "18:00"}, RateLimit: 10},
+			{TimeWindow: TimeWindow{Start: "08:00", End: "20:00"}, RateLimit: 50},
+		},
+	}
+	for hour, expected := range map[int]uint{7: 100, 8: 50, 12: 10, 19: 50, 20: 100} {
+		rateLimit, err := config.GetRateLimit(time.Date(2023, 1, 1, hour, 0, 0, 0, time.UTC))
+		g.Expect(err).Should(BeNil())
+		g.Expect(*rateLimit).Should(Equal(expected), "hour %d", hour)
+	}
+
+	config.RateLimit = nil
+	limit, err := config.GetRateLimit(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	g.Expect(err).Should(BeNil())
+	g.Expect(limit).Should(BeNil())
+}
@@ -77,6 +77,7 @@ type ComponentAccessor interface {
 	PodManagementPolicy() apps.PodManagementPolicyType
 	TopologySpreadConstraints() []corev1.TopologySpreadConstraint
 	SuspendAction() *SuspendAction
+	UpgradeHooks() *UpgradeHooks
 }
 
 func (tc *TidbCluster) AllComponentSpec() []ComponentAccessor {
@@ -461,6 +462,13 @@ func (a *componentAccessorImpl) SuspendAction() *SuspendAction {
 	return action
 }
 
+func (a *componentAccessorImpl) UpgradeHooks() *UpgradeHooks {
+	if a.ComponentSpec == nil {
+		return nil
+	}
+	return a.ComponentSpec.UpgradeHooks
+}
+
 func getComponentLabelValue(c MemberType) string {
 	switch c {
 	case PDMemberType:
@@ -41,6 +41,10 @@ const (
 	BackupScheduleKind    = "BackupSchedule"
 	BackupScheduleKindKey = "backupschedule"
 
+	RestoreDrillName    = "restoredrills"
+	RestoreDrillKind    = "RestoreDrill"
+	RestoreDrillKindKey = "restoredrill"
+
 	TiDBMonitorName    = "tidbmonitors"
 	TiDBMonitorKind    = "TidbMonitor"
 	TiDBMonitorKindKey = "tidbmonitor"
@@ -79,6 +83,7 @@ type CrdKinds struct {
 	Backup                CrdKind
 	Restore               CrdKind
 	BackupSchedule        CrdKind
+	RestoreDrill          CrdKind
 	TiDBMonitor           CrdKind
 	TiDBInitializer       CrdKind
 	TidbClusterAutoScaler CrdKind
@@ -92,6 +97,7 @@ var DefaultCrdKinds = CrdKinds{
 	Backup:                CrdKind{Plural: BackupName, Kind: BackupKind, ShortNames: []string{"bk"}, SpecName: SpecPath + BackupKind},
 	Restore:               CrdKind{Plural: RestoreName, Kind: RestoreKind, ShortNames: []string{"rt"}, SpecName: SpecPath + RestoreKind},
 	BackupSchedule:        CrdKind{Plural: BackupScheduleName, Kind: BackupScheduleKind, ShortNames: []string{"bks"}, SpecName: SpecPath + BackupScheduleKind},
+	RestoreDrill:          CrdKind{Plural: RestoreDrillName, Kind: RestoreDrillKind, ShortNames: []string{"rdr"}, SpecName: SpecPath + RestoreDrillKind},
 	TiDBMonitor:           CrdKind{Plural: TiDBMonitorName, Kind: TiDBMonitorKind, ShortNames: []string{"tm"}, SpecName: SpecPath + TiDBMonitorKind},
 	TiDBInitializer:       CrdKind{Plural: TiDBInitializerName, Kind: TiDBInitializerKind, ShortNames: []string{"ti"}, SpecName: SpecPath + TiDBInitializerKind},
 	TidbClusterAutoScaler: CrdKind{Plural: TidbClusterAutoScalerName, Kind: TidbClusterAutoScalerKind, ShortNames: []string{"ta"}, SpecName: SpecPath + TidbClusterAutoScalerKind},
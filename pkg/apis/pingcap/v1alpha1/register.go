@@ -58,6 +58,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&BackupScheduleList{},
 		&Restore{},
 		&RestoreList{},
+		&RestoreDrill{},
+		&RestoreDrillList{},
 		&DataResource{},
 		&DataResourceList{},
 		&TidbInitializer{},
@@ -141,3 +141,21 @@ func IsRestoreDataComplete(restore *Restore) bool {
 	_, condition := GetRestoreCondition(&restore.Status, RestoreDataComplete)
 	return condition != nil && condition.Status == corev1.ConditionTrue
 }
+
+// GetCleanupPolicy returns the cleanup policy of the TidbCluster created from the template
+func (t *RestoreClusterTemplate) GetCleanupPolicy() RestoreClusterCleanupPolicy {
+	if t.CleanupPolicy == "" {
+		return RestoreClusterCleanupPolicyDelete
+	}
+	return t.CleanupPolicy
+}
+
+// IsRestoreClusterFinished returns true if the TidbCluster created from the template no longer needs
+// to be synced by the Restore, that is it is updated to the template or cleaned up after the restore.
+func IsRestoreClusterFinished(restore *Restore) bool {
+	switch restore.Status.ClusterPhase {
+	case RestoreClusterNormal, RestoreClusterDeleted, RestoreClusterRetained:
+		return true
+	}
+	return false
+}
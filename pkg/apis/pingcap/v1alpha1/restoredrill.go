@@ -0,0 +1,42 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"fmt"
+)
+
+// DefaultRestoreDrillHistoryLimit is the default number of the finished drills kept in the status
+const DefaultRestoreDrillHistoryLimit = 5
+
+// GetHistoryLimit returns the number of the finished drills kept in the status
+func (rd *RestoreDrill) GetHistoryLimit() int {
+	if rd.Spec.HistoryLimit == nil {
+		return DefaultRestoreDrillHistoryLimit
+	}
+	return int(*rd.Spec.HistoryLimit)
+}
+
+// GetCheckJobName returns the name of the job running the checks of the drill restored by the restore
+func (rd *RestoreDrill) GetCheckJobName(restoreName string) string {
+	return fmt.Sprintf("check-%s", restoreName)
+}
+
+// GetMinRows returns the min number of the rows for the RowCount check
+func (c *RestoreDrillCheck) GetMinRows() int64 {
+	if c.MinRows == nil {
+		return 1
+	}
+	return *c.MinRows
+}
@@ -0,0 +1,214 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestoreDrill restores the latest complete backup of a BackupSchedule into a temporary TidbCluster
+// on a schedule, runs the SQL checks against it and tears it down.
+//
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName="rdr"
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`,description="The cron format string used for drill scheduling"
+// +kubebuilder:printcolumn:name="BackupSchedule",type=string,JSONPath=`.spec.backupScheduleName`,description="The BackupSchedule whose backups are restored"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.currentRun.phase`,description="The phase of the running drill"
+// +kubebuilder:printcolumn:name="LastResult",type=string,JSONPath=`.status.lastResult`,description="The result of the last finished drill"
+// +kubebuilder:printcolumn:name="LastSuccessfulTime",type=date,JSONPath=`.status.lastSuccessfulTime`,description="The completion time of the last passed drill",priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type RestoreDrill struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Spec contains all spec about the restore drill.
+	Spec RestoreDrillSpec `json:"spec"`
+
+	// Status is most recently observed status of the restore drill.
+	//
+	// +k8s:openapi-gen=false
+	Status RestoreDrillStatus `json:"status,omitempty"`
+}
+
+// RestoreDrillList is a RestoreDrill list.
+//
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type RestoreDrillList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +k8s:openapi-gen=false
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RestoreDrill `json:"items"`
+}
+
+// RestoreDrillSpec is spec of the restore drill.
+//
+// +k8s:openapi-gen=true
+type RestoreDrillSpec struct {
+	// Schedule specifies the cron string used for drill scheduling.
+	Schedule string `json:"schedule"`
+	// Pause means the drill is not scheduled, the running drill is not affected.
+	// +optional
+	Pause bool `json:"pause,omitempty"`
+	// BackupScheduleName is the name of the BackupSchedule in the same namespace,
+	// the latest complete snapshot backup of it is restored.
+	BackupScheduleName string `json:"backupScheduleName"`
+	// RestoreTemplate is the specification of the restore of the drill, `clusterTemplate` must be set to
+	// create the temporary TidbCluster. The storage, the type and `br.cluster` are set by the drill.
+	RestoreTemplate RestoreSpec `json:"restoreTemplate"`
+	// Access is the config to access TiDB of the temporary TidbCluster to run the checks.
+	// An empty host means the TiDB service of the TidbCluster, and an empty secretName means no password.
+	// If it is not set, the checks access the TiDB service by root without password.
+	// +optional
+	Access *TiDBAccessConfig `json:"access,omitempty"`
+	// Checks are the SQL checks run against the temporary TidbCluster after the restore is complete
+	// +optional
+	Checks []RestoreDrillCheck `json:"checks,omitempty"`
+	// HistoryLimit is the number of the finished drills kept in the status, default is 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+}
+
+// RestoreDrillCheckType is the type of the SQL check of the restore drill
+type RestoreDrillCheckType string
+
+const (
+	// RestoreDrillCheckRowCount counts the rows of the table by `SELECT COUNT(*)`
+	RestoreDrillCheckRowCount RestoreDrillCheckType = "RowCount"
+	// RestoreDrillCheckChecksum calculates the checksum of the table by `ADMIN CHECKSUM TABLE`
+	RestoreDrillCheckChecksum RestoreDrillCheckType = "Checksum"
+)
+
+// RestoreDrillCheck is a SQL check run against the restored TidbCluster.
+//
+// +k8s:openapi-gen=true
+type RestoreDrillCheck struct {
+	// Name of the check, it should be unique in the drill
+	Name string `json:"name"`
+	// Type of the check
+	// +kubebuilder:validation:Enum:="RowCount";"Checksum"
+	Type RestoreDrillCheckType `json:"type"`
+	// Table is the table to check in the format of `db.table`
+	Table string `json:"table"`
+	// MinRows is the min number of the rows for the RowCount check, default is 1
+	// +optional
+	MinRows *int64 `json:"minRows,omitempty"`
+	// ExpectedChecksum is the expected `Checksum_crc64_xor` of the Checksum check.
+	// If it is not set, the check passes as long as the checksum is calculated.
+	// +optional
+	ExpectedChecksum string `json:"expectedChecksum,omitempty"`
+}
+
+// RestoreDrillPhase is the phase of a restore drill
+type RestoreDrillPhase string
+
+const (
+	// RestoreDrillRestoring means the backup is being restored into the temporary TidbCluster
+	RestoreDrillRestoring RestoreDrillPhase = "Restoring"
+	// RestoreDrillChecking means the checks are running
+	RestoreDrillChecking RestoreDrillPhase = "Checking"
+	// RestoreDrillCleaningUp means the result is determined and the resources are being deleted
+	RestoreDrillCleaningUp RestoreDrillPhase = "CleaningUp"
+	// RestoreDrillFinished means the drill is finished
+	RestoreDrillFinished RestoreDrillPhase = "Finished"
+)
+
+// RestoreDrillResult is the result of a restore drill
+type RestoreDrillResult string
+
+const (
+	// RestoreDrillPassed means the backup is restored and all checks pass
+	RestoreDrillPassed RestoreDrillResult = "Passed"
+	// RestoreDrillFailed means the restore or any of the checks failed
+	RestoreDrillFailed RestoreDrillResult = "Failed"
+)
+
+// RestoreDrillCheckResult is the result of a check of the restore drill.
+//
+// +k8s:openapi-gen=true
+type RestoreDrillCheckResult struct {
+	// Name of the check
+	Name string `json:"name"`
+	// Passed is whether the check passes
+	Passed bool `json:"passed"`
+	// Value is the row count or the checksum of the table
+	// +optional
+	Value string `json:"value,omitempty"`
+	// Message is the reason of the failed check
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Duration is the time used by the check
+	// +optional
+	Duration string `json:"duration,omitempty"`
+}
+
+// RestoreDrillRun is the status of a scheduled restore drill.
+//
+// +k8s:openapi-gen=true
+type RestoreDrillRun struct {
+	// ScheduleTime is the time at which the drill was scheduled
+	ScheduleTime metav1.Time `json:"scheduleTime"`
+	// Phase of the drill
+	Phase RestoreDrillPhase `json:"phase,omitempty"`
+	// Result of the drill, it is set when the phase is CleaningUp or Finished
+	// +optional
+	Result RestoreDrillResult `json:"result,omitempty"`
+	// Message is the reason of the failed drill
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Backup is the name of the restored backup
+	// +optional
+	Backup string `json:"backup,omitempty"`
+	// Restore is the name of the Restore created by the drill
+	// +optional
+	Restore string `json:"restore,omitempty"`
+	// Cluster is the name of the temporary TidbCluster
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+	// RestoreDuration is the time used by the restore, including creating the TidbCluster
+	// +optional
+	RestoreDuration string `json:"restoreDuration,omitempty"`
+	// Checks are the results of the checks
+	// +optional
+	Checks []RestoreDrillCheckResult `json:"checks,omitempty"`
+	// CompletionTime is the time at which the drill was finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// RestoreDrillStatus is status of the restore drill.
+type RestoreDrillStatus struct {
+	// LastScheduleTime is the time at which the last drill was scheduled
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastResult is the result of the last finished drill
+	// +optional
+	LastResult RestoreDrillResult `json:"lastResult,omitempty"`
+	// LastSuccessfulTime is the completion time of the last passed drill
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// CurrentRun is the running drill
+	// +optional
+	CurrentRun *RestoreDrillRun `json:"currentRun,omitempty"`
+	// History are the finished drills, the latest is the first
+	// +optional
+	History []RestoreDrillRun `json:"history,omitempty"`
+}
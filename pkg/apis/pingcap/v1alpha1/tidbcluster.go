@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
@@ -43,6 +44,10 @@ const (
 	// defaultTiCDCGracefulShutdownTimeout is the timeout limit of graceful
 	// shutdown a TiCDC pod.
 	defaultTiCDCGracefulShutdownTimeout = 10 * time.Minute
+	// defaultTiDBCanaryBakeTime is the time to observe the canaries of the TiDB canary upgrade
+	defaultTiDBCanaryBakeTime = 10 * time.Minute
+	// defaultTiDBCanaryProgressDeadline is the max time for the canaries of the TiDB canary upgrade to become healthy
+	defaultTiDBCanaryProgressDeadline = 10 * time.Minute
 
 	// the latest version
 	versionLatest = "latest"
@@ -1015,6 +1020,79 @@ func (tidb *TiDBSpec) GetServicePort() int32 {
 	return port
 }
 
+// GetMaxUnavailable returns the max number of TiDB pods upgraded at the same time
+func (tidb *TiDBSpec) GetMaxUnavailable() (int, error) {
+	return getMaxUnavailable(tidb.MaxUnavailable, tidb.Replicas)
+}
+
+// GetMaxUnavailable returns the max number of TiProxy pods upgraded at the same time
+func (tiproxy *TiProxySpec) GetMaxUnavailable() (int, error) {
+	return getMaxUnavailable(tiproxy.MaxUnavailable, tiproxy.Replicas)
+}
+
+// GetMaxUnavailable returns the max number of TiCDC pods upgraded at the same time
+func (ticdc *TiCDCSpec) GetMaxUnavailable() (int, error) {
+	return getMaxUnavailable(ticdc.MaxUnavailable, ticdc.Replicas)
+}
+
+func getMaxUnavailable(maxUnavailable *intstr.IntOrString, replicas int32) (int, error) {
+	if maxUnavailable == nil {
+		return 1, nil
+	}
+	n, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, int(replicas), false)
+	if err != nil {
+		return 0, err
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n, nil
+}
+
+// GetReplicas returns the number of canaries in the TiDB pods of the given replicas,
+// it is at least 1 and at most the replicas.
+func (c *TiDBCanaryUpgrade) GetReplicas(replicas int32) (int32, error) {
+	canaries := int32(1)
+	if c.Replicas != nil {
+		n, err := intstr.GetScaledValueFromIntOrPercent(c.Replicas, int(replicas), true)
+		if err != nil {
+			return 0, err
+		}
+		canaries = int32(n)
+	}
+	if canaries < 1 {
+		canaries = 1
+	}
+	if canaries > replicas {
+		canaries = replicas
+	}
+	return canaries, nil
+}
+
+// GetBakeTime returns the time to observe the canaries
+func (c *TiDBCanaryUpgrade) GetBakeTime() time.Duration {
+	if c.BakeTime == nil {
+		return defaultTiDBCanaryBakeTime
+	}
+	return c.BakeTime.Duration
+}
+
+// GetProgressDeadline returns the max time for the canaries to become healthy
+func (c *TiDBCanaryUpgrade) GetProgressDeadline() time.Duration {
+	if c.ProgressDeadline == nil {
+		return defaultTiDBCanaryProgressDeadline
+	}
+	return c.ProgressDeadline.Duration
+}
+
+// GetMaxRestarts returns the max number of container restarts of each canary
+func (c *TiDBCanaryUpgrade) GetMaxRestarts() int32 {
+	if c.MaxRestarts == nil {
+		return 0
+	}
+	return *c.MaxRestarts
+}
+
 func (tikv *TiKVSpec) ShouldSeparateRocksDBLog() bool {
 	separateRocksDBLog := tikv.SeparateRocksDBLog
 	if separateRocksDBLog == nil {
@@ -1031,6 +1109,10 @@ func (tikv *TiKVSpec) ShouldSeparateRaftLog() bool {
 	return *separateRaftLog
 }
 
+func (tikv *TiKVSpec) ShouldUpgradeByZone() bool {
+	return tikv.ZoneAwareUpgrade != nil && *tikv.ZoneAwareUpgrade
+}
+
 func (tikv *TiKVSpec) GetLogTailerSpec() LogTailerSpec {
 	if tikv.LogTailer == nil {
 		return defaultLogTailerSpec
@@ -1242,6 +1324,15 @@ func (tc *TidbCluster) IsComponentLeaderEvicting(compType MemberType) bool {
 	return meta.IsStatusConditionTrue(conds, ConditionTypeLeaderEvicting)
 }
 
+// AppendOperationRecord appends the record to `status.operationHistory`, the oldest records
+// are dropped if there are more than MaxOperationHistory records.
+func (tc *TidbCluster) AppendOperationRecord(record OperationRecord) {
+	tc.Status.OperationHistory = append(tc.Status.OperationHistory, record)
+	if n := len(tc.Status.OperationHistory); n > MaxOperationHistory {
+		tc.Status.OperationHistory = append([]OperationRecord(nil), tc.Status.OperationHistory[n-MaxOperationHistory:]...)
+	}
+}
+
 func (tc *TidbCluster) StartScriptVersion() StartScriptVersion {
 	switch tc.Spec.StartScriptVersion {
 	case StartScriptV1, StartScriptV2:
@@ -14,6 +14,7 @@
 package v1alpha1
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 )
 
@@ -813,6 +815,64 @@ func TestTiCDCGracefulShutdownTimeout(t *testing.T) {
 	g.Expect(tc.TiCDCGracefulShutdownTimeout()).To(Equal(time.Minute))
 }
 
+func TestTiDBCanaryUpgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	canary := &TiDBCanaryUpgrade{}
+	g.Expect(canary.GetReplicas(5)).To(Equal(int32(1)))
+	g.Expect(canary.GetBakeTime()).To(Equal(defaultTiDBCanaryBakeTime))
+	g.Expect(canary.GetProgressDeadline()).To(Equal(defaultTiDBCanaryProgressDeadline))
+	g.Expect(canary.GetMaxRestarts()).To(Equal(int32(0)))
+
+	replicas := intstr.FromString("30%")
+	canary.Replicas = &replicas
+	g.Expect(canary.GetReplicas(5)).To(Equal(int32(2)))
+	replicas = intstr.FromString("0%")
+	canary.Replicas = &replicas
+	g.Expect(canary.GetReplicas(5)).To(Equal(int32(1)))
+	replicas = intstr.FromInt(10)
+	canary.Replicas = &replicas
+	g.Expect(canary.GetReplicas(5)).To(Equal(int32(5)))
+	replicas = intstr.FromString("abc")
+	canary.Replicas = &replicas
+	_, err := canary.GetReplicas(5)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestGetMaxUnavailable(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tidb := &TiDBSpec{Replicas: 10}
+	g.Expect(tidb.GetMaxUnavailable()).To(Equal(1))
+
+	maxUnavailable := intstr.FromString("25%")
+	tidb.MaxUnavailable = &maxUnavailable
+	g.Expect(tidb.GetMaxUnavailable()).To(Equal(2))
+	maxUnavailable = intstr.FromString("5%")
+	g.Expect(tidb.GetMaxUnavailable()).To(Equal(1))
+	maxUnavailable = intstr.FromInt(3)
+	g.Expect(tidb.GetMaxUnavailable()).To(Equal(3))
+	maxUnavailable = intstr.FromString("abc")
+	_, err := tidb.GetMaxUnavailable()
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestAppendOperationRecord(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	for i := 0; i < MaxOperationHistory+5; i++ {
+		tc.AppendOperationRecord(OperationRecord{
+			Component: TiKVMemberType,
+			Reason:    "StoreDeleted",
+			Message:   fmt.Sprintf("delete store %d", i),
+		})
+	}
+	g.Expect(tc.Status.OperationHistory).To(HaveLen(MaxOperationHistory))
+	g.Expect(tc.Status.OperationHistory[0].Message).To(Equal("delete store 5"))
+	g.Expect(tc.Status.OperationHistory[MaxOperationHistory-1].Message).To(Equal(fmt.Sprintf("delete store %d", MaxOperationHistory+4)))
+}
+
 func TestComponentFunc(t *testing.T) {
 	t.Run("ComponentIsNormal", func(t *testing.T) {
 		g := NewGomegaWithT(t)
@@ -68,6 +68,34 @@ type TidbClusterAutoScalerSpec struct {
 	// TiDB represents the auto-scaling spec for tidb
 	// +optional
 	TiDB *TidbAutoScalerSpec `json:"tidb,omitempty"`
+
+	// TiFlash represents the auto-scaling spec for tiflash
+	// +optional
+	TiFlash *TiflashAutoScalerSpec `json:"tiflash,omitempty"`
+
+	// TiCDC represents the auto-scaling spec for ticdc
+	// +optional
+	TiCDC *TicdcAutoScalerSpec `json:"ticdc,omitempty"`
+
+	// Monitor references the TidbMonitor which provides the Prometheus to evaluate
+	// the custom auto-scaling rules
+	// +optional
+	Monitor *TidbMonitorRef `json:"monitor,omitempty"`
+
+	// MetricsURL is the raw Prometheus URL to evaluate the custom auto-scaling rules,
+	// it takes precedence over Monitor if both are set
+	// +optional
+	MetricsURL *string `json:"metricsUrl,omitempty"`
+
+	// DryRun makes the auto-scaler only compute the recommendations and record them in
+	// the status and events, the autoscaling TidbClusters are never created, updated or deleted
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// RecommendationHistoryLimit is the max number of recommendations kept in the status,
+	// default to 10
+	// +optional
+	RecommendationHistoryLimit *int32 `json:"recommendationHistoryLimit,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -98,12 +126,69 @@ type AutoRule struct {
 // TikvAutoScalerSpec describes the spec for tikv auto-scaling
 type TikvAutoScalerSpec struct {
 	BasicAutoScalerSpec `json:",inline"`
+
+	// Vertical makes the auto-scaler adjust the resources of tikv in the target TidbCluster
+	// +optional
+	Vertical *VerticalAutoScalerSpec `json:"vertical,omitempty"`
 }
 
 // +k8s:openapi-gen=true
 // TidbAutoScalerSpec describes the spec for tidb auto-scaling
 type TidbAutoScalerSpec struct {
 	BasicAutoScalerSpec `json:",inline"`
+
+	// Vertical makes the auto-scaler adjust the resources of tidb in the target TidbCluster
+	// +optional
+	Vertical *VerticalAutoScalerSpec `json:"vertical,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// VerticalAutoScalerSpec describes the spec for vertical auto-scaling, which adjusts the cpu and memory
+// requests of the component in the target TidbCluster in place according to the observed usage.
+// The limits are scaled in proportion to the requests. The change is rolled out by the upgraders
+// of the TidbCluster, so the TiKV leaders are evicted and TiDB is shut down gracefully.
+type VerticalAutoScalerSpec struct {
+	// MinAllowed is the lower bound of the cpu and memory requests
+	// +optional
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+	// MaxAllowed is the upper bound of the cpu and memory requests
+	// +optional
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+	// Percentile is the percentile of the observed usage in the window which the requests are based on.
+	// Default to 90
+	// +optional
+	Percentile *int32 `json:"percentile,omitempty"`
+	// Window is the time range of the observed usage, e.g. `24h` or `7d`.
+	// Default to `24h`
+	// +optional
+	Window string `json:"window,omitempty"`
+	// TargetUtilization is the expected ratio of the usage to the requests.
+	// Default to 0.8
+	// +optional
+	TargetUtilization *float64 `json:"targetUtilization,omitempty"`
+	// Tolerance is the min relative change of the requests to update the TidbCluster.
+	// Default to 0.1
+	// +optional
+	Tolerance *float64 `json:"tolerance,omitempty"`
+	// IntervalSeconds represents the duration seconds between each vertical auto-scaling.
+	// Default to 3600
+	// +optional
+	IntervalSeconds *int32 `json:"intervalSeconds,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TiflashAutoScalerSpec describes the spec for tiflash auto-scaling.
+// The auto-scaler never scales in TiFlash below the max TiFlash replica count
+// of the tables in the cluster.
+type TiflashAutoScalerSpec struct {
+	BasicAutoScalerSpec `json:",inline"`
+}
+
+// +k8s:openapi-gen=true
+// TicdcAutoScalerSpec describes the spec for ticdc auto-scaling.
+// The captures are drained before the TiCDC pods are removed during scaling in.
+type TicdcAutoScalerSpec struct {
+	BasicAutoScalerSpec `json:",inline"`
 }
 
 // +k8s:openapi-gen=true
@@ -131,6 +216,81 @@ type BasicAutoScalerSpec struct {
 	// The key is resource_type name of the resource
 	// +optional
 	Resources map[string]AutoResource `json:"resources,omitempty"`
+
+	// Custom makes the auto-scaler controller able to calculate the recommended replicas
+	// for TiKV/TiDB with custom PromQL queries
+	// +optional
+	Custom *CustomConfig `json:"custom,omitempty"`
+
+	// Schedules defines the scheduled scaling policies. While a policy is active,
+	// the higher of its replicas and the replicas recommended by the metric rules wins.
+	// The replicas scaled by the schedules use the same resources as the target TidbCluster.
+	// +optional
+	Schedules []ScheduledScalingPolicy `json:"schedules,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// ScheduledScalingPolicy describes a time window in which the component is scaled to the target replicas
+type ScheduledScalingPolicy struct {
+	// Name is the name of this policy
+	Name string `json:"name"`
+	// Schedule is the cron expression of the beginning of the time window
+	Schedule string `json:"schedule"`
+	// Duration is the length of the time window, e.g. `2h30m`
+	Duration string `json:"duration"`
+	// TimeZone is the IANA time zone name of the schedule, e.g. `Asia/Shanghai`.
+	// Default to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// Replicas is the target replicas of the component in the time window,
+	// including the replicas of the target TidbCluster
+	Replicas int32 `json:"replicas"`
+	// MinReplicas is the lower bound of the replicas of the component in the time window
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the upper bound of the replicas of the component in the time window,
+	// which also limits the replicas recommended by the metric rules
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// CustomConfig represents the config of the auto-scaling with custom PromQL queries.
+type CustomConfig struct {
+	// Rules defines the custom metric rules, the max of the recommended replicas
+	// calculated by each rule would be taken
+	Rules []CustomAutoRule `json:"rules"`
+	// maxReplicas is the upper limit for the number of replicas to which the autoscaler can scale out.
+	MaxReplicas int32 `json:"maxReplicas"`
+}
+
+// CustomRuleAggregation is the way to aggregate the samples returned by the PromQL query
+type CustomRuleAggregation string
+
+const (
+	// CustomRuleAggregationSum means the samples are summed up and divided by the target value
+	// to get the recommended replicas
+	CustomRuleAggregationSum CustomRuleAggregation = "sum"
+	// CustomRuleAggregationAvg means the average of the samples is compared with the target value
+	CustomRuleAggregationAvg CustomRuleAggregation = "avg"
+	// CustomRuleAggregationMax means the max of the samples is compared with the target value
+	CustomRuleAggregationMax CustomRuleAggregation = "max"
+)
+
+// +k8s:openapi-gen=true
+// CustomAutoRule describes a rule for auto-scaling with a custom PromQL query
+type CustomAutoRule struct {
+	// Name is the name of this rule
+	Name string `json:"name"`
+	// Query is the PromQL query to fetch the metric, e.g. the QPS or the p99 latency.
+	// The query should return an instant vector, usually with one sample for each instance.
+	Query string `json:"query"`
+	// TargetValue is the target value of the metric for each instance
+	TargetValue float64 `json:"targetValue"`
+	// Aggregation is the way to aggregate the samples returned by the query,
+	// could be `sum`, `avg` or `max`. Default to `avg`.
+	// +optional
+	Aggregation CustomRuleAggregation `json:"aggregation,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -169,6 +329,39 @@ type TidbClusterAutoScalerStatus struct {
 	// Tidb describes the status of each group for the tidb in the last auto-scaling reconciliation
 	// +optional
 	TiDB map[string]TidbAutoScalerStatus `json:"tidb,omitempty"`
+	// TiFlash describes the status of each group for the tiflash in the last auto-scaling reconciliation
+	// +optional
+	TiFlash map[string]TiflashAutoScalerStatus `json:"tiflash,omitempty"`
+	// TiCDC describes the status of each group for the ticdc in the last auto-scaling reconciliation
+	// +optional
+	TiCDC map[string]TicdcAutoScalerStatus `json:"ticdc,omitempty"`
+	// Recommendations is the history of the recommendations computed in dry-run mode, the latest comes last
+	// +optional
+	Recommendations []AutoScalerRecommendation `json:"recommendations,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// AutoScalerRecommendation describes a recommendation computed by the auto-scaler
+type AutoScalerRecommendation struct {
+	// Component is the component to scale
+	Component MemberType `json:"component"`
+	// Group is the autoscaling group, `external` for the external autoscaling cluster
+	Group string `json:"group"`
+	// ResourceType is the resource type of the autoscaling cluster
+	// +optional
+	ResourceType string `json:"resourceType,omitempty"`
+	// CurrentReplicas is the replicas of the autoscaling cluster when the recommendation is computed
+	CurrentReplicas int32 `json:"currentReplicas"`
+	// TargetReplicas is the recommended replicas of the autoscaling cluster
+	TargetReplicas int32 `json:"targetReplicas"`
+	// Policy is the policy which decides the target replicas, `pd` for the PD plans,
+	// `metrics` for the external endpoint or custom rules, `schedule/<name>` for a scheduled policy
+	Policy string `json:"policy"`
+	// Metrics are the metric values which trigger the recommendation
+	// +optional
+	Metrics map[string]string `json:"metrics,omitempty"`
+	// Timestamp is the time when the recommendation is computed
+	Timestamp metav1.Time `json:"timestamp"`
 }
 
 // +k8s:openapi-gen=true
@@ -183,12 +376,31 @@ type TikvAutoScalerStatus struct {
 	BasicAutoScalerStatus `json:",inline"`
 }
 
+// +k8s:openapi-gen=true
+// TiflashAutoScalerStatus describe the auto-scaling status of tiflash
+type TiflashAutoScalerStatus struct {
+	BasicAutoScalerStatus `json:",inline"`
+}
+
+// +k8s:openapi-gen=true
+// TicdcAutoScalerStatus describe the auto-scaling status of ticdc
+type TicdcAutoScalerStatus struct {
+	BasicAutoScalerStatus `json:",inline"`
+}
+
 // +k8s:openapi-gen=true
 // BasicAutoScalerStatus describe the basic auto-scaling status
 type BasicAutoScalerStatus struct {
-	// LastAutoScalingTimestamp describes the last auto-scaling timestamp for the component(tidb/tikv)
+	// LastAutoScalingTimestamp describes the last auto-scaling timestamp for the component(tidb/tikv/tiflash/ticdc)
 	// +optional
 	LastAutoScalingTimestamp *metav1.Time `json:"lastAutoScalingTimestamp,omitempty"`
+	// LastScalingPolicy describes the policy which decided the replicas in the last reconciliation,
+	// `metrics` for the metric rules or `schedule/<name>` for a scheduled policy
+	// +optional
+	LastScalingPolicy string `json:"lastScalingPolicy,omitempty"`
+	// RecommendedResources describes the cpu and memory requests recommended by the vertical auto-scaling
+	// +optional
+	RecommendedResources corev1.ResourceList `json:"recommendedResources,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -20,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
 )
@@ -374,6 +375,43 @@ type TidbClusterSpec struct {
 
 	// PreferIPv6 indicates whether to prefer IPv6 addresses for all components.
 	PreferIPv6 bool `json:"preferIPv6,omitempty"`
+
+	// UpgradePolicy defines the pause points of the component upgrades, the upgrade stops at each pause point
+	// until it is approved by the annotation `tidb.pingcap.com/upgrade-approve`.
+	// +optional
+	UpgradePolicy *UpgradePolicy `json:"upgradePolicy,omitempty"`
+}
+
+// UpgradePolicy is the policy of the component upgrades
+// +k8s:openapi-gen=true
+type UpgradePolicy struct {
+	// PausePoints are the points where the upgrade stops and waits for the manual approval.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	PausePoints []UpgradePausePoint `json:"pausePoints,omitempty"`
+}
+
+// UpgradePausePoint is a point of the component upgrade where the upgrade stops until it is approved.
+// The approval is given by adding the name of the pause point to the comma separated list in the annotation
+// `tidb.pingcap.com/upgrade-approve` of the TidbCluster, the name is removed from the annotation once the
+// approval is consumed, and the approval only takes effect for the current upgrade of the component.
+// +k8s:openapi-gen=true
+type UpgradePausePoint struct {
+	// Name is the unique name of the pause point which is used in the approval annotation.
+	Name string `json:"name"`
+
+	// Component is the component whose upgrade stops at this pause point,
+	// it is one of pd, tikv, tidb, tiflash, ticdc and tiproxy.
+	Component MemberType `json:"component"`
+
+	// AfterPods is the number of the upgraded pods of the component when the upgrade stops.
+	// 0 means the upgrade stops before any pod of the component is upgraded.
+	// It does not take effect if it is not less than the replicas of the component.
+	// Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	AfterPods int32 `json:"afterPods,omitempty"`
 }
 
 // TidbClusterStatus represents the current status of a tidb cluster.
@@ -391,6 +429,48 @@ type TidbClusterStatus struct {
 	// +optional
 	// +nullable
 	Conditions []TidbClusterCondition `json:"conditions,omitempty"`
+	// OperationHistory records the latest significant operations performed by the operator,
+	// such as leader eviction, store deletion and failover, the oldest one comes first.
+	// At most MaxOperationHistory records are kept.
+	// +optional
+	// +nullable
+	OperationHistory []OperationRecord `json:"operationHistory,omitempty"`
+
+	// UpgradePausePoints records the approved pause points of the component upgrades.
+	// +optional
+	// +nullable
+	UpgradePausePoints []UpgradePausePointStatus `json:"upgradePausePoints,omitempty"`
+}
+
+// UpgradePausePointStatus is the approval of a pause point of the component upgrade
+type UpgradePausePointStatus struct {
+	// Name is the name of the pause point.
+	Name string `json:"name"`
+	// TemplateHash is the hash of the pod template the component is upgraded to when the pause point is approved,
+	// the approval is invalid for the later upgrades.
+	TemplateHash string `json:"templateHash"`
+	// ApprovedTime is the time when the pause point is approved.
+	ApprovedTime metav1.Time `json:"approvedTime"`
+}
+
+// MaxOperationHistory is the max number of records kept in `status.operationHistory`
+const MaxOperationHistory = 50
+
+// OperationRecord is a significant operation performed by the operator on a tidb cluster,
+// the same information is also emitted as an event of the TidbCluster.
+type OperationRecord struct {
+	// Time is when the operation is performed.
+	Time metav1.Time `json:"time"`
+	// Component is the component which the operation is performed on.
+	// +optional
+	Component MemberType `json:"component,omitempty"`
+	// Type is the type of the event, Normal or Warning.
+	Type string `json:"type"`
+	// Reason is the reason of the event, which identifies the operation.
+	Reason string `json:"reason"`
+	// Message is a human readable description of the operation.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // TidbClusterCondition describes the state of a tidb cluster at a certain point.
@@ -426,12 +506,21 @@ const (
 	// - All TiKV stores are up.
 	// - All TiFlash stores are up.
 	TidbClusterReady TidbClusterConditionType = "Ready"
+	// TiDBCanaryUpgradeCondition indicates the decision of the TiDB canary upgrade.
+	// It is Unknown when the canaries are upgrading or baking, True when the health gates pass
+	// and False when the canaries are rolled back.
+	TiDBCanaryUpgradeCondition TidbClusterConditionType = "TiDBCanaryUpgrade"
 )
 
 // The `Type` of the component condition
 const (
 	// ComponentVolumeResizing indicates that any volume of this component is resizing.
 	ComponentVolumeResizing string = "ComponentVolumeResizing"
+	// ComponentUpgradePaused indicates that the upgrade of this component stops at a pause point
+	// and waits for the approval.
+	ComponentUpgradePaused string = "ComponentUpgradePaused"
+	// ComponentUpgradeHookFailed indicates that the pre-upgrade or post-upgrade hook Job of this component fails.
+	ComponentUpgradeHookFailed string = "ComponentUpgradeHookFailed"
 )
 
 // +k8s:openapi-gen=true
@@ -629,6 +718,14 @@ type TiKVSpec struct {
 	// ScalePolicy is the scale configuration for TiKV
 	// +optional
 	ScalePolicy ScalePolicy `json:"scalePolicy,omitempty"`
+
+	// Whether upgrade TiKV by zone, the stores in a zone are upgraded at the same time and the zones are
+	// upgraded one by one. The zone of a store is the value of the top-level location label of PD.
+	// Leaders are evicted from all stores in the zone before their pods are recreated, the statefulset
+	// uses the OnDelete update strategy during the upgrade.
+	// Optional: Defaults to false
+	// +optional
+	ZoneAwareUpgrade *bool `json:"zoneAwareUpgrade,omitempty"`
 }
 
 // TiFlashSpec contains details of TiFlash members
@@ -734,6 +831,15 @@ type TiCDCSpec struct {
 	// Defaults to 10m
 	// +optional
 	GracefulShutdownTimeout *metav1.Duration `json:"gracefulShutdownTimeout,omitempty"`
+
+	// MaxUnavailable is the max number or percentage of TiCDC pods upgraded at the same time during
+	// the rolling upgrade, the percentage is rounded down. The next batch is upgraded after all pods
+	// of the current batch are healthy.
+	// If it's greater than 1, the StatefulSet uses the OnDelete update strategy during the upgrade
+	// and the pods of a batch are deleted to be replaced at the same time.
+	// Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // TiCDCConfig is the configuration of tidbcdc
@@ -798,6 +904,15 @@ type TiProxySpec struct {
 	// Defaults to Kubernetes default storage class.
 	// +optional
 	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// MaxUnavailable is the max number or percentage of TiProxy pods upgraded at the same time during
+	// the rolling upgrade, the percentage is rounded down. The next batch is upgraded after all pods
+	// of the current batch are healthy.
+	// If it's greater than 1, the StatefulSet uses the OnDelete update strategy during the upgrade
+	// and the pods of a batch are deleted to be replaced at the same time.
+	// Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // LogTailerSpec represents an optional log tailer sidecar container
@@ -921,6 +1036,64 @@ type TiDBSpec struct {
 	// Only v6.6.0+ supports this feature.
 	// +optional
 	BootstrapSQLConfigMapName *string `json:"bootstrapSQLConfigMapName,omitempty"`
+
+	// CanaryUpgrade upgrades a part of TiDB pods first and upgrades the rest after the canaries stay healthy
+	// for the bake time, the canaries are rolled back to the previous template if any health gate fails.
+	// +optional
+	CanaryUpgrade *TiDBCanaryUpgrade `json:"canaryUpgrade,omitempty"`
+
+	// MaxUnavailable is the max number or percentage of TiDB pods upgraded at the same time during
+	// the rolling upgrade, the percentage is rounded down. The next batch is upgraded after all pods
+	// of the current batch are healthy.
+	// If it's greater than 1, the StatefulSet uses the OnDelete update strategy during the upgrade
+	// and the pods of a batch are deleted to be replaced at the same time.
+	// Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// TiDBCanaryUpgrade is the canary strategy of the TiDB upgrade
+// +k8s:openapi-gen=true
+type TiDBCanaryUpgrade struct {
+	// Replicas is the number or the percentage of TiDB pods upgraded as canaries, the percentage is rounded up.
+	// Defaults to 1.
+	// +optional
+	Replicas *intstr.IntOrString `json:"replicas,omitempty"`
+
+	// BakeTime is the time to observe the canaries after they are healthy before upgrading the rest pods.
+	// Defaults to 10m.
+	// +optional
+	BakeTime *metav1.Duration `json:"bakeTime,omitempty"`
+
+	// ProgressDeadline is the max time for the canaries to become healthy after the canary upgrade starts,
+	// the canaries are rolled back if any of them is not healthy in time, e.g. the image fails to be pulled.
+	// Defaults to 10m.
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+
+	// MaxRestarts is the max number of container restarts of each canary during the canary upgrade.
+	// Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRestarts *int32 `json:"maxRestarts,omitempty"`
+
+	// ErrorRate is the optional health gate evaluated by a PromQL query at the end of the bake time.
+	// +optional
+	ErrorRate *TiDBCanaryErrorRate `json:"errorRate,omitempty"`
+}
+
+// TiDBCanaryErrorRate is the health gate of the TiDB canary upgrade evaluated by a PromQL query
+// +k8s:openapi-gen=true
+type TiDBCanaryErrorRate struct {
+	// PrometheusAddress is the address of Prometheus, e.g. http://basic-prometheus.tidb-cluster:9090
+	PrometheusAddress string `json:"prometheusAddress"`
+
+	// Query is the instant PromQL query of the error rate. `$CANARY_PODS` in the query is replaced by
+	// the regular expression matching the names of the canaries, e.g. `instance=~"$CANARY_PODS"`.
+	Query string `json:"query"`
+
+	// Threshold is the max error rate, the gate fails if any sample of the query exceeds it.
+	Threshold float64 `json:"threshold"`
 }
 
 type TiDBInitializer struct {
@@ -1179,6 +1352,43 @@ type ComponentSpec struct {
 	// the default behavior is like setting type as "tcp"
 	// +optional
 	ReadinessProbe *Probe `json:"readinessProbe,omitempty"`
+
+	// UpgradeHooks defines the Jobs run before and after the upgrade of the component.
+	// It only takes effect for PD, TiKV, TiDB, TiFlash, TiCDC and TiProxy.
+	// +optional
+	UpgradeHooks *UpgradeHooks `json:"upgradeHooks,omitempty"`
+}
+
+// UpgradeHooks defines the Jobs run before and after the upgrade of a component.
+// The env vars of the cluster connection info are added to all containers of the Jobs:
+//   - CLUSTER_NAME: the name of the TidbCluster
+//   - NAMESPACE: the namespace of the TidbCluster
+//   - COMPONENT: the component which is upgraded
+//   - UPGRADE_HOOK: pre-upgrade or post-upgrade
+//   - PD_ADDR: the address of the PD service
+//   - TIDB_HOST and TIDB_PORT: the host and the port of the TiDB service if TiDB is deployed
+//   - TC_TLS_ENABLED: whether TLS is enabled between the components of the TidbCluster
+//
+// +k8s:openapi-gen=true
+type UpgradeHooks struct {
+	// PreUpgrade is run before any pod of the component is upgraded, the upgrade starts after the Job completes.
+	// +optional
+	PreUpgrade *UpgradeHook `json:"preUpgrade,omitempty"`
+
+	// PostUpgrade is run after all pods of the component are upgraded, the component stays in the upgrade
+	// phase until the Job completes, so that the components upgraded after it wait for the Job.
+	// +optional
+	PostUpgrade *UpgradeHook `json:"postUpgrade,omitempty"`
+}
+
+// UpgradeHook is a Job run at a stage of the component upgrade.
+// If the Job fails, the upgrade stops and the `ComponentUpgradeHookFailed` condition of the component is set,
+// delete the Job to retry it.
+// +k8s:openapi-gen=true
+type UpgradeHook struct {
+	// JobTemplate refers to the key of a ConfigMap in the namespace of the TidbCluster, the value is the manifest
+	// of the Job in YAML or JSON. The name and the namespace of the Job are set by the operator.
+	JobTemplate corev1.ConfigMapKeySelector `json:"jobTemplate"`
 }
 
 // ServiceSpec specifies the service object in k8s
@@ -1349,6 +1559,48 @@ type TiDBStatus struct {
 	// +optional
 	// +nullable
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// CanaryUpgrade is the status of the canary upgrade
+	// +optional
+	CanaryUpgrade *TiDBCanaryUpgradeStatus `json:"canaryUpgrade,omitempty"`
+}
+
+// TiDBCanaryUpgradePhase is the phase of the TiDB canary upgrade
+type TiDBCanaryUpgradePhase string
+
+const (
+	// TiDBCanaryUpgrading means the canaries are being upgraded
+	TiDBCanaryUpgrading TiDBCanaryUpgradePhase = "Upgrading"
+	// TiDBCanaryBaking means all canaries are healthy and they are being observed for the bake time
+	TiDBCanaryBaking TiDBCanaryUpgradePhase = "Baking"
+	// TiDBCanaryPassed means all health gates pass and the rest pods are being upgraded
+	TiDBCanaryPassed TiDBCanaryUpgradePhase = "Passed"
+	// TiDBCanaryRolledBack means a health gate failed and the canaries are rolled back to the previous template,
+	// the upgrade does not start again until the spec of TiDB is changed.
+	TiDBCanaryRolledBack TiDBCanaryUpgradePhase = "RolledBack"
+)
+
+// TiDBCanaryUpgradeStatus is the status of the TiDB canary upgrade
+type TiDBCanaryUpgradeStatus struct {
+	// Phase of the canary upgrade
+	Phase TiDBCanaryUpgradePhase `json:"phase"`
+	// Revision is the update revision of the TiDB StatefulSet being upgraded
+	Revision string `json:"revision,omitempty"`
+	// TemplateHash is the hash of the pod template being upgraded to
+	TemplateHash string `json:"templateHash,omitempty"`
+	// Canaries are the names of the canary pods
+	// +optional
+	Canaries []string `json:"canaries,omitempty"`
+	// StartTime is the time at which the canary upgrade started
+	// +optional
+	// +nullable
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// BakeStartTime is the time at which all canaries became healthy
+	// +optional
+	// +nullable
+	BakeStartTime *metav1.Time `json:"bakeStartTime,omitempty"`
+	// Message is the reason of the rollback
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // TiDBMember is TiDB member
@@ -1979,6 +2231,93 @@ type BackupSpec struct {
 
 	// BackoffRetryPolicy the backoff retry policy, currently only valid for snapshot backup
 	BackoffRetryPolicy BackoffRetryPolicy `json:"backoffRetryPolicy,omitempty"`
+
+	// Verify means to verify the integrity of the backup files in the storage after the backup is complete,
+	// the result is recorded as the Verified or Corrupted condition. Currently only valid for BR snapshot backup.
+	// +optional
+	Verify bool `json:"verify,omitempty"`
+
+	// SecondaryStorages are the storages which the backup is copied to after it is complete,
+	// e.g. the buckets in other regions. The copies are retried according to BackoffRetryPolicy,
+	// and cleaned with the backup data according to CleanPolicy.
+	// Currently only valid for BR snapshot backup.
+	// +optional
+	SecondaryStorages []SecondaryStorage `json:"secondaryStorages,omitempty"`
+
+	// Encryption configures the client-side encryption of the backup data by BR,
+	// the Restore of the backup must use the same encryption.
+	// Currently only valid for BR snapshot backup.
+	// +optional
+	Encryption *BackupEncryption `json:"encryption,omitempty"`
+}
+
+// BackupEncryptionMethod is the crypter method used by BR to encrypt the backup data
+// +k8s:openapi-gen=true
+type BackupEncryptionMethod string
+
+const (
+	// BackupEncryptionMethodAES128CTR means encrypting with AES-128 in CTR mode
+	BackupEncryptionMethodAES128CTR BackupEncryptionMethod = "aes128-ctr"
+	// BackupEncryptionMethodAES192CTR means encrypting with AES-192 in CTR mode
+	BackupEncryptionMethodAES192CTR BackupEncryptionMethod = "aes192-ctr"
+	// BackupEncryptionMethodAES256CTR means encrypting with AES-256 in CTR mode
+	BackupEncryptionMethodAES256CTR BackupEncryptionMethod = "aes256-ctr"
+)
+
+// DefaultBackupEncryptionSecretKey is the default key of the encryption key in the secret
+const DefaultBackupEncryptionSecretKey = "encryption-key"
+
+// BackupEncryption is the client-side encryption configuration of BR
+// +k8s:openapi-gen=true
+type BackupEncryption struct {
+	// Method is the crypter method, such as aes128-ctr, aes192-ctr and aes256-ctr.
+	// +kubebuilder:validation:Enum:="aes128-ctr";"aes192-ctr";"aes256-ctr"
+	Method BackupEncryptionMethod `json:"method"`
+	// SecretName is the name of the secret in the same namespace which contains the hex encoded key,
+	// the key length must match the method, e.g. 32 bytes for aes256-ctr.
+	SecretName string `json:"secretName"`
+	// SecretKey is the key of the encryption key in the secret, defaults to "encryption-key".
+	// +optional
+	SecretKey string `json:"secretKey,omitempty"`
+}
+
+// SecondaryStorage is a storage which the backup is copied to
+type SecondaryStorage struct {
+	// Name is the unique name of the secondary storage in the backup
+	Name string `json:"name"`
+	// StorageProvider is the storage which the backup is copied to.
+	// The credentials of the storage are read from the secret referenced by the provider,
+	// or from the environment of the backup job if the secret is not set.
+	StorageProvider `json:",inline"`
+}
+
+// SecondaryStoragePhase is the phase of copying the backup to a secondary storage
+type SecondaryStoragePhase string
+
+const (
+	// SecondaryStorageCopying means the backup is being copied to the secondary storage
+	SecondaryStorageCopying SecondaryStoragePhase = "Copying"
+	// SecondaryStorageComplete means the backup has been copied to the secondary storage
+	SecondaryStorageComplete SecondaryStoragePhase = "Complete"
+	// SecondaryStorageFailed means the backup failed to be copied to the secondary storage after retries
+	SecondaryStorageFailed SecondaryStoragePhase = "Failed"
+)
+
+// SecondaryStorageStatus is the status of copying the backup to a secondary storage
+type SecondaryStorageStatus struct {
+	// Name is the name of the secondary storage
+	Name string `json:"name"`
+	// Phase is the phase of copying the backup to the secondary storage
+	Phase SecondaryStoragePhase `json:"phase,omitempty"`
+	// BackupPath is the location of the backup copy
+	BackupPath string `json:"backupPath,omitempty"`
+	// RetryNum is the number of retries to copy the backup
+	RetryNum int `json:"retryNum,omitempty"`
+	// TimeCompleted is the time at which the backup was copied
+	// +nullable
+	TimeCompleted *metav1.Time `json:"timeCompleted,omitempty"`
+	// Message is the error message of the last failed copy
+	Message string `json:"message,omitempty"`
 }
 
 // FederalVolumeBackupPhase represents a phase to execute in federal volume backup
@@ -2000,6 +2339,140 @@ type DumplingConfig struct {
 	Options []string `json:"options,omitempty"`
 	// Deprecated. Please use `Spec.TableFilter` instead. TableFilter means Table filter expression for 'db.table' matching
 	TableFilter []string `json:"tableFilter,omitempty"`
+	// FileType is the file type of the exported data, sql or csv. Defaults to sql.
+	// +kubebuilder:validation:Enum:="";"sql";"csv"
+	// +optional
+	FileType DumplingFileType `json:"fileType,omitempty"`
+	// Compress is the compression algorithm of the exported files, such as gzip, snappy and zstd.
+	// The files are not compressed if it is not set.
+	// +kubebuilder:validation:Enum:="";"gzip";"snappy";"zstd"
+	// +optional
+	Compress DumplingCompressType `json:"compress,omitempty"`
+	// Rows is the number of rows in each exported file, which enables the concurrent export within a table.
+	// +optional
+	Rows *uint64 `json:"rows,omitempty"`
+	// Snapshot is the snapshot to export, it is only valid when Consistency is snapshot.
+	// Format supports TSO or datetime, e.g. '400036290571534337', '2018-05-11 01:42:23'.
+	// Defaults to the current timestamp.
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+	// Consistency is the consistency mode of the export, such as auto, none, flush, lock and snapshot.
+	// Defaults to auto.
+	// +kubebuilder:validation:Enum:="";"auto";"none";"flush";"lock";"snapshot"
+	// +optional
+	Consistency DumplingConsistency `json:"consistency,omitempty"`
+}
+
+// DumplingFileType is the file type of the data exported by dumpling
+type DumplingFileType string
+
+const (
+	// DumplingFileTypeSQL means exporting the data as SQL statements
+	DumplingFileTypeSQL DumplingFileType = "sql"
+	// DumplingFileTypeCSV means exporting the data as CSV files
+	DumplingFileTypeCSV DumplingFileType = "csv"
+)
+
+// DumplingCompressType is the compression algorithm of the files exported by dumpling
+type DumplingCompressType string
+
+const (
+	// DumplingCompressTypeGzip means compressing the files with gzip
+	DumplingCompressTypeGzip DumplingCompressType = "gzip"
+	// DumplingCompressTypeSnappy means compressing the files with snappy
+	DumplingCompressTypeSnappy DumplingCompressType = "snappy"
+	// DumplingCompressTypeZstd means compressing the files with zstd
+	DumplingCompressTypeZstd DumplingCompressType = "zstd"
+)
+
+// DumplingConsistency is the consistency mode of dumpling
+type DumplingConsistency string
+
+const (
+	// DumplingConsistencyAuto means using flush for MySQL and snapshot for TiDB
+	DumplingConsistencyAuto DumplingConsistency = "auto"
+	// DumplingConsistencyNone means not guaranteeing the consistency
+	DumplingConsistencyNone DumplingConsistency = "none"
+	// DumplingConsistencyFlush means using FLUSH TABLES WITH READ LOCK
+	DumplingConsistencyFlush DumplingConsistency = "flush"
+	// DumplingConsistencyLock means adding read locks to the tables to export
+	DumplingConsistencyLock DumplingConsistency = "lock"
+	// DumplingConsistencySnapshot means exporting the snapshot of a TSO
+	DumplingConsistencySnapshot DumplingConsistency = "snapshot"
+)
+
+// +k8s:openapi-gen=true
+// LightningConfig contains config for lightning
+type LightningConfig struct {
+	// Backend is the backend of lightning, tidb or local. Defaults to tidb.
+	// The tidb backend imports the data by SQL statements, and the local backend imports the
+	// sorted key-value pairs into TiKV directly, which requires more disk space in the restore job.
+	// +kubebuilder:validation:Enum:="";"tidb";"local"
+	// +optional
+	Backend LightningBackend `json:"backend,omitempty"`
+	// DuplicateResolution is how to resolve the duplicated rows.
+	// For the tidb backend, it can be replace, ignore or error, and defaults to replace.
+	// For the local backend, it can be none or remove, and defaults to none.
+	// +optional
+	DuplicateResolution LightningDuplicateResolution `json:"duplicateResolution,omitempty"`
+	// Checkpoint configures the checkpoint storage of lightning, which allows the import to be
+	// resumed from the checkpoint after the restore job fails. The checkpoint is disabled if it is not set.
+	// +optional
+	Checkpoint *LightningCheckpoint `json:"checkpoint,omitempty"`
+	// PDAddress is the address of PD used by the local backend, e.g. 'basic-pd:2379'.
+	// If it is not set, lightning gets it from the status port of TiDB.
+	// +optional
+	PDAddress string `json:"pdAddress,omitempty"`
+}
+
+// LightningBackend is the backend of lightning
+type LightningBackend string
+
+const (
+	// LightningBackendTiDB means importing the data by SQL statements
+	LightningBackendTiDB LightningBackend = "tidb"
+	// LightningBackendLocal means importing the sorted key-value pairs into TiKV
+	LightningBackendLocal LightningBackend = "local"
+)
+
+// LightningDuplicateResolution is how lightning resolves the duplicated rows
+type LightningDuplicateResolution string
+
+const (
+	// LightningDuplicateReplace means replacing the existing rows with the new rows, only for the tidb backend
+	LightningDuplicateReplace LightningDuplicateResolution = "replace"
+	// LightningDuplicateIgnore means keeping the existing rows and ignoring the new rows, only for the tidb backend
+	LightningDuplicateIgnore LightningDuplicateResolution = "ignore"
+	// LightningDuplicateError means reporting an error when inserting the duplicated rows, only for the tidb backend
+	LightningDuplicateError LightningDuplicateResolution = "error"
+	// LightningDuplicateNone means not detecting the duplicated rows, only for the local backend
+	LightningDuplicateNone LightningDuplicateResolution = "none"
+	// LightningDuplicateRemove means removing all the duplicated rows, only for the local backend
+	LightningDuplicateRemove LightningDuplicateResolution = "remove"
+)
+
+// LightningCheckpointDriver is the storage driver of the lightning checkpoint
+type LightningCheckpointDriver string
+
+const (
+	// LightningCheckpointDriverFile means storing the checkpoint in a local file
+	LightningCheckpointDriverFile LightningCheckpointDriver = "file"
+	// LightningCheckpointDriverMySQL means storing the checkpoint in the target TiDB cluster
+	LightningCheckpointDriverMySQL LightningCheckpointDriver = "mysql"
+)
+
+// +k8s:openapi-gen=true
+// LightningCheckpoint is the checkpoint storage of lightning
+type LightningCheckpoint struct {
+	// Driver is the storage driver of the checkpoint, file or mysql. Defaults to file.
+	// The file is stored in the volume of the restore job, so it can be reused when the job is retried.
+	// +kubebuilder:validation:Enum:="";"file";"mysql"
+	// +optional
+	Driver LightningCheckpointDriver `json:"driver,omitempty"`
+	// Schema is the database storing the checkpoint for the mysql driver.
+	// Defaults to tidb_lightning_checkpoint.
+	// +optional
+	Schema string `json:"schema,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -2021,6 +2494,10 @@ type BRConfig struct {
 	Concurrency *uint32 `json:"concurrency,omitempty"`
 	// RateLimit is the rate limit of the backup task, MB/s per node
 	RateLimit *uint `json:"rateLimit,omitempty"`
+	// RateLimitWindows are the rate limits in the daily time windows. The rate limit of the first window
+	// containing the start time of the task is used, and RateLimit is used if no window contains it.
+	// +optional
+	RateLimitWindows []BRRateLimitWindow `json:"rateLimitWindows,omitempty"`
 	// TimeAgo is the history version of the backup task, e.g. 1m, 1h
 	TimeAgo string `json:"timeAgo,omitempty"`
 	// Checksum specifies whether to run checksum after backup
@@ -2035,6 +2512,26 @@ type BRConfig struct {
 	Options []string `json:"options,omitempty"`
 }
 
+// TimeWindow is a daily time window in UTC, such as 01:00-06:00.
+// The window crosses midnight if End is not after Start.
+// +k8s:openapi-gen=true
+type TimeWindow struct {
+	// Start is the start time of the window in the format of HH:MM, inclusive
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// End is the end time of the window in the format of HH:MM, exclusive
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+}
+
+// BRRateLimitWindow is the rate limit of BR in a daily time window
+// +k8s:openapi-gen=true
+type BRRateLimitWindow struct {
+	TimeWindow `json:",inline"`
+	// RateLimit is the rate limit of the task in the window, MB/s per node
+	RateLimit uint `json:"rateLimit"`
+}
+
 // BackoffRetryPolicy is the backoff retry policy, currently only valid for snapshot backup.
 // When backup job or pod failed, it will retry in the following way:
 // first time: retry after MinRetryDuration
@@ -2107,6 +2604,12 @@ const (
 	VolumeBackupComplete BackupConditionType = "VolumeBackupComplete"
 	// VolumeBackupFailed means the volume backup take volume snapshots failed
 	VolumeBackupFailed BackupConditionType = "VolumeBackupFailed"
+	// BackupVerified means all the backup files referenced by the backup meta are verified,
+	// it doesn't change the phase of the backup
+	BackupVerified BackupConditionType = "Verified"
+	// BackupCorrupted means some backup files referenced by the backup meta are missing or corrupted,
+	// it doesn't change the phase of the backup
+	BackupCorrupted BackupConditionType = "Corrupted"
 )
 
 // BackupCondition describes the observed state of a Backup at a certain point.
@@ -2186,6 +2689,9 @@ type BackupStatus struct {
 	Progresses []Progress `json:"progresses,omitempty"`
 	// BackoffRetryStatus is status of the backoff retry, it will be used when backup pod or job exited unexpectedly
 	BackoffRetryStatus []BackoffRetryRecord `json:"backoffRetryStatus,omitempty"`
+	// SecondaryStorageStatuses are the statuses of copying the backup to the secondary storages
+	// +nullable
+	SecondaryStorageStatuses []SecondaryStorageStatus `json:"secondaryStorageStatuses,omitempty"`
 }
 
 // +genclient
@@ -2234,12 +2740,33 @@ type BackupScheduleSpec struct {
 	// and MaxBackups is ignored.
 	MaxBackups *int32 `json:"maxBackups,omitempty"`
 	// MaxReservedTime is to specify how long backups we want to keep.
+	// If RetentionPolicy is set, MaxReservedTime is only used as the PiTR window
+	// of the log backup, and the snapshot backups in the window are always kept.
 	MaxReservedTime *string `json:"maxReservedTime,omitempty"`
+	// RetentionPolicy is the tiered retention policy of the snapshot backups.
+	// If RetentionPolicy is set, it is preferred and MaxBackups is ignored.
+	// +optional
+	RetentionPolicy *BackupRetentionPolicy `json:"retentionPolicy,omitempty"`
 	// BackupTemplate is the specification of the backup structure to get scheduled.
 	// +optional
 	BackupTemplate BackupSpec `json:"backupTemplate"`
 	// LogBackupTemplate is the specification of the log backup structure to get scheduled.
 	LogBackupTemplate *BackupSpec `json:"logBackupTemplate"`
+	// ExecutionWindows are the daily time windows in which the scheduled snapshot backups are allowed to start.
+	// If it is empty, the backups can start at any time.
+	// +optional
+	ExecutionWindows []TimeWindow `json:"executionWindows,omitempty"`
+	// OutOfWindowPolicy is the policy of the scheduled snapshot backups out of ExecutionWindows.
+	// Skip means the backup is skipped, and Defer means the backup is created when the next window opens.
+	// +kubebuilder:validation:Enum=Skip;Defer
+	// +kubebuilder:default=Skip
+	// +optional
+	OutOfWindowPolicy OutOfWindowPolicy `json:"outOfWindowPolicy,omitempty"`
+	// RecoveryPointObjective is the max tolerable duration between now and the latest restorable time,
+	// in the format of Go Duration, such as 1h. If the latest restorable time falls behind it, the
+	// RPOViolated condition of the backup schedule is set to true.
+	// +optional
+	RecoveryPointObjective *string `json:"recoveryPointObjective,omitempty"`
 	// The storageClassName of the persistent volume for Backup data storage if not storage class name set in BackupSpec.
 	// Defaults to Kubernetes default storage class.
 	// +optional
@@ -2251,6 +2778,39 @@ type BackupScheduleSpec struct {
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 }
 
+// OutOfWindowPolicy is the policy of the scheduled backups out of the execution windows
+type OutOfWindowPolicy string
+
+const (
+	// OutOfWindowPolicySkip means the scheduled backup out of the execution windows is skipped
+	OutOfWindowPolicySkip OutOfWindowPolicy = "Skip"
+	// OutOfWindowPolicyDefer means the scheduled backup out of the execution windows is created when the next window opens
+	OutOfWindowPolicyDefer OutOfWindowPolicy = "Defer"
+)
+
+// BackupRetentionPolicy is the grandfather-father-son retention policy of the snapshot backups.
+// The latest backup of each of the last N hours, days, weeks and months that have backups is kept,
+// and a backup is kept if it is selected by any of the tiers. The latest backup is always kept.
+// +k8s:openapi-gen=true
+type BackupRetentionPolicy struct {
+	// Hourly is the number of the hourly backups to keep
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Hourly int32 `json:"hourly,omitempty"`
+	// Daily is the number of the daily backups to keep
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Daily int32 `json:"daily,omitempty"`
+	// Weekly is the number of the weekly backups to keep, the weeks are ISO 8601 weeks
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Weekly int32 `json:"weekly,omitempty"`
+	// Monthly is the number of the monthly backups to keep
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Monthly int32 `json:"monthly,omitempty"`
+}
+
 // BackupScheduleStatus represents the current state of a BackupSchedule.
 type BackupScheduleStatus struct {
 	// LastBackup represents the last backup.
@@ -2261,6 +2821,68 @@ type BackupScheduleStatus struct {
 	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
 	// AllBackupCleanTime represents the time when all backup entries are cleaned up
 	AllBackupCleanTime *metav1.Time `json:"allBackupCleanTime,omitempty"`
+	// LastSkippedBackupTime represents the scheduled time of the last backup skipped out of the execution windows.
+	// +optional
+	LastSkippedBackupTime *metav1.Time `json:"lastSkippedBackupTime,omitempty"`
+	// SkippedBackups represents the number of the backups skipped out of the execution windows.
+	// +optional
+	SkippedBackups int32 `json:"skippedBackups,omitempty"`
+	// DeferredBackupTime represents the scheduled time of the backup deferred to the next execution window.
+	// +optional
+	DeferredBackupTime *metav1.Time `json:"deferredBackupTime,omitempty"`
+	// RecoverableWindow represents the range of time that can be restored by the backups of the schedule.
+	// +optional
+	RecoverableWindow *BackupRecoverableWindow `json:"recoverableWindow,omitempty"`
+	// Conditions represents the conditions of the backup schedule.
+	// +optional
+	Conditions []BackupScheduleCondition `json:"conditions,omitempty"`
+}
+
+// BackupRecoverableWindow represents the range of time that can be restored by the complete snapshot
+// backups and the log backup of a backup schedule.
+// +k8s:openapi-gen=true
+type BackupRecoverableWindow struct {
+	// EarliestTs is the earliest restorable TSO.
+	EarliestTs string `json:"earliestTs,omitempty"`
+	// EarliestTime is the earliest restorable time.
+	// +nullable
+	EarliestTime *metav1.Time `json:"earliestTime,omitempty"`
+	// LatestTs is the latest restorable TSO.
+	LatestTs string `json:"latestTs,omitempty"`
+	// LatestTime is the latest restorable time.
+	// +nullable
+	LatestTime *metav1.Time `json:"latestTime,omitempty"`
+	// Gaps are the ranges between EarliestTs and LatestTs that can't be restored, such as the time
+	// between two snapshot backups which are not covered by the log backup.
+	// +optional
+	Gaps []BackupCoverageGap `json:"gaps,omitempty"`
+}
+
+// BackupCoverageGap represents a range of time that can't be restored, the start and the end are restorable.
+// +k8s:openapi-gen=true
+type BackupCoverageGap struct {
+	// StartTs is the restorable TSO before the gap.
+	StartTs string `json:"startTs"`
+	// EndTs is the restorable TSO after the gap.
+	EndTs string `json:"endTs"`
+}
+
+// BackupScheduleConditionType represents a valid condition of a BackupSchedule.
+type BackupScheduleConditionType string
+
+const (
+	// BackupScheduleRPOViolated means the latest restorable time falls behind the recovery point objective.
+	BackupScheduleRPOViolated BackupScheduleConditionType = "RPOViolated"
+)
+
+// BackupScheduleCondition describes the observed state of a BackupSchedule at a certain point.
+type BackupScheduleCondition struct {
+	Type   BackupScheduleConditionType `json:"type"`
+	Status corev1.ConditionStatus      `json:"status"`
+	// +nullable
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	Reason             string      `json:"reason,omitempty"`
+	Message            string      `json:"message,omitempty"`
 }
 
 // +genclient
@@ -2397,6 +3019,16 @@ type RestoreSpec struct {
 	StorageProvider `json:",inline"`
 	// PitrFullBackupStorageProvider configures where and how pitr dependent full backup should be stored.
 	PitrFullBackupStorageProvider StorageProvider `json:"pitrFullBackupStorageProvider,omitempty"`
+	// PitrBackupScheduleRef references the BackupSchedule in the same namespace whose backups are used by pitr.
+	// If it is set and PitrFullBackupStorageProvider is not configured, the full backup and the log backup
+	// that cover PitrRestoredTs are resolved from the BackupSchedule, and the storage providers are filled.
+	// +optional
+	PitrBackupScheduleRef *corev1.LocalObjectReference `json:"pitrBackupScheduleRef,omitempty"`
+	// Encryption configures the client-side encryption used by BR to decrypt the backup data,
+	// it must be the same as the encryption of the backup.
+	// If it is not set, it is filled from the Backup in the same namespace which is stored in the same location.
+	// +optional
+	Encryption *BackupEncryption `json:"encryption,omitempty"`
 	// The storageClassName of the persistent volume for Restore data storage.
 	// Defaults to Kubernetes default storage class.
 	// +optional
@@ -2405,6 +3037,12 @@ type RestoreSpec struct {
 	StorageSize string `json:"storageSize,omitempty"`
 	// BR is the configs for BR.
 	BR *BRConfig `json:"br,omitempty"`
+	// ClusterTemplate is the template of the TidbCluster to restore into, only valid for BR snapshot and PiTR restore.
+	// If it is set, the TidbCluster specified by `br.cluster` is created from the template and the restore
+	// starts after PD, TiKV and TiDB are ready. The restore is invalid if the TidbCluster already exists
+	// and is not created from the template of this restore.
+	// +optional
+	ClusterTemplate *RestoreClusterTemplate `json:"clusterTemplate,omitempty"`
 	// Base tolerations of restore Pods, components may add more tolerations upon this respectively
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
@@ -2425,6 +3063,9 @@ type RestoreSpec struct {
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 	// TableFilter means Table filter expression for 'db.table' matching. BR supports this from v4.0.3.
 	TableFilter []string `json:"tableFilter,omitempty"`
+	// Lightning is the configs for lightning, only used when BR is not set.
+	// +optional
+	Lightning *LightningConfig `json:"lightning,omitempty"`
 
 	// PodSecurityContext of the component
 	// +optional
@@ -2434,6 +3075,58 @@ type RestoreSpec struct {
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
+// RestoreClusterCleanupPolicy is the policy of the TidbCluster created by Restore when the restore failed
+type RestoreClusterCleanupPolicy string
+
+const (
+	// RestoreClusterCleanupPolicyDelete means the created TidbCluster is deleted if the restore failed
+	RestoreClusterCleanupPolicyDelete RestoreClusterCleanupPolicy = "Delete"
+	// RestoreClusterCleanupPolicyRetain means the created TidbCluster is retained if the restore failed
+	RestoreClusterCleanupPolicyRetain RestoreClusterCleanupPolicy = "Retain"
+)
+
+// RestoreClusterTemplate is the template of the TidbCluster created by Restore.
+// Exactly one of Spec and SpecFrom must be set.
+// +k8s:openapi-gen=true
+type RestoreClusterTemplate struct {
+	// Labels of the created TidbCluster
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations of the created TidbCluster
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Spec is the spec of the created TidbCluster.
+	// TiCDC, Pump and TiProxy are not created and TiDB runs only one replica until the restore is complete,
+	// then the TidbCluster is updated to the spec.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:XPreserveUnknownFields
+	Spec *TidbClusterSpec `json:"spec,omitempty"`
+	// SpecFrom refers to an existing TidbCluster whose spec is copied as the spec of the created TidbCluster
+	// +optional
+	SpecFrom *TidbClusterRef `json:"specFrom,omitempty"`
+	// CleanupPolicy is the policy of the created TidbCluster when the restore failed, default is Delete
+	// +kubebuilder:validation:Enum:="Delete";"Retain"
+	// +optional
+	CleanupPolicy RestoreClusterCleanupPolicy `json:"cleanupPolicy,omitempty"`
+}
+
+// RestoreClusterPhase is the phase of the TidbCluster created by Restore
+type RestoreClusterPhase string
+
+const (
+	// RestoreClusterCreating means the TidbCluster is created and the restore is waiting for it to be ready
+	RestoreClusterCreating RestoreClusterPhase = "Creating"
+	// RestoreClusterReady means PD, TiKV and TiDB of the TidbCluster are ready and the restore is running
+	RestoreClusterReady RestoreClusterPhase = "Ready"
+	// RestoreClusterNormal means the restore is complete and the TidbCluster is updated to the template
+	RestoreClusterNormal RestoreClusterPhase = "Normal"
+	// RestoreClusterDeleted means the restore failed and the TidbCluster is deleted
+	RestoreClusterDeleted RestoreClusterPhase = "Deleted"
+	// RestoreClusterRetained means the restore failed and the TidbCluster is retained
+	RestoreClusterRetained RestoreClusterPhase = "Retained"
+)
+
 // FederalVolumeRestorePhase represents a phase to execute in federal volume restore
 type FederalVolumeRestorePhase string
 
@@ -2463,6 +3156,9 @@ type RestoreStatus struct {
 	// Progresses is the progress of restore.
 	// +nullable
 	Progresses []Progress `json:"progresses,omitempty"`
+	// ClusterPhase is the phase of the TidbCluster created from `spec.clusterTemplate`.
+	// +optional
+	ClusterPhase RestoreClusterPhase `json:"clusterPhase,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -14,6 +14,7 @@
 package validation
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -27,10 +28,13 @@ import (
 	"github.com/Masterminds/semver"
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
 	"github.com/prometheus/common/model"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilnet "k8s.io/utils/net"
@@ -145,9 +149,15 @@ func validateTiDBClusterSpec(spec *v1alpha1.TidbClusterSpec, fldPath *field.Path
 	if spec.TiCDC != nil {
 		allErrs = append(allErrs, validateTiCDCSpec(spec.TiCDC, fldPath.Child("ticdc"))...)
 	}
+	if spec.TiProxy != nil {
+		allErrs = append(allErrs, validateMaxUnavailable(spec.TiProxy.MaxUnavailable, fldPath.Child("tiproxy", "maxUnavailable"))...)
+	}
 	if spec.PDAddresses != nil {
 		allErrs = append(allErrs, validatePDAddresses(spec.PDAddresses, fldPath.Child("pdAddresses"))...)
 	}
+	if spec.UpgradePolicy != nil {
+		allErrs = append(allErrs, validateUpgradePolicy(spec.UpgradePolicy, fldPath.Child("upgradePolicy"))...)
+	}
 	return allErrs
 }
 
@@ -226,6 +236,7 @@ func validateTiCDCSpec(spec *v1alpha1.TiCDCSpec, fldPath *field.Path) field.Erro
 	if len(spec.StorageVolumes) > 0 {
 		allErrs = append(allErrs, validateStorageVolumes(spec.StorageVolumes, fldPath.Child("storageVolumes"))...)
 	}
+	allErrs = append(allErrs, validateMaxUnavailable(spec.MaxUnavailable, fldPath.Child("maxUnavailable"))...)
 	return allErrs
 }
 
@@ -292,6 +303,82 @@ func validateTiDBSpec(spec *v1alpha1.TiDBSpec, fldPath *field.Path) field.ErrorL
 	if spec.ShouldSeparateSlowLog() && spec.SlowLogVolumeName != "" {
 		allErrs = append(allErrs, validateVolumeName(spec.SlowLogVolumeName, spec.StorageVolumes, spec.AdditionalVolumes, spec.AdditionalVolumeMounts, fldPath)...)
 	}
+	if spec.CanaryUpgrade != nil {
+		allErrs = append(allErrs, validateTiDBCanaryUpgrade(spec.CanaryUpgrade, fldPath.Child("canaryUpgrade"))...)
+	}
+	allErrs = append(allErrs, validateMaxUnavailable(spec.MaxUnavailable, fldPath.Child("maxUnavailable"))...)
+	return allErrs
+}
+
+func validateMaxUnavailable(maxUnavailable *intstr.IntOrString, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if maxUnavailable == nil {
+		return allErrs
+	}
+	n, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, 100, false)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, maxUnavailable.String(), err.Error()))
+	} else if n < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, maxUnavailable.String(), "must not be negative"))
+	}
+	return allErrs
+}
+
+func validateUpgradePolicy(policy *v1alpha1.UpgradePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	components := sets.NewString(
+		v1alpha1.PDMemberType.String(),
+		v1alpha1.TiKVMemberType.String(),
+		v1alpha1.TiDBMemberType.String(),
+		v1alpha1.TiFlashMemberType.String(),
+		v1alpha1.TiCDCMemberType.String(),
+		v1alpha1.TiProxyMemberType.String(),
+	)
+	names := sets.NewString()
+	for i, point := range policy.PausePoints {
+		idxPath := fldPath.Child("pausePoints").Index(i)
+		if point.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name must not be empty"))
+		} else if strings.Contains(point.Name, ",") {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), point.Name, "name must not contain ','"))
+		} else if names.Has(point.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), point.Name))
+		}
+		names.Insert(point.Name)
+		if !components.Has(point.Component.String()) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("component"), point.Component, components.List()))
+		}
+		if point.AfterPods < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("afterPods"), point.AfterPods, "must not be negative"))
+		}
+	}
+	return allErrs
+}
+
+func validateTiDBCanaryUpgrade(canary *v1alpha1.TiDBCanaryUpgrade, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if canary.Replicas != nil {
+		if _, err := intstr.GetScaledValueFromIntOrPercent(canary.Replicas, 1, true); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), canary.Replicas.String(), err.Error()))
+		}
+	}
+	if canary.BakeTime != nil && canary.BakeTime.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bakeTime"), canary.BakeTime.Duration.String(), "must not be negative"))
+	}
+	if canary.ProgressDeadline != nil && canary.ProgressDeadline.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("progressDeadline"), canary.ProgressDeadline.Duration.String(), "must be positive"))
+	}
+	if canary.MaxRestarts != nil && *canary.MaxRestarts < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxRestarts"), *canary.MaxRestarts, "must not be negative"))
+	}
+	if errorRate := canary.ErrorRate; errorRate != nil {
+		if errorRate.PrometheusAddress == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("errorRate", "prometheusAddress"), "prometheus address must be set"))
+		}
+		if errorRate.Query == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("errorRate", "query"), "query must be set"))
+		}
+	}
 	return allErrs
 }
 
@@ -374,6 +461,24 @@ func validateComponentSpec(spec *v1alpha1.ComponentSpec, fldPath *field.Path) fi
 	// TODO validate other fields
 	allErrs = append(allErrs, validateEnv(spec.Env, fldPath.Child("env"))...)
 	allErrs = append(allErrs, validateAdditionalContainers(spec.AdditionalContainers, fldPath.Child("additionalContainers"))...)
+	if spec.UpgradeHooks != nil {
+		allErrs = append(allErrs, validateUpgradeHook(spec.UpgradeHooks.PreUpgrade, fldPath.Child("upgradeHooks", "preUpgrade"))...)
+		allErrs = append(allErrs, validateUpgradeHook(spec.UpgradeHooks.PostUpgrade, fldPath.Child("upgradeHooks", "postUpgrade"))...)
+	}
+	return allErrs
+}
+
+func validateUpgradeHook(hook *v1alpha1.UpgradeHook, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if hook == nil {
+		return allErrs
+	}
+	if hook.JobTemplate.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("jobTemplate", "name"), "name of the ConfigMap must not be empty"))
+	}
+	if hook.JobTemplate.Key == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("jobTemplate", "key"), "key of the ConfigMap must not be empty"))
+	}
 	return allErrs
 }
 
@@ -796,3 +901,312 @@ func validateScalePolicy(scalePolicy *v1alpha1.ScalePolicy, fldPath *field.Path)
 	}
 	return allErrs
 }
+
+var backupEncryptionKeyLengths = map[v1alpha1.BackupEncryptionMethod]int{
+	v1alpha1.BackupEncryptionMethodAES128CTR: 16,
+	v1alpha1.BackupEncryptionMethodAES192CTR: 24,
+	v1alpha1.BackupEncryptionMethodAES256CTR: 32,
+}
+
+// ValidateBackupEncryption validates the client-side encryption of a Backup or a Restore
+func ValidateBackupEncryption(encryption *v1alpha1.BackupEncryption, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if encryption == nil {
+		return allErrs
+	}
+	if _, ok := backupEncryptionKeyLengths[encryption.Method]; !ok {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("method"), encryption.Method, []string{
+			string(v1alpha1.BackupEncryptionMethodAES128CTR),
+			string(v1alpha1.BackupEncryptionMethodAES192CTR),
+			string(v1alpha1.BackupEncryptionMethodAES256CTR),
+		}))
+	}
+	if encryption.SecretName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("secretName"), "secretName must not be empty"))
+	}
+	return allErrs
+}
+
+// ValidateBackupEncryptionKey validates the key in the secret is a hex encoded key whose length matches the method
+func ValidateBackupEncryptionKey(encryption *v1alpha1.BackupEncryption, key string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	keyPath := fldPath.Child("secretName").Key(encryption.GetSecretKey())
+	decoded, err := hex.DecodeString(strings.TrimSpace(key))
+	if err != nil {
+		return append(allErrs, field.Invalid(keyPath, "<redacted>", "the key must be hex encoded"))
+	}
+	if length, ok := backupEncryptionKeyLengths[encryption.Method]; ok && len(decoded) != length {
+		allErrs = append(allErrs, field.Invalid(keyPath, "<redacted>",
+			fmt.Sprintf("the key length of %s must be %d bytes, got %d bytes", encryption.Method, length, len(decoded))))
+	}
+	return allErrs
+}
+
+// ValidateRestoreEncryption validates the encryption of the Restore is compatible with the Backup it restores from,
+// that is, the Restore uses the same method and the same key material as the Backup. The keys are read from the
+// secrets referenced by the encryptions, the key comparison is skipped if either of them is empty.
+func ValidateRestoreEncryption(restore *v1alpha1.Restore, backup *v1alpha1.Backup, restoreKey, backupKey string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("spec", "encryption")
+	restoreEnc, backupEnc := restore.Spec.Encryption, backup.Spec.Encryption
+	switch {
+	case restoreEnc == nil && backupEnc == nil:
+		return allErrs
+	case restoreEnc == nil:
+		return append(allErrs, field.Required(fldPath,
+			fmt.Sprintf("backup %s/%s is encrypted by %s", backup.Namespace, backup.Name, backupEnc.Method)))
+	case backupEnc == nil:
+		return append(allErrs, field.Forbidden(fldPath,
+			fmt.Sprintf("backup %s/%s is not encrypted", backup.Namespace, backup.Name)))
+	}
+
+	if restoreEnc.Method != backupEnc.Method {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("method"), restoreEnc.Method,
+			fmt.Sprintf("backup %s/%s is encrypted by %s", backup.Namespace, backup.Name, backupEnc.Method)))
+	}
+	if restoreKey != "" && backupKey != "" {
+		if !strings.EqualFold(strings.TrimSpace(restoreKey), strings.TrimSpace(backupKey)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("secretName"), restoreEnc.SecretName,
+				fmt.Sprintf("the key is different from the key of backup %s/%s", backup.Namespace, backup.Name)))
+		}
+	} else if restore.Namespace == backup.Namespace &&
+		(restoreEnc.SecretName != backupEnc.SecretName || restoreEnc.GetSecretKey() != backupEnc.GetSecretKey()) {
+		// without the key material, the same secret is required in the same namespace
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("secretName"), restoreEnc.SecretName,
+			fmt.Sprintf("backup %s/%s is encrypted by the key %s in secret %s", backup.Namespace, backup.Name,
+				backupEnc.GetSecretKey(), backupEnc.SecretName)))
+	}
+	return allErrs
+}
+
+// ValidateBackup validates the fields of a Backup which can be checked without the cluster
+func ValidateBackup(backup *v1alpha1.Backup) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("spec")
+	if backup.Spec.Dumpling != nil {
+		allErrs = append(allErrs, validateDumplingConfig(backup.Spec.Dumpling, fldPath.Child("dumpling"))...)
+	}
+	allErrs = append(allErrs, ValidateBackupEncryption(backup.Spec.Encryption, fldPath.Child("encryption"))...)
+	return allErrs
+}
+
+// ValidateRestore validates the fields of a Restore which can be checked without the cluster
+func ValidateRestore(restore *v1alpha1.Restore) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("spec")
+	if restore.Spec.Lightning != nil {
+		allErrs = append(allErrs, validateLightningConfig(restore.Spec.Lightning, fldPath.Child("lightning"))...)
+	}
+	allErrs = append(allErrs, ValidateBackupEncryption(restore.Spec.Encryption, fldPath.Child("encryption"))...)
+	if restore.Spec.ClusterTemplate != nil {
+		allErrs = append(allErrs, validateRestoreClusterTemplate(restore, fldPath.Child("clusterTemplate"))...)
+	}
+	return allErrs
+}
+
+// ValidateBackupSchedule validates the fields of a BackupSchedule which are not validated by the CRD
+func ValidateBackupSchedule(bs *v1alpha1.BackupSchedule) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("spec")
+	if rpo := bs.Spec.RecoveryPointObjective; rpo != nil {
+		if d, err := time.ParseDuration(*rpo); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("recoveryPointObjective"), *rpo, err.Error()))
+		} else if d <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("recoveryPointObjective"), *rpo, "recoveryPointObjective should be positive"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateRestoreDrill validates a RestoreDrill, it does not validate the restore template which is
+// validated when the restore is created.
+func ValidateRestoreDrill(rd *v1alpha1.RestoreDrill) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("spec")
+	if rd.Spec.Schedule == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("schedule"), "schedule should be set"))
+	}
+	if rd.Spec.BackupScheduleName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("backupScheduleName"), "name of the backup schedule should be set"))
+	}
+	templatePath := fldPath.Child("restoreTemplate")
+	if rd.Spec.RestoreTemplate.ClusterTemplate == nil {
+		allErrs = append(allErrs, field.Required(templatePath.Child("clusterTemplate"), "clusterTemplate should be set to create the temporary cluster"))
+	}
+	if mode := rd.Spec.RestoreTemplate.Mode; mode != "" && mode != v1alpha1.RestoreModeSnapshot {
+		allErrs = append(allErrs, field.NotSupported(templatePath.Child("mode"), mode, []string{string(v1alpha1.RestoreModeSnapshot)}))
+	}
+	if rd.Spec.HistoryLimit != nil && *rd.Spec.HistoryLimit < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("historyLimit"), *rd.Spec.HistoryLimit, "historyLimit should be positive"))
+	}
+
+	names := map[string]bool{}
+	for i := range rd.Spec.Checks {
+		check := &rd.Spec.Checks[i]
+		checkPath := fldPath.Child("checks").Index(i)
+		if check.Name == "" {
+			allErrs = append(allErrs, field.Required(checkPath.Child("name"), "name of the check should be set"))
+		} else if names[check.Name] {
+			allErrs = append(allErrs, field.Duplicate(checkPath.Child("name"), check.Name))
+		}
+		names[check.Name] = true
+		if parts := strings.Split(check.Table, "."); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			allErrs = append(allErrs, field.Invalid(checkPath.Child("table"), check.Table, "table should be in the format of db.table"))
+		}
+		switch check.Type {
+		case v1alpha1.RestoreDrillCheckRowCount:
+			if check.MinRows != nil && *check.MinRows < 0 {
+				allErrs = append(allErrs, field.Invalid(checkPath.Child("minRows"), *check.MinRows, "minRows should not be negative"))
+			}
+			if check.ExpectedChecksum != "" {
+				allErrs = append(allErrs, field.Forbidden(checkPath.Child("expectedChecksum"), "expectedChecksum is only supported by the Checksum check"))
+			}
+		case v1alpha1.RestoreDrillCheckChecksum:
+			if check.MinRows != nil {
+				allErrs = append(allErrs, field.Forbidden(checkPath.Child("minRows"), "minRows is only supported by the RowCount check"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(checkPath.Child("type"), check.Type, []string{
+				string(v1alpha1.RestoreDrillCheckRowCount), string(v1alpha1.RestoreDrillCheckChecksum)}))
+		}
+	}
+	return allErrs
+}
+
+func validateRestoreClusterTemplate(restore *v1alpha1.Restore, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	template := restore.Spec.ClusterTemplate
+	if restore.Spec.BR == nil {
+		return append(allErrs, field.Forbidden(fldPath, "clusterTemplate is only supported by BR restore"))
+	}
+	if restore.Spec.Mode == v1alpha1.RestoreModeVolumeSnapshot {
+		return append(allErrs, field.Forbidden(fldPath, "clusterTemplate is not supported by volume snapshot restore"))
+	}
+	switch {
+	case template.Spec == nil && template.SpecFrom == nil:
+		allErrs = append(allErrs, field.Required(fldPath, "one of spec and specFrom should be set"))
+	case template.Spec != nil && template.SpecFrom != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("specFrom"), "specFrom can not be set together with spec"))
+	case template.SpecFrom != nil:
+		if template.SpecFrom.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("specFrom", "name"), "name of the TidbCluster should be set"))
+		}
+		clusterNamespace := restore.Spec.BR.ClusterNamespace
+		if clusterNamespace == "" {
+			clusterNamespace = restore.Namespace
+		}
+		specFromNamespace := template.SpecFrom.Namespace
+		if specFromNamespace == "" {
+			specFromNamespace = restore.Namespace
+		}
+		if specFromNamespace == clusterNamespace && template.SpecFrom.Name == restore.Spec.BR.Cluster {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("specFrom"), template.SpecFrom.Name,
+				"specFrom should not refer to the TidbCluster restored into"))
+		}
+	}
+	switch template.CleanupPolicy {
+	case "", v1alpha1.RestoreClusterCleanupPolicyDelete, v1alpha1.RestoreClusterCleanupPolicyRetain:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("cleanupPolicy"), template.CleanupPolicy, []string{
+			string(v1alpha1.RestoreClusterCleanupPolicyDelete), string(v1alpha1.RestoreClusterCleanupPolicyRetain)}))
+	}
+	return allErrs
+}
+
+func validateDumplingConfig(dumpling *v1alpha1.DumplingConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch dumpling.FileType {
+	case "", v1alpha1.DumplingFileTypeSQL, v1alpha1.DumplingFileTypeCSV:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("fileType"), dumpling.FileType, []string{
+			string(v1alpha1.DumplingFileTypeSQL), string(v1alpha1.DumplingFileTypeCSV)}))
+	}
+	switch dumpling.Compress {
+	case "", v1alpha1.DumplingCompressTypeGzip, v1alpha1.DumplingCompressTypeSnappy, v1alpha1.DumplingCompressTypeZstd:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("compress"), dumpling.Compress, []string{
+			string(v1alpha1.DumplingCompressTypeGzip), string(v1alpha1.DumplingCompressTypeSnappy), string(v1alpha1.DumplingCompressTypeZstd)}))
+	}
+	if dumpling.Rows != nil && *dumpling.Rows == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("rows"), *dumpling.Rows, "rows should be positive"))
+	}
+	switch dumpling.Consistency {
+	case "", v1alpha1.DumplingConsistencyAuto, v1alpha1.DumplingConsistencySnapshot:
+		if dumpling.Snapshot != "" {
+			if _, err := config.ParseTSString(dumpling.Snapshot); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("snapshot"), dumpling.Snapshot, err.Error()))
+			}
+		}
+	case v1alpha1.DumplingConsistencyNone, v1alpha1.DumplingConsistencyFlush, v1alpha1.DumplingConsistencyLock:
+		if dumpling.Snapshot != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("snapshot"),
+				fmt.Sprintf("snapshot is not supported by consistency %s", dumpling.Consistency)))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("consistency"), dumpling.Consistency, []string{
+			string(v1alpha1.DumplingConsistencyAuto), string(v1alpha1.DumplingConsistencyNone), string(v1alpha1.DumplingConsistencyFlush),
+			string(v1alpha1.DumplingConsistencyLock), string(v1alpha1.DumplingConsistencySnapshot)}))
+	}
+
+	// the typed fields can't be overridden by the options
+	typedFlags := map[string]bool{
+		"--filetype":    dumpling.FileType != "",
+		"--compress":    dumpling.Compress != "",
+		"--rows":        dumpling.Rows != nil,
+		"-r":            dumpling.Rows != nil,
+		"--snapshot":    dumpling.Snapshot != "",
+		"--consistency": dumpling.Consistency != "",
+	}
+	for i, option := range dumpling.Options {
+		flag := strings.SplitN(option, "=", 2)[0]
+		if typedFlags[flag] {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("options").Index(i), option,
+				fmt.Sprintf("%s is configured by the typed field", flag)))
+		}
+	}
+	return allErrs
+}
+
+func validateLightningConfig(lightning *v1alpha1.LightningConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	var duplicateResolutions []v1alpha1.LightningDuplicateResolution
+	switch lightning.Backend {
+	case "", v1alpha1.LightningBackendTiDB:
+		duplicateResolutions = []v1alpha1.LightningDuplicateResolution{
+			v1alpha1.LightningDuplicateReplace, v1alpha1.LightningDuplicateIgnore, v1alpha1.LightningDuplicateError}
+	case v1alpha1.LightningBackendLocal:
+		duplicateResolutions = []v1alpha1.LightningDuplicateResolution{
+			v1alpha1.LightningDuplicateNone, v1alpha1.LightningDuplicateRemove}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("backend"), lightning.Backend, []string{
+			string(v1alpha1.LightningBackendTiDB), string(v1alpha1.LightningBackendLocal)}))
+	}
+	if lightning.DuplicateResolution != "" && duplicateResolutions != nil {
+		supported := false
+		var values []string
+		for _, r := range duplicateResolutions {
+			supported = supported || r == lightning.DuplicateResolution
+			values = append(values, string(r))
+		}
+		if !supported {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("duplicateResolution"), lightning.DuplicateResolution, values))
+		}
+	}
+	if lightning.PDAddress != "" && (lightning.Backend == "" || lightning.Backend == v1alpha1.LightningBackendTiDB) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("pdAddress"), "pdAddress is only used by the local backend"))
+	}
+
+	if checkpoint := lightning.Checkpoint; checkpoint != nil {
+		switch checkpoint.Driver {
+		case "", v1alpha1.LightningCheckpointDriverFile:
+			if checkpoint.Schema != "" {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("checkpoint", "schema"), "schema is only used by the mysql driver"))
+			}
+		case v1alpha1.LightningCheckpointDriverMySQL:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("checkpoint", "driver"), checkpoint.Driver, []string{
+				string(v1alpha1.LightningCheckpointDriverFile), string(v1alpha1.LightningCheckpointDriverMySQL)}))
+		}
+	}
+	return allErrs
+}
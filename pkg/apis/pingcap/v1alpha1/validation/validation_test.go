@@ -709,6 +709,74 @@ func TestValidatePDAddresses(t *testing.T) {
 	}
 }
 
+func TestValidateUpgradePolicy(t *testing.T) {
+	successCases := []v1alpha1.UpgradePolicy{
+		{},
+		{
+			PausePoints: []v1alpha1.UpgradePausePoint{
+				{Name: "before-tikv", Component: v1alpha1.TiKVMemberType},
+				{Name: "after-tikv-1", Component: v1alpha1.TiKVMemberType, AfterPods: 1},
+				{Name: "before-tidb", Component: v1alpha1.TiDBMemberType},
+			},
+		},
+	}
+
+	for _, c := range successCases {
+		errs := validateUpgradePolicy(&c, field.NewPath("upgradePolicy"))
+		if len(errs) > 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := []v1alpha1.UpgradePolicy{
+		{
+			PausePoints: []v1alpha1.UpgradePausePoint{
+				{Component: v1alpha1.TiKVMemberType},
+			},
+		},
+		{
+			PausePoints: []v1alpha1.UpgradePausePoint{
+				{Name: "before-tikv,tidb", Component: v1alpha1.TiKVMemberType},
+			},
+		},
+		{
+			PausePoints: []v1alpha1.UpgradePausePoint{
+				{Name: "before", Component: v1alpha1.TiKVMemberType},
+				{Name: "before", Component: v1alpha1.TiDBMemberType},
+			},
+		},
+		{
+			PausePoints: []v1alpha1.UpgradePausePoint{
+				{Name: "before-pump", Component: v1alpha1.PumpMemberType},
+			},
+		},
+		{
+			PausePoints: []v1alpha1.UpgradePausePoint{
+				{Name: "after-tikv", Component: v1alpha1.TiKVMemberType, AfterPods: -1},
+			},
+		},
+	}
+
+	for _, c := range errorCases {
+		errs := validateUpgradePolicy(&c, field.NewPath("upgradePolicy"))
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %v", c)
+		}
+	}
+}
+
+func TestValidateUpgradeHook(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hook := &v1alpha1.UpgradeHook{JobTemplate: corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "hooks"},
+		Key:                  "pre-upgrade",
+	}}
+	g.Expect(validateUpgradeHook(nil, field.NewPath("preUpgrade"))).To(BeEmpty())
+	g.Expect(validateUpgradeHook(hook, field.NewPath("preUpgrade"))).To(BeEmpty())
+	g.Expect(validateUpgradeHook(&v1alpha1.UpgradeHook{}, field.NewPath("preUpgrade"))).To(HaveLen(2))
+}
+
 func TestValidatePDSpec(t *testing.T) {
 	g := NewGomegaWithT(t)
 	tests := []struct {
@@ -826,3 +894,243 @@ func Test_disallowMutateBootstrapSQLConfigMapName(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBackupEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+	fldPath := field.NewPath("spec", "encryption")
+
+	g.Expect(ValidateBackupEncryption(nil, fldPath)).Should(BeEmpty())
+	g.Expect(ValidateBackupEncryption(&v1alpha1.BackupEncryption{
+		Method:     v1alpha1.BackupEncryptionMethodAES256CTR,
+		SecretName: "backup-key",
+	}, fldPath)).Should(BeEmpty())
+	g.Expect(ValidateBackupEncryption(&v1alpha1.BackupEncryption{Method: "aes512-ctr"}, fldPath)).Should(HaveLen(2))
+
+	enc := &v1alpha1.BackupEncryption{Method: v1alpha1.BackupEncryptionMethodAES128CTR, SecretName: "backup-key"}
+	g.Expect(ValidateBackupEncryptionKey(enc, "0123456789abcdef0123456789abcdef\n", fldPath)).Should(BeEmpty())
+	g.Expect(ValidateBackupEncryptionKey(enc, "0123456789abcdef", fldPath)).Should(HaveLen(1))
+	g.Expect(ValidateBackupEncryptionKey(enc, "not-a-hex-key", fldPath)).Should(HaveLen(1))
+}
+
+func TestValidateRestoreEncryption(t *testing.T) {
+	newBackup := func(enc *v1alpha1.BackupEncryption) *v1alpha1.Backup {
+		return &v1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backup"},
+			Spec:       v1alpha1.BackupSpec{Encryption: enc},
+		}
+	}
+	newRestore := func(ns string, enc *v1alpha1.BackupEncryption) *v1alpha1.Restore {
+		return &v1alpha1.Restore{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "restore"},
+			Spec:       v1alpha1.RestoreSpec{Encryption: enc},
+		}
+	}
+	aes256 := &v1alpha1.BackupEncryption{Method: v1alpha1.BackupEncryptionMethodAES256CTR, SecretName: "backup-key"}
+	aes128 := &v1alpha1.BackupEncryption{Method: v1alpha1.BackupEncryptionMethodAES128CTR, SecretName: "backup-key"}
+	otherSecret := &v1alpha1.BackupEncryption{Method: v1alpha1.BackupEncryptionMethodAES256CTR, SecretName: "other-key"}
+
+	tests := []struct {
+		name       string
+		restore    *v1alpha1.Restore
+		backup     *v1alpha1.Backup
+		restoreKey string
+		backupKey  string
+		errs       int
+	}{
+		{name: "no encryption", restore: newRestore("ns", nil), backup: newBackup(nil)},
+		{name: "same secret", restore: newRestore("ns", aes256), backup: newBackup(aes256)},
+		{name: "missing encryption", restore: newRestore("ns", nil), backup: newBackup(aes256), errs: 1},
+		{name: "backup not encrypted", restore: newRestore("ns", aes256), backup: newBackup(nil), errs: 1},
+		{name: "different method", restore: newRestore("ns", aes128), backup: newBackup(aes256), errs: 1},
+		{name: "different secret in the same namespace", restore: newRestore("ns", otherSecret), backup: newBackup(aes256), errs: 1},
+		{name: "different secret with the same key", restore: newRestore("ns", otherSecret), backup: newBackup(aes256),
+			restoreKey: "0123456789ABCDEF", backupKey: "0123456789abcdef"},
+		{name: "different key", restore: newRestore("other", aes256), backup: newBackup(aes256),
+			restoreKey: "0123456789abcdef", backupKey: "fedcba9876543210", errs: 1},
+		{name: "secret in another namespace", restore: newRestore("other", otherSecret), backup: newBackup(aes256)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			errs := ValidateRestoreEncryption(tt.restore, tt.backup, tt.restoreKey, tt.backupKey)
+			g.Expect(errs).Should(HaveLen(tt.errs), "%v", errs)
+		})
+	}
+}
+
+func TestValidateBackup(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	backup := &v1alpha1.Backup{}
+	g.Expect(ValidateBackup(backup)).Should(BeEmpty())
+
+	rows, zero := uint64(10000), uint64(0)
+
+	backup.Spec.Dumpling = &v1alpha1.DumplingConfig{
+		FileType:    v1alpha1.DumplingFileTypeCSV,
+		Compress:    v1alpha1.DumplingCompressTypeZstd,
+		Rows:        &rows,
+		Snapshot:    "2023-01-01 00:00:00",
+		Consistency: v1alpha1.DumplingConsistencySnapshot,
+		Options:     []string{"--threads=16"},
+	}
+	g.Expect(ValidateBackup(backup)).Should(BeEmpty())
+
+	tests := []struct {
+		name   string
+		modify func(d *v1alpha1.DumplingConfig)
+	}{
+		{name: "invalid file type", modify: func(d *v1alpha1.DumplingConfig) { d.FileType = "parquet" }},
+		{name: "invalid compress", modify: func(d *v1alpha1.DumplingConfig) { d.Compress = "lz4" }},
+		{name: "zero rows", modify: func(d *v1alpha1.DumplingConfig) { d.Rows = &zero }},
+		{name: "invalid snapshot", modify: func(d *v1alpha1.DumplingConfig) { d.Snapshot = "yesterday" }},
+		{name: "snapshot with lock", modify: func(d *v1alpha1.DumplingConfig) { d.Consistency = v1alpha1.DumplingConsistencyLock }},
+		{name: "invalid consistency", modify: func(d *v1alpha1.DumplingConfig) { d.Consistency = "strong" }},
+		{name: "conflicted option", modify: func(d *v1alpha1.DumplingConfig) { d.Options = append(d.Options, "--filetype=sql") }},
+	}
+	for _, tt := range tests {
+		b := backup.DeepCopy()
+		tt.modify(b.Spec.Dumpling)
+		g.Expect(ValidateBackup(b)).Should(HaveLen(1), tt.name)
+	}
+}
+
+func TestValidateRestore(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	restore := &v1alpha1.Restore{}
+	g.Expect(ValidateRestore(restore)).Should(BeEmpty())
+
+	restore.Spec.Lightning = &v1alpha1.LightningConfig{
+		Backend:             v1alpha1.LightningBackendLocal,
+		DuplicateResolution: v1alpha1.LightningDuplicateRemove,
+		PDAddress:           "basic-pd:2379",
+		Checkpoint: &v1alpha1.LightningCheckpoint{
+			Driver: v1alpha1.LightningCheckpointDriverMySQL,
+			Schema: "checkpoint",
+		},
+	}
+	g.Expect(ValidateRestore(restore)).Should(BeEmpty())
+
+	tests := []struct {
+		name   string
+		modify func(l *v1alpha1.LightningConfig)
+	}{
+		{name: "invalid backend", modify: func(l *v1alpha1.LightningConfig) { l.Backend = "importer" }},
+		{name: "duplicate resolution of tidb backend", modify: func(l *v1alpha1.LightningConfig) { l.DuplicateResolution = v1alpha1.LightningDuplicateReplace }},
+		{name: "pd address of tidb backend", modify: func(l *v1alpha1.LightningConfig) {
+			l.Backend = v1alpha1.LightningBackendTiDB
+			l.DuplicateResolution = v1alpha1.LightningDuplicateIgnore
+		}},
+		{name: "schema of file driver", modify: func(l *v1alpha1.LightningConfig) { l.Checkpoint.Driver = v1alpha1.LightningCheckpointDriverFile }},
+		{name: "invalid driver", modify: func(l *v1alpha1.LightningConfig) { l.Checkpoint.Driver = "etcd" }},
+	}
+	for _, tt := range tests {
+		r := restore.DeepCopy()
+		tt.modify(r.Spec.Lightning)
+		g.Expect(ValidateRestore(r)).Should(HaveLen(1), tt.name)
+	}
+}
+
+func TestValidateRestoreClusterTemplate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	restore := &v1alpha1.Restore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "restore"},
+		Spec: v1alpha1.RestoreSpec{
+			BR: &v1alpha1.BRConfig{Cluster: "drill"},
+			ClusterTemplate: &v1alpha1.RestoreClusterTemplate{
+				Spec: &v1alpha1.TidbClusterSpec{},
+			},
+		},
+	}
+	g.Expect(ValidateRestore(restore)).Should(BeEmpty())
+
+	tests := []struct {
+		name   string
+		modify func(r *v1alpha1.Restore)
+	}{
+		{name: "lightning restore", modify: func(r *v1alpha1.Restore) { r.Spec.BR = nil }},
+		{name: "volume snapshot restore", modify: func(r *v1alpha1.Restore) { r.Spec.Mode = v1alpha1.RestoreModeVolumeSnapshot }},
+		{name: "no spec", modify: func(r *v1alpha1.Restore) { r.Spec.ClusterTemplate.Spec = nil }},
+		{name: "both spec and specFrom", modify: func(r *v1alpha1.Restore) {
+			r.Spec.ClusterTemplate.SpecFrom = &v1alpha1.TidbClusterRef{Name: "prod"}
+		}},
+		{name: "specFrom without name", modify: func(r *v1alpha1.Restore) {
+			r.Spec.ClusterTemplate.Spec = nil
+			r.Spec.ClusterTemplate.SpecFrom = &v1alpha1.TidbClusterRef{Namespace: "prod"}
+		}},
+		{name: "specFrom refers to the restored cluster", modify: func(r *v1alpha1.Restore) {
+			r.Spec.ClusterTemplate.Spec = nil
+			r.Spec.ClusterTemplate.SpecFrom = &v1alpha1.TidbClusterRef{Name: "drill"}
+		}},
+		{name: "invalid cleanup policy", modify: func(r *v1alpha1.Restore) { r.Spec.ClusterTemplate.CleanupPolicy = "Orphan" }},
+	}
+	for _, tt := range tests {
+		r := restore.DeepCopy()
+		tt.modify(r)
+		g.Expect(ValidateRestore(r)).Should(HaveLen(1), tt.name)
+	}
+
+	r := restore.DeepCopy()
+	r.Spec.ClusterTemplate.Spec = nil
+	r.Spec.ClusterTemplate.SpecFrom = &v1alpha1.TidbClusterRef{Namespace: "prod", Name: "drill"}
+	r.Spec.ClusterTemplate.CleanupPolicy = v1alpha1.RestoreClusterCleanupPolicyRetain
+	g.Expect(ValidateRestore(r)).Should(BeEmpty())
+}
+
+func TestValidateBackupSchedule(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	bs := &v1alpha1.BackupSchedule{}
+	g.Expect(ValidateBackupSchedule(bs)).Should(BeEmpty())
+	for rpo, valid := range map[string]bool{"1h30m": true, "90": false, "1day": false, "0s": false, "-1h": false} {
+		rpo := rpo
+		bs.Spec.RecoveryPointObjective = &rpo
+		if valid {
+			g.Expect(ValidateBackupSchedule(bs)).Should(BeEmpty(), rpo)
+		} else {
+			g.Expect(ValidateBackupSchedule(bs)).Should(HaveLen(1), rpo)
+		}
+	}
+}
+
+func TestValidateRestoreDrill(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	minRows := int64(10)
+	rd := &v1alpha1.RestoreDrill{
+		Spec: v1alpha1.RestoreDrillSpec{
+			Schedule:           "0 0 1 */3 *",
+			BackupScheduleName: "daily",
+			RestoreTemplate: v1alpha1.RestoreSpec{
+				ClusterTemplate: &v1alpha1.RestoreClusterTemplate{Spec: &v1alpha1.TidbClusterSpec{}},
+			},
+			Checks: []v1alpha1.RestoreDrillCheck{
+				{Name: "orders", Type: v1alpha1.RestoreDrillCheckRowCount, Table: "shop.orders", MinRows: &minRows},
+				{Name: "users", Type: v1alpha1.RestoreDrillCheckChecksum, Table: "shop.users", ExpectedChecksum: "123"},
+			},
+		},
+	}
+	g.Expect(ValidateRestoreDrill(rd)).Should(BeEmpty())
+
+	tests := []struct {
+		name   string
+		modify func(rd *v1alpha1.RestoreDrill)
+	}{
+		{name: "no schedule", modify: func(rd *v1alpha1.RestoreDrill) { rd.Spec.Schedule = "" }},
+		{name: "no backup schedule", modify: func(rd *v1alpha1.RestoreDrill) { rd.Spec.BackupScheduleName = "" }},
+		{name: "no cluster template", modify: func(rd *v1alpha1.RestoreDrill) { rd.Spec.RestoreTemplate.ClusterTemplate = nil }},
+		{name: "pitr restore", modify: func(rd *v1alpha1.RestoreDrill) { rd.Spec.RestoreTemplate.Mode = v1alpha1.RestoreModePiTR }},
+		{name: "duplicated name", modify: func(rd *v1alpha1.RestoreDrill) { rd.Spec.Checks[1].Name = "orders" }},
+		{name: "invalid table", modify: func(rd *v1alpha1.RestoreDrill) { rd.Spec.Checks[0].Table = "orders" }},
+		{name: "invalid type", modify: func(rd *v1alpha1.RestoreDrill) { rd.Spec.Checks[0].Type = "Query" }},
+		{name: "expected checksum of row count", modify: func(rd *v1alpha1.RestoreDrill) { rd.Spec.Checks[0].ExpectedChecksum = "123" }},
+		{name: "min rows of checksum", modify: func(rd *v1alpha1.RestoreDrill) { rd.Spec.Checks[1].MinRows = &minRows }},
+	}
+	for _, tt := range tests {
+		r := rd.DeepCopy()
+		tt.modify(r)
+		g.Expect(ValidateRestoreDrill(r)).Should(HaveLen(1), tt.name)
+	}
+}
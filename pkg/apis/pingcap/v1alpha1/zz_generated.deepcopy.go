@@ -29,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	types "k8s.io/apimachinery/pkg/types"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -81,6 +82,30 @@ func (in *AutoRule) DeepCopy() *AutoRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoScalerRecommendation) DeepCopyInto(out *AutoScalerRecommendation) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoScalerRecommendation.
+func (in *AutoScalerRecommendation) DeepCopy() *AutoScalerRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoScalerRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzblobStorageProvider) DeepCopyInto(out *AzblobStorageProvider) {
 	*out = *in
@@ -110,6 +135,11 @@ func (in *BRConfig) DeepCopyInto(out *BRConfig) {
 		*out = new(uint)
 		**out = **in
 	}
+	if in.RateLimitWindows != nil {
+		in, out := &in.RateLimitWindows, &out.RateLimitWindows
+		*out = make([]BRRateLimitWindow, len(*in))
+		copy(*out, *in)
+	}
 	if in.Checksum != nil {
 		in, out := &in.Checksum, &out.Checksum
 		*out = new(bool)
@@ -148,6 +178,23 @@ func (in *BRConfig) DeepCopy() *BRConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BRRateLimitWindow) DeepCopyInto(out *BRRateLimitWindow) {
+	*out = *in
+	out.TimeWindow = in.TimeWindow
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BRRateLimitWindow.
+func (in *BRRateLimitWindow) DeepCopy() *BRRateLimitWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(BRRateLimitWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackoffRetryPolicy) DeepCopyInto(out *BackoffRetryPolicy) {
 	*out = *in
@@ -237,6 +284,38 @@ func (in *BackupCondition) DeepCopy() *BackupCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupCoverageGap) DeepCopyInto(out *BackupCoverageGap) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupCoverageGap.
+func (in *BackupCoverageGap) DeepCopy() *BackupCoverageGap {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupCoverageGap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupEncryption) DeepCopyInto(out *BackupEncryption) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupEncryption.
+func (in *BackupEncryption) DeepCopy() *BackupEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupList) DeepCopyInto(out *BackupList) {
 	*out = *in
@@ -270,6 +349,51 @@ func (in *BackupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRecoverableWindow) DeepCopyInto(out *BackupRecoverableWindow) {
+	*out = *in
+	if in.EarliestTime != nil {
+		in, out := &in.EarliestTime, &out.EarliestTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LatestTime != nil {
+		in, out := &in.LatestTime, &out.LatestTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Gaps != nil {
+		in, out := &in.Gaps, &out.Gaps
+		*out = make([]BackupCoverageGap, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRecoverableWindow.
+func (in *BackupRecoverableWindow) DeepCopy() *BackupRecoverableWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRecoverableWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRetentionPolicy) DeepCopyInto(out *BackupRetentionPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRetentionPolicy.
+func (in *BackupRetentionPolicy) DeepCopy() *BackupRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupSchedule) DeepCopyInto(out *BackupSchedule) {
 	*out = *in
@@ -298,6 +422,23 @@ func (in *BackupSchedule) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupScheduleCondition) DeepCopyInto(out *BackupScheduleCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupScheduleCondition.
+func (in *BackupScheduleCondition) DeepCopy() *BackupScheduleCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupScheduleCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupScheduleList) DeepCopyInto(out *BackupScheduleList) {
 	*out = *in
@@ -344,12 +485,27 @@ func (in *BackupScheduleSpec) DeepCopyInto(out *BackupScheduleSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.RetentionPolicy != nil {
+		in, out := &in.RetentionPolicy, &out.RetentionPolicy
+		*out = new(BackupRetentionPolicy)
+		**out = **in
+	}
 	in.BackupTemplate.DeepCopyInto(&out.BackupTemplate)
 	if in.LogBackupTemplate != nil {
 		in, out := &in.LogBackupTemplate, &out.LogBackupTemplate
 		*out = new(BackupSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExecutionWindows != nil {
+		in, out := &in.ExecutionWindows, &out.ExecutionWindows
+		*out = make([]TimeWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecoveryPointObjective != nil {
+		in, out := &in.RecoveryPointObjective, &out.RecoveryPointObjective
+		*out = new(string)
+		**out = **in
+	}
 	if in.StorageClassName != nil {
 		in, out := &in.StorageClassName, &out.StorageClassName
 		*out = new(string)
@@ -389,6 +545,26 @@ func (in *BackupScheduleStatus) DeepCopyInto(out *BackupScheduleStatus) {
 		in, out := &in.AllBackupCleanTime, &out.AllBackupCleanTime
 		*out = (*in).DeepCopy()
 	}
+	if in.LastSkippedBackupTime != nil {
+		in, out := &in.LastSkippedBackupTime, &out.LastSkippedBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DeferredBackupTime != nil {
+		in, out := &in.DeferredBackupTime, &out.DeferredBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RecoverableWindow != nil {
+		in, out := &in.RecoverableWindow, &out.RecoverableWindow
+		*out = new(BackupRecoverableWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]BackupScheduleCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -472,6 +648,18 @@ func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	out.BackoffRetryPolicy = in.BackoffRetryPolicy
+	if in.SecondaryStorages != nil {
+		in, out := &in.SecondaryStorages, &out.SecondaryStorages
+		*out = make([]SecondaryStorage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(BackupEncryption)
+		**out = **in
+	}
 	return
 }
 
@@ -518,6 +706,13 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SecondaryStorageStatuses != nil {
+		in, out := &in.SecondaryStorageStatuses, &out.SecondaryStorageStatuses
+		*out = make([]SecondaryStorageStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -581,6 +776,18 @@ func (in *BasicAutoScalerSpec) DeepCopyInto(out *BasicAutoScalerSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(CustomConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]ScheduledScalingPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -601,6 +808,13 @@ func (in *BasicAutoScalerStatus) DeepCopyInto(out *BasicAutoScalerStatus) {
 		in, out := &in.LastAutoScalingTimestamp, &out.LastAutoScalingTimestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.RecommendedResources != nil {
+		in, out := &in.RecommendedResources, &out.RecommendedResources
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -993,6 +1207,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = new(Probe)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UpgradeHooks != nil {
+		in, out := &in.UpgradeHooks, &out.UpgradeHooks
+		*out = new(UpgradeHooks)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1097,6 +1316,7 @@ func (in *CrdKinds) DeepCopyInto(out *CrdKinds) {
 	in.Backup.DeepCopyInto(&out.Backup)
 	in.Restore.DeepCopyInto(&out.Restore)
 	in.BackupSchedule.DeepCopyInto(&out.BackupSchedule)
+	in.RestoreDrill.DeepCopyInto(&out.RestoreDrill)
 	in.TiDBMonitor.DeepCopyInto(&out.TiDBMonitor)
 	in.TiDBInitializer.DeepCopyInto(&out.TiDBInitializer)
 	in.TidbClusterAutoScaler.DeepCopyInto(&out.TidbClusterAutoScaler)
@@ -1114,6 +1334,43 @@ func (in *CrdKinds) DeepCopy() *CrdKinds {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomAutoRule) DeepCopyInto(out *CustomAutoRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomAutoRule.
+func (in *CustomAutoRule) DeepCopy() *CustomAutoRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomAutoRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomConfig) DeepCopyInto(out *CustomConfig) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]CustomAutoRule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomConfig.
+func (in *CustomConfig) DeepCopy() *CustomConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DMCluster) DeepCopyInto(out *DMCluster) {
 	*out = *in
@@ -1596,6 +1853,11 @@ func (in *DumplingConfig) DeepCopyInto(out *DumplingConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Rows != nil {
+		in, out := &in.Rows, &out.Rows
+		*out = new(uint64)
+		**out = **in
+	}
 	return
 }
 
@@ -2379,6 +2641,43 @@ func (in *IsolationRead) DeepCopy() *IsolationRead {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LightningCheckpoint) DeepCopyInto(out *LightningCheckpoint) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LightningCheckpoint.
+func (in *LightningCheckpoint) DeepCopy() *LightningCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(LightningCheckpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LightningConfig) DeepCopyInto(out *LightningConfig) {
+	*out = *in
+	if in.Checkpoint != nil {
+		in, out := &in.Checkpoint, &out.Checkpoint
+		*out = new(LightningCheckpoint)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LightningConfig.
+func (in *LightningConfig) DeepCopy() *LightningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LightningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalStorageProvider) DeepCopyInto(out *LocalStorageProvider) {
 	*out = *in
@@ -3064,6 +3363,23 @@ func (in *OpenTracingSampler) DeepCopy() *OpenTracingSampler {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationRecord) DeepCopyInto(out *OperationRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationRecord.
+func (in *OperationRecord) DeepCopy() *OperationRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PDConfig) DeepCopyInto(out *PDConfig) {
 	*out = *in
@@ -4722,6 +5038,46 @@ func (in *Restore) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreClusterTemplate) DeepCopyInto(out *RestoreClusterTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(TidbClusterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SpecFrom != nil {
+		in, out := &in.SpecFrom, &out.SpecFrom
+		*out = new(TidbClusterRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreClusterTemplate.
+func (in *RestoreClusterTemplate) DeepCopy() *RestoreClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestoreCondition) DeepCopyInto(out *RestoreCondition) {
 	*out = *in
@@ -4740,32 +5096,226 @@ func (in *RestoreCondition) DeepCopy() *RestoreCondition {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RestoreList) DeepCopyInto(out *RestoreList) {
+func (in *RestoreDrill) DeepCopyInto(out *RestoreDrill) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Restore, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreList.
-func (in *RestoreList) DeepCopy() *RestoreList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrill.
+func (in *RestoreDrill) DeepCopy() *RestoreDrill {
 	if in == nil {
 		return nil
 	}
-	out := new(RestoreList)
+	out := new(RestoreDrill)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RestoreList) DeepCopyObject() runtime.Object {
+func (in *RestoreDrill) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreDrillCheck) DeepCopyInto(out *RestoreDrillCheck) {
+	*out = *in
+	if in.MinRows != nil {
+		in, out := &in.MinRows, &out.MinRows
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrillCheck.
+func (in *RestoreDrillCheck) DeepCopy() *RestoreDrillCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreDrillCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreDrillCheckResult) DeepCopyInto(out *RestoreDrillCheckResult) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrillCheckResult.
+func (in *RestoreDrillCheckResult) DeepCopy() *RestoreDrillCheckResult {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreDrillCheckResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreDrillList) DeepCopyInto(out *RestoreDrillList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RestoreDrill, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrillList.
+func (in *RestoreDrillList) DeepCopy() *RestoreDrillList {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreDrillList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RestoreDrillList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreDrillRun) DeepCopyInto(out *RestoreDrillRun) {
+	*out = *in
+	in.ScheduleTime.DeepCopyInto(&out.ScheduleTime)
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]RestoreDrillCheckResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrillRun.
+func (in *RestoreDrillRun) DeepCopy() *RestoreDrillRun {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreDrillRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreDrillSpec) DeepCopyInto(out *RestoreDrillSpec) {
+	*out = *in
+	in.RestoreTemplate.DeepCopyInto(&out.RestoreTemplate)
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = new(TiDBAccessConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]RestoreDrillCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrillSpec.
+func (in *RestoreDrillSpec) DeepCopy() *RestoreDrillSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreDrillSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreDrillStatus) DeepCopyInto(out *RestoreDrillStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CurrentRun != nil {
+		in, out := &in.CurrentRun, &out.CurrentRun
+		*out = new(RestoreDrillRun)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]RestoreDrillRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrillStatus.
+func (in *RestoreDrillStatus) DeepCopy() *RestoreDrillStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreDrillStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreList) DeepCopyInto(out *RestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Restore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreList.
+func (in *RestoreList) DeepCopy() *RestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RestoreList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4795,6 +5345,16 @@ func (in *RestoreSpec) DeepCopyInto(out *RestoreSpec) {
 	}
 	in.StorageProvider.DeepCopyInto(&out.StorageProvider)
 	in.PitrFullBackupStorageProvider.DeepCopyInto(&out.PitrFullBackupStorageProvider)
+	if in.PitrBackupScheduleRef != nil {
+		in, out := &in.PitrBackupScheduleRef, &out.PitrBackupScheduleRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(BackupEncryption)
+		**out = **in
+	}
 	if in.StorageClassName != nil {
 		in, out := &in.StorageClassName, &out.StorageClassName
 		*out = new(string)
@@ -4805,6 +5365,11 @@ func (in *RestoreSpec) DeepCopyInto(out *RestoreSpec) {
 		*out = new(BRConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClusterTemplate != nil {
+		in, out := &in.ClusterTemplate, &out.ClusterTemplate
+		*out = new(RestoreClusterTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]v1.Toleration, len(*in))
@@ -4827,6 +5392,11 @@ func (in *RestoreSpec) DeepCopyInto(out *RestoreSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Lightning != nil {
+		in, out := &in.Lightning, &out.Lightning
+		*out = new(LightningConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PodSecurityContext != nil {
 		in, out := &in.PodSecurityContext, &out.PodSecurityContext
 		*out = new(v1.PodSecurityContext)
@@ -4947,6 +5517,69 @@ func (in *ScalePolicy) DeepCopy() *ScalePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledScalingPolicy) DeepCopyInto(out *ScheduledScalingPolicy) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledScalingPolicy.
+func (in *ScheduledScalingPolicy) DeepCopy() *ScheduledScalingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledScalingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecondaryStorage) DeepCopyInto(out *SecondaryStorage) {
+	*out = *in
+	in.StorageProvider.DeepCopyInto(&out.StorageProvider)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecondaryStorage.
+func (in *SecondaryStorage) DeepCopy() *SecondaryStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(SecondaryStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecondaryStorageStatus) DeepCopyInto(out *SecondaryStorageStatus) {
+	*out = *in
+	if in.TimeCompleted != nil {
+		in, out := &in.TimeCompleted, &out.TimeCompleted
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecondaryStorageStatus.
+func (in *SecondaryStorageStatus) DeepCopy() *SecondaryStorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecondaryStorageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretOrConfigMap) DeepCopyInto(out *SecretOrConfigMap) {
 	*out = *in
@@ -5476,6 +6109,11 @@ func (in *TiCDCSpec) DeepCopyInto(out *TiCDCSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	return
 }
 
@@ -5560,6 +6198,92 @@ func (in *TiDBAccessConfig) DeepCopy() *TiDBAccessConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBCanaryErrorRate) DeepCopyInto(out *TiDBCanaryErrorRate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBCanaryErrorRate.
+func (in *TiDBCanaryErrorRate) DeepCopy() *TiDBCanaryErrorRate {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBCanaryErrorRate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBCanaryUpgrade) DeepCopyInto(out *TiDBCanaryUpgrade) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.BakeTime != nil {
+		in, out := &in.BakeTime, &out.BakeTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxRestarts != nil {
+		in, out := &in.MaxRestarts, &out.MaxRestarts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ErrorRate != nil {
+		in, out := &in.ErrorRate, &out.ErrorRate
+		*out = new(TiDBCanaryErrorRate)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBCanaryUpgrade.
+func (in *TiDBCanaryUpgrade) DeepCopy() *TiDBCanaryUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBCanaryUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBCanaryUpgradeStatus) DeepCopyInto(out *TiDBCanaryUpgradeStatus) {
+	*out = *in
+	if in.Canaries != nil {
+		in, out := &in.Canaries, &out.Canaries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BakeStartTime != nil {
+		in, out := &in.BakeStartTime, &out.BakeStartTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBCanaryUpgradeStatus.
+func (in *TiDBCanaryUpgradeStatus) DeepCopy() *TiDBCanaryUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBCanaryUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TiDBConfig) DeepCopyInto(out *TiDBConfig) {
 	*out = *in
@@ -6021,6 +6745,16 @@ func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CanaryUpgrade != nil {
+		in, out := &in.CanaryUpgrade, &out.CanaryUpgrade
+		*out = new(TiDBCanaryUpgrade)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	return
 }
 
@@ -6083,6 +6817,11 @@ func (in *TiDBStatus) DeepCopyInto(out *TiDBStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CanaryUpgrade != nil {
+		in, out := &in.CanaryUpgrade, &out.CanaryUpgrade
+		*out = new(TiDBCanaryUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -8138,6 +8877,11 @@ func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
 		copy(*out, *in)
 	}
 	in.ScalePolicy.DeepCopyInto(&out.ScalePolicy)
+	if in.ZoneAwareUpgrade != nil {
+		in, out := &in.ZoneAwareUpgrade, &out.ZoneAwareUpgrade
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -8578,6 +9322,11 @@ func (in *TiProxySpec) DeepCopyInto(out *TiProxySpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	return
 }
 
@@ -8641,10 +9390,49 @@ func (in *TiProxyStatus) DeepCopy() *TiProxyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TicdcAutoScalerSpec) DeepCopyInto(out *TicdcAutoScalerSpec) {
+	*out = *in
+	in.BasicAutoScalerSpec.DeepCopyInto(&out.BasicAutoScalerSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TicdcAutoScalerSpec.
+func (in *TicdcAutoScalerSpec) DeepCopy() *TicdcAutoScalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TicdcAutoScalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TicdcAutoScalerStatus) DeepCopyInto(out *TicdcAutoScalerStatus) {
+	*out = *in
+	in.BasicAutoScalerStatus.DeepCopyInto(&out.BasicAutoScalerStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TicdcAutoScalerStatus.
+func (in *TicdcAutoScalerStatus) DeepCopy() *TicdcAutoScalerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TicdcAutoScalerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TidbAutoScalerSpec) DeepCopyInto(out *TidbAutoScalerSpec) {
 	*out = *in
 	in.BasicAutoScalerSpec.DeepCopyInto(&out.BasicAutoScalerSpec)
+	if in.Vertical != nil {
+		in, out := &in.Vertical, &out.Vertical
+		*out = new(VerticalAutoScalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -8794,6 +9582,31 @@ func (in *TidbClusterAutoScalerSpec) DeepCopyInto(out *TidbClusterAutoScalerSpec
 		*out = new(TidbAutoScalerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TiFlash != nil {
+		in, out := &in.TiFlash, &out.TiFlash
+		*out = new(TiflashAutoScalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TiCDC != nil {
+		in, out := &in.TiCDC, &out.TiCDC
+		*out = new(TicdcAutoScalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitor != nil {
+		in, out := &in.Monitor, &out.Monitor
+		*out = new(TidbMonitorRef)
+		**out = **in
+	}
+	if in.MetricsURL != nil {
+		in, out := &in.MetricsURL, &out.MetricsURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.RecommendationHistoryLimit != nil {
+		in, out := &in.RecommendationHistoryLimit, &out.RecommendationHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -8824,6 +9637,27 @@ func (in *TidbClusterAutoScalerStatus) DeepCopyInto(out *TidbClusterAutoScalerSt
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.TiFlash != nil {
+		in, out := &in.TiFlash, &out.TiFlash
+		*out = make(map[string]TiflashAutoScalerStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.TiCDC != nil {
+		in, out := &in.TiCDC, &out.TiCDC
+		*out = make(map[string]TicdcAutoScalerStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = make([]AutoScalerRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -9051,6 +9885,11 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 		*out = new(SuspendAction)
 		**out = **in
 	}
+	if in.UpgradePolicy != nil {
+		in, out := &in.UpgradePolicy, &out.UpgradePolicy
+		*out = new(UpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -9086,6 +9925,20 @@ func (in *TidbClusterStatus) DeepCopyInto(out *TidbClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.OperationHistory != nil {
+		in, out := &in.OperationHistory, &out.OperationHistory
+		*out = make([]OperationRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpgradePausePoints != nil {
+		in, out := &in.UpgradePausePoints, &out.UpgradePausePoints
+		*out = make([]UpgradePausePointStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -9729,10 +10582,49 @@ func (in *TidbNGMonitoringStatus) DeepCopy() *TidbNGMonitoringStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiflashAutoScalerSpec) DeepCopyInto(out *TiflashAutoScalerSpec) {
+	*out = *in
+	in.BasicAutoScalerSpec.DeepCopyInto(&out.BasicAutoScalerSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiflashAutoScalerSpec.
+func (in *TiflashAutoScalerSpec) DeepCopy() *TiflashAutoScalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiflashAutoScalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiflashAutoScalerStatus) DeepCopyInto(out *TiflashAutoScalerStatus) {
+	*out = *in
+	in.BasicAutoScalerStatus.DeepCopyInto(&out.BasicAutoScalerStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiflashAutoScalerStatus.
+func (in *TiflashAutoScalerStatus) DeepCopy() *TiflashAutoScalerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TiflashAutoScalerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TikvAutoScalerSpec) DeepCopyInto(out *TikvAutoScalerSpec) {
 	*out = *in
 	in.BasicAutoScalerSpec.DeepCopyInto(&out.BasicAutoScalerSpec)
+	if in.Vertical != nil {
+		in, out := &in.Vertical, &out.Vertical
+		*out = new(VerticalAutoScalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -9763,6 +10655,22 @@ func (in *TikvAutoScalerStatus) DeepCopy() *TikvAutoScalerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeWindow) DeepCopyInto(out *TimeWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeWindow.
+func (in *TimeWindow) DeepCopy() *TimeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopologySpreadConstraint) DeepCopyInto(out *TopologySpreadConstraint) {
 	*out = *in
@@ -9829,6 +10737,103 @@ func (in *UnjoinedMember) DeepCopy() *UnjoinedMember {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeHook) DeepCopyInto(out *UpgradeHook) {
+	*out = *in
+	in.JobTemplate.DeepCopyInto(&out.JobTemplate)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeHook.
+func (in *UpgradeHook) DeepCopy() *UpgradeHook {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeHooks) DeepCopyInto(out *UpgradeHooks) {
+	*out = *in
+	if in.PreUpgrade != nil {
+		in, out := &in.PreUpgrade, &out.PreUpgrade
+		*out = new(UpgradeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostUpgrade != nil {
+		in, out := &in.PostUpgrade, &out.PostUpgrade
+		*out = new(UpgradeHook)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeHooks.
+func (in *UpgradeHooks) DeepCopy() *UpgradeHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePausePoint) DeepCopyInto(out *UpgradePausePoint) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePausePoint.
+func (in *UpgradePausePoint) DeepCopy() *UpgradePausePoint {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePausePoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePausePointStatus) DeepCopyInto(out *UpgradePausePointStatus) {
+	*out = *in
+	in.ApprovedTime.DeepCopyInto(&out.ApprovedTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePausePointStatus.
+func (in *UpgradePausePointStatus) DeepCopy() *UpgradePausePointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePausePointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePolicy) DeepCopyInto(out *UpgradePolicy) {
+	*out = *in
+	if in.PausePoints != nil {
+		in, out := &in.PausePoints, &out.PausePoints
+		*out = make([]UpgradePausePoint, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePolicy.
+func (in *UpgradePolicy) DeepCopy() *UpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
@@ -9860,6 +10865,56 @@ func (in *User) DeepCopy() *User {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerticalAutoScalerSpec) DeepCopyInto(out *VerticalAutoScalerSpec) {
+	*out = *in
+	if in.MinAllowed != nil {
+		in, out := &in.MinAllowed, &out.MinAllowed
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxAllowed != nil {
+		in, out := &in.MaxAllowed, &out.MaxAllowed
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Percentile != nil {
+		in, out := &in.Percentile, &out.Percentile
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetUtilization != nil {
+		in, out := &in.TargetUtilization, &out.TargetUtilization
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Tolerance != nil {
+		in, out := &in.Tolerance, &out.Tolerance
+		*out = new(float64)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticalAutoScalerSpec.
+func (in *VerticalAutoScalerSpec) DeepCopy() *VerticalAutoScalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VerticalAutoScalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkerConfig) DeepCopyInto(out *WorkerConfig) {
 	*out = *in
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/autoscaler/autoscaler/calculate"
 	"github.com/pingcap/tidb-operator/pkg/autoscaler/autoscaler/query"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -75,13 +76,7 @@ func (am *autoScalerManager) Sync(tac *v1alpha1.TidbClusterAutoScaler) error {
 }
 
 func (am *autoScalerManager) syncExternal(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) error {
-	var cfg *v1alpha1.ExternalConfig
-	switch component {
-	case v1alpha1.TiDBMemberType:
-		cfg = tac.Spec.TiDB.External
-	case v1alpha1.TiKVMemberType:
-		cfg = tac.Spec.TiKV.External
-	}
+	cfg := getBasicAutoScalerSpec(tac, component).External
 
 	targetReplicas, err := query.ExternalService(tc, component, cfg.Endpoint, am.deps.SecretLister)
 	if err != nil {
@@ -93,7 +88,39 @@ func (am *autoScalerManager) syncExternal(tc *v1alpha1.TidbCluster, tac *v1alpha
 		targetReplicas = cfg.MaxReplicas
 	}
 
-	return am.syncExternalResult(tc, tac, component, targetReplicas)
+	return am.syncScheduledResult(tc, tac, component, 0, targetReplicas, nil)
+}
+
+func (am *autoScalerManager) syncCustom(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) error {
+	cfg := getBasicAutoScalerSpec(tac, component).Custom
+	endpoint, err := genMetricsEndpoint(tac)
+	if err != nil {
+		return err
+	}
+
+	baseReplicas := getBaseReplicas(tc, component)
+	autoReplicas, err := am.getExternalAutoReplicas(tc, component)
+	if err != nil {
+		return err
+	}
+
+	recommendedReplicas, metrics, err := calculate.CustomRecommendedReplicas(nil, endpoint, cfg.Rules, baseReplicas+autoReplicas)
+	if err != nil {
+		klog.Errorf("tac[%s/%s] failed to calculate the recommended replicas with custom rules for component %s, err: %v", tac.Namespace, tac.Name, component.String(), err)
+		return err
+	}
+
+	// The base cluster is never scaled by the auto-scaler, so the
+	// autoscaling cluster only provides the replicas beyond it
+	targetReplicas := recommendedReplicas - baseReplicas
+	if targetReplicas < 0 {
+		targetReplicas = 0
+	}
+	if targetReplicas > cfg.MaxReplicas {
+		targetReplicas = cfg.MaxReplicas
+	}
+
+	return am.syncScheduledResult(tc, tac, component, 0, targetReplicas, formatMetrics(metrics))
 }
 
 func (am *autoScalerManager) syncPD(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) error {
@@ -105,37 +132,65 @@ func (am *autoScalerManager) syncPD(tc *v1alpha1.TidbCluster, tac *v1alpha1.Tidb
 		return err
 	}
 
-	// Apply auto-scaling plans
-	if err := am.syncPlans(tc, tac, plans, component); err != nil {
+	if tac.Spec.DryRun {
+		if err := am.recommendPlans(tac, plans, component); err != nil {
+			klog.Errorf("tac[%s/%s] cannot record autoscaling plans for component %v err:%v", tac.Namespace, tac.Name, component, err)
+			return err
+		}
+	} else if err := am.syncPlans(tc, tac, plans, component); err != nil {
+		// Apply auto-scaling plans
 		klog.Errorf("tac[%s/%s] cannot apply autoscaling plans for component %v err:%v", tac.Namespace, tac.Name, component, err)
 		return err
 	}
-	return nil
+
+	if len(getBasicAutoScalerSpec(tac, component).Schedules) == 0 {
+		return nil
+	}
+	// The external autoscaling cluster provides the replicas required by the
+	// scheduled policies beyond the ones planned by PD
+	var plannedReplicas int32
+	for _, plan := range plans {
+		if plan.Component == component.String() {
+			plannedReplicas += int32(plan.Count)
+		}
+	}
+	return am.syncScheduledResult(tc, tac, component, plannedReplicas, 0, nil)
 }
 
 func (am *autoScalerManager) syncAutoScaling(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler) error {
 	var errs []error
-	if tac.Spec.TiDB != nil {
-		if tac.Spec.TiDB.External != nil {
-			if err := am.syncExternal(tc, tac, v1alpha1.TiDBMemberType); err != nil {
-				errs = append(errs, err)
-			}
-		} else {
-			if err := am.syncPD(tc, tac, v1alpha1.TiDBMemberType); err != nil {
-				errs = append(errs, err)
-			}
+	for _, component := range autoScalingComponents {
+		spec := getBasicAutoScalerSpec(tac, component)
+		if spec == nil {
+			continue
+		}
+		if getComponentSpec(tc, component) == nil {
+			klog.Errorf("tac[%s/%s] auto-scales component %s, but it does not exist in tc[%s/%s]", tac.Namespace, tac.Name, component.String(), tc.Namespace, tc.Name)
+			continue
 		}
-	}
 
-	if tac.Spec.TiKV != nil {
-		if tac.Spec.TiKV.External != nil {
-			if err := am.syncExternal(tc, tac, v1alpha1.TiKVMemberType); err != nil {
+		if getVerticalAutoScalerSpec(tac, component) != nil {
+			if err := am.syncVertical(tc, tac, component); err != nil {
 				errs = append(errs, err)
 			}
+		}
+		if !hasHorizontalAutoScaling(spec) {
+			continue
+		}
+
+		var err error
+		if spec.External != nil {
+			err = am.syncExternal(tc, tac, component)
+		} else if spec.Custom != nil {
+			err = am.syncCustom(tc, tac, component)
+		} else if len(spec.Rules) == 0 {
+			// Only the scheduled policies are defined
+			err = am.syncScheduledResult(tc, tac, component, 0, 0, nil)
 		} else {
-			if err := am.syncPD(tc, tac, v1alpha1.TiKVMemberType); err != nil {
-				errs = append(errs, err)
-			}
+			err = am.syncPD(tc, tac, component)
+		}
+		if err != nil {
+			errs = append(errs, err)
 		}
 	}
 
@@ -145,23 +200,28 @@ func (am *autoScalerManager) syncAutoScaling(tc *v1alpha1.TidbCluster, tac *v1al
 
 func (am *autoScalerManager) gracefullyDeleteTidbCluster(deleteTc *v1alpha1.TidbCluster) error {
 	// Remove cluster
-	// If there are TiKV pods, delete the cluster gracefully because we need to transfer data
-	if deleteTc.Spec.TiKV != nil {
+	// If there are TiKV or TiFlash pods, delete the cluster gracefully because we need to transfer data.
+	// If there are TiCDC pods, the captures are drained by the TiCDC scaler before the pods are removed.
+	for _, component := range []v1alpha1.MemberType{v1alpha1.TiKVMemberType, v1alpha1.TiFlashMemberType, v1alpha1.TiCDCMemberType} {
+		if getComponentSpec(deleteTc, component) == nil {
+			continue
+		}
+
 		// The TC is not shutting down, set replicas to 0 to trigger data transfer
-		if deleteTc.Spec.TiKV.Replicas != 0 {
+		if getBaseReplicas(deleteTc, component) != 0 {
 			cloned := deleteTc.DeepCopy()
-			cloned.Spec.TiKV.Replicas = 0
+			setReplicas(cloned, component, 0)
 			_, err := am.deps.TiDBClusterControl.UpdateTidbCluster(cloned, &cloned.Status, &deleteTc.Status)
 			return err
 		}
 
 		// The TC is shutting down, check for its status if all pods have been deleted
-		if deleteTc.Status.TiKV.StatefulSet != nil && deleteTc.Status.TiKV.StatefulSet.Replicas != 0 {
+		if getStatefulSetReplicas(deleteTc, component) != 0 {
 			// Still shutting down, do nothing
 			return nil
 		}
 
-		// The TC has scaled in, fall through the code to delete it
+		// The component has scaled in, fall through the code to delete it
 	}
 
 	return am.deps.Clientset.PingcapV1alpha1().TidbClusters(deleteTc.Namespace).Delete(context.TODO(), deleteTc.Name, metav1.DeleteOptions{})
@@ -197,20 +257,47 @@ func (am *autoScalerManager) updateTidbClusterAutoScaler(tac *v1alpha1.TidbClust
 }
 
 func updateLastAutoScalingTimestamp(tac *v1alpha1.TidbClusterAutoScaler, memberType string, group string) {
+	updateBasicAutoScalerStatus(tac, v1alpha1.MemberType(memberType), group, func(status *v1alpha1.BasicAutoScalerStatus) {
+		status.LastAutoScalingTimestamp = &metav1.Time{Time: time.Now()}
+	})
+}
+
+func updateLastScalingPolicy(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.MemberType, group string, policy string) {
+	updateBasicAutoScalerStatus(tac, memberType, group, func(status *v1alpha1.BasicAutoScalerStatus) {
+		status.LastScalingPolicy = policy
+	})
+}
+
+// updateBasicAutoScalerStatus updates the status of the group for the component, the status is created if not existed
+func updateBasicAutoScalerStatus(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.MemberType, group string, update func(status *v1alpha1.BasicAutoScalerStatus)) {
 	switch memberType {
-	case v1alpha1.TiKVMemberType.String():
+	case v1alpha1.TiKVMemberType:
 		if tac.Status.TiKV == nil {
 			tac.Status.TiKV = map[string]v1alpha1.TikvAutoScalerStatus{}
 		}
 		status := tac.Status.TiKV[group]
-		status.LastAutoScalingTimestamp = &metav1.Time{Time: time.Now()}
+		update(&status.BasicAutoScalerStatus)
 		tac.Status.TiKV[group] = status
-	case v1alpha1.TiDBMemberType.String():
+	case v1alpha1.TiDBMemberType:
 		if tac.Status.TiDB == nil {
 			tac.Status.TiDB = map[string]v1alpha1.TidbAutoScalerStatus{}
 		}
 		status := tac.Status.TiDB[group]
-		status.LastAutoScalingTimestamp = &metav1.Time{Time: time.Now()}
+		update(&status.BasicAutoScalerStatus)
 		tac.Status.TiDB[group] = status
+	case v1alpha1.TiFlashMemberType:
+		if tac.Status.TiFlash == nil {
+			tac.Status.TiFlash = map[string]v1alpha1.TiflashAutoScalerStatus{}
+		}
+		status := tac.Status.TiFlash[group]
+		update(&status.BasicAutoScalerStatus)
+		tac.Status.TiFlash[group] = status
+	case v1alpha1.TiCDCMemberType:
+		if tac.Status.TiCDC == nil {
+			tac.Status.TiCDC = map[string]v1alpha1.TicdcAutoScalerStatus{}
+		}
+		status := tac.Status.TiCDC[group]
+		update(&status.BasicAutoScalerStatus)
+		tac.Status.TiCDC[group] = status
 	}
 }
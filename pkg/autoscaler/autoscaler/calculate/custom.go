@@ -0,0 +1,165 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calculate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+const (
+	queryPath           = "/api/v1/query"
+	defaultQueryTimeout = 5 * time.Second
+	statusSuccess       = "success"
+	resultTypeVector    = "vector"
+)
+
+// CustomRecommendedReplicas queries each custom rule against the Prometheus endpoint and
+// returns the max of the recommended replicas calculated by the rules, along with the
+// aggregated metric value of each rule keyed by the rule name.
+// currentReplicas is the total replicas of the component when the metrics are collected.
+func CustomRecommendedReplicas(client *http.Client, endpoint string, rules []v1alpha1.CustomAutoRule, currentReplicas int32) (int32, map[string]float64, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultQueryTimeout}
+	}
+	now := time.Now().Unix()
+	var recommended int32 = -1
+	metrics := make(map[string]float64, len(rules))
+	for _, rule := range rules {
+		sq := &SingleQuery{
+			Endpoint:  endpoint,
+			Timestamp: now,
+			Query:     rule.Query,
+		}
+		values, err := queryMetrics(client, sq)
+		if err != nil {
+			return -1, nil, fmt.Errorf("query metrics for rule %s failed: %v", rule.Name, err)
+		}
+		if len(values) == 0 {
+			return -1, nil, fmt.Errorf("query metrics for rule %s returns no samples", rule.Name)
+		}
+		replicas, value, err := calculateRuleReplicas(rule, values, currentReplicas)
+		if err != nil {
+			return -1, nil, err
+		}
+		metrics[rule.Name] = value
+		if replicas > recommended {
+			recommended = replicas
+		}
+	}
+	return recommended, metrics, nil
+}
+
+// QueryValues sends the instant query to the Prometheus endpoint and returns the values of the samples
+func QueryValues(client *http.Client, endpoint, query string) ([]float64, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultQueryTimeout}
+	}
+	return queryMetrics(client, &SingleQuery{
+		Endpoint:  endpoint,
+		Timestamp: time.Now().Unix(),
+		Query:     query,
+	})
+}
+
+// calculateRuleReplicas calculates the recommended replicas from the samples of a single rule,
+// and returns the aggregated value of the samples.
+func calculateRuleReplicas(rule v1alpha1.CustomAutoRule, values []float64, currentReplicas int32) (int32, float64, error) {
+	if rule.TargetValue <= 0 {
+		return -1, 0, fmt.Errorf("target value of rule %s should be positive", rule.Name)
+	}
+	var sum, max float64
+	for i, v := range values {
+		sum += v
+		if i == 0 || v > max {
+			max = v
+		}
+	}
+
+	var value, recommended float64
+	switch rule.Aggregation {
+	case v1alpha1.CustomRuleAggregationSum:
+		value = sum
+		recommended = sum / rule.TargetValue
+	case v1alpha1.CustomRuleAggregationMax:
+		value = max
+		recommended = float64(currentReplicas) * max / rule.TargetValue
+	case v1alpha1.CustomRuleAggregationAvg, "":
+		value = sum / float64(len(values))
+		recommended = float64(currentReplicas) * value / rule.TargetValue
+	default:
+		return -1, 0, fmt.Errorf("unknown aggregation %s of rule %s", rule.Aggregation, rule.Name)
+	}
+	if math.IsNaN(recommended) || math.IsInf(recommended, 0) {
+		return -1, 0, fmt.Errorf("invalid recommended replicas %v of rule %s", recommended, rule.Name)
+	}
+	return int32(math.Ceil(recommended)), value, nil
+}
+
+// queryMetrics sends an instant query to Prometheus and returns the values of the samples
+func queryMetrics(client *http.Client, sq *SingleQuery) ([]float64, error) {
+	params := url.Values{}
+	params.Set("query", sq.Query)
+	params.Set("time", strconv.FormatInt(sq.Timestamp, 10))
+	u := fmt.Sprintf("%s%s?%s", sq.Endpoint, queryPath, params.Encode())
+
+	r, err := client.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("query from prometheus [%s] failed, response: %v, status code: %v", u, string(body), r.StatusCode)
+	}
+
+	resp := &Response{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != statusSuccess {
+		return nil, fmt.Errorf("query from prometheus [%s] failed, status: %s", u, resp.Status)
+	}
+	if resp.Data.ResultType != resultTypeVector {
+		return nil, fmt.Errorf("query from prometheus [%s] returns unexpected result type %s", u, resp.Data.ResultType)
+	}
+
+	values := make([]float64, 0, len(resp.Data.Result))
+	for _, result := range resp.Data.Result {
+		if len(result.Value) != 2 {
+			return nil, fmt.Errorf("unexpected sample %v of instance %s", result.Value, result.Metric.Instance)
+		}
+		s, ok := result.Value[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected sample value %v of instance %s", result.Value[1], result.Metric.Instance)
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calculate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+func newFakePrometheus(g *GomegaWithT, results map[string][]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Path).Should(Equal(queryPath))
+		values, ok := results[r.URL.Query().Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"error"}`))
+			return
+		}
+		resp := Response{
+			Status: statusSuccess,
+			Data: Data{
+				ResultType: resultTypeVector,
+			},
+		}
+		for i, v := range values {
+			resp.Data.Result = append(resp.Data.Result, Result{
+				Metric: Metric{Instance: string(rune('a' + i))},
+				Value:  []interface{}{1600000000, v},
+			})
+		}
+		data, err := json.Marshal(resp)
+		g.Expect(err).Should(BeNil())
+		w.Write(data)
+	}))
+}
+
+func TestCustomRecommendedReplicas(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	server := newFakePrometheus(g, map[string][]string{
+		"qps":         {"1500", "2500", "2000"},
+		"connections": {"100", "300", "200"},
+		"latency":     {"0.125", "0.5", "0.25"},
+		"empty":       {},
+	})
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		rules           []v1alpha1.CustomAutoRule
+		currentReplicas int32
+		expected        int32
+		expectedMetrics map[string]float64
+		expectErr       bool
+	}{
+		{
+			name: "sum",
+			rules: []v1alpha1.CustomAutoRule{
+				{Name: "qps", Query: "qps", TargetValue: 1000, Aggregation: v1alpha1.CustomRuleAggregationSum},
+			},
+			currentReplicas: 3,
+			expected:        6,
+			expectedMetrics: map[string]float64{"qps": 6000},
+		},
+		{
+			name: "avg",
+			rules: []v1alpha1.CustomAutoRule{
+				{Name: "connections", Query: "connections", TargetValue: 100, Aggregation: v1alpha1.CustomRuleAggregationAvg},
+			},
+			currentReplicas: 3,
+			expected:        6,
+			expectedMetrics: map[string]float64{"connections": 200},
+		},
+		{
+			name: "max",
+			rules: []v1alpha1.CustomAutoRule{
+				{Name: "latency", Query: "latency", TargetValue: 0.25, Aggregation: v1alpha1.CustomRuleAggregationMax},
+			},
+			currentReplicas: 3,
+			expected:        6,
+			expectedMetrics: map[string]float64{"latency": 0.5},
+		},
+		{
+			name: "max of multiple rules",
+			rules: []v1alpha1.CustomAutoRule{
+				{Name: "qps", Query: "qps", TargetValue: 3000, Aggregation: v1alpha1.CustomRuleAggregationSum},
+				{Name: "latency", Query: "latency", TargetValue: 0.5, Aggregation: v1alpha1.CustomRuleAggregationMax},
+				{Name: "connections", Query: "connections", TargetValue: 100, Aggregation: v1alpha1.CustomRuleAggregationAvg},
+			},
+			currentReplicas: 3,
+			expected:        6,
+			expectedMetrics: map[string]float64{"qps": 6000, "latency": 0.5, "connections": 200},
+		},
+		{
+			name: "no samples",
+			rules: []v1alpha1.CustomAutoRule{
+				{Name: "empty", Query: "empty", TargetValue: 1},
+			},
+			currentReplicas: 3,
+			expectErr:       true,
+		},
+		{
+			name: "query failed",
+			rules: []v1alpha1.CustomAutoRule{
+				{Name: "unknown", Query: "unknown", TargetValue: 1},
+			},
+			currentReplicas: 3,
+			expectErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicas, metrics, err := CustomRecommendedReplicas(nil, server.URL, tt.rules, tt.currentReplicas)
+			if tt.expectErr {
+				g.Expect(err).ShouldNot(BeNil())
+				return
+			}
+			g.Expect(err).Should(BeNil())
+			g.Expect(replicas).Should(Equal(tt.expected))
+			g.Expect(metrics).Should(Equal(tt.expectedMetrics))
+		})
+	}
+}
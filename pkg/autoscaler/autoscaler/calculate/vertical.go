@@ -0,0 +1,59 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calculate
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// CPUUsagePercentileMetricsPattern is the max of the cpu usage percentile of the component pods over the window
+	CPUUsagePercentileMetricsPattern = `max(quantile_over_time(%v, rate(container_cpu_usage_seconds_total{namespace="%s",pod=~"%s-%s-[0-9]+",container="%s"}[1m])[%s:1m]))`
+	// MemoryUsagePercentileMetricsPattern is the max of the memory usage percentile of the component pods over the window
+	MemoryUsagePercentileMetricsPattern = `max(quantile_over_time(%v, container_memory_working_set_bytes{namespace="%s",pod=~"%s-%s-[0-9]+",container="%s"}[%s]))`
+)
+
+// UsagePercentile queries the percentile of the cpu and memory usage of the pods of the component over the window.
+// The cpu usage is in cores and the memory usage is in bytes.
+func UsagePercentile(client *http.Client, endpoint, namespace, tcName, component string, percentile float64, window string) (map[corev1.ResourceName]float64, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultQueryTimeout}
+	}
+	now := time.Now().Unix()
+	patterns := map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    CPUUsagePercentileMetricsPattern,
+		corev1.ResourceMemory: MemoryUsagePercentileMetricsPattern,
+	}
+	usage := make(map[corev1.ResourceName]float64, len(patterns))
+	for name, pattern := range patterns {
+		sq := &SingleQuery{
+			Endpoint:  endpoint,
+			Timestamp: now,
+			Query:     fmt.Sprintf(pattern, percentile, namespace, tcName, component, component, window),
+		}
+		values, err := queryMetrics(client, sq)
+		if err != nil {
+			return nil, fmt.Errorf("query %s usage of %s failed: %v", name, component, err)
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("query %s usage of %s returns no samples", name, component)
+		}
+		usage[name] = values[0]
+	}
+	return usage, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calculate
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestUsagePercentile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	server := newFakePrometheus(g, map[string][]string{
+		`max(quantile_over_time(0.9, rate(container_cpu_usage_seconds_total{namespace="ns",pod=~"tc-tikv-[0-9]+",container="tikv"}[1m])[24h:1m]))`: {"1.5"},
+		`max(quantile_over_time(0.9, container_memory_working_set_bytes{namespace="ns",pod=~"tc-tikv-[0-9]+",container="tikv"}[24h]))`:             {"4294967296"},
+		`max(quantile_over_time(0.9, rate(container_cpu_usage_seconds_total{namespace="ns",pod=~"tc-tidb-[0-9]+",container="tidb"}[1m])[24h:1m]))`: {"1"},
+		`max(quantile_over_time(0.9, container_memory_working_set_bytes{namespace="ns",pod=~"tc-tidb-[0-9]+",container="tidb"}[24h]))`:             {},
+	})
+	defer server.Close()
+
+	usage, err := UsagePercentile(nil, server.URL, "ns", "tc", "tikv", 0.9, "24h")
+	g.Expect(err).Should(BeNil())
+	g.Expect(usage).Should(Equal(map[corev1.ResourceName]float64{
+		corev1.ResourceCPU:    1.5,
+		corev1.ResourceMemory: 4294967296,
+	}))
+
+	_, err = UsagePercentile(nil, server.URL, "ns", "tc", "tidb", 0.9, "24h")
+	g.Expect(err).ShouldNot(BeNil())
+}
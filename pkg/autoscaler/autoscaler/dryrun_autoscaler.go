@@ -0,0 +1,166 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultRecommendationHistoryLimit = 10
+	// pdScalingPolicy means the replicas are decided by the PD plans
+	pdScalingPolicy = "pd"
+	// autoScalingRecommendedReason is the event reason of the recommendations in dry-run mode
+	autoScalingRecommendedReason = "AutoScalingRecommended"
+)
+
+// recommendPlans records the recommendations of the PD plans for the component instead of applying them
+func (am *autoScalerManager) recommendPlans(tac *v1alpha1.TidbClusterAutoScaler, plans []pdapi.Plan, component v1alpha1.MemberType) error {
+	tcList, err := am.getAutoScaledClusters(tac, []v1alpha1.MemberType{component})
+	if err != nil {
+		return err
+	}
+	groupTcMap := make(map[string]*v1alpha1.TidbCluster)
+	for _, tc := range tcList {
+		if group, ok := tc.Labels[label.AutoScalingGroupLabelKey]; ok && len(group) > 0 {
+			groupTcMap[group] = tc
+		}
+	}
+
+	now := metav1.Now()
+	planned := make(map[string]struct{}, len(plans))
+	for _, plan := range plans {
+		if plan.Component != component.String() {
+			continue
+		}
+		group := plan.Labels[groupLabelKey]
+		planned[group] = struct{}{}
+
+		var currentReplicas int32
+		if autoTc, ok := groupTcMap[group]; ok {
+			currentReplicas = getBaseReplicas(autoTc, component)
+		}
+		am.recordRecommendation(tac, v1alpha1.AutoScalerRecommendation{
+			Component:       component,
+			Group:           group,
+			ResourceType:    plan.ResourceType,
+			CurrentReplicas: currentReplicas,
+			TargetReplicas:  int32(plan.Count),
+			Policy:          pdScalingPolicy,
+			Timestamp:       now,
+		})
+	}
+
+	// The autoscaling clusters of the groups not in the plans would be deleted
+	groups := make([]string, 0, len(groupTcMap))
+	for group := range groupTcMap {
+		if _, ok := planned[group]; !ok {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		am.recordRecommendation(tac, v1alpha1.AutoScalerRecommendation{
+			Component:       component,
+			Group:           group,
+			CurrentReplicas: getBaseReplicas(groupTcMap[group], component),
+			TargetReplicas:  0,
+			Policy:          pdScalingPolicy,
+			Timestamp:       now,
+		})
+	}
+	return nil
+}
+
+// recommendExternalResult records the recommendation of the external autoscaling cluster instead of applying it
+func (am *autoScalerManager) recommendExternalResult(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, targetReplicas int32, policy string, metrics map[string]string) error {
+	currentReplicas, err := am.getExternalAutoReplicas(tc, component)
+	if err != nil {
+		return err
+	}
+	am.recordRecommendation(tac, v1alpha1.AutoScalerRecommendation{
+		Component:       component,
+		Group:           externalStatusKey,
+		CurrentReplicas: currentReplicas,
+		TargetReplicas:  targetReplicas,
+		Policy:          policy,
+		Metrics:         metrics,
+		Timestamp:       metav1.Now(),
+	})
+	return nil
+}
+
+// recordRecommendation appends the recommendation to the bounded history in the status and emits an event.
+// The recommendation is ignored if it scales nothing or it is the same as the latest one of the group.
+func (am *autoScalerManager) recordRecommendation(tac *v1alpha1.TidbClusterAutoScaler, rec v1alpha1.AutoScalerRecommendation) {
+	if rec.CurrentReplicas == rec.TargetReplicas {
+		return
+	}
+	if latest := latestRecommendation(tac, rec.Component, rec.Group); latest != nil &&
+		latest.CurrentReplicas == rec.CurrentReplicas &&
+		latest.TargetReplicas == rec.TargetReplicas &&
+		latest.ResourceType == rec.ResourceType &&
+		latest.Policy == rec.Policy {
+		return
+	}
+
+	limit := defaultRecommendationHistoryLimit
+	if tac.Spec.RecommendationHistoryLimit != nil {
+		limit = int(*tac.Spec.RecommendationHistoryLimit)
+	}
+	history := append(tac.Status.Recommendations, rec)
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	tac.Status.Recommendations = history
+
+	msg := fmt.Sprintf("recommend scaling %s group %s from %d to %d replicas by policy %s", rec.Component, rec.Group, rec.CurrentReplicas, rec.TargetReplicas, rec.Policy)
+	if len(rec.ResourceType) > 0 {
+		msg += fmt.Sprintf(" with resource type %s", rec.ResourceType)
+	}
+	klog.Infof("tac[%s/%s] %s in dry-run mode", tac.Namespace, tac.Name, msg)
+	am.deps.Recorder.Event(tac, corev1.EventTypeNormal, autoScalingRecommendedReason, msg)
+}
+
+// latestRecommendation returns the latest recommendation of the group for the component, nil if not found
+func latestRecommendation(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, group string) *v1alpha1.AutoScalerRecommendation {
+	for i := len(tac.Status.Recommendations) - 1; i >= 0; i-- {
+		rec := &tac.Status.Recommendations[i]
+		if rec.Component == component && rec.Group == group {
+			return rec
+		}
+	}
+	return nil
+}
+
+// formatMetrics formats the metric values to be recorded in the recommendations
+func formatMetrics(metrics map[string]float64) map[string]string {
+	if len(metrics) == 0 {
+		return nil
+	}
+	formatted := make(map[string]string, len(metrics))
+	for name, value := range metrics {
+		formatted[name] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return formatted
+}
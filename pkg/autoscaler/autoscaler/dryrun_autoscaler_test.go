@@ -0,0 +1,111 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+)
+
+func TestDryRunPDPlans(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewFakeDependencies()
+	am := NewAutoScalerManager(deps)
+
+	tc := newTidbCluster()
+	tac := newTidbClusterAutoScaler()
+	tac.Spec.TiDB = nil
+	tac.Spec.DryRun = true
+	tac.Spec.TiKV.Rules = map[corev1.ResourceName]v1alpha1.AutoRule{
+		"cpu": {MaxThreshold: 0.8},
+	}
+	defaultTAC(tac, tc)
+	g.Expect(validateTAC(tac)).Should(Succeed())
+
+	// The group "obsolete" is not in the plans any more
+	obsoleteTc := newAutoScalingCluster(tc, tac, "auto-obsolete", v1alpha1.TiKVMemberType.String())
+	obsoleteTc.Labels[label.AutoScalingGroupLabelKey] = "obsolete"
+	obsoleteTc.Spec.TiKV.Replicas = 2
+	g.Expect(deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Add(obsoleteTc)).Should(Succeed())
+
+	pdClient := pdapi.NewFakePDClient()
+	pdClient.AddReaction(pdapi.GetAutoscalingPlansActionType, func(action *pdapi.Action) (interface{}, error) {
+		return []pdapi.Plan{
+			{Component: v1alpha1.TiKVMemberType.String(), Count: 3, ResourceType: "default_tikv", Labels: map[string]string{groupLabelKey: "hot"}},
+		}, nil
+	})
+	deps.PDControl.(*pdapi.FakePDControl).SetPDClient(pdapi.Namespace(tc.Namespace), tc.Name, pdClient)
+
+	g.Expect(am.syncPD(tc, tac, v1alpha1.TiKVMemberType)).Should(Succeed())
+
+	// No autoscaling cluster is touched
+	tcs, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).List(context.TODO(), metav1.ListOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(tcs.Items).Should(BeEmpty())
+
+	g.Expect(tac.Status.Recommendations).Should(HaveLen(2))
+	g.Expect(tac.Status.Recommendations[0].Group).Should(Equal("hot"))
+	g.Expect(tac.Status.Recommendations[0].ResourceType).Should(Equal("default_tikv"))
+	g.Expect(tac.Status.Recommendations[0].CurrentReplicas).Should(Equal(int32(0)))
+	g.Expect(tac.Status.Recommendations[0].TargetReplicas).Should(Equal(int32(3)))
+	g.Expect(tac.Status.Recommendations[0].Policy).Should(Equal(pdScalingPolicy))
+	g.Expect(tac.Status.Recommendations[1].Group).Should(Equal("obsolete"))
+	g.Expect(tac.Status.Recommendations[1].CurrentReplicas).Should(Equal(int32(2)))
+	g.Expect(tac.Status.Recommendations[1].TargetReplicas).Should(Equal(int32(0)))
+	g.Expect(deps.Recorder.(*record.FakeRecorder).Events).Should(HaveLen(2))
+
+	// The same recommendations are not recorded again
+	g.Expect(am.syncPD(tc, tac, v1alpha1.TiKVMemberType)).Should(Succeed())
+	g.Expect(tac.Status.Recommendations).Should(HaveLen(2))
+	g.Expect(deps.Recorder.(*record.FakeRecorder).Events).Should(HaveLen(2))
+}
+
+func TestRecordRecommendation(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewFakeDependencies()
+	am := NewAutoScalerManager(deps)
+
+	tac := newTidbClusterAutoScaler()
+	tac.Spec.RecommendationHistoryLimit = pointer.Int32Ptr(2)
+
+	// Nothing to scale
+	am.recordRecommendation(tac, v1alpha1.AutoScalerRecommendation{Component: v1alpha1.TiDBMemberType, Group: externalStatusKey, CurrentReplicas: 1, TargetReplicas: 1})
+	g.Expect(tac.Status.Recommendations).Should(BeEmpty())
+
+	for _, target := range []int32{2, 3, 4} {
+		am.recordRecommendation(tac, v1alpha1.AutoScalerRecommendation{
+			Component:       v1alpha1.TiDBMemberType,
+			Group:           externalStatusKey,
+			CurrentReplicas: 1,
+			TargetReplicas:  target,
+			Policy:          metricsScalingPolicy,
+			Metrics:         map[string]string{"qps": "1000"},
+		})
+	}
+	g.Expect(tac.Status.Recommendations).Should(HaveLen(2))
+	g.Expect(tac.Status.Recommendations[0].TargetReplicas).Should(Equal(int32(3)))
+	g.Expect(tac.Status.Recommendations[1].TargetReplicas).Should(Equal(int32(4)))
+	g.Expect(tac.Status.Recommendations[1].Metrics).Should(Equal(map[string]string{"qps": "1000"}))
+	g.Expect(deps.Recorder.(*record.FakeRecorder).Events).Should(HaveLen(3))
+}
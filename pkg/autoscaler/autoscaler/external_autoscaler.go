@@ -46,6 +46,13 @@ func (am *autoScalerManager) syncExternalResult(tc *v1alpha1.TidbCluster, tac *v
 		return err
 	}
 
+	if component == v1alpha1.TiFlashMemberType {
+		targetReplicas, err = am.limitTiFlashScaleIn(tc, tac, externalTc, targetReplicas)
+		if err != nil {
+			return err
+		}
+	}
+
 	if targetReplicas <= 0 {
 		err := am.gracefullyDeleteTidbCluster(externalTc)
 		if err != nil {
@@ -53,27 +60,32 @@ func (am *autoScalerManager) syncExternalResult(tc *v1alpha1.TidbCluster, tac *v
 			return err
 		}
 
-		switch component {
-		case v1alpha1.TiDBMemberType:
-			delete(tac.Status.TiDB, externalStatusKey)
-		case v1alpha1.TiKVMemberType:
-			delete(tac.Status.TiKV, externalStatusKey)
-		}
-
+		deleteBasicAutoScalerStatus(tac, component, externalStatusKey)
 		return nil
 	}
 
 	return am.updateExternalAutoCluster(externalTc, tac, component, targetReplicas)
 }
 
+// getExternalAutoReplicas returns the replicas of the external autoscaling cluster, 0 if it does not exist
+func (am *autoScalerManager) getExternalAutoReplicas(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) (int32, error) {
+	externalTcName := fmt.Sprintf(externalTcNamePattern, tc.ClusterName, component.String())
+	externalTc, err := am.deps.TiDBClusterLister.TidbClusters(tc.Namespace).Get(externalTcName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return getBaseReplicas(externalTc, component), nil
+}
+
 func (am *autoScalerManager) createExternalAutoCluster(tc *v1alpha1.TidbCluster, externalTcName string, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, targetReplicas int32) error {
 	autoTc := newAutoScalingCluster(tc, tac, externalTcName, component.String())
 
+	setReplicas(autoTc, component, targetReplicas)
 	switch component {
-	case v1alpha1.TiDBMemberType:
-		autoTc.Spec.TiDB.Replicas = targetReplicas
 	case v1alpha1.TiKVMemberType:
-		autoTc.Spec.TiKV.Replicas = targetReplicas
 		autoTc.Spec.TiKV.Config.Set("server.labels."+specialUseLabelKey, specialUseHotRegion)
 	}
 
@@ -89,26 +101,15 @@ func (am *autoScalerManager) createExternalAutoCluster(tc *v1alpha1.TidbCluster,
 
 func (am *autoScalerManager) updateExternalAutoCluster(externalTc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, targetReplicas int32) error {
 	updated := externalTc.DeepCopy()
-	switch component {
-	case v1alpha1.TiDBMemberType:
-		if updated.Spec.TiDB.Replicas == targetReplicas {
-			return nil
-		}
-
-		if !checkAutoScaling(tac, component, externalStatusKey, updated.Spec.TiDB.Replicas, targetReplicas) {
-			return nil
-		}
-		updated.Spec.TiDB.Replicas = targetReplicas
-	case v1alpha1.TiKVMemberType:
-		if updated.Spec.TiKV.Replicas == targetReplicas {
-			return nil
-		}
+	currentReplicas := getBaseReplicas(updated, component)
+	if currentReplicas == targetReplicas {
+		return nil
+	}
 
-		if !checkAutoScaling(tac, component, externalStatusKey, updated.Spec.TiKV.Replicas, targetReplicas) {
-			return nil
-		}
-		updated.Spec.TiKV.Replicas = targetReplicas
+	if !checkAutoScaling(tac, component, externalStatusKey, currentReplicas, targetReplicas) {
+		return nil
 	}
+	setReplicas(updated, component, targetReplicas)
 
 	_, err := am.deps.TiDBClusterControl.UpdateTidbCluster(updated, &updated.Status, &externalTc.Status)
 	if err != nil {
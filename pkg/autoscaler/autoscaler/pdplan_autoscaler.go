@@ -91,7 +91,7 @@ func (am *autoScalerManager) syncPlans(tc *v1alpha1.TidbCluster, tac *v1alpha1.T
 	}
 
 	toUpdate := planGroups.Intersection(existedGroups)
-	err = am.updateAutoscalingClusters(tac, toUpdate.UnsortedList(), groupTcMap, groupPlanMap)
+	err = am.updateAutoscalingClusters(tc, tac, toUpdate.UnsortedList(), groupTcMap, groupPlanMap)
 	if err != nil {
 		return err
 	}
@@ -109,6 +109,19 @@ func (am *autoScalerManager) deleteAutoscalingClusters(tac *v1alpha1.TidbCluster
 	var errs []error
 	for _, group := range groupsToDelete {
 		deleteTc := groupTcMap[group]
+		component := v1alpha1.MemberType(deleteTc.Labels[label.AutoComponentLabelKey])
+
+		if component == v1alpha1.TiFlashMemberType {
+			targetReplicas, err := am.limitTiFlashScaleIn(tc, tac, deleteTc, 0)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if targetReplicas > 0 {
+				klog.Infof("tac[%s/%s] keeps tc[%s/%s] for group %s to respect the tiflash replicas of tables", tac.Namespace, tac.Name, deleteTc.Namespace, deleteTc.Name, group)
+				continue
+			}
+		}
 
 		err := am.gracefullyDeleteTidbCluster(deleteTc)
 		if err != nil {
@@ -116,42 +129,45 @@ func (am *autoScalerManager) deleteAutoscalingClusters(tac *v1alpha1.TidbCluster
 			continue
 		}
 
-		if deleteTc.Spec.TiDB != nil {
-			delete(tac.Status.TiDB, group)
-		} else if deleteTc.Spec.TiKV != nil {
-			delete(tac.Status.TiKV, group)
-		}
+		deleteBasicAutoScalerStatus(tac, component, group)
 	}
 	return errorutils.NewAggregate(errs)
 }
 
-func (am *autoScalerManager) updateAutoscalingClusters(tac *v1alpha1.TidbClusterAutoScaler, groupsToUpdate []string, groupTcMap map[string]*v1alpha1.TidbCluster, groupPlanMap map[string]pdapi.Plan) error {
+func (am *autoScalerManager) updateAutoscalingClusters(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, groupsToUpdate []string, groupTcMap map[string]*v1alpha1.TidbCluster, groupPlanMap map[string]pdapi.Plan) error {
 	var errs []error
 	for _, group := range groupsToUpdate {
 		actual, oldTc, plan := groupTcMap[group].DeepCopy(), groupTcMap[group], groupPlanMap[group]
+		component := v1alpha1.MemberType(plan.Component)
 
-		switch plan.Component {
-		case v1alpha1.TiKVMemberType.String():
-			if tac.Spec.TiKV == nil || actual.Spec.TiKV.Replicas == int32(plan.Count) {
-				continue
-			}
-			if !checkAutoScaling(tac, v1alpha1.TiKVMemberType, group, actual.Spec.TiKV.Replicas, int32(plan.Count)) {
-				continue
-			}
-			actual.Spec.TiKV.Replicas = int32(plan.Count)
-		case v1alpha1.TiDBMemberType.String():
-			if tac.Spec.TiDB == nil || actual.Spec.TiDB.Replicas == int32(plan.Count) {
-				continue
-			}
-			if !checkAutoScaling(tac, v1alpha1.TiDBMemberType, group, actual.Spec.TiDB.Replicas, int32(plan.Count)) {
-				continue
-			}
-			actual.Spec.TiDB.Replicas = int32(plan.Count)
+		switch component {
+		case v1alpha1.TiKVMemberType, v1alpha1.TiDBMemberType, v1alpha1.TiFlashMemberType, v1alpha1.TiCDCMemberType:
 		default:
 			errs = append(errs, fmt.Errorf("unexpected component %s for group %s in autoscaling plan", plan.Component, group))
 			continue
 		}
 
+		targetReplicas := int32(plan.Count)
+		if getBasicAutoScalerSpec(tac, component) == nil || getComponentSpec(actual, component) == nil {
+			continue
+		}
+		if component == v1alpha1.TiFlashMemberType {
+			var err error
+			targetReplicas, err = am.limitTiFlashScaleIn(tc, tac, actual, targetReplicas)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		currentReplicas := getBaseReplicas(actual, component)
+		if currentReplicas == targetReplicas {
+			continue
+		}
+		if !checkAutoScaling(tac, component, group, currentReplicas, targetReplicas) {
+			continue
+		}
+		setReplicas(actual, component, targetReplicas)
+
 		_, err := am.deps.TiDBClusterControl.UpdateTidbCluster(actual, &actual.Status, &oldTc.Status)
 		if err != nil {
 			klog.Errorf("tac[%s/%s] failed to update tc[%s/%s] for group %s, err: %v", tac.Namespace, tac.Name, actual.Namespace, actual.Name, group, err)
@@ -224,6 +240,28 @@ func (am *autoScalerManager) createAutoscalingClusters(tc *v1alpha1.TidbCluster,
 			for k, v := range plan.Labels {
 				autoTc.Spec.TiDB.Config.Set("labels."+k, v)
 			}
+		case v1alpha1.TiFlashMemberType.String():
+			autoTc.Spec.TiFlash.Replicas = int32(plan.Count)
+			autoTc.Spec.TiFlash.ResourceRequirements = corev1.ResourceRequirements{
+				Limits:   limitsResourceList,
+				Requests: requestsResourceList,
+			}
+			if len(autoTc.Spec.TiFlash.StorageClaims) > 0 && resource.Storage.Cmp(zeroQuantity) > 0 {
+				autoTc.Spec.TiFlash.StorageClaims[0].Resources.Requests = corev1.ResourceList{
+					corev1.ResourceStorage: resource.Storage,
+				}
+			}
+
+			// Assign Plan Labels
+			for k, v := range plan.Labels {
+				autoTc.Spec.TiFlash.Config.Proxy.Set("server.labels."+k, v)
+			}
+		case v1alpha1.TiCDCMemberType.String():
+			autoTc.Spec.TiCDC.Replicas = int32(plan.Count)
+			autoTc.Spec.TiCDC.ResourceRequirements = corev1.ResourceRequirements{
+				Limits:   limitsResourceList,
+				Requests: requestsResourceList,
+			}
 		}
 
 		_, err = am.deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), autoTc, metav1.CreateOptions{})
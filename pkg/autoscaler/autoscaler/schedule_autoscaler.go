@@ -0,0 +1,131 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/robfig/cron"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// metricsScalingPolicy means the replicas are decided by the metric rules
+	metricsScalingPolicy = "metrics"
+	// scheduleScalingPolicyPrefix is the prefix of the scheduled policy which decides the replicas
+	scheduleScalingPolicyPrefix = "schedule/"
+)
+
+// syncScheduledResult combines the replicas recommended by the metric rules with the active scheduled
+// policy, then syncs the external autoscaling cluster.
+// autoReplicas is the replicas of the other autoscaling clusters, targetReplicas is the replicas
+// of the external autoscaling cluster recommended by the metric rules, metrics are the metric
+// values which the recommendation is based on.
+func (am *autoScalerManager) syncScheduledResult(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, autoReplicas, targetReplicas int32, metrics map[string]string) error {
+	spec := getBasicAutoScalerSpec(tac, component)
+	if len(spec.Schedules) == 0 {
+		if tac.Spec.DryRun {
+			return am.recommendExternalResult(tc, tac, component, targetReplicas, metricsScalingPolicy, metrics)
+		}
+		return am.syncExternalResult(tc, tac, component, targetReplicas)
+	}
+
+	baseReplicas := getBaseReplicas(tc, component)
+	replicas, policy, err := applySchedules(spec.Schedules, baseReplicas+autoReplicas+targetReplicas, time.Now())
+	if err != nil {
+		klog.Errorf("tac[%s/%s] failed to apply the scheduled policies for component %s, err: %v", tac.Namespace, tac.Name, component.String(), err)
+		return err
+	}
+
+	targetReplicas = replicas - baseReplicas - autoReplicas
+	if targetReplicas < 0 {
+		targetReplicas = 0
+	}
+	if tac.Spec.DryRun {
+		return am.recommendExternalResult(tc, tac, component, targetReplicas, policy, metrics)
+	}
+	if err := am.syncExternalResult(tc, tac, component, targetReplicas); err != nil {
+		return err
+	}
+	if targetReplicas > 0 {
+		updateLastScalingPolicy(tac, component, externalStatusKey, policy)
+	}
+	return nil
+}
+
+// applySchedules returns the total replicas of the component when the active scheduled policy is applied
+// to the replicas recommended by the metric rules, and the policy which decides the replicas
+func applySchedules(policies []v1alpha1.ScheduledScalingPolicy, recommendedReplicas int32, now time.Time) (int32, string, error) {
+	policy, err := activeSchedulePolicy(policies, now)
+	if err != nil {
+		return recommendedReplicas, metricsScalingPolicy, err
+	}
+	if policy == nil {
+		return recommendedReplicas, metricsScalingPolicy, nil
+	}
+
+	replicas, fired := recommendedReplicas, metricsScalingPolicy
+	if policy.Replicas > replicas {
+		replicas, fired = policy.Replicas, scheduleScalingPolicyPrefix+policy.Name
+	}
+	if policy.MaxReplicas != nil && replicas > *policy.MaxReplicas {
+		replicas, fired = *policy.MaxReplicas, scheduleScalingPolicyPrefix+policy.Name
+	}
+	if policy.MinReplicas != nil && replicas < *policy.MinReplicas {
+		replicas, fired = *policy.MinReplicas, scheduleScalingPolicyPrefix+policy.Name
+	}
+	return replicas, fired, nil
+}
+
+// activeSchedulePolicy returns the active policy with the highest replicas, nil if no policy is active
+func activeSchedulePolicy(policies []v1alpha1.ScheduledScalingPolicy, now time.Time) (*v1alpha1.ScheduledScalingPolicy, error) {
+	var active *v1alpha1.ScheduledScalingPolicy
+	for i := range policies {
+		policy := &policies[i]
+		ok, err := isSchedulePolicyActive(policy, now)
+		if err != nil {
+			return nil, err
+		}
+		if ok && (active == nil || policy.Replicas > active.Replicas) {
+			active = policy
+		}
+	}
+	return active, nil
+}
+
+// isSchedulePolicyActive checks whether the time window of the policy covers now,
+// that is, the window begins in (now - duration, now]
+func isSchedulePolicyActive(policy *v1alpha1.ScheduledScalingPolicy, now time.Time) (bool, error) {
+	sched, err := cron.ParseStandard(policy.Schedule)
+	if err != nil {
+		return false, fmt.Errorf("parse schedule %s of policy %s failed, err: %v", policy.Schedule, policy.Name, err)
+	}
+	duration, err := time.ParseDuration(policy.Duration)
+	if err != nil {
+		return false, fmt.Errorf("parse duration %s of policy %s failed, err: %v", policy.Duration, policy.Name, err)
+	}
+	loc := time.UTC
+	if len(policy.TimeZone) > 0 {
+		loc, err = time.LoadLocation(policy.TimeZone)
+		if err != nil {
+			return false, fmt.Errorf("load time zone %s of policy %s failed, err: %v", policy.TimeZone, policy.Name, err)
+		}
+	}
+
+	now = now.In(loc)
+	begin := sched.Next(now.Add(-duration))
+	return !begin.After(now), nil
+}
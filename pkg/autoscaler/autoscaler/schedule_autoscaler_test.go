@@ -0,0 +1,176 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestIsSchedulePolicyActive(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("time zone data is not available: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		policy   v1alpha1.ScheduledScalingPolicy
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "before the window",
+			policy:   v1alpha1.ScheduledScalingPolicy{Name: "batch", Schedule: "0 1 * * *", Duration: "2h"},
+			now:      time.Date(2023, 5, 1, 0, 59, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "at the beginning of the window",
+			policy:   v1alpha1.ScheduledScalingPolicy{Name: "batch", Schedule: "0 1 * * *", Duration: "2h"},
+			now:      time.Date(2023, 5, 1, 1, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "in the window",
+			policy:   v1alpha1.ScheduledScalingPolicy{Name: "batch", Schedule: "0 1 * * *", Duration: "2h"},
+			now:      time.Date(2023, 5, 1, 2, 30, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "at the end of the window",
+			policy:   v1alpha1.ScheduledScalingPolicy{Name: "batch", Schedule: "0 1 * * *", Duration: "2h"},
+			now:      time.Date(2023, 5, 1, 3, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "in the window of the time zone",
+			policy:   v1alpha1.ScheduledScalingPolicy{Name: "batch", Schedule: "0 9 * * *", Duration: "1h", TimeZone: "Asia/Shanghai"},
+			now:      time.Date(2023, 5, 1, 9, 30, 0, 0, shanghai),
+			expected: true,
+		},
+		{
+			name:     "out of the window of the time zone",
+			policy:   v1alpha1.ScheduledScalingPolicy{Name: "batch", Schedule: "0 9 * * *", Duration: "1h", TimeZone: "Asia/Shanghai"},
+			now:      time.Date(2023, 5, 1, 9, 30, 0, 0, time.UTC),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			active, err := isSchedulePolicyActive(&tt.policy, tt.now)
+			g.Expect(err).Should(BeNil())
+			g.Expect(active).Should(Equal(tt.expected))
+		})
+	}
+}
+
+func TestApplySchedules(t *testing.T) {
+	now := time.Date(2023, 5, 1, 2, 0, 0, 0, time.UTC)
+	policies := []v1alpha1.ScheduledScalingPolicy{
+		{Name: "batch", Schedule: "0 1 * * *", Duration: "2h", Replicas: 5, MaxReplicas: pointer.Int32Ptr(8)},
+		{Name: "report", Schedule: "30 1 * * *", Duration: "1h", Replicas: 6},
+		{Name: "night", Schedule: "0 22 * * *", Duration: "1h", Replicas: 10},
+	}
+
+	tests := []struct {
+		name             string
+		policies         []v1alpha1.ScheduledScalingPolicy
+		recommended      int32
+		expectedReplicas int32
+		expectedPolicy   string
+	}{
+		{
+			name:             "no active policy",
+			policies:         policies[2:],
+			recommended:      3,
+			expectedReplicas: 3,
+			expectedPolicy:   metricsScalingPolicy,
+		},
+		{
+			name:             "schedule wins",
+			policies:         policies[:1],
+			recommended:      3,
+			expectedReplicas: 5,
+			expectedPolicy:   "schedule/batch",
+		},
+		{
+			name:             "metrics wins",
+			policies:         policies[:1],
+			recommended:      7,
+			expectedReplicas: 7,
+			expectedPolicy:   metricsScalingPolicy,
+		},
+		{
+			name:             "metrics is bounded",
+			policies:         policies[:1],
+			recommended:      9,
+			expectedReplicas: 8,
+			expectedPolicy:   "schedule/batch",
+		},
+		{
+			name:             "the active policy with the highest replicas is taken",
+			policies:         policies,
+			recommended:      3,
+			expectedReplicas: 6,
+			expectedPolicy:   "schedule/report",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			replicas, policy, err := applySchedules(tt.policies, tt.recommended, now)
+			g.Expect(err).Should(BeNil())
+			g.Expect(replicas).Should(Equal(tt.expectedReplicas))
+			g.Expect(policy).Should(Equal(tt.expectedPolicy))
+		})
+	}
+}
+
+func TestSyncScheduledResult(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewFakeDependencies()
+	am := NewAutoScalerManager(deps)
+
+	tc := newTidbCluster()
+	tc.Spec.TiDB.Replicas = 2
+	tac := newTidbClusterAutoScaler()
+	tac.Spec.TiKV = nil
+	tac.Spec.TiDB.Schedules = []v1alpha1.ScheduledScalingPolicy{
+		{Name: "always", Schedule: "* * * * *", Duration: "2m", Replicas: 5},
+	}
+	defaultTAC(tac, tc)
+	g.Expect(validateTAC(tac)).Should(Succeed())
+
+	err := am.syncScheduledResult(tc, tac, v1alpha1.TiDBMemberType, 0, 1, nil)
+	g.Expect(err).Should(BeNil())
+
+	externalTcName := fmt.Sprintf(externalTcNamePattern, tc.ClusterName, v1alpha1.TiDBMemberType.String())
+	externalTc, err := deps.Clientset.PingcapV1alpha1().TidbClusters(tc.Namespace).Get(context.TODO(), externalTcName, metav1.GetOptions{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(externalTc.Spec.TiDB.Replicas).Should(Equal(int32(3)))
+	g.Expect(tac.Status.TiDB[externalStatusKey].LastScalingPolicy).Should(Equal("schedule/always"))
+	g.Expect(tac.Status.TiDB[externalStatusKey].LastAutoScalingTimestamp).ShouldNot(BeNil())
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"k8s.io/klog/v2"
+)
+
+// tiflashRuleGroup is the placement rule group which PD uses to place the TiFlash replicas of tables
+const tiflashRuleGroup = "tiflash"
+
+// limitTiFlashScaleIn limits the target replicas of the autoscaling TiFlash cluster autoTc,
+// so that the TiFlash stores left in the whole cluster are not fewer than the max TiFlash
+// replica count of the tables, otherwise the TiFlash replicas could never be placed.
+func (am *autoScalerManager) limitTiFlashScaleIn(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, autoTc *v1alpha1.TidbCluster, targetReplicas int32) (int32, error) {
+	currentReplicas := getBaseReplicas(autoTc, v1alpha1.TiFlashMemberType)
+	if targetReplicas >= currentReplicas {
+		return targetReplicas, nil
+	}
+
+	rules, err := controller.GetPDClient(am.deps.PDControl, tc).GetPlacementRules(tiflashRuleGroup)
+	if err != nil {
+		klog.Errorf("tac[%s/%s] failed to get the tiflash placement rules, err: %v", tac.Namespace, tac.Name, err)
+		return currentReplicas, err
+	}
+	var maxReplicas int32
+	for _, rule := range rules {
+		if int32(rule.Count) > maxReplicas {
+			maxReplicas = int32(rule.Count)
+		}
+	}
+
+	tcList, err := am.getAutoScaledClusters(tac, []v1alpha1.MemberType{v1alpha1.TiFlashMemberType})
+	if err != nil {
+		return currentReplicas, err
+	}
+	otherReplicas := getBaseReplicas(tc, v1alpha1.TiFlashMemberType)
+	for _, other := range tcList {
+		if other.Name == autoTc.Name {
+			continue
+		}
+		otherReplicas += getBaseReplicas(other, v1alpha1.TiFlashMemberType)
+	}
+
+	if otherReplicas+targetReplicas < maxReplicas {
+		limited := maxReplicas - otherReplicas
+		if limited > currentReplicas {
+			limited = currentReplicas
+		}
+		klog.Infof("tac[%s/%s] limits the replicas of tiflash tc[%s/%s] from %d to %d, max tiflash replicas of tables: %d", tac.Namespace, tac.Name, autoTc.Namespace, autoTc.Name, targetReplicas, limited, maxReplicas)
+		targetReplicas = limited
+	}
+	return targetReplicas, nil
+}
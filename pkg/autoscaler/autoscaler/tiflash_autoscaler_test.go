@@ -0,0 +1,116 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+)
+
+func TestLimitTiFlashScaleIn(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxTableReplica int
+		baseReplicas    int32
+		otherReplicas   int32
+		currentReplicas int32
+		targetReplicas  int32
+		expected        int32
+	}{
+		{
+			name:            "scale out is not limited",
+			maxTableReplica: 3,
+			baseReplicas:    1,
+			currentReplicas: 1,
+			targetReplicas:  3,
+			expected:        3,
+		},
+		{
+			name:            "scale in is not limited",
+			maxTableReplica: 2,
+			baseReplicas:    1,
+			currentReplicas: 3,
+			targetReplicas:  1,
+			expected:        1,
+		},
+		{
+			name:            "scale in is limited by table replicas",
+			maxTableReplica: 3,
+			baseReplicas:    1,
+			currentReplicas: 3,
+			targetReplicas:  0,
+			expected:        2,
+		},
+		{
+			name:            "scale in is limited with other autoscaling clusters",
+			maxTableReplica: 3,
+			baseReplicas:    1,
+			otherReplicas:   1,
+			currentReplicas: 3,
+			targetReplicas:  0,
+			expected:        1,
+		},
+		{
+			name:            "never scale out when limited",
+			maxTableReplica: 5,
+			baseReplicas:    1,
+			currentReplicas: 2,
+			targetReplicas:  1,
+			expected:        2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			deps := controller.NewFakeDependencies()
+			am := NewAutoScalerManager(deps)
+
+			tac := newTidbClusterAutoScaler()
+			tac.Spec.TiFlash = &v1alpha1.TiflashAutoScalerSpec{}
+			tc := newTidbCluster()
+			tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{Replicas: tt.baseReplicas}
+
+			pdClient := pdapi.NewFakePDClient()
+			pdClient.AddReaction(pdapi.GetPlacementRulesActionType, func(action *pdapi.Action) (interface{}, error) {
+				g.Expect(action.Name).Should(Equal(tiflashRuleGroup))
+				return []*pdapi.PlacementRule{
+					{GroupID: tiflashRuleGroup, ID: "table-1-r", Role: "learner", Count: 1},
+					{GroupID: tiflashRuleGroup, ID: "table-2-r", Role: "learner", Count: tt.maxTableReplica},
+				}, nil
+			})
+			deps.PDControl.(*pdapi.FakePDControl).SetPDClient(pdapi.Namespace(tc.Namespace), tc.Name, pdClient)
+
+			autoTc := newAutoScalingCluster(tc, tac, "auto-tiflash", v1alpha1.TiFlashMemberType.String())
+			autoTc.Spec.TiFlash.Replicas = tt.currentReplicas
+			indexer := deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer()
+			g.Expect(indexer.Add(autoTc)).Should(Succeed())
+			if tt.otherReplicas > 0 {
+				otherTc := newAutoScalingCluster(tc, tac, "auto-tiflash-other", v1alpha1.TiFlashMemberType.String())
+				otherTc.Spec.TiFlash.Replicas = tt.otherReplicas
+				g.Expect(otherTc.Labels[label.AutoComponentLabelKey]).Should(Equal(v1alpha1.TiFlashMemberType.String()))
+				g.Expect(indexer.Add(otherTc)).Should(Succeed())
+			}
+
+			replicas, err := am.limitTiFlashScaleIn(tc, tac, autoTc, tt.targetReplicas)
+			g.Expect(err).Should(BeNil())
+			g.Expect(replicas).Should(Equal(tt.expected))
+		})
+	}
+}
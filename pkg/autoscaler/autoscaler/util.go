@@ -17,12 +17,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/prometheus/common/model"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,42 +35,35 @@ import (
 
 var zeroQuantity = resource.MustParse("0")
 
+// autoScalingComponents are the components that can be auto-scaled, in the order of reconciliation
+var autoScalingComponents = []v1alpha1.MemberType{
+	v1alpha1.TiDBMemberType,
+	v1alpha1.TiKVMemberType,
+	v1alpha1.TiFlashMemberType,
+	v1alpha1.TiCDCMemberType,
+}
+
 // checkAutoScaling would check whether an autoscaling for a group is permitted
 func checkAutoScaling(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.MemberType, group string, beforeReplicas, afterReplicas int32) bool {
+	spec := getBasicAutoScalerSpec(tac, memberType)
+	if spec == nil {
+		return true
+	}
 	if beforeReplicas > afterReplicas {
-		switch memberType {
-		case v1alpha1.TiKVMemberType:
-			return checkAutoScalingInterval(tac, *tac.Spec.TiKV.ScaleInIntervalSeconds, memberType, group)
-		case v1alpha1.TiDBMemberType:
-			return checkAutoScalingInterval(tac, *tac.Spec.TiDB.ScaleInIntervalSeconds, memberType, group)
-		}
+		return checkAutoScalingInterval(tac, *spec.ScaleInIntervalSeconds, memberType, group)
 	} else if beforeReplicas < afterReplicas {
-		switch memberType {
-		case v1alpha1.TiKVMemberType:
-			return checkAutoScalingInterval(tac, *tac.Spec.TiKV.ScaleOutIntervalSeconds, memberType, group)
-		case v1alpha1.TiDBMemberType:
-			return checkAutoScalingInterval(tac, *tac.Spec.TiDB.ScaleOutIntervalSeconds, memberType, group)
-		}
+		return checkAutoScalingInterval(tac, *spec.ScaleOutIntervalSeconds, memberType, group)
 	}
 	return true
 }
 
 // checkAutoScalingInterval would check whether there is enough interval duration between every two auto-scaling
 func checkAutoScalingInterval(tac *v1alpha1.TidbClusterAutoScaler, intervalSeconds int32, memberType v1alpha1.MemberType, group string) bool {
-	var lastAutoScalingTimestamp *metav1.Time
-	if memberType == v1alpha1.TiKVMemberType {
-		status, existed := tac.Status.TiKV[group]
-		if !existed {
-			return true
-		}
-		lastAutoScalingTimestamp = status.LastAutoScalingTimestamp
-	} else if memberType == v1alpha1.TiDBMemberType {
-		status, existed := tac.Status.TiDB[group]
-		if !existed {
-			return true
-		}
-		lastAutoScalingTimestamp = status.LastAutoScalingTimestamp
+	status := getBasicAutoScalerStatus(tac, memberType, group)
+	if status == nil {
+		return true
 	}
+	lastAutoScalingTimestamp := status.LastAutoScalingTimestamp
 	if lastAutoScalingTimestamp == nil {
 		return true
 	}
@@ -77,6 +73,43 @@ func checkAutoScalingInterval(tac *v1alpha1.TidbClusterAutoScaler, intervalSecon
 	return true
 }
 
+// getBasicAutoScalerStatus returns the status of the group for the component, nil if not existed
+func getBasicAutoScalerStatus(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.MemberType, group string) *v1alpha1.BasicAutoScalerStatus {
+	switch memberType {
+	case v1alpha1.TiKVMemberType:
+		if status, existed := tac.Status.TiKV[group]; existed {
+			return &status.BasicAutoScalerStatus
+		}
+	case v1alpha1.TiDBMemberType:
+		if status, existed := tac.Status.TiDB[group]; existed {
+			return &status.BasicAutoScalerStatus
+		}
+	case v1alpha1.TiFlashMemberType:
+		if status, existed := tac.Status.TiFlash[group]; existed {
+			return &status.BasicAutoScalerStatus
+		}
+	case v1alpha1.TiCDCMemberType:
+		if status, existed := tac.Status.TiCDC[group]; existed {
+			return &status.BasicAutoScalerStatus
+		}
+	}
+	return nil
+}
+
+// deleteBasicAutoScalerStatus deletes the status of the group for the component
+func deleteBasicAutoScalerStatus(tac *v1alpha1.TidbClusterAutoScaler, memberType v1alpha1.MemberType, group string) {
+	switch memberType {
+	case v1alpha1.TiKVMemberType:
+		delete(tac.Status.TiKV, group)
+	case v1alpha1.TiDBMemberType:
+		delete(tac.Status.TiDB, group)
+	case v1alpha1.TiFlashMemberType:
+		delete(tac.Status.TiFlash, group)
+	case v1alpha1.TiCDCMemberType:
+		delete(tac.Status.TiCDC, group)
+	}
+}
+
 func defaultResources(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) {
 	typ := fmt.Sprintf("default_%s", component.String())
 	resource := v1alpha1.AutoResource{}
@@ -87,6 +120,15 @@ func defaultResources(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoSca
 		requests = tc.Spec.TiDB.Requests
 	case v1alpha1.TiKVMemberType:
 		requests = tc.Spec.TiKV.Requests
+	case v1alpha1.TiFlashMemberType:
+		requests = tc.Spec.TiFlash.Requests
+		if len(tc.Spec.TiFlash.StorageClaims) > 0 {
+			if storage, ok := tc.Spec.TiFlash.StorageClaims[0].Resources.Requests[corev1.ResourceStorage]; ok {
+				resource.Storage = storage
+			}
+		}
+	case v1alpha1.TiCDCMemberType:
+		requests = tc.Spec.TiCDC.Requests
 	}
 
 	for res, v := range requests {
@@ -100,18 +142,11 @@ func defaultResources(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoSca
 		}
 	}
 
-	switch component {
-	case v1alpha1.TiDBMemberType:
-		if tac.Spec.TiDB.Resources == nil {
-			tac.Spec.TiDB.Resources = make(map[string]v1alpha1.AutoResource)
-		}
-		tac.Spec.TiDB.Resources[typ] = resource
-	case v1alpha1.TiKVMemberType:
-		if tac.Spec.TiKV.Resources == nil {
-			tac.Spec.TiKV.Resources = make(map[string]v1alpha1.AutoResource)
-		}
-		tac.Spec.TiKV.Resources[typ] = resource
+	spec := getBasicAutoScalerSpec(tac, component)
+	if spec.Resources == nil {
+		spec.Resources = make(map[string]v1alpha1.AutoResource)
 	}
+	spec.Resources[typ] = resource
 }
 
 func defaultResourceTypes(tac *v1alpha1.TidbClusterAutoScaler, rule *v1alpha1.AutoRule, component v1alpha1.MemberType) {
@@ -131,27 +166,52 @@ func defaultResourceTypes(tac *v1alpha1.TidbClusterAutoScaler, rule *v1alpha1.Au
 func getBasicAutoScalerSpec(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) *v1alpha1.BasicAutoScalerSpec {
 	switch component {
 	case v1alpha1.TiDBMemberType:
-		return &tac.Spec.TiDB.BasicAutoScalerSpec
+		if tac.Spec.TiDB != nil {
+			return &tac.Spec.TiDB.BasicAutoScalerSpec
+		}
 	case v1alpha1.TiKVMemberType:
-		return &tac.Spec.TiKV.BasicAutoScalerSpec
+		if tac.Spec.TiKV != nil {
+			return &tac.Spec.TiKV.BasicAutoScalerSpec
+		}
+	case v1alpha1.TiFlashMemberType:
+		if tac.Spec.TiFlash != nil {
+			return &tac.Spec.TiFlash.BasicAutoScalerSpec
+		}
+	case v1alpha1.TiCDCMemberType:
+		if tac.Spec.TiCDC != nil {
+			return &tac.Spec.TiCDC.BasicAutoScalerSpec
+		}
 	}
 	return nil
 }
 
-func getSpecResources(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) map[string]v1alpha1.AutoResource {
+// getVerticalAutoScalerSpec returns the vertical auto-scaling spec of the component, nil if not set
+func getVerticalAutoScalerSpec(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) *v1alpha1.VerticalAutoScalerSpec {
 	switch component {
 	case v1alpha1.TiDBMemberType:
 		if tac.Spec.TiDB != nil {
-			return tac.Spec.TiDB.Resources
+			return tac.Spec.TiDB.Vertical
 		}
 	case v1alpha1.TiKVMemberType:
 		if tac.Spec.TiKV != nil {
-			return tac.Spec.TiKV.Resources
+			return tac.Spec.TiKV.Vertical
 		}
 	}
 	return nil
 }
 
+// hasHorizontalAutoScaling checks whether the replicas of the component are auto-scaled
+func hasHorizontalAutoScaling(spec *v1alpha1.BasicAutoScalerSpec) bool {
+	return spec.External != nil || spec.Custom != nil || len(spec.Rules) > 0 || len(spec.Schedules) > 0
+}
+
+func getSpecResources(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) map[string]v1alpha1.AutoResource {
+	if spec := getBasicAutoScalerSpec(tac, component); spec != nil {
+		return spec.Resources
+	}
+	return nil
+}
+
 func defaultBasicAutoScaler(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) {
 	spec := getBasicAutoScalerSpec(tac, component)
 
@@ -166,6 +226,15 @@ func defaultBasicAutoScaler(tac *v1alpha1.TidbClusterAutoScaler, component v1alp
 		return
 	}
 
+	if spec.Custom != nil {
+		for i := range spec.Custom.Rules {
+			if spec.Custom.Rules[i].Aggregation == "" {
+				spec.Custom.Rules[i].Aggregation = v1alpha1.CustomRuleAggregationAvg
+			}
+		}
+		return
+	}
+
 	for res := range spec.Rules {
 		rule := spec.Rules[res]
 
@@ -186,34 +255,173 @@ func defaultTAC(tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
 	if tac.Annotations == nil {
 		tac.Annotations = map[string]string{}
 	}
+	if tac.Spec.RecommendationHistoryLimit == nil {
+		tac.Spec.RecommendationHistoryLimit = pointer.Int32Ptr(defaultRecommendationHistoryLimit)
+	}
 
-	// Construct default resource
-	if tac.Spec.TiKV != nil && tac.Spec.TiKV.External == nil && len(tac.Spec.TiKV.Resources) == 0 {
-		defaultResources(tc, tac, v1alpha1.TiKVMemberType)
+	for _, component := range autoScalingComponents {
+		spec := getBasicAutoScalerSpec(tac, component)
+		if spec == nil {
+			continue
+		}
+		// Construct default resource
+		if spec.External == nil && spec.Custom == nil && len(spec.Resources) == 0 && getComponentSpec(tc, component) != nil {
+			defaultResources(tc, tac, component)
+		}
+		defaultBasicAutoScaler(tac, component)
+		defaultVerticalAutoScaler(tac, component)
 	}
+}
 
-	if tac.Spec.TiDB != nil && tac.Spec.TiDB.External == nil && len(tac.Spec.TiDB.Resources) == 0 {
-		defaultResources(tc, tac, v1alpha1.TiDBMemberType)
+func defaultVerticalAutoScaler(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) {
+	spec := getVerticalAutoScalerSpec(tac, component)
+	if spec == nil {
+		return
+	}
+	if spec.Percentile == nil {
+		spec.Percentile = pointer.Int32Ptr(90)
+	}
+	if len(spec.Window) == 0 {
+		spec.Window = "24h"
+	}
+	if spec.TargetUtilization == nil {
+		spec.TargetUtilization = pointer.Float64Ptr(0.8)
+	}
+	if spec.Tolerance == nil {
+		spec.Tolerance = pointer.Float64Ptr(0.1)
 	}
+	if spec.IntervalSeconds == nil {
+		spec.IntervalSeconds = pointer.Int32Ptr(3600)
+	}
+}
 
-	if tidb := tac.Spec.TiDB; tidb != nil {
-		defaultBasicAutoScaler(tac, v1alpha1.TiDBMemberType)
+func validateVerticalAutoScaler(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) error {
+	spec := getVerticalAutoScalerSpec(tac, component)
+	if spec == nil {
+		return nil
+	}
+	if tac.Spec.MetricsURL == nil && tac.Spec.Monitor == nil {
+		return fmt.Errorf("neither metricsUrl nor monitor is provided for the vertical auto-scaling of %s in %s/%s", component.String(), tac.Namespace, tac.Name)
+	}
+	for _, bounds := range []corev1.ResourceList{spec.MinAllowed, spec.MaxAllowed} {
+		for name := range bounds {
+			if name != corev1.ResourceCPU && name != corev1.ResourceMemory {
+				return fmt.Errorf("unsupported resource %s for the vertical auto-scaling of %s in %s/%s", name, component.String(), tac.Namespace, tac.Name)
+			}
+		}
+	}
+	for name, min := range spec.MinAllowed {
+		if max, ok := spec.MaxAllowed[name]; ok && min.Cmp(max) > 0 {
+			return fmt.Errorf("minAllowed %s (%s) is larger than maxAllowed (%s) for the vertical auto-scaling of %s in %s/%s", name, min.String(), max.String(), component.String(), tac.Namespace, tac.Name)
+		}
+	}
+	if p := *spec.Percentile; p <= 0 || p > 100 {
+		return fmt.Errorf("percentile (%d) should be in (0, 100] for the vertical auto-scaling of %s in %s/%s", p, component.String(), tac.Namespace, tac.Name)
 	}
+	if _, err := model.ParseDuration(spec.Window); err != nil {
+		return fmt.Errorf("invalid window %s for the vertical auto-scaling of %s in %s/%s: %v", spec.Window, component.String(), tac.Namespace, tac.Name, err)
+	}
+	if u := *spec.TargetUtilization; u <= 0 || u > 1 {
+		return fmt.Errorf("targetUtilization (%v) should be in (0, 1] for the vertical auto-scaling of %s in %s/%s", u, component.String(), tac.Namespace, tac.Name)
+	}
+	if *spec.Tolerance < 0 {
+		return fmt.Errorf("tolerance (%v) should not be negative for the vertical auto-scaling of %s in %s/%s", *spec.Tolerance, component.String(), tac.Namespace, tac.Name)
+	}
+	if *spec.IntervalSeconds < 0 {
+		return fmt.Errorf("intervalSeconds (%d) should not be negative for the vertical auto-scaling of %s in %s/%s", *spec.IntervalSeconds, component.String(), tac.Namespace, tac.Name)
+	}
+	return nil
+}
 
-	if tikv := tac.Spec.TiKV; tikv != nil {
-		defaultBasicAutoScaler(tac, v1alpha1.TiKVMemberType)
+func validateCustomConfig(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, cfg *v1alpha1.CustomConfig) error {
+	if tac.Spec.MetricsURL == nil && tac.Spec.Monitor == nil {
+		return fmt.Errorf("neither metricsUrl nor monitor is provided for the custom rules of %s in %s/%s", component.String(), tac.Namespace, tac.Name)
+	}
+	if len(cfg.Rules) == 0 {
+		return fmt.Errorf("no custom rules defined for component %s in %s/%s", component.String(), tac.Namespace, tac.Name)
+	}
+	if cfg.MaxReplicas < 0 {
+		return fmt.Errorf("maxReplicas (%d) should not be negative for custom rules of %s in %s/%s", cfg.MaxReplicas, component.String(), tac.Namespace, tac.Name)
+	}
+	names := map[string]struct{}{}
+	for _, rule := range cfg.Rules {
+		if len(rule.Name) == 0 {
+			return fmt.Errorf("custom rule without name of %s in %s/%s", component.String(), tac.Namespace, tac.Name)
+		}
+		if _, ok := names[rule.Name]; ok {
+			return fmt.Errorf("duplicated custom rule %s of %s in %s/%s", rule.Name, component.String(), tac.Namespace, tac.Name)
+		}
+		names[rule.Name] = struct{}{}
+		if len(rule.Query) == 0 {
+			return fmt.Errorf("no query provided for custom rule %s of %s in %s/%s", rule.Name, component.String(), tac.Namespace, tac.Name)
+		}
+		if rule.TargetValue <= 0 {
+			return fmt.Errorf("targetValue (%v) should be positive for custom rule %s of %s in %s/%s", rule.TargetValue, rule.Name, component.String(), tac.Namespace, tac.Name)
+		}
+		switch rule.Aggregation {
+		case v1alpha1.CustomRuleAggregationSum, v1alpha1.CustomRuleAggregationAvg, v1alpha1.CustomRuleAggregationMax:
+		default:
+			return fmt.Errorf("unknown aggregation %s for custom rule %s of %s in %s/%s", rule.Aggregation, rule.Name, component.String(), tac.Namespace, tac.Name)
+		}
 	}
+	return nil
+}
 
+func validateSchedules(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType, policies []v1alpha1.ScheduledScalingPolicy) error {
+	names := map[string]struct{}{}
+	for i := range policies {
+		policy := &policies[i]
+		if len(policy.Name) == 0 {
+			return fmt.Errorf("scheduled policy without name of %s in %s/%s", component.String(), tac.Namespace, tac.Name)
+		}
+		if _, ok := names[policy.Name]; ok {
+			return fmt.Errorf("duplicated scheduled policy %s of %s in %s/%s", policy.Name, component.String(), tac.Namespace, tac.Name)
+		}
+		names[policy.Name] = struct{}{}
+		if _, err := isSchedulePolicyActive(policy, time.Now()); err != nil {
+			return fmt.Errorf("invalid scheduled policy of %s in %s/%s: %v", component.String(), tac.Namespace, tac.Name, err)
+		}
+		if duration, _ := time.ParseDuration(policy.Duration); duration <= 0 {
+			return fmt.Errorf("duration (%s) should be positive for scheduled policy %s of %s in %s/%s", policy.Duration, policy.Name, component.String(), tac.Namespace, tac.Name)
+		}
+		if policy.Replicas < 0 {
+			return fmt.Errorf("replicas (%d) should not be negative for scheduled policy %s of %s in %s/%s", policy.Replicas, policy.Name, component.String(), tac.Namespace, tac.Name)
+		}
+		if policy.MinReplicas != nil && *policy.MinReplicas > policy.Replicas {
+			return fmt.Errorf("minReplicas (%d) > replicas (%d) for scheduled policy %s of %s in %s/%s", *policy.MinReplicas, policy.Replicas, policy.Name, component.String(), tac.Namespace, tac.Name)
+		}
+		if policy.MaxReplicas != nil && *policy.MaxReplicas < policy.Replicas {
+			return fmt.Errorf("maxReplicas (%d) < replicas (%d) for scheduled policy %s of %s in %s/%s", *policy.MaxReplicas, policy.Replicas, policy.Name, component.String(), tac.Namespace, tac.Name)
+		}
+	}
+	return nil
 }
 
 func validateBasicAutoScalerSpec(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) error {
 	spec := getBasicAutoScalerSpec(tac, component)
 
+	if err := validateSchedules(tac, component, spec.Schedules); err != nil {
+		return err
+	}
+	if err := validateVerticalAutoScaler(tac, component); err != nil {
+		return err
+	}
+
 	if spec.External != nil {
+		if spec.Custom != nil {
+			return fmt.Errorf("external and custom can not be both set for component %s in %s/%s", component.String(), tac.Namespace, tac.Name)
+		}
 		return nil
 	}
 
+	if spec.Custom != nil {
+		return validateCustomConfig(tac, component, spec.Custom)
+	}
+
 	if len(spec.Rules) == 0 {
+		if len(spec.Schedules) > 0 || getVerticalAutoScalerSpec(tac, component) != nil {
+			return nil
+		}
 		return fmt.Errorf("no rules defined for component %s in %s/%s", component.String(), tac.Namespace, tac.Name)
 	}
 	resources := getSpecResources(tac, component)
@@ -268,24 +476,22 @@ func validateBasicAutoScalerSpec(tac *v1alpha1.TidbClusterAutoScaler, component
 }
 
 func validateTAC(tac *v1alpha1.TidbClusterAutoScaler) error {
-	if tac.Spec.TiDB != nil && tac.Spec.TiDB.External == nil && len(tac.Spec.TiDB.Resources) == 0 {
-		return fmt.Errorf("no resources provided for tidb in %s/%s", tac.Namespace, tac.Name)
-	}
-
-	if tac.Spec.TiKV != nil && tac.Spec.TiKV.External == nil && len(tac.Spec.TiKV.Resources) == 0 {
-		return fmt.Errorf("no resources provided for tikv in %s/%s", tac.Namespace, tac.Name)
+	if limit := tac.Spec.RecommendationHistoryLimit; limit != nil && *limit <= 0 {
+		return fmt.Errorf("recommendationHistoryLimit (%d) should be positive in %s/%s", *limit, tac.Namespace, tac.Name)
 	}
 
-	if tidb := tac.Spec.TiDB; tidb != nil {
-		err := validateBasicAutoScalerSpec(tac, v1alpha1.TiDBMemberType)
-		if err != nil {
-			return err
+	for _, component := range autoScalingComponents {
+		spec := getBasicAutoScalerSpec(tac, component)
+		if spec != nil && spec.External == nil && spec.Custom == nil && len(spec.Resources) == 0 {
+			return fmt.Errorf("no resources provided for %s in %s/%s", component.String(), tac.Namespace, tac.Name)
 		}
 	}
 
-	if tikv := tac.Spec.TiKV; tikv != nil {
-		err := validateBasicAutoScalerSpec(tac, v1alpha1.TiKVMemberType)
-		if err != nil {
+	for _, component := range autoScalingComponents {
+		if getBasicAutoScalerSpec(tac, component) == nil {
+			continue
+		}
+		if err := validateBasicAutoScalerSpec(tac, component); err != nil {
 			return err
 		}
 	}
@@ -313,11 +519,8 @@ func autoscalerToStrategy(tac *v1alpha1.TidbClusterAutoScaler, component v1alpha
 		strategy.Resources = append(strategy.Resources, resource)
 	}
 
-	switch component {
-	case v1alpha1.TiDBMemberType:
-		strategy.Rules = []*pdapi.Rule{autoRulesToStrategyRule(component.String(), tac.Spec.TiDB.Rules)}
-	case v1alpha1.TiKVMemberType:
-		strategy.Rules = []*pdapi.Rule{autoRulesToStrategyRule(component.String(), tac.Spec.TiKV.Rules)}
+	if spec := getBasicAutoScalerSpec(tac, component); spec != nil {
+		strategy.Rules = []*pdapi.Rule{autoRulesToStrategyRule(component.String(), spec.Rules)}
 	}
 
 	return strategy
@@ -350,6 +553,100 @@ func autoRulesToStrategyRule(component string, rules map[corev1.ResourceName]v1a
 	return result
 }
 
+// getComponentSpec returns the spec of the component in the TidbCluster, nil if not existed
+func getComponentSpec(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) interface{} {
+	switch component {
+	case v1alpha1.TiDBMemberType:
+		if tc.Spec.TiDB != nil {
+			return tc.Spec.TiDB
+		}
+	case v1alpha1.TiKVMemberType:
+		if tc.Spec.TiKV != nil {
+			return tc.Spec.TiKV
+		}
+	case v1alpha1.TiFlashMemberType:
+		if tc.Spec.TiFlash != nil {
+			return tc.Spec.TiFlash
+		}
+	case v1alpha1.TiCDCMemberType:
+		if tc.Spec.TiCDC != nil {
+			return tc.Spec.TiCDC
+		}
+	}
+	return nil
+}
+
+// getBaseReplicas returns the replicas of the component in the TidbCluster
+func getBaseReplicas(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) int32 {
+	switch component {
+	case v1alpha1.TiDBMemberType:
+		if tc.Spec.TiDB != nil {
+			return tc.Spec.TiDB.Replicas
+		}
+	case v1alpha1.TiKVMemberType:
+		if tc.Spec.TiKV != nil {
+			return tc.Spec.TiKV.Replicas
+		}
+	case v1alpha1.TiFlashMemberType:
+		if tc.Spec.TiFlash != nil {
+			return tc.Spec.TiFlash.Replicas
+		}
+	case v1alpha1.TiCDCMemberType:
+		if tc.Spec.TiCDC != nil {
+			return tc.Spec.TiCDC.Replicas
+		}
+	}
+	return 0
+}
+
+// setReplicas sets the replicas of the component in the TidbCluster
+func setReplicas(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType, replicas int32) {
+	switch component {
+	case v1alpha1.TiDBMemberType:
+		tc.Spec.TiDB.Replicas = replicas
+	case v1alpha1.TiKVMemberType:
+		tc.Spec.TiKV.Replicas = replicas
+	case v1alpha1.TiFlashMemberType:
+		tc.Spec.TiFlash.Replicas = replicas
+	case v1alpha1.TiCDCMemberType:
+		tc.Spec.TiCDC.Replicas = replicas
+	}
+}
+
+// getStatefulSetReplicas returns the replicas of the StatefulSet of the component in the TidbCluster status
+func getStatefulSetReplicas(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) int32 {
+	var sts *appsv1.StatefulSetStatus
+	switch component {
+	case v1alpha1.TiDBMemberType:
+		sts = tc.Status.TiDB.StatefulSet
+	case v1alpha1.TiKVMemberType:
+		sts = tc.Status.TiKV.StatefulSet
+	case v1alpha1.TiFlashMemberType:
+		sts = tc.Status.TiFlash.StatefulSet
+	case v1alpha1.TiCDCMemberType:
+		sts = tc.Status.TiCDC.StatefulSet
+	}
+	if sts == nil {
+		return 0
+	}
+	return sts.Replicas
+}
+
+// genMetricsEndpoint returns the Prometheus endpoint to evaluate the custom rules
+func genMetricsEndpoint(tac *v1alpha1.TidbClusterAutoScaler) (string, error) {
+	if tac.Spec.MetricsURL != nil {
+		return strings.TrimSuffix(*tac.Spec.MetricsURL, "/"), nil
+	}
+	if tac.Spec.Monitor != nil {
+		ns := tac.Spec.Monitor.Namespace
+		if len(ns) < 1 {
+			ns = tac.Namespace
+		}
+		return fmt.Sprintf("http://%s-prometheus.%s.svc:9090", tac.Spec.Monitor.Name, ns), nil
+	}
+	return "", fmt.Errorf("tac[%s/%s] neither metricsUrl nor monitor is provided", tac.Namespace, tac.Name)
+}
+
 const autoClusterPrefix = "auto-"
 
 func genAutoClusterName(tas *v1alpha1.TidbClusterAutoScaler, component string, labels map[string]string, resource v1alpha1.AutoResource) (string, error) {
@@ -392,24 +689,45 @@ func newAutoScalingCluster(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAu
 		Name:      tc.Name,
 	}
 
-	autoTc.Spec.TiCDC = nil
-	autoTc.Spec.TiFlash = nil
 	autoTc.Spec.PD = nil
 	autoTc.Spec.Pump = nil
 
 	switch component {
 	case v1alpha1.TiDBMemberType.String():
 		autoTc.Spec.TiKV = nil
+		autoTc.Spec.TiFlash = nil
+		autoTc.Spec.TiCDC = nil
 		// Initialize Config
 		if autoTc.Spec.TiDB.Config == nil {
 			autoTc.Spec.TiDB.Config = v1alpha1.NewTiDBConfig()
 		}
 	case v1alpha1.TiKVMemberType.String():
 		autoTc.Spec.TiDB = nil
+		autoTc.Spec.TiFlash = nil
+		autoTc.Spec.TiCDC = nil
 		// Initialize Config
 		if autoTc.Spec.TiKV.Config == nil {
 			autoTc.Spec.TiKV.Config = v1alpha1.NewTiKVConfig()
 		}
+	case v1alpha1.TiFlashMemberType.String():
+		autoTc.Spec.TiDB = nil
+		autoTc.Spec.TiKV = nil
+		autoTc.Spec.TiCDC = nil
+		// Initialize Config
+		if autoTc.Spec.TiFlash.Config == nil {
+			autoTc.Spec.TiFlash.Config = v1alpha1.NewTiFlashConfig()
+		}
+		if autoTc.Spec.TiFlash.Config.Proxy == nil {
+			autoTc.Spec.TiFlash.Config.Proxy = v1alpha1.NewTiFlashProxyConfig()
+		}
+	case v1alpha1.TiCDCMemberType.String():
+		autoTc.Spec.TiDB = nil
+		autoTc.Spec.TiKV = nil
+		autoTc.Spec.TiFlash = nil
+		// Initialize Config
+		if autoTc.Spec.TiCDC.Config == nil {
+			autoTc.Spec.TiCDC.Config = v1alpha1.NewCDCConfig()
+		}
 	}
 
 	return autoTc
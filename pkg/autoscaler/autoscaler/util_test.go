@@ -581,6 +581,76 @@ func TestValidateTidbClusterAutoScaler(t *testing.T) {
 	g.Expect(err).Should(BeNil())
 }
 
+func TestValidateCustomRules(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tac := newTidbClusterAutoScaler()
+	tac.Spec.TiKV = nil
+	tac.Spec.TiDB.Custom = &v1alpha1.CustomConfig{
+		MaxReplicas: 3,
+		Rules: []v1alpha1.CustomAutoRule{
+			{
+				Name:        "qps",
+				Query:       `sum(rate(tidb_executor_statement_total[1m])) by (instance)`,
+				TargetValue: 1000,
+			},
+		},
+	}
+
+	// Case 1: No Prometheus provided
+	err := validateTAC(tac)
+	g.Expect(err).Should(MatchError(fmt.Errorf("neither metricsUrl nor monitor is provided for the custom rules of tidb in %s/%s", tac.Namespace, tac.Name)))
+
+	// Case 2: Aggregation is defaulted
+	tac.Spec.Monitor = &v1alpha1.TidbMonitorRef{Name: "monitor"}
+	defaultTAC(tac, newTidbCluster())
+	g.Expect(tac.Spec.TiDB.Custom.Rules[0].Aggregation).Should(Equal(v1alpha1.CustomRuleAggregationAvg))
+	g.Expect(tac.Spec.TiDB.Resources).Should(BeEmpty())
+	err = validateTAC(tac)
+	g.Expect(err).Should(BeNil())
+
+	// Case 3: Invalid target value
+	tac.Spec.TiDB.Custom.Rules[0].TargetValue = 0
+	err = validateTAC(tac)
+	g.Expect(err).Should(MatchError(fmt.Errorf("targetValue (%v) should be positive for custom rule qps of tidb in %s/%s", 0, tac.Namespace, tac.Name)))
+
+	// Case 4: Unknown aggregation
+	tac.Spec.TiDB.Custom.Rules[0].TargetValue = 1000
+	tac.Spec.TiDB.Custom.Rules[0].Aggregation = "min"
+	err = validateTAC(tac)
+	g.Expect(err).Should(MatchError(fmt.Errorf("unknown aggregation min for custom rule qps of tidb in %s/%s", tac.Namespace, tac.Name)))
+
+	// Case 5: Duplicated rules
+	tac.Spec.TiDB.Custom.Rules[0].Aggregation = v1alpha1.CustomRuleAggregationMax
+	tac.Spec.TiDB.Custom.Rules = append(tac.Spec.TiDB.Custom.Rules, tac.Spec.TiDB.Custom.Rules[0])
+	err = validateTAC(tac)
+	g.Expect(err).Should(MatchError(fmt.Errorf("duplicated custom rule qps of tidb in %s/%s", tac.Namespace, tac.Name)))
+
+	// Case 6: External and custom are both set
+	tac.Spec.TiDB.Custom.Rules = tac.Spec.TiDB.Custom.Rules[:1]
+	tac.Spec.TiDB.External = &v1alpha1.ExternalConfig{MaxReplicas: 3}
+	err = validateTAC(tac)
+	g.Expect(err).Should(MatchError(fmt.Errorf("external and custom can not be both set for component tidb in %s/%s", tac.Namespace, tac.Name)))
+}
+
+func TestGenMetricsEndpoint(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tac := newTidbClusterAutoScaler()
+	_, err := genMetricsEndpoint(tac)
+	g.Expect(err).ShouldNot(BeNil())
+
+	tac.Spec.Monitor = &v1alpha1.TidbMonitorRef{Name: "monitor"}
+	endpoint, err := genMetricsEndpoint(tac)
+	g.Expect(err).Should(BeNil())
+	g.Expect(endpoint).Should(Equal("http://monitor-prometheus.default.svc:9090"))
+
+	tac.Spec.MetricsURL = pointer.StringPtr("http://prometheus:9090/")
+	endpoint, err = genMetricsEndpoint(tac)
+	g.Expect(err).Should(BeNil())
+	g.Expect(endpoint).Should(Equal("http://prometheus:9090"))
+}
+
 func newTidbClusterAutoScaler() *v1alpha1.TidbClusterAutoScaler {
 	tac := &v1alpha1.TidbClusterAutoScaler{}
 	tac.Name = "tac"
@@ -622,3 +692,107 @@ func newTidbCluster() *v1alpha1.TidbCluster {
 	}
 	return tc
 }
+
+func TestDefaultTacForTiFlashAndTiCDC(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{
+		ResourceRequirements: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+		},
+		StorageClaims: []v1alpha1.StorageClaim{
+			{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("100Gi"),
+					},
+				},
+			},
+		},
+	}
+	tc.Spec.TiCDC = &v1alpha1.TiCDCSpec{
+		ResourceRequirements: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+		},
+	}
+
+	tac := newTidbClusterAutoScaler()
+	tac.Spec.TiDB = nil
+	tac.Spec.TiKV = nil
+	tac.Spec.TiFlash = &v1alpha1.TiflashAutoScalerSpec{
+		BasicAutoScalerSpec: v1alpha1.BasicAutoScalerSpec{
+			Rules: map[corev1.ResourceName]v1alpha1.AutoRule{
+				corev1.ResourceCPU: {
+					MaxThreshold: 0.8,
+				},
+			},
+		},
+	}
+	tac.Spec.TiCDC = &v1alpha1.TicdcAutoScalerSpec{
+		BasicAutoScalerSpec: v1alpha1.BasicAutoScalerSpec{
+			External: &v1alpha1.ExternalConfig{
+				MaxReplicas: 3,
+			},
+		},
+	}
+
+	defaultTAC(tac, tc)
+	g.Expect(tac.Spec.TiFlash.Resources).Should(Equal(map[string]v1alpha1.AutoResource{
+		"default_tiflash": {
+			CPU:     resource.MustParse("4"),
+			Memory:  resource.MustParse("16Gi"),
+			Storage: resource.MustParse("100Gi"),
+		},
+	}))
+	g.Expect(tac.Spec.TiFlash.Rules[corev1.ResourceCPU].ResourceTypes).Should(Equal([]string{"default_tiflash"}))
+	g.Expect(*tac.Spec.TiFlash.ScaleInIntervalSeconds).Should(Equal(int32(500)))
+	g.Expect(tac.Spec.TiCDC.Resources).Should(BeEmpty())
+	g.Expect(*tac.Spec.TiCDC.ScaleOutIntervalSeconds).Should(Equal(int32(300)))
+	g.Expect(validateTAC(tac)).Should(Succeed())
+}
+
+func TestValidateVerticalAutoScaler(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tac := newTidbClusterAutoScaler()
+	tac.Spec.TiDB = nil
+	tac.Spec.TiKV.Vertical = &v1alpha1.VerticalAutoScalerSpec{
+		MinAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		MaxAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")},
+		Window:     "7d",
+	}
+	defaultTAC(tac, newTidbCluster())
+	g.Expect(*tac.Spec.TiKV.Vertical.Percentile).Should(Equal(int32(90)))
+	g.Expect(*tac.Spec.TiKV.Vertical.TargetUtilization).Should(Equal(0.8))
+
+	// Case 1: No Prometheus provided
+	err := validateTAC(tac)
+	g.Expect(err).Should(MatchError(fmt.Errorf("neither metricsUrl nor monitor is provided for the vertical auto-scaling of tikv in %s/%s", tac.Namespace, tac.Name)))
+
+	// Case 2: Valid without horizontal rules
+	tac.Spec.Monitor = &v1alpha1.TidbMonitorRef{Name: "monitor"}
+	g.Expect(validateTAC(tac)).Should(Succeed())
+
+	// Case 3: Min is larger than max
+	tac.Spec.TiKV.Vertical.MaxAllowed[corev1.ResourceCPU] = resource.MustParse("2")
+	err = validateTAC(tac)
+	g.Expect(err).Should(MatchError(fmt.Errorf("minAllowed cpu (4) is larger than maxAllowed (2) for the vertical auto-scaling of tikv in %s/%s", tac.Namespace, tac.Name)))
+
+	// Case 4: Unsupported resource
+	tac.Spec.TiKV.Vertical.MaxAllowed = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("100Gi")}
+	err = validateTAC(tac)
+	g.Expect(err).Should(MatchError(fmt.Errorf("unsupported resource storage for the vertical auto-scaling of tikv in %s/%s", tac.Namespace, tac.Name)))
+
+	// Case 5: Invalid target utilization
+	tac.Spec.TiKV.Vertical.MaxAllowed = nil
+	tac.Spec.TiKV.Vertical.TargetUtilization = pointer.Float64Ptr(1.5)
+	err = validateTAC(tac)
+	g.Expect(err).Should(MatchError(fmt.Errorf("targetUtilization (1.5) should be in (0, 1] for the vertical auto-scaling of tikv in %s/%s", tac.Namespace, tac.Name)))
+}
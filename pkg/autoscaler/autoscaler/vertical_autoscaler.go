@@ -0,0 +1,180 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/autoscaler/autoscaler/calculate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// verticalStatusKey is the status key of the vertical auto-scaling
+	verticalStatusKey = "vertical"
+	// memoryRoundUnit is the unit which the recommended memory is rounded up to
+	memoryRoundUnit = 1 << 20
+)
+
+// verticalResources are the resources adjusted by the vertical auto-scaling
+var verticalResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// syncVertical adjusts the cpu and memory of the component in the target TidbCluster according to
+// the observed usage. The TidbCluster controller rolls out the change with the upgraders.
+func (am *autoScalerManager) syncVertical(tc *v1alpha1.TidbCluster, tac *v1alpha1.TidbClusterAutoScaler, component v1alpha1.MemberType) error {
+	spec := getVerticalAutoScalerSpec(tac, component)
+	if phase := getComponentPhase(tc, component); phase != v1alpha1.NormalPhase {
+		klog.Infof("tac[%s/%s] skips the vertical auto-scaling of %s, tc[%s/%s] is in %s phase", tac.Namespace, tac.Name, component.String(), tc.Namespace, tc.Name, phase)
+		return nil
+	}
+
+	endpoint, err := genMetricsEndpoint(tac)
+	if err != nil {
+		return err
+	}
+	usage, err := calculate.UsagePercentile(nil, endpoint, tc.Namespace, tc.Name, component.String(), float64(*spec.Percentile)/100, spec.Window)
+	if err != nil {
+		klog.Errorf("tac[%s/%s] failed to query the usage of component %s, err: %v", tac.Namespace, tac.Name, component.String(), err)
+		return err
+	}
+
+	current := getComponentResources(tc, component)
+	recommended := recommendVerticalResources(spec, usage)
+	updateBasicAutoScalerStatus(tac, component, verticalStatusKey, func(status *v1alpha1.BasicAutoScalerStatus) {
+		status.RecommendedResources = recommended
+	})
+
+	if !exceedTolerance(current.Requests, recommended, *spec.Tolerance) {
+		return nil
+	}
+	if !checkAutoScalingInterval(tac, *spec.IntervalSeconds, component, verticalStatusKey) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("recommend %s requests cpu %s, memory %s", component.String(), recommended.Cpu().String(), recommended.Memory().String())
+	if tac.Spec.DryRun {
+		klog.Infof("tac[%s/%s] %s in dry-run mode", tac.Namespace, tac.Name, msg)
+		am.deps.Recorder.Event(tac, corev1.EventTypeNormal, autoScalingRecommendedReason, msg)
+		return nil
+	}
+
+	updated := tc.DeepCopy()
+	setComponentResources(updated, component, scaleResourceRequirements(current, recommended))
+	if _, err := am.deps.TiDBClusterControl.UpdateTidbCluster(updated, &updated.Status, &tc.Status); err != nil {
+		klog.Errorf("tac[%s/%s] failed to update the resources of %s in tc[%s/%s], err: %v", tac.Namespace, tac.Name, component.String(), tc.Namespace, tc.Name, err)
+		return err
+	}
+	klog.Infof("tac[%s/%s] updated tc[%s/%s]: %s", tac.Namespace, tac.Name, tc.Namespace, tc.Name, msg)
+	updateLastAutoScalingTimestamp(tac, component.String(), verticalStatusKey)
+	return nil
+}
+
+// recommendVerticalResources calculates the requests from the usage, bounded by the min and max allowed resources
+func recommendVerticalResources(spec *v1alpha1.VerticalAutoScalerSpec, usage map[corev1.ResourceName]float64) corev1.ResourceList {
+	recommended := corev1.ResourceList{}
+	for _, name := range verticalResources {
+		value := usage[name] / *spec.TargetUtilization
+		var q resource.Quantity
+		switch name {
+		case corev1.ResourceCPU:
+			q = *resource.NewMilliQuantity(int64(math.Ceil(value*1000)), resource.DecimalSI)
+		case corev1.ResourceMemory:
+			q = *resource.NewQuantity(int64(math.Ceil(value/memoryRoundUnit))*memoryRoundUnit, resource.BinarySI)
+		}
+		if min, ok := spec.MinAllowed[name]; ok && q.Cmp(min) < 0 {
+			q = min.DeepCopy()
+		}
+		if max, ok := spec.MaxAllowed[name]; ok && q.Cmp(max) > 0 {
+			q = max.DeepCopy()
+		}
+		recommended[name] = q
+	}
+	return recommended
+}
+
+// exceedTolerance checks whether the relative change from the current requests to the recommended ones exceeds the tolerance
+func exceedTolerance(current, recommended corev1.ResourceList, tolerance float64) bool {
+	for _, name := range verticalResources {
+		cur, ok := current[name]
+		if !ok || cur.IsZero() {
+			return true
+		}
+		rec := recommended[name]
+		change := math.Abs(float64(rec.MilliValue()-cur.MilliValue())) / float64(cur.MilliValue())
+		if change > tolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// scaleResourceRequirements sets the requests to the recommended ones and scales the limits in proportion,
+// the other resources such as storage are kept
+func scaleResourceRequirements(current corev1.ResourceRequirements, recommended corev1.ResourceList) corev1.ResourceRequirements {
+	updated := *current.DeepCopy()
+	if updated.Requests == nil {
+		updated.Requests = corev1.ResourceList{}
+	}
+	for _, name := range verticalResources {
+		rec := recommended[name]
+		if limit, ok := updated.Limits[name]; ok {
+			if req, ok := updated.Requests[name]; ok && !req.IsZero() {
+				ratio := float64(limit.MilliValue()) / float64(req.MilliValue())
+				if name == corev1.ResourceCPU {
+					updated.Limits[name] = *resource.NewMilliQuantity(int64(math.Ceil(float64(rec.MilliValue())*ratio)), resource.DecimalSI)
+				} else {
+					updated.Limits[name] = *resource.NewQuantity(int64(math.Ceil(float64(rec.Value())*ratio)), resource.BinarySI)
+				}
+			} else {
+				// The requests default to the limits
+				updated.Limits[name] = rec.DeepCopy()
+			}
+		}
+		updated.Requests[name] = rec.DeepCopy()
+	}
+	return updated
+}
+
+func getComponentPhase(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) v1alpha1.MemberPhase {
+	switch component {
+	case v1alpha1.TiDBMemberType:
+		return tc.Status.TiDB.Phase
+	case v1alpha1.TiKVMemberType:
+		return tc.Status.TiKV.Phase
+	}
+	return ""
+}
+
+func getComponentResources(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType) corev1.ResourceRequirements {
+	switch component {
+	case v1alpha1.TiDBMemberType:
+		return tc.Spec.TiDB.ResourceRequirements
+	case v1alpha1.TiKVMemberType:
+		return tc.Spec.TiKV.ResourceRequirements
+	}
+	return corev1.ResourceRequirements{}
+}
+
+func setComponentResources(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType, resources corev1.ResourceRequirements) {
+	switch component {
+	case v1alpha1.TiDBMemberType:
+		tc.Spec.TiDB.ResourceRequirements = resources
+	case v1alpha1.TiKVMemberType:
+		tc.Spec.TiKV.ResourceRequirements = resources
+	}
+}
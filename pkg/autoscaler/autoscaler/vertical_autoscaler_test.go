@@ -0,0 +1,175 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+)
+
+func TestRecommendVerticalResources(t *testing.T) {
+	g := NewGomegaWithT(t)
+	spec := &v1alpha1.VerticalAutoScalerSpec{
+		MinAllowed: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("1"),
+		},
+		MaxAllowed: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		},
+		TargetUtilization: pointer.Float64Ptr(0.5),
+	}
+
+	recommended := recommendVerticalResources(spec, map[corev1.ResourceName]float64{
+		corev1.ResourceCPU:    0.3,
+		corev1.ResourceMemory: 1.5 * (1 << 30),
+	})
+	g.Expect(recommended.Cpu().String()).Should(Equal("1"))
+	g.Expect(recommended.Memory().String()).Should(Equal("3Gi"))
+
+	recommended = recommendVerticalResources(spec, map[corev1.ResourceName]float64{
+		corev1.ResourceCPU:    1.25,
+		corev1.ResourceMemory: 5 * (1 << 30),
+	})
+	g.Expect(recommended.Cpu().String()).Should(Equal("2500m"))
+	g.Expect(recommended.Memory().String()).Should(Equal("8Gi"))
+}
+
+func TestScaleResourceRequirements(t *testing.T) {
+	g := NewGomegaWithT(t)
+	current := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:     resource.MustParse("1"),
+			corev1.ResourceMemory:  resource.MustParse("2Gi"),
+			corev1.ResourceStorage: resource.MustParse("100Gi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		},
+	}
+
+	updated := scaleResourceRequirements(current, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1500m"),
+		corev1.ResourceMemory: resource.MustParse("4Gi"),
+	})
+	g.Expect(updated.Requests.Cpu().String()).Should(Equal("1500m"))
+	g.Expect(updated.Requests.Memory().String()).Should(Equal("4Gi"))
+	g.Expect(updated.Requests.Storage().String()).Should(Equal("100Gi"))
+	g.Expect(updated.Limits.Cpu().String()).Should(Equal("3"))
+	g.Expect(updated.Limits.Memory().String()).Should(Equal("4Gi"))
+	// The current requirements are not changed
+	g.Expect(current.Requests.Cpu().String()).Should(Equal("1"))
+}
+
+func TestExceedTolerance(t *testing.T) {
+	g := NewGomegaWithT(t)
+	current := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("10Gi"),
+	}
+
+	g.Expect(exceedTolerance(current, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1050m"),
+		corev1.ResourceMemory: resource.MustParse("9Gi"),
+	}, 0.1)).Should(BeFalse())
+	g.Expect(exceedTolerance(current, corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("800m"),
+		corev1.ResourceMemory: resource.MustParse("10Gi"),
+	}, 0.1)).Should(BeTrue())
+	g.Expect(exceedTolerance(corev1.ResourceList{}, current, 0.1)).Should(BeTrue())
+}
+
+func TestSyncVertical(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := "2"
+		if strings.Contains(r.URL.Query().Get("query"), "memory") {
+			value = fmt.Sprint(4 << 30)
+		}
+		w.Write([]byte(fmt.Sprintf(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1600000000,"%s"]}]}}`, value)))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		phase       v1alpha1.MemberPhase
+		dryRun      bool
+		expectedCPU string
+	}{
+		{
+			name:        "update the resources",
+			phase:       v1alpha1.NormalPhase,
+			expectedCPU: "2500m",
+		},
+		{
+			name:        "skip while upgrading",
+			phase:       v1alpha1.UpgradePhase,
+			expectedCPU: "1",
+		},
+		{
+			name:        "dry run",
+			phase:       v1alpha1.NormalPhase,
+			dryRun:      true,
+			expectedCPU: "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			deps := controller.NewFakeDependencies()
+			am := NewAutoScalerManager(deps)
+
+			tc := newTidbCluster()
+			tc.Status.TiKV.Phase = tt.phase
+			g.Expect(deps.InformerFactory.Pingcap().V1alpha1().TidbClusters().Informer().GetIndexer().Add(tc)).Should(Succeed())
+
+			tac := newTidbClusterAutoScaler()
+			tac.Spec.TiDB = nil
+			tac.Spec.DryRun = tt.dryRun
+			tac.Spec.MetricsURL = pointer.StringPtr(server.URL)
+			tac.Spec.TiKV.Vertical = &v1alpha1.VerticalAutoScalerSpec{}
+			defaultTAC(tac, tc)
+			g.Expect(validateTAC(tac)).Should(Succeed())
+
+			g.Expect(am.syncVertical(tc, tac, v1alpha1.TiKVMemberType)).Should(Succeed())
+
+			updated, err := deps.TiDBClusterLister.TidbClusters(tc.Namespace).Get(tc.Name)
+			g.Expect(err).Should(BeNil())
+			g.Expect(updated.Spec.TiKV.Requests.Cpu().String()).Should(Equal(tt.expectedCPU))
+			g.Expect(updated.Spec.TiKV.Requests.Storage().String()).Should(Equal("1000Gi"))
+			if tt.phase != v1alpha1.NormalPhase {
+				g.Expect(tac.Status.TiKV).Should(BeEmpty())
+				return
+			}
+			status := tac.Status.TiKV[verticalStatusKey]
+			g.Expect(status.RecommendedResources.Memory().String()).Should(Equal("5Gi"))
+			if tt.dryRun {
+				g.Expect(status.LastAutoScalingTimestamp).Should(BeNil())
+			} else {
+				g.Expect(updated.Spec.TiKV.Limits.Cpu().String()).Should(Equal("2500m"))
+				g.Expect(status.LastAutoScalingTimestamp).ShouldNot(BeNil())
+			}
+		})
+	}
+}
@@ -39,3 +39,9 @@ type BackupScheduleManager interface {
 	// Sync	implements the logic for syncing BackupSchedule.
 	Sync(backup *v1alpha1.BackupSchedule) error
 }
+
+// RestoreDrillManager implements the logic for manage restoreDrill.
+type RestoreDrillManager interface {
+	// Sync	implements the logic for syncing RestoreDrill.
+	Sync(rd *v1alpha1.RestoreDrill) error
+}
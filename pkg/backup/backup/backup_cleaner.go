@@ -154,6 +154,7 @@ func (bc *backupCleaner) makeCleanJob(backup *v1alpha1.Backup) (*batchv1.Job, st
 		volumes = append(volumes, localVolume)
 		volumeMounts = append(volumeMounts, localVolumeMount)
 	}
+	volumes, volumeMounts = backuputil.AppendSecondaryStorageVolumes(backup, volumes, volumeMounts)
 
 	serviceAccount := constants.DefaultServiceAccountName
 	if backup.Spec.ServiceAccount != "" {
@@ -532,6 +532,12 @@ func (bm *backupManager) makeBRBackupJob(backup *v1alpha1.Backup) (*batchv1.Job,
 		return nil, reason, fmt.Errorf("backup %s/%s, %v", ns, name, err)
 	}
 
+	if backup.Spec.Encryption != nil {
+		if _, reason, err := backuputil.GetEncryptionKey(ns, backup.Spec.Encryption, bm.deps.SecretLister); err != nil {
+			return nil, reason, fmt.Errorf("backup %s/%s, %v", ns, name, err)
+		}
+	}
+
 	envVars = append(envVars, storageEnv...)
 	envVars = append(envVars, corev1.EnvVar{
 		Name:  "BR_LOG_TO_TERM",
@@ -647,6 +653,8 @@ func (bm *backupManager) makeBRBackupJob(backup *v1alpha1.Backup) (*batchv1.Job,
 		volumes = append(volumes, backup.Spec.Local.Volume)
 		volumeMounts = append(volumeMounts, backup.Spec.Local.VolumeMount)
 	}
+	volumes, volumeMounts = backuputil.AppendSecondaryStorageVolumes(backup, volumes, volumeMounts)
+	volumes, volumeMounts = backuputil.AppendEncryptionKeyVolume(backup.Spec.Encryption, volumes, volumeMounts)
 
 	serviceAccount := constants.DefaultServiceAccountName
 	if backup.Spec.ServiceAccount != "" {
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
@@ -50,6 +51,8 @@ func NewBackupScheduleManager(deps *controller.Dependencies) backup.BackupSchedu
 }
 
 func (bm *backupScheduleManager) Sync(bs *v1alpha1.BackupSchedule) error {
+	// the recoverable window is updated after the backups are GC'ed
+	defer bm.updateRecoverableWindow(bs)
 	defer bm.backupGC(bs)
 
 	if bs.Spec.Pause {
@@ -65,6 +68,7 @@ func (bm *backupScheduleManager) Sync(bs *v1alpha1.BackupSchedule) error {
 	}
 
 	scheduledTime, err := getLastScheduledTime(bs, bm.now)
+	scheduledTime, err = bm.checkExecutionWindows(bs, scheduledTime, err)
 	if scheduledTime == nil {
 		return err
 	}
@@ -82,9 +86,59 @@ func (bm *backupScheduleManager) Sync(bs *v1alpha1.BackupSchedule) error {
 	bs.Status.LastBackup = backup.GetName()
 	bs.Status.LastBackupTime = &metav1.Time{Time: *scheduledTime}
 	bs.Status.AllBackupCleanTime = nil
+	bs.Status.DeferredBackupTime = nil
 	return nil
 }
 
+// checkExecutionWindows checks whether the backup can be created now according to ExecutionWindows.
+// The scheduled backup out of the windows is skipped or deferred according to OutOfWindowPolicy,
+// and the deferred backup is returned if there is no new scheduled backup when the next window opens.
+func (bm *backupScheduleManager) checkExecutionWindows(bs *v1alpha1.BackupSchedule, scheduledTime *time.Time, err error) (*time.Time, error) {
+	ns := bs.GetNamespace()
+	bsName := bs.GetName()
+
+	if len(bs.Spec.ExecutionWindows) == 0 {
+		return scheduledTime, err
+	}
+	if scheduledTime == nil && bs.Status.DeferredBackupTime == nil {
+		return nil, err
+	}
+
+	now := bm.now()
+	inWindow, windowErr := v1alpha1.IsInTimeWindows(bs.Spec.ExecutionWindows, now)
+	if windowErr != nil {
+		return nil, fmt.Errorf("backup schedule %s/%s, check execution windows failed, err: %v", ns, bsName, windowErr)
+	}
+	if inWindow {
+		if scheduledTime == nil {
+			klog.Infof("backup schedule %s/%s, create the backup deferred from %s in the execution window",
+				ns, bsName, bs.Status.DeferredBackupTime.Format(time.RFC3339))
+			deferredTime := bs.Status.DeferredBackupTime.Time
+			return &deferredTime, nil
+		}
+		return scheduledTime, err
+	}
+	if scheduledTime == nil {
+		return nil, err
+	}
+
+	if bs.Spec.OutOfWindowPolicy == v1alpha1.OutOfWindowPolicyDefer {
+		// only the latest scheduled backup is deferred
+		bs.Status.DeferredBackupTime = &metav1.Time{Time: *scheduledTime}
+		klog.Infof("backup schedule %s/%s, defer the backup scheduled at %s to the next execution window", ns, bsName, scheduledTime.Format(time.RFC3339))
+		bm.deps.Recorder.Eventf(bs, corev1.EventTypeNormal, "BackupDeferred",
+			"backup scheduled at %s is deferred to the next execution window", scheduledTime.Format(time.RFC3339))
+		return nil, nil
+	}
+
+	bs.Status.LastSkippedBackupTime = &metav1.Time{Time: *scheduledTime}
+	bs.Status.SkippedBackups++
+	klog.Infof("backup schedule %s/%s, skip the backup scheduled at %s out of the execution windows", ns, bsName, scheduledTime.Format(time.RFC3339))
+	bm.deps.Recorder.Eventf(bs, corev1.EventTypeWarning, "BackupSkipped",
+		"backup scheduled at %s is skipped out of the execution windows", scheduledTime.Format(time.RFC3339))
+	return nil, nil
+}
+
 func (bm *backupScheduleManager) deleteLastBackupJob(bs *v1alpha1.BackupSchedule) error {
 	ns := bs.GetNamespace()
 	bsName := bs.GetName()
@@ -177,6 +231,13 @@ func getLastScheduledTime(bs *v1alpha1.BackupSchedule, nowFn nowFn) (*time.Time,
 		earliestTime = bs.ObjectMeta.CreationTimestamp.Time
 	}
 
+	// the scheduled times skipped or deferred out of the execution windows are not scheduled again
+	for _, t := range []*metav1.Time{bs.Status.LastSkippedBackupTime, bs.Status.DeferredBackupTime} {
+		if t != nil && t.After(earliestTime) {
+			earliestTime = t.Time
+		}
+	}
+
 	now := nowFn()
 	if earliestTime.After(now) {
 		// timestamp fallback, waiting for the next backup schedule period
@@ -243,14 +304,10 @@ func buildBackup(bs *v1alpha1.BackupSchedule, timestamp time.Time) *v1alpha1.Bac
 		pdAddress = fmt.Sprintf("%s-pd.%s:%d", backupSpec.BR.Cluster, clusterNamespace, v1alpha1.DefaultPDClientPort)
 
 		backupPrefix := strings.ReplaceAll(pdAddress, ":", "-") + "-" + timestamp.UTC().Format(v1alpha1.BackupNameTimeFormat)
-		if backupSpec.S3 != nil {
-			backupSpec.S3.Prefix = path.Join(backupSpec.S3.Prefix, backupPrefix)
-		} else if backupSpec.Gcs != nil {
-			backupSpec.Gcs.Prefix = path.Join(backupSpec.Gcs.Prefix, backupPrefix)
-		} else if backupSpec.Azblob != nil {
-			backupSpec.Azblob.Prefix = path.Join(backupSpec.Azblob.Prefix, backupPrefix)
-		} else if backupSpec.Local != nil {
-			backupSpec.Local.Prefix = path.Join(backupSpec.Local.Prefix, backupPrefix)
+		joinStoragePrefix(&backupSpec.StorageProvider, backupPrefix)
+		// the copies in the secondary storages have the same layout as the backup
+		for i := range backupSpec.SecondaryStorages {
+			joinStoragePrefix(&backupSpec.SecondaryStorages[i].StorageProvider, backupPrefix)
 		}
 	}
 
@@ -275,6 +332,19 @@ func buildBackup(bs *v1alpha1.BackupSchedule, timestamp time.Time) *v1alpha1.Bac
 	return backup
 }
 
+// joinStoragePrefix joins the prefix of the storage with the backup prefix
+func joinStoragePrefix(provider *v1alpha1.StorageProvider, backupPrefix string) {
+	if provider.S3 != nil {
+		provider.S3.Prefix = path.Join(provider.S3.Prefix, backupPrefix)
+	} else if provider.Gcs != nil {
+		provider.Gcs.Prefix = path.Join(provider.Gcs.Prefix, backupPrefix)
+	} else if provider.Azblob != nil {
+		provider.Azblob.Prefix = path.Join(provider.Azblob.Prefix, backupPrefix)
+	} else if provider.Local != nil {
+		provider.Local.Prefix = path.Join(provider.Local.Prefix, backupPrefix)
+	}
+}
+
 func buildLogBackup(bs *v1alpha1.BackupSchedule, timestamp time.Time) *v1alpha1.Backup {
 	ns := bs.GetNamespace()
 	bsName := bs.GetName()
@@ -326,6 +396,12 @@ func (bm *backupScheduleManager) backupGC(bs *v1alpha1.BackupSchedule) {
 	ns := bs.GetNamespace()
 	bsName := bs.GetName()
 
+	// if RetentionPolicy is set, it is preferred and MaxReservedTime is used as the PiTR window.
+	if bs.Spec.RetentionPolicy != nil {
+		bm.backupGCByRetentionPolicy(bs)
+		return
+	}
+
 	// if MaxBackups and MaxReservedTime are set at the same time, MaxReservedTime is preferred.
 	if bs.Spec.MaxReservedTime != nil {
 		bm.backupGCByMaxReservedTime(bs)
@@ -380,9 +456,53 @@ func (bm *backupScheduleManager) backupGCByMaxReservedTime(bs *v1alpha1.BackupSc
 		}
 	}
 
+	bm.gcExpiredBackups(bs, backupsList, expiredBackups, logBackup, truncateTSO)
+}
+
+func (bm *backupScheduleManager) backupGCByRetentionPolicy(bs *v1alpha1.BackupSchedule) {
+	ns := bs.GetNamespace()
+	bsName := bs.GetName()
+
+	var (
+		reservedTime time.Duration
+		err          error
+	)
+	if bs.Spec.MaxReservedTime != nil {
+		reservedTime, err = time.ParseDuration(*bs.Spec.MaxReservedTime)
+		if err != nil {
+			klog.Errorf("backup schedule %s/%s, invalid MaxReservedTime %s", ns, bsName, *bs.Spec.MaxReservedTime)
+			return
+		}
+	}
+
+	backupsList, err := bm.getBackupList(bs)
+	if err != nil {
+		klog.Errorf("backupGCByRetentionPolicy, err: %s", err)
+		return
+	}
+
+	ascBackups, logBackup := separateSnapshotBackupsAndLogBackup(backupsList)
+	if len(ascBackups) == 0 {
+		return
+	}
+
+	expiredBackups, truncateTSO, err := calExpiredBackupsByRetentionPolicy(ascBackups, logBackup, bs.Spec.RetentionPolicy, reservedTime)
+	if err != nil {
+		klog.Errorf("caculate expired backups by retention policy, err: %s", err)
+		return
+	}
+
+	bm.gcExpiredBackups(bs, backupsList, expiredBackups, logBackup, truncateTSO)
+}
+
+// gcExpiredBackups deletes the expired backups and truncates the log backup to truncateTSO if it is not 0
+func (bm *backupScheduleManager) gcExpiredBackups(bs *v1alpha1.BackupSchedule, backupsList, expiredBackups []*v1alpha1.Backup, logBackup *v1alpha1.Backup, truncateTSO uint64) {
+	ns := bs.GetNamespace()
+	bsName := bs.GetName()
+
 	for _, backup := range expiredBackups {
 		// delete the expired backup
-		if err = bm.deps.BackupControl.DeleteBackup(backup); err != nil {
+		if err := bm.deps.BackupControl.DeleteBackup(backup); err != nil {
 			klog.Errorf("backup schedule %s/%s gc backup %s failed, err %v", ns, bsName, backup.GetName(), err)
 			return
 		}
@@ -391,7 +511,7 @@ func (bm *backupScheduleManager) backupGCByMaxReservedTime(bs *v1alpha1.BackupSc
 
 	if truncateTSO > 0 {
 		// truncate the log backup
-		if err = bm.deps.BackupControl.TruncateLogBackup(logBackup, truncateTSO); err != nil {
+		if err := bm.deps.BackupControl.TruncateLogBackup(logBackup, truncateTSO); err != nil {
 			klog.Errorf("backup schedule %s/%s truncate log backup %s failed, truncateTSO %d, err %v", ns, bsName, logBackup.GetName(), truncateTSO, err)
 			return
 		}
@@ -404,6 +524,99 @@ func (bm *backupScheduleManager) backupGCByMaxReservedTime(bs *v1alpha1.BackupSc
 	}
 }
 
+// calExpiredBackupsByRetentionPolicy calculate what backups and log backup we can delete or truncate by the retention policy.
+//
+// The backups selected by any tier of the policy are kept. If there is log backup and reservedTime is not 0,
+// the snapshot backups needed to restore to any time in the last reservedTime are kept too,
+// and the log backup is truncated to the start of the PiTR window as calExpiredBackupsAndLogBackup does.
+// Otherwise the log backup is truncated to the earliest kept backup, because the log before it can not be used by PiTR.
+func calExpiredBackupsByRetentionPolicy(backupsList []*v1alpha1.Backup, logBackup *v1alpha1.Backup, policy *v1alpha1.BackupRetentionPolicy, reservedTime time.Duration) ([]*v1alpha1.Backup, uint64, error) {
+	kept := calKeptBackupsByRetentionPolicy(backupsList, policy)
+
+	var expiredTSO uint64
+	if logBackup != nil && reservedTime > 0 {
+		latestTSO, err := calculateLatestTSO(backupsList, logBackup)
+		if err != nil {
+			return nil, 0, perrors.Annotate(err, "calculate latest tso")
+		}
+		expiredTSO = calculateExpiredTSO(latestTSO, reservedTime)
+
+		pitrExpiredBackups, err := calExpiredBackupsWithLogBackupOn(backupsList, expiredTSO)
+		if err != nil {
+			return nil, 0, perrors.Annotate(err, "calculate backups which are not needed by PiTR")
+		}
+		for i := len(pitrExpiredBackups); i < len(backupsList); i++ {
+			kept[i] = true
+		}
+	}
+
+	var (
+		expiredBackups []*v1alpha1.Backup
+		earliestKept   = -1
+	)
+	for i, backup := range backupsList {
+		if !kept[i] {
+			expiredBackups = append(expiredBackups, backup)
+		} else if earliestKept < 0 {
+			earliestKept = i
+		}
+	}
+
+	// same as calExpiredBackupsAndLogBackup, only truncate the log backup when there are backups to delete
+	if len(expiredBackups) == 0 || logBackup == nil {
+		return expiredBackups, 0, nil
+	}
+
+	if expiredTSO == 0 {
+		earliestKeptTSO, err := config.ParseTSString(backupsList[earliestKept].Status.CommitTs)
+		if err != nil {
+			return nil, 0, perrors.Annotatef(err, "parse backup ts of backup %s/%s", backupsList[earliestKept].Namespace, backupsList[earliestKept].Name)
+		}
+		expiredTSO = earliestKeptTSO
+	}
+
+	truncateTSO, err := calLogBackupTruncateTSO(backupsList, logBackup, expiredTSO)
+	if err != nil {
+		return nil, 0, perrors.Annotate(err, "calculate expired log backup tso which should be truncated")
+	}
+	return expiredBackups, truncateTSO, nil
+}
+
+// calKeptBackupsByRetentionPolicy returns the indexes of the backups kept by the tiers of the retention policy.
+// The backups are ordered by create time asc, and the latest backup is always kept.
+// For each tier, the latest complete backup of each of the latest N periods is kept.
+func calKeptBackupsByRetentionPolicy(backupsList []*v1alpha1.Backup, policy *v1alpha1.BackupRetentionPolicy) map[int]bool {
+	tiers := []struct {
+		keep   int32
+		period func(t time.Time) string
+	}{
+		{keep: policy.Hourly, period: func(t time.Time) string { return t.Format("2006-01-02T15") }},
+		{keep: policy.Daily, period: func(t time.Time) string { return t.Format("2006-01-02") }},
+		{keep: policy.Weekly, period: func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{keep: policy.Monthly, period: func(t time.Time) string { return t.Format("2006-01") }},
+	}
+
+	kept := map[int]bool{len(backupsList) - 1: true}
+	for _, tier := range tiers {
+		periods := map[string]bool{}
+		for i := len(backupsList) - 1; i >= 0 && len(periods) < int(tier.keep); i-- {
+			if !v1alpha1.IsBackupComplete(backupsList[i]) {
+				continue
+			}
+			period := tier.period(backupsList[i].CreationTimestamp.UTC())
+			if periods[period] {
+				continue
+			}
+			periods[period] = true
+			kept[i] = true
+		}
+	}
+	return kept
+}
+
 // separateSnapshotBackupsAndLogBackup return snapot backups ordry by create time asc and log backup
 func separateSnapshotBackupsAndLogBackup(backupsList []*v1alpha1.Backup) ([]*v1alpha1.Backup, *v1alpha1.Backup) {
 	var (
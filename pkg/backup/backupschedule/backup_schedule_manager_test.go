@@ -232,6 +232,19 @@ func TestBuildBackup(t *testing.T) {
 	if diff := cmp.Diff(bk, get); diff != "" {
 		t.Errorf("unexpected (-want, +got): %s", diff)
 	}
+
+	// the prefixes of the secondary storages are joined with the backup prefix
+	g := NewGomegaWithT(t)
+	bs.Spec.BackupTemplate.BR.Cluster = "demo"
+	bs.Spec.BackupTemplate.S3 = &v1alpha1.S3StorageProvider{Bucket: "primary", Prefix: "backups"}
+	bs.Spec.BackupTemplate.SecondaryStorages = []v1alpha1.SecondaryStorage{
+		{Name: "dr", StorageProvider: v1alpha1.StorageProvider{Gcs: &v1alpha1.GcsStorageProvider{Bucket: "secondary", Prefix: "dr"}}},
+	}
+	get = buildBackup(bs, now)
+	backupPrefix := "demo-pd.ns-2379-" + now.UTC().Format(v1alpha1.BackupNameTimeFormat)
+	g.Expect(get.Spec.S3.Prefix).Should(Equal("backups/" + backupPrefix))
+	g.Expect(get.Spec.SecondaryStorages[0].Gcs.Prefix).Should(Equal("dr/" + backupPrefix))
+	g.Expect(bs.Spec.BackupTemplate.SecondaryStorages[0].Gcs.Prefix).Should(Equal("dr"))
 }
 
 func TestCaculateExpiredBackupsWithLogBackup(t *testing.T) {
@@ -336,6 +349,269 @@ func TestCaculateExpiredBackupsWithLogBackup(t *testing.T) {
 	}
 }
 
+func TestCalExpiredBackupsByRetentionPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// one backup every 6 hours in the last 60 days
+	now := time.Date(2023, 3, 31, 23, 0, 0, 0, time.UTC)
+	var backups []*v1alpha1.Backup
+	for i := 60*4 - 1; i >= 0; i-- {
+		ts := now.Add(-time.Duration(i) * 6 * time.Hour)
+		backup := fakeBackup(pointer.Int64Ptr(ts.Unix()))
+		backup.Name = ts.Format("2006-01-02T15")
+		backup.CreationTimestamp = metav1.NewTime(ts)
+		backup.Status.Conditions = []v1alpha1.BackupCondition{{Type: v1alpha1.BackupComplete, Status: v1.ConditionTrue}}
+		backups = append(backups, backup)
+	}
+	keptNames := func(expired []*v1alpha1.Backup) []string {
+		expiredNames := map[string]bool{}
+		for _, backup := range expired {
+			expiredNames[backup.Name] = true
+		}
+		var names []string
+		for _, backup := range backups {
+			if !expiredNames[backup.Name] {
+				names = append(names, backup.Name)
+			}
+		}
+		return names
+	}
+
+	// the tiers are merged, and the latest backup is always kept
+	expired, truncateTSO, err := calExpiredBackupsByRetentionPolicy(backups, nil, &v1alpha1.BackupRetentionPolicy{
+		Hourly:  2,
+		Daily:   2,
+		Weekly:  2,
+		Monthly: 3,
+	}, 0)
+	g.Expect(err).Should(BeNil())
+	g.Expect(truncateTSO).Should(BeZero())
+	g.Expect(keptNames(expired)).Should(Equal([]string{
+		"2023-01-31T23",
+		"2023-02-28T23",
+		"2023-03-26T23",
+		"2023-03-30T23",
+		"2023-03-31T17",
+		"2023-03-31T23",
+	}))
+
+	expired, _, err = calExpiredBackupsByRetentionPolicy(backups, nil, &v1alpha1.BackupRetentionPolicy{}, 0)
+	g.Expect(err).Should(BeNil())
+	g.Expect(keptNames(expired)).Should(Equal([]string{"2023-03-31T23"}))
+
+	// the log backup is truncated to the earliest kept backup without the PiTR window
+	logBackup := fakeLogBackup(pointer.Int64Ptr(now.AddDate(0, 0, -90).Unix()), pointer.Int64Ptr(now.Unix()))
+	expired, truncateTSO, err = calExpiredBackupsByRetentionPolicy(backups, logBackup, &v1alpha1.BackupRetentionPolicy{Daily: 7}, 0)
+	g.Expect(err).Should(BeNil())
+	g.Expect(keptNames(expired)).Should(HaveLen(7))
+	g.Expect(truncateTSO).Should(Equal(getTSO(now.AddDate(0, 0, -6).Unix())))
+
+	// the backups needed by the PiTR window are kept
+	expired, truncateTSO, err = calExpiredBackupsByRetentionPolicy(backups, logBackup, &v1alpha1.BackupRetentionPolicy{Monthly: 2}, 25*time.Hour)
+	g.Expect(err).Should(BeNil())
+	g.Expect(keptNames(expired)).Should(Equal([]string{
+		"2023-02-28T23",
+		"2023-03-30T17",
+		"2023-03-30T23",
+		"2023-03-31T05",
+		"2023-03-31T11",
+		"2023-03-31T17",
+		"2023-03-31T23",
+	}))
+	g.Expect(truncateTSO).Should(Equal(getTSO(now.Add(-30 * time.Hour).Unix())))
+
+	// the incomplete backups are not kept by the tiers
+	backups[len(backups)-2].Status.Conditions = []v1alpha1.BackupCondition{{Type: v1alpha1.BackupFailed, Status: v1.ConditionTrue}}
+	expired, _, err = calExpiredBackupsByRetentionPolicy(backups, nil, &v1alpha1.BackupRetentionPolicy{Hourly: 2}, 0)
+	g.Expect(err).Should(BeNil())
+	g.Expect(keptNames(expired)).Should(Equal([]string{"2023-03-31T11", "2023-03-31T23"}))
+}
+
+func TestCheckExecutionWindows(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+
+	var now time.Time
+	m := &backupScheduleManager{
+		deps: helper.deps,
+		now:  func() time.Time { return now },
+	}
+	bs := &v1alpha1.BackupSchedule{}
+	bs.Namespace = "ns"
+	bs.Name = "bs"
+	bs.Spec.Schedule = "0 * * * *" // Run every hour
+	bs.Spec.ExecutionWindows = []v1alpha1.TimeWindow{{Start: "22:00", End: "06:00"}}
+	at := func(hour int) time.Time {
+		return time.Date(2023, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+	timePtr := func(t time.Time) *time.Time {
+		return &t
+	}
+	check := func() *time.Time {
+		scheduledTime, err := getLastScheduledTime(bs, m.now)
+		g.Expect(err).Should(BeNil())
+		scheduledTime, err = m.checkExecutionWindows(bs, scheduledTime, err)
+		g.Expect(err).Should(BeNil())
+		return scheduledTime
+	}
+
+	// the backup in the window is created
+	bs.Status.LastBackupTime = &metav1.Time{Time: at(4)}
+	now = at(5).Add(time.Minute)
+	g.Expect(check()).Should(Equal(timePtr(at(5))))
+	bs.Status.LastBackupTime = &metav1.Time{Time: at(5)}
+
+	// the backups out of the window are skipped
+	now = at(6).Add(time.Minute)
+	g.Expect(check()).Should(BeNil())
+	now = at(7).Add(time.Minute)
+	g.Expect(check()).Should(BeNil())
+	g.Expect(bs.Status.SkippedBackups).Should(Equal(int32(2)))
+	g.Expect(bs.Status.LastSkippedBackupTime.Time).Should(Equal(at(7)))
+	// the skipped backup is not scheduled again
+	g.Expect(check()).Should(BeNil())
+	g.Expect(bs.Status.SkippedBackups).Should(Equal(int32(2)))
+
+	// the backups out of the window are deferred to the next window
+	bs.Spec.OutOfWindowPolicy = v1alpha1.OutOfWindowPolicyDefer
+	now = at(8).Add(time.Minute)
+	g.Expect(check()).Should(BeNil())
+	now = at(9).Add(time.Minute)
+	g.Expect(check()).Should(BeNil())
+	g.Expect(bs.Status.DeferredBackupTime.Time).Should(Equal(at(9)))
+	g.Expect(bs.Status.SkippedBackups).Should(Equal(int32(2)))
+	now = at(9).Add(30 * time.Minute)
+	g.Expect(check()).Should(BeNil())
+
+	// the deferred backup is created when the window opens
+	bs.Spec.ExecutionWindows = []v1alpha1.TimeWindow{{Start: "09:30", End: "10:30"}}
+	g.Expect(check()).Should(Equal(timePtr(at(9))))
+}
+
+func TestCalRecoverableWindow(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hour := func(h int64) int64 {
+		return time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix() + h*3600
+	}
+	var backups []*v1alpha1.Backup
+	for _, h := range []int64{1, 3, 5, 7, 9} {
+		backup := fakeBackup(pointer.Int64Ptr(hour(h)))
+		backup.Name = fmt.Sprintf("backup-%d", h)
+		backup.Status.Conditions = []v1alpha1.BackupCondition{{Type: v1alpha1.BackupComplete, Status: v1.ConditionTrue}}
+		backups = append(backups, backup)
+	}
+	// the failed backup is not restorable
+	backups[3].Status.Conditions[0].Type = v1alpha1.BackupFailed
+
+	window, err := calRecoverableWindow(nil, nil)
+	g.Expect(err).Should(BeNil())
+	g.Expect(window).Should(BeNil())
+
+	// only the snapshot backups
+	window, err = calRecoverableWindow(backups, nil)
+	g.Expect(err).Should(BeNil())
+	g.Expect(window.EarliestTs).Should(Equal(getTSOStr(hour(1))))
+	g.Expect(window.EarliestTime.Time.Equal(time.Unix(hour(1), 0))).Should(BeTrue())
+	g.Expect(window.LatestTs).Should(Equal(getTSOStr(hour(9))))
+	g.Expect(window.LatestTime.Time.Equal(time.Unix(hour(9), 0))).Should(BeTrue())
+	g.Expect(window.Gaps).Should(Equal([]v1alpha1.BackupCoverageGap{
+		{StartTs: getTSOStr(hour(1)), EndTs: getTSOStr(hour(3))},
+		{StartTs: getTSOStr(hour(3)), EndTs: getTSOStr(hour(5))},
+		{StartTs: getTSOStr(hour(5)), EndTs: getTSOStr(hour(9))},
+	}))
+
+	// the log backup is truncated to hour 4, and its checkpoint is at hour 8
+	logBackup := fakeLogBackup(pointer.Int64Ptr(hour(0)), pointer.Int64Ptr(hour(8)))
+	logBackup.Status.LogSuccessTruncateUntil = getTSOStr(hour(4))
+	window, err = calRecoverableWindow(backups, logBackup)
+	g.Expect(err).Should(BeNil())
+	g.Expect(window.EarliestTs).Should(Equal(getTSOStr(hour(1))))
+	g.Expect(window.LatestTs).Should(Equal(getTSOStr(hour(9))))
+	g.Expect(window.Gaps).Should(Equal([]v1alpha1.BackupCoverageGap{
+		{StartTs: getTSOStr(hour(1)), EndTs: getTSOStr(hour(3))},
+		{StartTs: getTSOStr(hour(3)), EndTs: getTSOStr(hour(5))},
+		{StartTs: getTSOStr(hour(8)), EndTs: getTSOStr(hour(9))},
+	}))
+
+	// the log backup covers all the backups
+	logBackup = fakeLogBackup(pointer.Int64Ptr(hour(0)), pointer.Int64Ptr(hour(10)))
+	window, err = calRecoverableWindow(backups, logBackup)
+	g.Expect(err).Should(BeNil())
+	g.Expect(window.EarliestTs).Should(Equal(getTSOStr(hour(1))))
+	g.Expect(window.LatestTs).Should(Equal(getTSOStr(hour(10))))
+	g.Expect(window.Gaps).Should(BeEmpty())
+}
+
+func TestUpdateRPOCondition(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+
+	now := time.Now()
+	m := &backupScheduleManager{
+		deps: helper.deps,
+		now:  func() time.Time { return now },
+	}
+	bs := &v1alpha1.BackupSchedule{}
+	bs.Namespace = "ns"
+	bs.Name = "bs"
+
+	// no condition without the RPO
+	m.updateRPOCondition(bs)
+	g.Expect(bs.Status.Conditions).Should(BeEmpty())
+
+	bs.Spec.RecoveryPointObjective = pointer.StringPtr("1h")
+	m.updateRPOCondition(bs)
+	g.Expect(v1alpha1.IsBackupScheduleRPOViolated(bs)).Should(BeTrue())
+	_, condition := v1alpha1.GetBackupScheduleCondition(&bs.Status, v1alpha1.BackupScheduleRPOViolated)
+	g.Expect(condition.Reason).Should(Equal("NoRestorableBackup"))
+
+	bs.Status.RecoverableWindow = &v1alpha1.BackupRecoverableWindow{
+		LatestTime: &metav1.Time{Time: now.Add(-30 * time.Minute)},
+	}
+	m.updateRPOCondition(bs)
+	g.Expect(v1alpha1.IsBackupScheduleRPOViolated(bs)).Should(BeFalse())
+
+	bs.Status.RecoverableWindow.LatestTime = &metav1.Time{Time: now.Add(-2 * time.Hour)}
+	m.updateRPOCondition(bs)
+	g.Expect(v1alpha1.IsBackupScheduleRPOViolated(bs)).Should(BeTrue())
+	_, condition = v1alpha1.GetBackupScheduleCondition(&bs.Status, v1alpha1.BackupScheduleRPOViolated)
+	g.Expect(condition.Reason).Should(Equal("LatestRestorableTimeBehindRPO"))
+	g.Expect(condition.Message).Should(ContainSubstring("2h0m0s behind"))
+
+	// the condition is removed if the RPO is removed
+	bs.Spec.RecoveryPointObjective = nil
+	m.updateRPOCondition(bs)
+	g.Expect(bs.Status.Conditions).Should(BeEmpty())
+}
+
+func TestSyncInvalidRPO(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.close()
+
+	now := time.Now()
+	m := &backupScheduleManager{deps: helper.deps, now: func() time.Time { return now.AddDate(0, 0, -2) }}
+	bs := &v1alpha1.BackupSchedule{}
+	bs.Namespace = "ns"
+	bs.Name = "bs"
+	bs.Spec.Schedule = "0 0 * * *"
+	bs.Spec.RecoveryPointObjective = pointer.StringPtr("1day")
+	m.resetLastBackup(bs)
+
+	// the backup is still scheduled with an invalid RPO, which is reported by the RPO condition
+	m.now = func() time.Time { return now }
+	g.Expect(m.Sync(bs)).Should(Succeed())
+	helper.checkBacklist(bs.Namespace, 1, false)
+	_, condition := v1alpha1.GetBackupScheduleCondition(&bs.Status, v1alpha1.BackupScheduleRPOViolated)
+	g.Expect(condition).ShouldNot(BeNil())
+	g.Expect(condition.Status).Should(Equal(v1.ConditionUnknown))
+	g.Expect(condition.Reason).Should(Equal("InvalidRPO"))
+	g.Expect(condition.Message).Should(ContainSubstring("recoveryPointObjective"))
+}
+
 type helper struct {
 	t    *testing.T
 	deps *controller.Dependencies
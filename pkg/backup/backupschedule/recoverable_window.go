@@ -0,0 +1,169 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupschedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	perrors "github.com/pingcap/errors"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// tsoRange is a restorable range [start, end] of TSO
+type tsoRange struct {
+	start uint64
+	end   uint64
+}
+
+// updateRecoverableWindow updates the recoverable window of the backup schedule,
+// and the RPOViolated condition if RecoveryPointObjective is set.
+func (bm *backupScheduleManager) updateRecoverableWindow(bs *v1alpha1.BackupSchedule) {
+	ns := bs.GetNamespace()
+	bsName := bs.GetName()
+
+	backupsList, err := bm.getBackupList(bs)
+	if err != nil {
+		klog.Errorf("updateRecoverableWindow, err: %s", err)
+		return
+	}
+
+	ascBackups, logBackup := separateSnapshotBackupsAndLogBackup(backupsList)
+	window, err := calRecoverableWindow(ascBackups, logBackup)
+	if err != nil {
+		klog.Errorf("backup schedule %s/%s, calculate recoverable window failed, err: %s", ns, bsName, err)
+		return
+	}
+	bs.Status.RecoverableWindow = window
+
+	bm.updateRPOCondition(bs)
+}
+
+// updateRPOCondition sets the RPOViolated condition to true if the latest restorable time falls behind
+// RecoveryPointObjective, and removes the condition if RecoveryPointObjective is not set. The condition is
+// unknown if RecoveryPointObjective is invalid, which doesn't stop the backups from being scheduled.
+func (bm *backupScheduleManager) updateRPOCondition(bs *v1alpha1.BackupSchedule) {
+	if bs.Spec.RecoveryPointObjective == nil {
+		if i, _ := v1alpha1.GetBackupScheduleCondition(&bs.Status, v1alpha1.BackupScheduleRPOViolated); i >= 0 {
+			bs.Status.Conditions = append(bs.Status.Conditions[:i], bs.Status.Conditions[i+1:]...)
+		}
+		return
+	}
+
+	condition := &v1alpha1.BackupScheduleCondition{
+		Type:   v1alpha1.BackupScheduleRPOViolated,
+		Status: corev1.ConditionFalse,
+		Reason: "LatestRestorableTimeInRPO",
+	}
+	// the RPO is used only if it's valid
+	rpo, _ := time.ParseDuration(*bs.Spec.RecoveryPointObjective)
+	if errs := validation.ValidateBackupSchedule(bs); len(errs) > 0 {
+		condition.Status = corev1.ConditionUnknown
+		condition.Reason = "InvalidRPO"
+		condition.Message = errs.ToAggregate().Error()
+	} else if window := bs.Status.RecoverableWindow; window == nil {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "NoRestorableBackup"
+		condition.Message = "no complete backup can be restored"
+	} else if lag := bm.now().Sub(window.LatestTime.Time); lag > rpo {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "LatestRestorableTimeBehindRPO"
+		condition.Message = fmt.Sprintf("the latest restorable time %s is %s behind, exceeds the recovery point objective %s",
+			window.LatestTime.UTC().Format(time.RFC3339), lag.Truncate(time.Second), rpo)
+	}
+
+	if v1alpha1.UpdateBackupScheduleCondition(&bs.Status, condition) && condition.Status != corev1.ConditionFalse {
+		bm.deps.Recorder.Event(bs, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+}
+
+// calRecoverableWindow calculates the recoverable window by the complete snapshot backups and the log backup.
+// Each complete snapshot backup can be restored to its commit ts, and the log backup can restore to any time
+// between the earliest snapshot backup within its range and its checkpoint ts. The gaps are the ranges between
+// the restorable ranges. It returns nil if there is no complete snapshot backup.
+//
+// snapshot1-------snapshot2-----------snapshot3-------snapshot4---------------------> snapshot backups
+// --------------------------------truncateTS-------------------------checkpointTS--> log backup
+// ---[t1]--gap--[t2]--------gap------[t3, checkpointTS]-----------------------------> recoverable window
+func calRecoverableWindow(backupsList []*v1alpha1.Backup, logBackup *v1alpha1.Backup) (*v1alpha1.BackupRecoverableWindow, error) {
+	var (
+		ranges          []tsoRange
+		completeBackups []*v1alpha1.Backup
+	)
+	for _, backup := range backupsList {
+		if !backuputil.IsPitrFullBackupCandidate(backup) {
+			continue
+		}
+		commitTSO, err := config.ParseTSString(backup.Status.CommitTs)
+		if err != nil {
+			return nil, perrors.Annotatef(err, "parse backup ts of backup %s/%s", backup.Namespace, backup.Name)
+		}
+		ranges = append(ranges, tsoRange{start: commitTSO, end: commitTSO})
+		completeBackups = append(completeBackups, backup)
+	}
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+
+	if logBackup != nil {
+		pitrStartTSO, pitrEndTSO, err := backuputil.GetPitrRecoverableWindow(completeBackups, logBackup)
+		if err != nil {
+			return nil, err
+		}
+		if pitrStartTSO != 0 {
+			ranges = append(ranges, tsoRange{start: pitrStartTSO, end: pitrEndTSO})
+		}
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+	window := &v1alpha1.BackupRecoverableWindow{}
+	current := ranges[0]
+	for _, r := range ranges[1:] {
+		if r.start <= current.end {
+			if r.end > current.end {
+				current.end = r.end
+			}
+			continue
+		}
+		window.Gaps = append(window.Gaps, v1alpha1.BackupCoverageGap{
+			StartTs: strconv.FormatUint(current.end, 10),
+			EndTs:   strconv.FormatUint(r.start, 10),
+		})
+		current = r
+	}
+
+	earliestTSO, latestTSO := ranges[0].start, current.end
+	window.EarliestTs = strconv.FormatUint(earliestTSO, 10)
+	window.EarliestTime = newTSOTime(earliestTSO)
+	window.LatestTs = strconv.FormatUint(latestTSO, 10)
+	window.LatestTime = newTSOTime(latestTSO)
+	return window, nil
+}
+
+// newTSOTime returns the time of the TSO in the precision of seconds, which is the same as the serialized
+// time, so that the status is not updated again if the TSO is not changed
+func newTSOTime(tso uint64) *metav1.Time {
+	t := metav1.NewTime(time.Unix(config.TSOToTS(tso), 0))
+	return &t
+}
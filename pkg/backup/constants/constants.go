@@ -59,6 +59,9 @@ const (
 	// BR certificate storage path
 	BRCertPath = "/var/lib/br-tls"
 
+	// BREncryptionKeyPath is the path where the encryption key of BR is mounted
+	BREncryptionKeyPath = "/var/lib/br-encryption"
+
 	// ServiceAccountCAPath is where is CABundle of serviceaccount locates
 	ServiceAccountCAPath = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
 
@@ -0,0 +1,257 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// isClusterCreatedByRestore returns whether the TidbCluster is created from the template of the restore
+func isClusterCreatedByRestore(tc *v1alpha1.TidbCluster, restore *v1alpha1.Restore) bool {
+	return tc.Labels[label.RestoreLabelKey] == restore.Name
+}
+
+// getTemplateClusterSpec returns the spec of the TidbCluster defined by the template of the restore
+func (rm *restoreManager) getTemplateClusterSpec(restore *v1alpha1.Restore) (*v1alpha1.TidbClusterSpec, error) {
+	template := restore.Spec.ClusterTemplate
+	if template.Spec != nil {
+		return template.Spec.DeepCopy(), nil
+	}
+
+	ref := template.SpecFrom
+	ns := ref.Namespace
+	if ns == "" {
+		ns = restore.Namespace
+	}
+	source, err := rm.deps.TiDBClusterLister.TidbClusters(ns).Get(ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tidbcluster %s/%s of specFrom, err: %v", ns, ref.Name, err)
+	}
+	spec := source.Spec.DeepCopy()
+	// the created cluster is independent of the source cluster and the clusters joined by it
+	spec.Cluster = nil
+	spec.PDAddresses = nil
+	spec.RecoveryMode = false
+	return spec, nil
+}
+
+// makeRecoveryClusterSpec returns the spec to run the TidbCluster during restore, the components replicating
+// or proxying the data are not created and TiDB runs one replica, they are added back after the restore.
+func makeRecoveryClusterSpec(spec *v1alpha1.TidbClusterSpec) *v1alpha1.TidbClusterSpec {
+	spec = spec.DeepCopy()
+	spec.TiCDC = nil
+	spec.Pump = nil
+	spec.TiProxy = nil
+	if spec.TiDB != nil && spec.TiDB.Replicas > 1 {
+		spec.TiDB.Replicas = 1
+	}
+	return spec
+}
+
+// createTemplateCluster creates the TidbCluster restored into from the template of the restore
+func (rm *restoreManager) createTemplateCluster(restore *v1alpha1.Restore, ns string) error {
+	name := restore.Spec.BR.Cluster
+
+	if errs := validation.ValidateRestore(restore); len(errs) > 0 {
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreInvalid,
+			Status:  corev1.ConditionTrue,
+			Reason:  "InvalidSpec",
+			Message: errs.ToAggregate().Error(),
+		}, nil)
+		return controller.IgnoreErrorf("invalid restore spec %s/%s: %v", restore.Namespace, restore.Name, errs.ToAggregate())
+	}
+
+	spec, err := rm.getTemplateClusterSpec(restore)
+	if err != nil {
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "GetClusterTemplateFailed",
+			Message: err.Error(),
+		}, nil)
+		return err
+	}
+	if spec.PD == nil || spec.TiKV == nil || spec.TiDB == nil {
+		msg := "pd, tikv and tidb should be set in the spec of the cluster template"
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreInvalid,
+			Status:  corev1.ConditionTrue,
+			Reason:  "InvalidClusterTemplate",
+			Message: msg,
+		}, nil)
+		return controller.IgnoreErrorf("restore %s/%s: %s", restore.Namespace, restore.Name, msg)
+	}
+
+	// validate the restore before creating the cluster, so that the cluster is not left behind by an invalid restore
+	templateTC := &v1alpha1.TidbCluster{Spec: *spec}
+	if err := backuputil.ValidateRestore(restore, templateTC.TiKVImage(), spec.AcrossK8s); err != nil {
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreInvalid,
+			Status:  corev1.ConditionTrue,
+			Reason:  "InvalidSpec",
+			Message: err.Error(),
+		}, nil)
+		return controller.IgnoreErrorf("invalid restore spec %s/%s", restore.Namespace, restore.Name)
+	}
+	if restore.Spec.Mode != v1alpha1.RestoreModeVolumeSnapshot {
+		if err := rm.resolveRestoreEncryption(restore); err != nil {
+			return err
+		}
+	}
+
+	template := restore.Spec.ClusterTemplate
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   ns,
+			Name:        name,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: *makeRecoveryClusterSpec(spec),
+	}
+	for k, v := range template.Labels {
+		tc.Labels[k] = v
+	}
+	for k, v := range template.Annotations {
+		tc.Annotations[k] = v
+	}
+	tc.Labels[label.RestoreLabelKey] = restore.Name
+
+	_, err = rm.deps.Clientset.PingcapV1alpha1().TidbClusters(ns).Create(context.TODO(), tc, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		// the cluster created in the last sync may not be synced to the lister yet,
+		// whether it is created from the template is checked after it is synced
+		klog.Infof("restore %s/%s tidbcluster %s/%s already exists", restore.Namespace, restore.Name, ns, name)
+	} else if err != nil {
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "CreateClusterFailed",
+			Message: err.Error(),
+		}, nil)
+		return fmt.Errorf("restore %s/%s create tidbcluster %s/%s failed, err: %v", restore.Namespace, restore.Name, ns, name, err)
+	} else {
+		klog.Infof("restore %s/%s created tidbcluster %s/%s from the cluster template", restore.Namespace, restore.Name, ns, name)
+		rm.deps.Recorder.Eventf(restore, corev1.EventTypeNormal, "ClusterCreated", "create tidbcluster %s/%s from the cluster template", ns, name)
+	}
+	phase := v1alpha1.RestoreClusterCreating
+	if err := rm.statusUpdater.Update(restore, nil, &controller.RestoreUpdateStatus{ClusterPhase: &phase}); err != nil {
+		return err
+	}
+	return controller.RequeueErrorf("restore %s/%s: waiting for tidbcluster %s/%s to be ready", restore.Namespace, restore.Name, ns, name)
+}
+
+// waitTemplateClusterReady waits until PD, TiKV and TiDB of the TidbCluster created from the template are ready
+func (rm *restoreManager) waitTemplateClusterReady(restore *v1alpha1.Restore, tc *v1alpha1.TidbCluster) error {
+	ns := restore.GetNamespace()
+	name := restore.GetName()
+
+	if !isClusterCreatedByRestore(tc, restore) {
+		msg := fmt.Sprintf("tidbcluster %s/%s already exists and is not created from the cluster template", tc.Namespace, tc.Name)
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreInvalid,
+			Status:  corev1.ConditionTrue,
+			Reason:  "ClusterAlreadyExists",
+			Message: msg,
+		}, nil)
+		return controller.IgnoreErrorf("restore %s/%s: %s", ns, name, msg)
+	}
+	if restore.Status.ClusterPhase != "" && restore.Status.ClusterPhase != v1alpha1.RestoreClusterCreating {
+		return nil
+	}
+
+	if !tc.PDAllMembersReady() {
+		return controller.RequeueErrorf("restore %s/%s: waiting for all PD members are ready in tidbcluster %s/%s", ns, name, tc.Namespace, tc.Name)
+	}
+	if !tc.TiKVAllStoresReady() {
+		return controller.RequeueErrorf("restore %s/%s: waiting for all TiKV stores are ready in tidbcluster %s/%s", ns, name, tc.Namespace, tc.Name)
+	}
+	if !tc.TiDBAllMembersReady() {
+		return controller.RequeueErrorf("restore %s/%s: waiting for all TiDB members are ready in tidbcluster %s/%s", ns, name, tc.Namespace, tc.Name)
+	}
+
+	phase := v1alpha1.RestoreClusterReady
+	if err := rm.statusUpdater.Update(restore, nil, &controller.RestoreUpdateStatus{ClusterPhase: &phase}); err != nil {
+		return err
+	}
+	// the status updater gets the restore from the lister, so wait for the updated status to be synced
+	return controller.RequeueErrorf("restore %s/%s: tidbcluster %s/%s is ready, requeue to start the restore", ns, name, tc.Namespace, tc.Name)
+}
+
+// finishTemplateCluster updates the TidbCluster created from the template to the template after the restore
+// is complete, or cleans it up according to the cleanup policy after the restore failed or is invalid.
+func (rm *restoreManager) finishTemplateCluster(restore *v1alpha1.Restore) error {
+	ns := restore.GetNamespace()
+	name := restore.GetName()
+	if v1alpha1.IsRestoreClusterFinished(restore) || restore.Status.ClusterPhase == "" {
+		return nil
+	}
+
+	clusterNamespace := restore.Spec.BR.ClusterNamespace
+	if clusterNamespace == "" {
+		clusterNamespace = ns
+	}
+	tc, err := rm.deps.TiDBClusterLister.TidbClusters(clusterNamespace).Get(restore.Spec.BR.Cluster)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("restore %s/%s get tidbcluster %s/%s failed, err: %v", ns, name, clusterNamespace, restore.Spec.BR.Cluster, err)
+	}
+	if tc != nil && !isClusterCreatedByRestore(tc, restore) {
+		tc = nil
+	}
+
+	var phase v1alpha1.RestoreClusterPhase
+	if v1alpha1.IsRestoreComplete(restore) {
+		if tc == nil {
+			return fmt.Errorf("restore %s/%s: tidbcluster %s/%s created from the cluster template is not found", ns, name, clusterNamespace, restore.Spec.BR.Cluster)
+		}
+		spec, err := rm.getTemplateClusterSpec(restore)
+		if err != nil {
+			return fmt.Errorf("restore %s/%s: %v", ns, name, err)
+		}
+		newTC := tc.DeepCopy()
+		newTC.Spec = *spec
+		if _, err := rm.deps.Clientset.PingcapV1alpha1().TidbClusters(clusterNamespace).Update(context.TODO(), newTC, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("restore %s/%s update tidbcluster %s/%s to the cluster template failed, err: %v", ns, name, clusterNamespace, tc.Name, err)
+		}
+		klog.Infof("restore %s/%s updated tidbcluster %s/%s to the cluster template", ns, name, clusterNamespace, tc.Name)
+		rm.deps.Recorder.Eventf(restore, corev1.EventTypeNormal, "ClusterNormalized", "update tidbcluster %s/%s to the cluster template", clusterNamespace, tc.Name)
+		phase = v1alpha1.RestoreClusterNormal
+	} else if restore.Spec.ClusterTemplate.GetCleanupPolicy() == v1alpha1.RestoreClusterCleanupPolicyDelete {
+		if tc != nil {
+			err := rm.deps.Clientset.PingcapV1alpha1().TidbClusters(clusterNamespace).Delete(context.TODO(), tc.Name,
+				metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &tc.UID}})
+			if err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("restore %s/%s delete tidbcluster %s/%s failed, err: %v", ns, name, clusterNamespace, tc.Name, err)
+			}
+			klog.Infof("restore %s/%s deleted tidbcluster %s/%s after the restore failed", ns, name, clusterNamespace, tc.Name)
+			rm.deps.Recorder.Eventf(restore, corev1.EventTypeNormal, "ClusterDeleted", "delete tidbcluster %s/%s after the restore failed", clusterNamespace, tc.Name)
+		}
+		phase = v1alpha1.RestoreClusterDeleted
+	} else {
+		phase = v1alpha1.RestoreClusterRetained
+	}
+	return rm.statusUpdater.Update(restore, nil, &controller.RestoreUpdateStatus{ClusterPhase: &phase})
+}
@@ -0,0 +1,231 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTemplateClusterSpec() *v1alpha1.TidbClusterSpec {
+	return &v1alpha1.TidbClusterSpec{
+		Version: "v7.1.0",
+		PD:      &v1alpha1.PDSpec{Replicas: 1},
+		TiKV:    &v1alpha1.TiKVSpec{BaseImage: "pingcap/tikv", Replicas: 3},
+		TiDB:    &v1alpha1.TiDBSpec{Replicas: 2},
+		TiCDC:   &v1alpha1.TiCDCSpec{Replicas: 1},
+	}
+}
+
+func TestRestoreIntoTemplateCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.Close()
+	deps := helper.Deps
+	m := NewRestoreManager(deps)
+
+	getRestore := func(r *v1alpha1.Restore) *v1alpha1.Restore {
+		got, err := deps.Clientset.PingcapV1alpha1().Restores(r.Namespace).Get(context.TODO(), r.Name, metav1.GetOptions{})
+		g.Expect(err).Should(Succeed())
+		return got
+	}
+	// wait until the restore and the tidbcluster in the listers are the same as the objects in the clientset
+	syncListers := func(r *v1alpha1.Restore, tc *v1alpha1.TidbCluster) {
+		g.Eventually(func() bool {
+			gotRestore, err := deps.RestoreLister.Restores(r.Namespace).Get(r.Name)
+			if err != nil || !apiequality.Semantic.DeepEqual(gotRestore, r) {
+				return false
+			}
+			gotTC, err := deps.TiDBClusterLister.TidbClusters(tc.Namespace).Get(tc.Name)
+			return err == nil && apiequality.Semantic.DeepEqual(gotTC, tc)
+		}, time.Second*10).Should(BeTrue())
+	}
+
+	restore := genValidBRRestores()[0]
+	restore.Spec.ClusterTemplate = &v1alpha1.RestoreClusterTemplate{
+		Labels: map[string]string{"drill": "true"},
+		Spec:   newTemplateClusterSpec(),
+	}
+	helper.createRestore(restore)
+	helper.CreateSecret(restore)
+	ns, name := restore.Spec.BR.ClusterNamespace, restore.Spec.BR.Cluster
+
+	// the cluster is created in recovery-friendly settings
+	err := m.Sync(restore)
+	g.Expect(controller.IsRequeueError(err)).Should(BeTrue())
+	tc, err := deps.Clientset.PingcapV1alpha1().TidbClusters(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(tc.Labels).Should(HaveKeyWithValue(label.RestoreLabelKey, restore.Name))
+	g.Expect(tc.Labels).Should(HaveKeyWithValue("drill", "true"))
+	g.Expect(tc.Spec.TiCDC).Should(BeNil())
+	g.Expect(tc.Spec.TiDB.Replicas).Should(Equal(int32(1)))
+	restore = getRestore(restore)
+	g.Expect(restore.Status.ClusterPhase).Should(Equal(v1alpha1.RestoreClusterCreating))
+
+	// the cluster created in the last sync is not synced to the lister yet
+	err = m.(*restoreManager).createTemplateCluster(restore, ns)
+	g.Expect(controller.IsRequeueError(err)).Should(BeTrue())
+	restore = getRestore(restore)
+	_, cond := v1alpha1.GetRestoreCondition(&restore.Status, v1alpha1.RestoreRetryFailed)
+	g.Expect(cond).Should(BeNil())
+	g.Expect(restore.Status.ClusterPhase).Should(Equal(v1alpha1.RestoreClusterCreating))
+
+	// the restore waits for the cluster to be ready
+	syncListers(restore, tc)
+	err = m.Sync(restore)
+	g.Expect(controller.IsRequeueError(err)).Should(BeTrue())
+	_, err = deps.KubeClientset.BatchV1().Jobs(restore.Namespace).Get(context.TODO(), restore.GetRestoreJobName(), metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{"pd-0": {Name: "pd-0", Health: true}}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", State: v1alpha1.TiKVStateUp},
+		"2": {ID: "2", State: v1alpha1.TiKVStateUp},
+		"3": {ID: "3", State: v1alpha1.TiKVStateUp},
+	}
+	tc.Status.TiDB.Members = map[string]v1alpha1.TiDBMember{"tidb-0": {Name: "tidb-0", Health: true}}
+	tc, err = deps.Clientset.PingcapV1alpha1().TidbClusters(ns).Update(context.TODO(), tc, metav1.UpdateOptions{})
+	g.Expect(err).Should(Succeed())
+	syncListers(restore, tc)
+	g.Expect(controller.IsRequeueError(m.Sync(restore))).Should(BeTrue())
+	restore = getRestore(restore)
+	g.Expect(restore.Status.ClusterPhase).Should(Equal(v1alpha1.RestoreClusterReady))
+	syncListers(restore, tc)
+	g.Expect(m.Sync(restore)).Should(Succeed())
+	helper.hasCondition(restore.Namespace, restore.Name, v1alpha1.RestoreScheduled, "")
+	_, err = deps.KubeClientset.BatchV1().Jobs(restore.Namespace).Get(context.TODO(), restore.GetRestoreJobName(), metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	restore = getRestore(restore)
+	g.Expect(restore.Status.ClusterPhase).Should(Equal(v1alpha1.RestoreClusterReady))
+
+	// the cluster is updated to the template after the restore is complete
+	g.Expect(m.UpdateCondition(restore, &v1alpha1.RestoreCondition{Type: v1alpha1.RestoreComplete, Status: corev1.ConditionTrue})).Should(Succeed())
+	restore = getRestore(restore)
+	syncListers(restore, tc)
+	g.Expect(m.Sync(restore)).Should(Succeed())
+	tc, err = deps.Clientset.PingcapV1alpha1().TidbClusters(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(tc.Spec).Should(Equal(*newTemplateClusterSpec()))
+	restore = getRestore(restore)
+	g.Expect(restore.Status.ClusterPhase).Should(Equal(v1alpha1.RestoreClusterNormal))
+}
+
+func TestRestoreIntoTemplateClusterFailed(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.Close()
+	deps := helper.Deps
+	m := NewRestoreManager(deps)
+
+	restores := genValidBRRestores()
+
+	// the existing cluster which is not created from the template is not restored into
+	existing := restores[0]
+	existing.Spec.ClusterTemplate = &v1alpha1.RestoreClusterTemplate{Spec: newTemplateClusterSpec()}
+	helper.createRestore(existing)
+	helper.CreateSecret(existing)
+	helper.CreateTC(existing.Spec.BR.ClusterNamespace, existing.Spec.BR.Cluster, false)
+	g.Expect(m.Sync(existing)).ShouldNot(Succeed())
+	helper.hasCondition(existing.Namespace, existing.Name, v1alpha1.RestoreInvalid, "ClusterAlreadyExists")
+
+	// the created cluster is deleted after the restore failed
+	restore := restores[1]
+	restore.Spec.ClusterTemplate = &v1alpha1.RestoreClusterTemplate{Spec: newTemplateClusterSpec()}
+	helper.createRestore(restore)
+	ns, name := restore.Spec.BR.ClusterNamespace, restore.Spec.BR.Cluster
+	g.Expect(controller.IsRequeueError(m.Sync(restore))).Should(BeTrue())
+	g.Eventually(func() v1alpha1.RestoreClusterPhase {
+		r, err := deps.RestoreLister.Restores(restore.Namespace).Get(restore.Name)
+		if err != nil {
+			return ""
+		}
+		return r.Status.ClusterPhase
+	}, time.Second*10).Should(Equal(v1alpha1.RestoreClusterCreating))
+	g.Expect(m.UpdateCondition(restore, &v1alpha1.RestoreCondition{Type: v1alpha1.RestoreFailed, Status: corev1.ConditionTrue})).Should(Succeed())
+	g.Eventually(func() bool {
+		r, err := deps.RestoreLister.Restores(restore.Namespace).Get(restore.Name)
+		if err != nil || !v1alpha1.IsRestoreFailed(r) {
+			return false
+		}
+		restore = r
+		_, err = deps.TiDBClusterLister.TidbClusters(ns).Get(name)
+		return err == nil
+	}, time.Second*10).Should(BeTrue())
+	g.Expect(m.Sync(restore)).Should(Succeed())
+	_, err := deps.Clientset.PingcapV1alpha1().TidbClusters(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+	restore, err = deps.Clientset.PingcapV1alpha1().Restores(restore.Namespace).Get(context.TODO(), restore.Name, metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(restore.Status.ClusterPhase).Should(Equal(v1alpha1.RestoreClusterDeleted))
+}
+
+func TestRestoreIntoTemplateClusterInvalid(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.Close()
+	deps := helper.Deps
+	m := NewRestoreManager(deps)
+
+	restores := genValidBRRestores()
+
+	// the cluster is not created for an invalid restore
+	invalid := restores[0]
+	invalid.Spec.Type = "invalid"
+	invalid.Spec.ClusterTemplate = &v1alpha1.RestoreClusterTemplate{Spec: newTemplateClusterSpec()}
+	helper.createRestore(invalid)
+	g.Expect(m.Sync(invalid)).ShouldNot(Succeed())
+	helper.hasCondition(invalid.Namespace, invalid.Name, v1alpha1.RestoreInvalid, "InvalidSpec")
+	_, err := deps.Clientset.PingcapV1alpha1().TidbClusters(invalid.Spec.BR.ClusterNamespace).Get(context.TODO(), invalid.Spec.BR.Cluster, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+
+	// the created cluster is deleted after the restore becomes invalid
+	restore := restores[1]
+	restore.Spec.ClusterTemplate = &v1alpha1.RestoreClusterTemplate{Spec: newTemplateClusterSpec()}
+	helper.createRestore(restore)
+	ns, name := restore.Spec.BR.ClusterNamespace, restore.Spec.BR.Cluster
+	g.Expect(controller.IsRequeueError(m.Sync(restore))).Should(BeTrue())
+	g.Eventually(func() v1alpha1.RestoreClusterPhase {
+		r, err := deps.RestoreLister.Restores(restore.Namespace).Get(restore.Name)
+		if err != nil {
+			return ""
+		}
+		return r.Status.ClusterPhase
+	}, time.Second*10).Should(Equal(v1alpha1.RestoreClusterCreating))
+	g.Expect(m.UpdateCondition(restore, &v1alpha1.RestoreCondition{Type: v1alpha1.RestoreInvalid, Status: corev1.ConditionTrue})).Should(Succeed())
+	g.Eventually(func() bool {
+		r, err := deps.RestoreLister.Restores(restore.Namespace).Get(restore.Name)
+		if err != nil || !v1alpha1.IsRestoreInvalid(r) {
+			return false
+		}
+		restore = r
+		_, err = deps.TiDBClusterLister.TidbClusters(ns).Get(name)
+		return err == nil
+	}, time.Second*10).Should(BeTrue())
+	g.Expect(m.Sync(restore)).Should(Succeed())
+	_, err = deps.Clientset.PingcapV1alpha1().TidbClusters(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+	restore, err = deps.Clientset.PingcapV1alpha1().Restores(restore.Namespace).Get(context.TODO(), restore.Name, metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(restore.Status.ClusterPhase).Should(Equal(v1alpha1.RestoreClusterDeleted))
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// getSourceBackup returns the snapshot backup in the same namespace which is stored in the location restored from,
+// that is the full backup for pitr restore. It returns nil if there is no such backup.
+func (rm *restoreManager) getSourceBackup(restore *v1alpha1.Restore) (*v1alpha1.Backup, error) {
+	provider := restore.Spec.StorageProvider
+	if restore.Spec.Mode == v1alpha1.RestoreModePiTR {
+		provider = restore.Spec.PitrFullBackupStorageProvider
+	}
+	if backuputil.GetStorageType(provider) == v1alpha1.BackupStorageTypeUnknown {
+		return nil, nil
+	}
+	url, err := backuputil.GetStorageURL(provider)
+	if err != nil {
+		return nil, nil
+	}
+
+	backups, err := rm.deps.BackupLister.Backups(restore.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, backup := range backups {
+		if backup.Spec.Mode != "" && backup.Spec.Mode != v1alpha1.BackupModeSnapshot {
+			continue
+		}
+		if backupURL, err := backuputil.GetStorageURL(backup.Spec.StorageProvider); err == nil && backupURL == url {
+			return backup, nil
+		}
+	}
+	return nil, nil
+}
+
+// resolveRestoreEncryption makes the encryption of the restore compatible with the backup restored from.
+// If the encryption is not set, it is filled from the backup and the restore is requeued; otherwise the
+// restore is marked invalid if its encryption is not compatible with the backup.
+func (rm *restoreManager) resolveRestoreEncryption(restore *v1alpha1.Restore) error {
+	ns := restore.GetNamespace()
+	name := restore.GetName()
+
+	backup, err := rm.getSourceBackup(restore)
+	if err != nil {
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "ListBackupsFailed",
+			Message: err.Error(),
+		}, nil)
+		return err
+	}
+	if backup == nil {
+		return nil
+	}
+
+	if restore.Spec.Encryption == nil && backup.Spec.Encryption != nil {
+		newRestore := restore.DeepCopy()
+		newRestore.Spec.Encryption = backup.Spec.Encryption.DeepCopy()
+		updated, err := rm.deps.Clientset.PingcapV1alpha1().Restores(ns).Update(context.TODO(), newRestore, metav1.UpdateOptions{})
+		if err != nil {
+			rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+				Type:    v1alpha1.RestoreRetryFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  "UpdateEncryptionFailed",
+				Message: err.Error(),
+			}, nil)
+			return fmt.Errorf("restore %s/%s update encryption failed, err: %v", ns, name, err)
+		}
+
+		klog.Infof("restore %s/%s uses the encryption of backup %s/%s", ns, name, ns, backup.Name)
+		rm.deps.Recorder.Eventf(updated, corev1.EventTypeNormal, "EncryptionResolved",
+			"use the encryption %s of backup %s", backup.Spec.Encryption.Method, backup.Name)
+		// the status updater gets the restore from the lister, so wait for the updated spec to be synced
+		return controller.RequeueErrorf("restore %s/%s: encryption is resolved, requeue to wait for the updated spec", ns, name)
+	}
+
+	// compare the key material if both keys can be read, the secrets are checked again when making the job
+	var restoreKey, backupKey string
+	if restore.Spec.Encryption != nil && backup.Spec.Encryption != nil {
+		restoreKey, _, _ = backuputil.GetEncryptionKey(ns, restore.Spec.Encryption, rm.deps.SecretLister)
+		backupKey, _, _ = backuputil.GetEncryptionKey(backup.Namespace, backup.Spec.Encryption, rm.deps.SecretLister)
+	}
+	if errs := validation.ValidateRestoreEncryption(restore, backup, restoreKey, backupKey); len(errs) > 0 {
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreInvalid,
+			Status:  corev1.ConditionTrue,
+			Reason:  "IncompatibleEncryption",
+			Message: fmt.Sprintf("backup %s/%s: %v", ns, backup.Name, errs.ToAggregate()),
+		}, nil)
+		return controller.IgnoreErrorf("restore %s/%s encryption is incompatible with backup %s/%s, err: %v", ns, name, ns, backup.Name, errs.ToAggregate())
+	}
+	return nil
+}
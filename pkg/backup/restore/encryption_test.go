@@ -0,0 +1,102 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRestoreEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.Close()
+	deps := helper.Deps
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "backup-key"},
+		Data:       map[string][]byte{v1alpha1.DefaultBackupEncryptionSecretKey: []byte("0123456789abcdef0123456789abcdef")},
+	}
+	_, err := deps.KubeClientset.CoreV1().Secrets(secret.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+
+	restore := genValidBRRestores()[0]
+	backup := &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: restore.Namespace, Name: "encrypted"},
+		Spec: v1alpha1.BackupSpec{
+			StorageProvider: *restore.Spec.StorageProvider.DeepCopy(),
+			Encryption: &v1alpha1.BackupEncryption{
+				Method:     v1alpha1.BackupEncryptionMethodAES128CTR,
+				SecretName: secret.Name,
+			},
+		},
+	}
+	_, err = deps.Clientset.PingcapV1alpha1().Backups(backup.Namespace).Create(context.TODO(), backup, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Eventually(func() error {
+		if _, err := deps.SecretLister.Secrets(secret.Namespace).Get(secret.Name); err != nil {
+			return err
+		}
+		_, err := deps.BackupLister.Backups(backup.Namespace).Get(backup.Name)
+		return err
+	}, time.Second*10).Should(Succeed())
+
+	// the method is different from the backup
+	invalid := restore.DeepCopy()
+	invalid.Name = "invalid"
+	invalid.Spec.Encryption = &v1alpha1.BackupEncryption{
+		Method:     v1alpha1.BackupEncryptionMethodAES256CTR,
+		SecretName: secret.Name,
+	}
+	helper.createRestore(invalid)
+	helper.CreateSecret(invalid)
+	helper.CreateTC(invalid.Spec.BR.ClusterNamespace, invalid.Spec.BR.Cluster, false)
+	m := NewRestoreManager(deps)
+	g.Expect(m.Sync(invalid)).ShouldNot(Succeed())
+	helper.hasCondition(invalid.Namespace, invalid.Name, v1alpha1.RestoreInvalid, "IncompatibleEncryption")
+
+	// the encryption is filled from the backup
+	helper.createRestore(restore)
+	err = m.Sync(restore)
+	g.Expect(controller.IsRequeueError(err)).Should(BeTrue())
+	g.Eventually(func() bool {
+		restore, err = deps.RestoreLister.Restores(restore.Namespace).Get(restore.Name)
+		return err == nil && restore.Spec.Encryption != nil
+	}, time.Second*10).Should(BeTrue())
+	g.Expect(restore.Spec.Encryption).Should(Equal(backup.Spec.Encryption))
+
+	g.Expect(m.Sync(restore.DeepCopy())).Should(Succeed())
+	helper.hasCondition(restore.Namespace, restore.Name, v1alpha1.RestoreScheduled, "")
+	job, err := deps.KubeClientset.BatchV1().Jobs(restore.Namespace).Get(context.TODO(), restore.GetRestoreJobName(), metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	var secretNames, mountPaths []string
+	for _, volume := range job.Spec.Template.Spec.Volumes {
+		if volume.Secret != nil {
+			secretNames = append(secretNames, volume.Secret.SecretName)
+		}
+	}
+	for _, volumeMount := range job.Spec.Template.Spec.Containers[0].VolumeMounts {
+		mountPaths = append(mountPaths, volumeMount.MountPath)
+	}
+	g.Expect(secretNames).Should(ContainElement(secret.Name))
+	g.Expect(mountPaths).Should(ContainElement(constants.BREncryptionKeyPath))
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// needResolvePitrBackups returns whether the full backup and the log backup of pitr should be
+// resolved from the BackupSchedule referenced by the restore
+func needResolvePitrBackups(restore *v1alpha1.Restore) bool {
+	return restore.Spec.Mode == v1alpha1.RestoreModePiTR &&
+		restore.Spec.PitrBackupScheduleRef != nil && restore.Spec.PitrBackupScheduleRef.Name != "" &&
+		backuputil.GetStorageType(restore.Spec.PitrFullBackupStorageProvider) == v1alpha1.BackupStorageTypeUnknown
+}
+
+// getPitrBackups returns the snapshot backups and the log backup of the BackupSchedule referenced by the restore
+func (rm *restoreManager) getPitrBackups(restore *v1alpha1.Restore) ([]*v1alpha1.Backup, *v1alpha1.Backup, string, error) {
+	ns := restore.GetNamespace()
+	bsName := restore.Spec.PitrBackupScheduleRef.Name
+
+	bs, err := rm.deps.BackupScheduleLister.BackupSchedules(ns).Get(bsName)
+	if err != nil {
+		return nil, nil, fmt.Sprintf("failed to fetch backupschedule %s/%s", ns, bsName), err
+	}
+
+	selector, err := label.NewBackupSchedule().Instance(bsName).BackupSchedule(bsName).Selector()
+	if err != nil {
+		return nil, nil, "BuildBackupScheduleSelectorFailed", err
+	}
+	backupsList, err := rm.deps.BackupLister.Backups(ns).List(selector)
+	if err != nil {
+		return nil, nil, fmt.Sprintf("failed to list backups of backupschedule %s/%s", ns, bsName), err
+	}
+
+	var (
+		backups   []*v1alpha1.Backup
+		logBackup *v1alpha1.Backup
+	)
+	for _, backup := range backupsList {
+		if backup.Spec.Mode != v1alpha1.BackupModeLog {
+			backups = append(backups, backup)
+			continue
+		}
+		// prefer the log backup recorded in the status if there are more than one log backups
+		if logBackup == nil || (bs.Status.LogBackup != nil && backup.Name == *bs.Status.LogBackup) {
+			logBackup = backup
+		}
+	}
+	return backups, logBackup, "", nil
+}
+
+// planPitrRestore chooses the latest complete snapshot backup whose commit ts is not after restoredTSO
+// and within the range of the log backup as the full backup, and returns an error if restoredTSO is
+// out of the recoverable window.
+func planPitrRestore(backups []*v1alpha1.Backup, logBackup *v1alpha1.Backup, restoredTSO uint64) (*v1alpha1.Backup, error) {
+	if logBackup == nil {
+		return nil, fmt.Errorf("no log backup found")
+	}
+	windowStart, windowEnd, err := backuputil.GetPitrRecoverableWindow(backups, logBackup)
+	if err != nil {
+		return nil, err
+	}
+	if windowStart == 0 {
+		return nil, fmt.Errorf("no complete snapshot backup within the range of log backup %s", logBackup.Name)
+	}
+	if restoredTSO < windowStart || restoredTSO > windowEnd {
+		return nil, fmt.Errorf("pitrRestoredTs %d (%s) is out of the recoverable window [%d (%s), %d (%s)]",
+			restoredTSO, backuputil.FormatTSO(restoredTSO),
+			windowStart, backuputil.FormatTSO(windowStart),
+			windowEnd, backuputil.FormatTSO(windowEnd))
+	}
+
+	logStartTSO, _, err := backuputil.GetLogBackupTSORange(logBackup)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		fullBackup    *v1alpha1.Backup
+		fullBackupTSO uint64
+	)
+	for _, backup := range backups {
+		if !backuputil.IsPitrFullBackupCandidate(backup) {
+			continue
+		}
+		commitTSO, err := config.ParseTSString(backup.Status.CommitTs)
+		if err != nil {
+			return nil, fmt.Errorf("parse commit ts of backup %s/%s failed: %v", backup.Namespace, backup.Name, err)
+		}
+		if commitTSO < logStartTSO || commitTSO > restoredTSO {
+			continue
+		}
+		if fullBackup == nil || commitTSO > fullBackupTSO {
+			fullBackup, fullBackupTSO = backup, commitTSO
+		}
+	}
+	return fullBackup, nil
+}
+
+// resolvePitrBackups resolves the full backup and the log backup of pitr from the BackupSchedule
+// referenced by the restore, and fills the storage providers in the spec of the restore, then the
+// restore is requeued.
+// The restore is marked invalid if pitrRestoredTs can't be restored by the backups.
+func (rm *restoreManager) resolvePitrBackups(restore *v1alpha1.Restore) error {
+	ns := restore.GetNamespace()
+	name := restore.GetName()
+	bsName := restore.Spec.PitrBackupScheduleRef.Name
+
+	backups, logBackup, reason, err := rm.getPitrBackups(restore)
+	if err != nil {
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  reason,
+			Message: err.Error(),
+		}, nil)
+		return err
+	}
+
+	restoredTSO, err := config.ParseTSString(restore.Spec.PitrRestoredTs)
+	if err == nil && restoredTSO == 0 {
+		err = fmt.Errorf("pitrRestoredTs should be configured")
+	}
+	var fullBackup *v1alpha1.Backup
+	if err == nil {
+		fullBackup, err = planPitrRestore(backups, logBackup, restoredTSO)
+	}
+	if err != nil {
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreInvalid,
+			Status:  corev1.ConditionTrue,
+			Reason:  "InvalidPitrRestoredTs",
+			Message: fmt.Sprintf("backupschedule %s/%s: %v", ns, bsName, err),
+		}, nil)
+		return controller.IgnoreErrorf("restore %s/%s resolve pitr backups from backupschedule %s/%s failed, err: %v", ns, name, ns, bsName, err)
+	}
+
+	newRestore := restore.DeepCopy()
+	newRestore.Spec.StorageProvider = *logBackup.Spec.StorageProvider.DeepCopy()
+	newRestore.Spec.PitrFullBackupStorageProvider = *fullBackup.Spec.StorageProvider.DeepCopy()
+	updated, err := rm.deps.Clientset.PingcapV1alpha1().Restores(ns).Update(context.TODO(), newRestore, metav1.UpdateOptions{})
+	if err != nil {
+		rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
+			Type:    v1alpha1.RestoreRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "UpdatePitrStorageFailed",
+			Message: err.Error(),
+		}, nil)
+		return fmt.Errorf("restore %s/%s update pitr storage failed, err: %v", ns, name, err)
+	}
+
+	klog.Infof("restore %s/%s resolves full backup %s and log backup %s of backupschedule %s/%s for pitrRestoredTs %s",
+		ns, name, fullBackup.Name, logBackup.Name, ns, bsName, restore.Spec.PitrRestoredTs)
+	rm.deps.Recorder.Eventf(updated, corev1.EventTypeNormal, "PitrBackupsResolved",
+		"use full backup %s and log backup %s of backupschedule %s", fullBackup.Name, logBackup.Name, bsName)
+	// the status updater gets the restore from the lister, so wait for the updated spec to be synced
+	return controller.RequeueErrorf("restore %s/%s: pitr backups are resolved, requeue to wait for the updated spec", ns, name)
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPitrBackup(name string, mode v1alpha1.BackupMode, commitTSO uint64) *v1alpha1.Backup {
+	backup := &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+			Labels:    label.NewBackupSchedule().Instance("bs").BackupSchedule("bs"),
+		},
+		Spec: v1alpha1.BackupSpec{
+			Mode: mode,
+			StorageProvider: v1alpha1.StorageProvider{
+				S3: &v1alpha1.S3StorageProvider{
+					Bucket: "bucket",
+					Prefix: "bs/" + name,
+				},
+			},
+		},
+		Status: v1alpha1.BackupStatus{
+			CommitTs: strconv.FormatUint(commitTSO, 10),
+		},
+	}
+	if mode == v1alpha1.BackupModeLog {
+		return backup
+	}
+	backup.Status.Phase = v1alpha1.BackupComplete
+	backup.Status.Conditions = []v1alpha1.BackupCondition{{
+		Type:   v1alpha1.BackupComplete,
+		Status: corev1.ConditionTrue,
+	}}
+	return backup
+}
+
+func TestPlanPitrRestore(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tso := func(hour int) uint64 {
+		return config.TSToTSO(time.Date(2023, 1, 1, hour, 0, 0, 0, time.UTC).Unix())
+	}
+	logBackup := newPitrBackup("log", v1alpha1.BackupModeLog, tso(1))
+	logBackup.Status.LogSuccessTruncateUntil = strconv.FormatUint(tso(3), 10)
+	logBackup.Status.LogCheckpointTs = strconv.FormatUint(tso(12), 10)
+
+	failed := newPitrBackup("failed", v1alpha1.BackupModeSnapshot, tso(8))
+	failed.Status.Conditions[0].Type = v1alpha1.BackupFailed
+	backups := []*v1alpha1.Backup{
+		// truncated from the log backup
+		newPitrBackup("snapshot-2", v1alpha1.BackupModeSnapshot, tso(2)),
+		newPitrBackup("snapshot-4", v1alpha1.BackupModeSnapshot, tso(4)),
+		newPitrBackup("snapshot-6", v1alpha1.BackupModeSnapshot, tso(6)),
+		failed,
+		// after the checkpoint of the log backup
+		newPitrBackup("snapshot-13", v1alpha1.BackupModeSnapshot, tso(13)),
+	}
+
+	tests := []struct {
+		name        string
+		restoredTSO uint64
+		expectFull  string
+		expectErr   string
+	}{
+		{name: "before the window", restoredTSO: tso(3), expectErr: "out of the recoverable window"},
+		{name: "at the start of the window", restoredTSO: tso(4), expectFull: "snapshot-4"},
+		{name: "between snapshots", restoredTSO: tso(5), expectFull: "snapshot-4"},
+		{name: "skip failed snapshot", restoredTSO: tso(9), expectFull: "snapshot-6"},
+		{name: "at the end of the window", restoredTSO: tso(12), expectFull: "snapshot-6"},
+		{name: "after the window", restoredTSO: tso(13), expectErr: "out of the recoverable window"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			full, err := planPitrRestore(backups, logBackup, tt.restoredTSO)
+			if tt.expectErr != "" {
+				g.Expect(err).Should(HaveOccurred())
+				g.Expect(err.Error()).Should(ContainSubstring(tt.expectErr))
+				return
+			}
+			g.Expect(err).Should(Succeed())
+			g.Expect(full.Name).Should(Equal(tt.expectFull))
+		})
+	}
+
+	_, err := planPitrRestore(backups, nil, tso(5))
+	g.Expect(err).Should(MatchError("no log backup found"))
+
+	_, err = planPitrRestore(backups[:1], logBackup, tso(5))
+	g.Expect(err.Error()).Should(ContainSubstring("no complete snapshot backup"))
+}
+
+func TestPitrRestoreWithBackupSchedule(t *testing.T) {
+	g := NewGomegaWithT(t)
+	helper := newHelper(t)
+	defer helper.Close()
+	deps := helper.Deps
+
+	bs := &v1alpha1.BackupSchedule{}
+	bs.Namespace = "ns"
+	bs.Name = "bs"
+	_, err := deps.Clientset.PingcapV1alpha1().BackupSchedules(bs.Namespace).Create(context.TODO(), bs, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+
+	logBackup := newPitrBackup("log", v1alpha1.BackupModeLog, 100<<18)
+	logBackup.Status.LogCheckpointTs = strconv.FormatUint(300<<18, 10)
+	for _, backup := range []*v1alpha1.Backup{
+		logBackup,
+		newPitrBackup("snapshot-1", v1alpha1.BackupModeSnapshot, 150<<18),
+		newPitrBackup("snapshot-2", v1alpha1.BackupModeSnapshot, 250<<18),
+	} {
+		_, err := deps.Clientset.PingcapV1alpha1().Backups(backup.Namespace).Create(context.TODO(), backup, metav1.CreateOptions{})
+		g.Expect(err).Should(Succeed())
+	}
+	g.Eventually(func() error {
+		if _, err := deps.BackupScheduleLister.BackupSchedules(bs.Namespace).Get(bs.Name); err != nil {
+			return err
+		}
+		selector, _ := label.NewBackupSchedule().Instance("bs").BackupSchedule("bs").Selector()
+		backups, err := deps.BackupLister.Backups(bs.Namespace).List(selector)
+		if err == nil && len(backups) != 3 {
+			err = fmt.Errorf("expect 3 backups, got %d", len(backups))
+		}
+		return err
+	}, time.Second*10).Should(Succeed())
+
+	newRestore := func(name string, restoredTSO uint64) *v1alpha1.Restore {
+		restore := genValidBRRestores()[0]
+		restore.Name = name
+		restore.Spec.Type = v1alpha1.BackupTypeFull
+		restore.Spec.StorageProvider = v1alpha1.StorageProvider{}
+		restore.Spec.Mode = v1alpha1.RestoreModePiTR
+		restore.Spec.PitrRestoredTs = strconv.FormatUint(restoredTSO, 10)
+		restore.Spec.PitrBackupScheduleRef = &corev1.LocalObjectReference{Name: bs.Name}
+		return restore
+	}
+
+	// out of the recoverable window
+	restore := newRestore("invalid", 400<<18)
+	helper.createRestore(restore)
+	m := NewRestoreManager(deps)
+	g.Expect(m.Sync(restore)).ShouldNot(Succeed())
+	helper.hasCondition(restore.Namespace, restore.Name, v1alpha1.RestoreInvalid, "InvalidPitrRestoredTs")
+
+	// the storages are resolved from the backups
+	restore = newRestore("valid", 200<<18)
+	helper.createRestore(restore)
+	helper.CreateSecret(restore)
+	helper.CreateTC(restore.Spec.BR.ClusterNamespace, restore.Spec.BR.Cluster, false)
+	err = m.Sync(restore)
+	g.Expect(controller.IsRequeueError(err)).Should(BeTrue())
+
+	g.Eventually(func() bool {
+		restore, err = deps.RestoreLister.Restores(restore.Namespace).Get(restore.Name)
+		return err == nil && restore.Spec.PitrFullBackupStorageProvider.S3 != nil
+	}, time.Second*10).Should(BeTrue())
+	g.Expect(restore.Spec.S3.Prefix).Should(Equal("bs/log"))
+	g.Expect(restore.Spec.PitrFullBackupStorageProvider.S3.Prefix).Should(Equal("bs/snapshot-1"))
+
+	g.Expect(m.Sync(restore.DeepCopy())).Should(Succeed())
+	helper.hasCondition(restore.Namespace, restore.Name, v1alpha1.RestoreScheduled, "")
+}
@@ -56,6 +56,10 @@ func NewRestoreManager(deps *controller.Dependencies) backup.RestoreManager {
 }
 
 func (rm *restoreManager) Sync(restore *v1alpha1.Restore) error {
+	if restore.Spec.BR != nil && restore.Spec.ClusterTemplate != nil &&
+		(v1alpha1.IsRestoreComplete(restore) || v1alpha1.IsRestoreFailed(restore) || v1alpha1.IsRestoreInvalid(restore)) {
+		return rm.finishTemplateCluster(restore)
+	}
 	return rm.syncRestoreJob(restore)
 }
 
@@ -73,6 +77,12 @@ func (rm *restoreManager) syncRestoreJob(restore *v1alpha1.Restore) error {
 		restoreNamespace string
 	)
 
+	if restore.Spec.BR != nil && needResolvePitrBackups(restore) {
+		if err := rm.resolvePitrBackups(restore); err != nil {
+			return err
+		}
+	}
+
 	if restore.Spec.BR == nil {
 		err = backuputil.ValidateRestore(restore, "", false)
 	} else {
@@ -82,6 +92,9 @@ func (rm *restoreManager) syncRestoreJob(restore *v1alpha1.Restore) error {
 		}
 
 		tc, err = rm.deps.TiDBClusterLister.TidbClusters(restoreNamespace).Get(restore.Spec.BR.Cluster)
+		if errors.IsNotFound(err) && restore.Spec.ClusterTemplate != nil {
+			return rm.createTemplateCluster(restore, restoreNamespace)
+		}
 		if err != nil {
 			reason := fmt.Sprintf("failed to fetch tidbcluster %s/%s", restoreNamespace, restore.Spec.BR.Cluster)
 			rm.statusUpdater.Update(restore, &v1alpha1.RestoreCondition{
@@ -108,6 +121,18 @@ func (rm *restoreManager) syncRestoreJob(restore *v1alpha1.Restore) error {
 		return controller.IgnoreErrorf("invalid restore spec %s/%s", ns, name)
 	}
 
+	if restore.Spec.BR != nil && restore.Spec.Mode != v1alpha1.RestoreModeVolumeSnapshot {
+		if err := rm.resolveRestoreEncryption(restore); err != nil {
+			return err
+		}
+	}
+
+	if restore.Spec.BR != nil && restore.Spec.ClusterTemplate != nil {
+		if err := rm.waitTemplateClusterReady(restore, tc); err != nil {
+			return err
+		}
+	}
+
 	if restore.Spec.BR != nil && restore.Spec.Mode == v1alpha1.RestoreModeVolumeSnapshot {
 		err = rm.validateRestore(restore, tc)
 
@@ -629,6 +654,12 @@ func (rm *restoreManager) makeRestoreJob(restore *v1alpha1.Restore) (*batchv1.Jo
 		return nil, reason, fmt.Errorf("restore %s/%s, %v", ns, name, err)
 	}
 
+	if restore.Spec.Encryption != nil {
+		if _, reason, err := backuputil.GetEncryptionKey(ns, restore.Spec.Encryption, rm.deps.SecretLister); err != nil {
+			return nil, reason, fmt.Errorf("restore %s/%s, %v", ns, name, err)
+		}
+	}
+
 	envVars = append(envVars, storageEnv...)
 	envVars = append(envVars, corev1.EnvVar{
 		Name:  "BR_LOG_TO_TERM",
@@ -728,6 +759,7 @@ func (rm *restoreManager) makeRestoreJob(restore *v1alpha1.Restore) (*batchv1.Jo
 		volumes = append(volumes, restore.Spec.Local.Volume)
 		volumeMounts = append(volumeMounts, restore.Spec.Local.VolumeMount)
 	}
+	volumes, volumeMounts = backuputil.AppendEncryptionKeyVolume(restore.Spec.Encryption, volumes, volumeMounts)
 
 	serviceAccount := constants.DefaultServiceAccountName
 	if restore.Spec.ServiceAccount != "" {
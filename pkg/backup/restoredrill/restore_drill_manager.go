@@ -0,0 +1,535 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restoredrill
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+	"github.com/pingcap/tidb-operator/pkg/backup"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	"github.com/robfig/cron"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+type nowFn func() time.Time
+
+type restoreDrillManager struct {
+	deps *controller.Dependencies
+	now  nowFn
+}
+
+// NewRestoreDrillManager return a *restoreDrillManager
+func NewRestoreDrillManager(deps *controller.Dependencies) backup.RestoreDrillManager {
+	return &restoreDrillManager{
+		deps: deps,
+		now:  time.Now,
+	}
+}
+
+func (rm *restoreDrillManager) Sync(rd *v1alpha1.RestoreDrill) error {
+	ns := rd.GetNamespace()
+	name := rd.GetName()
+
+	if rd.DeletionTimestamp != nil {
+		// clean up the running drill, otherwise the temporary TidbCluster and its PVCs are leaked
+		if run := rd.Status.CurrentRun; run != nil {
+			rm.failRun(run, "restore drill is deleted")
+			return rm.cleanup(rd)
+		}
+		return nil
+	}
+
+	if errs := validation.ValidateRestoreDrill(rd); len(errs) > 0 {
+		rm.deps.Recorder.Event(rd, corev1.EventTypeWarning, "InvalidSpec", errs.ToAggregate().Error())
+		return controller.IgnoreErrorf("invalid restore drill %s/%s: %v", ns, name, errs.ToAggregate())
+	}
+
+	if rd.Status.CurrentRun != nil {
+		return rm.syncCurrentRun(rd)
+	}
+
+	if rd.Spec.Pause {
+		return controller.IgnoreErrorf("restore drill %s/%s has been paused", ns, name)
+	}
+
+	scheduledTime, err := getLastScheduledTime(rd, rm.now)
+	if scheduledTime == nil {
+		return err
+	}
+	rd.Status.LastScheduleTime = &metav1.Time{Time: *scheduledTime}
+	run := &v1alpha1.RestoreDrillRun{
+		ScheduleTime: metav1.Time{Time: *scheduledTime},
+		Phase:        v1alpha1.RestoreDrillRestoring,
+	}
+
+	bk, err := rm.getLatestBackup(rd)
+	if err != nil {
+		return err
+	}
+	if bk == nil {
+		run.Result = v1alpha1.RestoreDrillFailed
+		run.Message = fmt.Sprintf("no complete snapshot backup of backup schedule %s", rd.Spec.BackupScheduleName)
+		rm.finishRun(rd, run)
+		return nil
+	}
+
+	restore := buildRestore(rd, bk, *scheduledTime)
+	if _, err := rm.deps.Clientset.PingcapV1alpha1().Restores(ns).Create(context.TODO(), restore, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("restore drill %s/%s, create restore %s failed, err: %v", ns, name, restore.Name, err)
+	}
+	klog.Infof("restore drill %s/%s, restore backup %s by restore %s", ns, name, bk.Name, restore.Name)
+	rm.deps.Recorder.Eventf(rd, corev1.EventTypeNormal, "RestoreCreated", "restore backup %s into tidbcluster %s by restore %s", bk.Name, restore.Spec.BR.Cluster, restore.Name)
+
+	run.Backup = bk.Name
+	run.Restore = restore.Name
+	run.Cluster = restore.Spec.BR.Cluster
+	rd.Status.CurrentRun = run
+	return nil
+}
+
+// syncCurrentRun moves the running drill forward according to its phase
+func (rm *restoreDrillManager) syncCurrentRun(rd *v1alpha1.RestoreDrill) error {
+	ns := rd.GetNamespace()
+	name := rd.GetName()
+	run := rd.Status.CurrentRun
+
+	switch run.Phase {
+	case v1alpha1.RestoreDrillRestoring:
+		restore, err := rm.deps.RestoreLister.Restores(ns).Get(run.Restore)
+		if errors.IsNotFound(err) {
+			rm.failRun(run, fmt.Sprintf("restore %s is not found", run.Restore))
+			return rm.cleanup(rd)
+		}
+		if err != nil {
+			return fmt.Errorf("restore drill %s/%s, get restore %s failed, err: %v", ns, name, run.Restore, err)
+		}
+		if message, failed := getRestoreFailure(restore); failed {
+			rm.failRun(run, fmt.Sprintf("restore %s failed: %s", run.Restore, message))
+			return rm.cleanup(rd)
+		}
+		if !v1alpha1.IsRestoreComplete(restore) {
+			return controller.RequeueErrorf("restore drill %s/%s, waiting for restore %s to complete", ns, name, run.Restore)
+		}
+
+		completionTime := restore.Status.TimeCompleted.Time
+		if completionTime.IsZero() {
+			completionTime = rm.now()
+		}
+		run.RestoreDuration = completionTime.Sub(restore.CreationTimestamp.Time).Round(time.Second).String()
+		if len(rd.Spec.Checks) == 0 {
+			run.Result = v1alpha1.RestoreDrillPassed
+			run.Phase = v1alpha1.RestoreDrillCleaningUp
+			return rm.cleanup(rd)
+		}
+		// the check job is created after the Checking phase is persisted, otherwise it exits without
+		// recording the results as the drill is not in the Checking phase
+		run.Phase = v1alpha1.RestoreDrillChecking
+		return controller.RequeueErrorf("restore drill %s/%s, restore %s is complete, start the checks", ns, name, run.Restore)
+	case v1alpha1.RestoreDrillChecking:
+		// the check job records the results and moves the drill to CleaningUp
+		job, err := rm.deps.JobLister.Jobs(ns).Get(rd.GetCheckJobName(run.Restore))
+		if errors.IsNotFound(err) {
+			if err := rm.ensureCheckJob(rd); err != nil {
+				return err
+			}
+			return controller.RequeueErrorf("restore drill %s/%s, waiting for the checks to finish", ns, name)
+		}
+		if err != nil {
+			return fmt.Errorf("restore drill %s/%s, get check job failed, err: %v", ns, name, err)
+		}
+		for _, c := range job.Status.Conditions {
+			if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+				rm.failRun(run, fmt.Sprintf("check job %s failed: %s", job.Name, c.Message))
+				return rm.cleanup(rd)
+			}
+			if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
+				return rm.syncCompleteCheckJob(rd, job)
+			}
+		}
+		return controller.RequeueErrorf("restore drill %s/%s, waiting for the checks to finish", ns, name)
+	case v1alpha1.RestoreDrillCleaningUp:
+		return rm.cleanup(rd)
+	default:
+		return fmt.Errorf("restore drill %s/%s, unknown phase %s", ns, name, run.Phase)
+	}
+}
+
+// syncCompleteCheckJob fails the drill if the check job completes without recording the results
+func (rm *restoreDrillManager) syncCompleteCheckJob(rd *v1alpha1.RestoreDrill, job *batchv1.Job) error {
+	ns := rd.GetNamespace()
+	name := rd.GetName()
+	run := rd.Status.CurrentRun
+
+	// the results may be recorded but not synced to the lister yet
+	latest, err := rm.deps.Clientset.PingcapV1alpha1().RestoreDrills(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("restore drill %s/%s, get restore drill failed, err: %v", ns, name, err)
+	}
+	latestRun := latest.Status.CurrentRun
+	if latestRun == nil || latestRun.Restore != run.Restore || latestRun.Phase != v1alpha1.RestoreDrillChecking {
+		return controller.RequeueErrorf("restore drill %s/%s, waiting for the recorded results to be synced", ns, name)
+	}
+	rm.failRun(run, fmt.Sprintf("check job %s completed without recording the results", job.Name))
+	return rm.cleanup(rd)
+}
+
+func (rm *restoreDrillManager) failRun(run *v1alpha1.RestoreDrillRun, message string) {
+	run.Phase = v1alpha1.RestoreDrillCleaningUp
+	run.Result = v1alpha1.RestoreDrillFailed
+	run.Message = message
+}
+
+// getRestoreFailure returns the message of the failed or invalid restore
+func getRestoreFailure(restore *v1alpha1.Restore) (string, bool) {
+	for _, tp := range []v1alpha1.RestoreConditionType{v1alpha1.RestoreInvalid, v1alpha1.RestoreFailed} {
+		_, c := v1alpha1.GetRestoreCondition(&restore.Status, tp)
+		if c != nil && c.Status == corev1.ConditionTrue {
+			return fmt.Sprintf("%s %s", c.Reason, c.Message), true
+		}
+	}
+	return "", false
+}
+
+// cleanup deletes the check job, the restore, the temporary TidbCluster and its PVCs, and finishes the drill
+func (rm *restoreDrillManager) cleanup(rd *v1alpha1.RestoreDrill) error {
+	ns := rd.GetNamespace()
+	name := rd.GetName()
+	run := rd.Status.CurrentRun
+
+	job, err := rm.deps.JobLister.Jobs(ns).Get(rd.GetCheckJobName(run.Restore))
+	if err == nil {
+		controller.SetRestoreDrillKind(rd)
+		if err := rm.deps.JobControl.DeleteJob(rd, job); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("restore drill %s/%s, delete check job %s failed, err: %v", ns, name, job.Name, err)
+		}
+	} else if !errors.IsNotFound(err) {
+		return fmt.Errorf("restore drill %s/%s, get check job failed, err: %v", ns, name, err)
+	}
+
+	if run.Restore != "" {
+		err := rm.deps.Clientset.PingcapV1alpha1().Restores(ns).Delete(context.TODO(), run.Restore, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("restore drill %s/%s, delete restore %s failed, err: %v", ns, name, run.Restore, err)
+		}
+	}
+
+	if run.Cluster != "" {
+		tc, err := rm.deps.TiDBClusterLister.TidbClusters(ns).Get(run.Cluster)
+		if err == nil && tc.Labels[label.RestoreLabelKey] == run.Restore {
+			err = rm.deps.Clientset.PingcapV1alpha1().TidbClusters(ns).Delete(context.TODO(), run.Cluster,
+				metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &tc.UID}})
+		}
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("restore drill %s/%s, delete tidbcluster %s failed, err: %v", ns, name, run.Cluster, err)
+		}
+
+		// the PVCs are not deleted with the TidbCluster, they are released after the pods are deleted
+		selector, err := label.New().Instance(run.Cluster).Selector()
+		if err != nil {
+			return fmt.Errorf("restore drill %s/%s, generate selector of tidbcluster %s failed, err: %v", ns, name, run.Cluster, err)
+		}
+		pvcs, err := rm.deps.PVCLister.PersistentVolumeClaims(ns).List(selector)
+		if err != nil {
+			return fmt.Errorf("restore drill %s/%s, list pvcs of tidbcluster %s failed, err: %v", ns, name, run.Cluster, err)
+		}
+		for _, pvc := range pvcs {
+			err := rm.deps.KubeClientset.CoreV1().PersistentVolumeClaims(ns).Delete(context.TODO(), pvc.Name, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("restore drill %s/%s, delete pvc %s failed, err: %v", ns, name, pvc.Name, err)
+			}
+		}
+	}
+
+	rm.finishRun(rd, run)
+	return nil
+}
+
+// finishRun records the finished drill in the history
+func (rm *restoreDrillManager) finishRun(rd *v1alpha1.RestoreDrill, run *v1alpha1.RestoreDrillRun) {
+	now := metav1.NewTime(rm.now())
+	run.Phase = v1alpha1.RestoreDrillFinished
+	run.CompletionTime = &now
+
+	rd.Status.CurrentRun = nil
+	rd.Status.LastResult = run.Result
+	if run.Result == v1alpha1.RestoreDrillPassed {
+		rd.Status.LastSuccessfulTime = &now
+		rm.deps.Recorder.Eventf(rd, corev1.EventTypeNormal, "DrillPassed", "backup %s is restored in %s and all checks passed", run.Backup, run.RestoreDuration)
+	} else {
+		rm.deps.Recorder.Event(rd, corev1.EventTypeWarning, "DrillFailed", run.Message)
+	}
+
+	rd.Status.History = append([]v1alpha1.RestoreDrillRun{*run}, rd.Status.History...)
+	if limit := rd.GetHistoryLimit(); len(rd.Status.History) > limit {
+		rd.Status.History = rd.Status.History[:limit]
+	}
+}
+
+// getLatestBackup returns the complete snapshot backup of the backup schedule with the latest commit ts,
+// it returns nil if there is no such backup.
+func (rm *restoreDrillManager) getLatestBackup(rd *v1alpha1.RestoreDrill) (*v1alpha1.Backup, error) {
+	ns := rd.GetNamespace()
+	bsName := rd.Spec.BackupScheduleName
+
+	selector, err := label.NewBackupSchedule().Instance(bsName).BackupSchedule(bsName).Selector()
+	if err != nil {
+		return nil, fmt.Errorf("generate backup schedule %s/%s label selector failed, err: %v", ns, bsName, err)
+	}
+	backups, err := rm.deps.BackupLister.Backups(ns).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("get backup schedule %s/%s backup list failed, selector: %s, err: %v", ns, bsName, selector, err)
+	}
+
+	var (
+		latest    *v1alpha1.Backup
+		latestTSO uint64
+	)
+	for _, bk := range backups {
+		if bk.Spec.BR == nil || !backuputil.IsPitrFullBackupCandidate(bk) {
+			continue
+		}
+		commitTSO, err := config.ParseTSString(bk.Status.CommitTs)
+		if err != nil {
+			klog.Warningf("restore drill %s/%s, parse commit ts of backup %s failed, err: %v", ns, rd.Name, bk.Name, err)
+			continue
+		}
+		if latest == nil || commitTSO > latestTSO {
+			latest, latestTSO = bk, commitTSO
+		}
+	}
+	return latest, nil
+}
+
+// buildRestore builds the restore of the backup into the temporary TidbCluster of the same name
+func buildRestore(rd *v1alpha1.RestoreDrill, bk *v1alpha1.Backup, timestamp time.Time) *v1alpha1.Restore {
+	ns := rd.GetNamespace()
+	name := fmt.Sprintf("%s-%s", rd.GetName(), timestamp.UTC().Format(v1alpha1.BackupNameTimeFormat))
+	drillLabels := label.NewRestoreDrill().RestoreDrill(rd.GetName())
+
+	spec := rd.Spec.RestoreTemplate.DeepCopy()
+	spec.Mode = v1alpha1.RestoreModeSnapshot
+	spec.StorageProvider = *bk.Spec.StorageProvider.DeepCopy()
+	spec.Type = bk.Spec.Type
+	if spec.BR == nil {
+		spec.BR = &v1alpha1.BRConfig{}
+	}
+	spec.BR.Cluster = name
+	spec.BR.ClusterNamespace = ns
+	spec.BR.DB = bk.Spec.BR.DB
+	spec.BR.Table = bk.Spec.BR.Table
+	spec.ClusterTemplate.Labels = util.CombineStringMap(drillLabels, spec.ClusterTemplate.Labels)
+	// the drill deletes the TidbCluster after the checks
+	spec.ClusterTemplate.CleanupPolicy = v1alpha1.RestoreClusterCleanupPolicyDelete
+
+	return &v1alpha1.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+			Labels:    util.CombineStringMap(drillLabels, rd.Labels),
+			OwnerReferences: []metav1.OwnerReference{
+				controller.GetRestoreDrillOwnerRef(rd),
+			},
+		},
+		Spec: *spec,
+	}
+}
+
+// ensureCheckJob creates the job running the checks of the drill if it does not exist
+func (rm *restoreDrillManager) ensureCheckJob(rd *v1alpha1.RestoreDrill) error {
+	ns := rd.GetNamespace()
+	name := rd.GetName()
+	run := rd.Status.CurrentRun
+
+	jobName := rd.GetCheckJobName(run.Restore)
+	if _, err := rm.deps.JobLister.Jobs(ns).Get(jobName); err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return fmt.Errorf("restore drill %s/%s, get check job %s failed, err: %v", ns, name, jobName, err)
+	}
+
+	job, err := rm.makeCheckJob(rd)
+	if err != nil {
+		return err
+	}
+	controller.SetRestoreDrillKind(rd)
+	if err := rm.deps.JobControl.CreateJob(rd, job); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("restore drill %s/%s, create check job %s failed, err: %v", ns, name, jobName, err)
+	}
+	return nil
+}
+
+func (rm *restoreDrillManager) makeCheckJob(rd *v1alpha1.RestoreDrill) (*batchv1.Job, error) {
+	ns := rd.GetNamespace()
+	name := rd.GetName()
+	template := &rd.Spec.RestoreTemplate
+
+	args := []string{
+		"drill",
+		fmt.Sprintf("--namespace=%s", ns),
+		fmt.Sprintf("--drillName=%s", name),
+	}
+	var (
+		envVars      []corev1.EnvVar
+		volumeMounts []corev1.VolumeMount
+		volumes      []corev1.Volume
+	)
+	if access := rd.Spec.Access; access != nil {
+		if access.SecretName != "" {
+			passwordEnv, _, err := backuputil.GenerateTidbPasswordEnv(ns, name, access.SecretName, template.UseKMS, rm.deps.SecretLister)
+			if err != nil {
+				return nil, fmt.Errorf("restore drill %s/%s, %v", ns, name, err)
+			}
+			envVars = append(envVars, passwordEnv...)
+		}
+		if access.TLSClientSecretName != nil {
+			args = append(args, "--client-tls=true")
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      "tidb-client-tls",
+				ReadOnly:  true,
+				MountPath: util.TiDBClientTLSPath,
+			})
+			volumes = append(volumes, corev1.Volume{
+				Name: "tidb-client-tls",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: *access.TLSClientSecretName,
+					},
+				},
+			})
+		}
+	}
+	envVars = util.AppendOverwriteEnv(envVars, template.Env)
+
+	serviceAccount := constants.DefaultServiceAccountName
+	if template.ServiceAccount != "" {
+		serviceAccount = template.ServiceAccount
+	}
+	jobLabels := util.CombineStringMap(label.NewRestoreDrill().RestoreDrill(name), rd.Labels)
+
+	podSpec := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      jobLabels,
+			Annotations: rd.Annotations,
+		},
+		Spec: corev1.PodSpec{
+			SecurityContext:    template.PodSecurityContext,
+			ServiceAccountName: serviceAccount,
+			Containers: []corev1.Container{
+				{
+					Name:            label.RestoreDrillJobLabelVal,
+					Image:           rm.deps.CLIConfig.TiDBBackupManagerImage,
+					Args:            args,
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					VolumeMounts:    volumeMounts,
+					Env:             util.AppendEnvIfPresent(envVars, "TZ"),
+				},
+			},
+			RestartPolicy:     corev1.RestartPolicyNever,
+			Tolerations:       template.Tolerations,
+			ImagePullSecrets:  template.ImagePullSecrets,
+			Affinity:          template.Affinity,
+			Volumes:           volumes,
+			PriorityClassName: template.PriorityClassName,
+		},
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        rd.GetCheckJobName(rd.Status.CurrentRun.Restore),
+			Namespace:   ns,
+			Labels:      jobLabels,
+			Annotations: rd.Annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				controller.GetRestoreDrillOwnerRef(rd),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32Ptr(0),
+			Template:     *podSpec,
+		},
+	}, nil
+}
+
+// getLastScheduledTime returns the latest scheduled time of the drill which is not after now,
+// it returns nil if there is no such time.
+func getLastScheduledTime(rd *v1alpha1.RestoreDrill, nowFn nowFn) (*time.Time, error) {
+	ns := rd.GetNamespace()
+	name := rd.GetName()
+
+	sched, err := cron.ParseStandard(rd.Spec.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("parse restore drill %s/%s cron format %s failed, err: %v", ns, name, rd.Spec.Schedule, err)
+	}
+
+	earliestTime := rd.CreationTimestamp.Time
+	if rd.Status.LastScheduleTime != nil {
+		earliestTime = rd.Status.LastScheduleTime.Time
+	}
+	now := nowFn()
+	if earliestTime.After(now) {
+		klog.Errorf("restore drill %s/%s timestamp fallback, lastScheduleTime: %s, now: %s",
+			ns, name, earliestTime.Format(time.RFC3339), now.Format(time.RFC3339))
+		return nil, nil
+	}
+
+	// only the latest missed drill is scheduled
+	var scheduledTime *time.Time
+	for t := sched.Next(earliestTime); !t.After(now); t = sched.Next(t) {
+		t := t
+		scheduledTime = &t
+	}
+	return scheduledTime, nil
+}
+
+var _ backup.RestoreDrillManager = &restoreDrillManager{}
+
+type FakeRestoreDrillManager struct {
+	err error
+}
+
+func NewFakeRestoreDrillManager() *FakeRestoreDrillManager {
+	return &FakeRestoreDrillManager{}
+}
+
+func (frm *FakeRestoreDrillManager) SetSyncError(err error) {
+	frm.err = err
+}
+
+func (frm *FakeRestoreDrillManager) Sync(rd *v1alpha1.RestoreDrill) error {
+	if frm.err != nil {
+		return frm.err
+	}
+
+	if rd.Status.LastScheduleTime != nil {
+		// simulate status update
+		rd.Status.LastScheduleTime = &metav1.Time{Time: rd.Status.LastScheduleTime.Add(1 * time.Hour)}
+	}
+	return nil
+}
+
+var _ backup.RestoreDrillManager = &FakeRestoreDrillManager{}
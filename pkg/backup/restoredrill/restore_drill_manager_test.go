@@ -0,0 +1,343 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restoredrill
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func newRestoreDrill() *v1alpha1.RestoreDrill {
+	rd := &v1alpha1.RestoreDrill{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns",
+			Name:              "drill",
+			UID:               "drill-uid",
+			CreationTimestamp: metav1.NewTime(time.Date(2023, 6, 1, 0, 30, 0, 0, time.UTC)),
+		},
+		Spec: v1alpha1.RestoreDrillSpec{
+			Schedule:           "0 * * * *",
+			BackupScheduleName: "bs",
+			RestoreTemplate: v1alpha1.RestoreSpec{
+				BR: &v1alpha1.BRConfig{},
+				ClusterTemplate: &v1alpha1.RestoreClusterTemplate{
+					Spec: &v1alpha1.TidbClusterSpec{
+						PD:   &v1alpha1.PDSpec{Replicas: 1},
+						TiKV: &v1alpha1.TiKVSpec{Replicas: 1},
+						TiDB: &v1alpha1.TiDBSpec{Replicas: 1},
+					},
+				},
+			},
+			Checks: []v1alpha1.RestoreDrillCheck{
+				{Name: "rows", Type: v1alpha1.RestoreDrillCheckRowCount, Table: "test.t"},
+			},
+		},
+	}
+	return rd
+}
+
+func newScheduledBackup(name, commitTs string) *v1alpha1.Backup {
+	return &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+			Labels:    label.NewBackupSchedule().Instance("bs").BackupSchedule("bs"),
+		},
+		Spec: v1alpha1.BackupSpec{
+			BR: &v1alpha1.BRConfig{Cluster: "source", DB: "test"},
+			StorageProvider: v1alpha1.StorageProvider{
+				S3: &v1alpha1.S3StorageProvider{Bucket: "bucket", Prefix: name},
+			},
+		},
+		Status: v1alpha1.BackupStatus{
+			CommitTs:   commitTs,
+			Conditions: []v1alpha1.BackupCondition{{Type: v1alpha1.BackupComplete, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func TestRestoreDrill(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewSimpleClientDependencies()
+	stop := make(chan struct{})
+	defer close(stop)
+	deps.InformerFactory.Start(stop)
+	deps.KubeInformerFactory.Start(stop)
+	deps.InformerFactory.WaitForCacheSync(stop)
+	deps.KubeInformerFactory.WaitForCacheSync(stop)
+
+	now := time.Date(2023, 6, 1, 2, 10, 0, 0, time.UTC)
+	m := &restoreDrillManager{deps: deps, now: func() time.Time { return now }}
+	rd := newRestoreDrill()
+
+	// the drill fails without any complete backup
+	g.Expect(m.Sync(rd)).Should(Succeed())
+	g.Expect(rd.Status.LastScheduleTime.Time).Should(Equal(time.Date(2023, 6, 1, 2, 0, 0, 0, time.UTC)))
+	g.Expect(rd.Status.CurrentRun).Should(BeNil())
+	g.Expect(rd.Status.LastResult).Should(Equal(v1alpha1.RestoreDrillFailed))
+	g.Expect(rd.Status.History).Should(HaveLen(1))
+
+	// the latest complete backup is restored into the temporary cluster
+	for _, bk := range []*v1alpha1.Backup{
+		newScheduledBackup("bs-1", "400000000000000000"),
+		newScheduledBackup("bs-2", "410000000000000000"),
+		newScheduledBackup("bs-3", ""),
+	} {
+		_, err := deps.Clientset.PingcapV1alpha1().Backups(bk.Namespace).Create(context.TODO(), bk, metav1.CreateOptions{})
+		g.Expect(err).Should(Succeed())
+	}
+	g.Eventually(func() int {
+		backups, _ := deps.BackupLister.Backups("ns").List(labels.Everything())
+		return len(backups)
+	}, time.Second*10).Should(Equal(3))
+	now = now.Add(time.Hour)
+	g.Expect(m.Sync(rd)).Should(Succeed())
+	run := rd.Status.CurrentRun
+	g.Expect(run).ShouldNot(BeNil())
+	g.Expect(run.Phase).Should(Equal(v1alpha1.RestoreDrillRestoring))
+	g.Expect(run.Backup).Should(Equal("bs-2"))
+	restore, err := deps.Clientset.PingcapV1alpha1().Restores("ns").Get(context.TODO(), run.Restore, metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(restore.Labels).Should(HaveKeyWithValue(label.RestoreDrillLabelKey, rd.Name))
+	g.Expect(restore.Spec.BR.Cluster).Should(Equal(run.Cluster))
+	g.Expect(restore.Spec.BR.DB).Should(Equal("test"))
+	g.Expect(restore.Spec.S3.Prefix).Should(Equal("bs-2"))
+	g.Expect(restore.Spec.ClusterTemplate.GetCleanupPolicy()).Should(Equal(v1alpha1.RestoreClusterCleanupPolicyDelete))
+
+	// the drill waits for the restore to complete and then runs the checks
+	g.Eventually(func() error {
+		_, err := deps.RestoreLister.Restores("ns").Get(run.Restore)
+		return err
+	}, time.Second*10).Should(Succeed())
+	g.Expect(controller.IsRequeueError(m.Sync(rd))).Should(BeTrue())
+	restore.CreationTimestamp = metav1.NewTime(now)
+	restore.Status.TimeCompleted = metav1.NewTime(now.Add(10 * time.Minute))
+	restore.Status.Conditions = []v1alpha1.RestoreCondition{{Type: v1alpha1.RestoreComplete, Status: corev1.ConditionTrue}}
+	restore, err = deps.Clientset.PingcapV1alpha1().Restores("ns").Update(context.TODO(), restore, metav1.UpdateOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Eventually(func() bool {
+		got, err := deps.RestoreLister.Restores("ns").Get(run.Restore)
+		return err == nil && apiequality.Semantic.DeepEqual(got, restore)
+	}, time.Second*10).Should(BeTrue())
+	g.Expect(controller.IsRequeueError(m.Sync(rd))).Should(BeTrue())
+	g.Expect(run.Phase).Should(Equal(v1alpha1.RestoreDrillChecking))
+	g.Expect(run.RestoreDuration).Should(Equal("10m0s"))
+	// the check job is created after the Checking phase is persisted
+	_, err = deps.KubeClientset.BatchV1().Jobs("ns").Get(context.TODO(), rd.GetCheckJobName(run.Restore), metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+	g.Expect(controller.IsRequeueError(m.Sync(rd))).Should(BeTrue())
+	job, err := deps.KubeClientset.BatchV1().Jobs("ns").Get(context.TODO(), rd.GetCheckJobName(run.Restore), metav1.GetOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(job.Spec.Template.Spec.Containers[0].Args).Should(ContainElement("--drillName=drill"))
+
+	// the resources are deleted after the checks pass
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      run.Cluster,
+			Labels:    map[string]string{label.RestoreLabelKey: run.Restore},
+		},
+	}
+	_, err = deps.Clientset.PingcapV1alpha1().TidbClusters("ns").Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Eventually(func() error {
+		if _, err := deps.JobLister.Jobs("ns").Get(job.Name); err != nil {
+			return err
+		}
+		_, err := deps.TiDBClusterLister.TidbClusters("ns").Get(tc.Name)
+		return err
+	}, time.Second*10).Should(Succeed())
+	run.Result = v1alpha1.RestoreDrillPassed
+	run.Phase = v1alpha1.RestoreDrillCleaningUp
+	g.Expect(m.Sync(rd)).Should(Succeed())
+	_, err = deps.Clientset.PingcapV1alpha1().Restores("ns").Get(context.TODO(), run.Restore, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+	_, err = deps.Clientset.PingcapV1alpha1().TidbClusters("ns").Get(context.TODO(), run.Cluster, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+	_, err = deps.KubeClientset.BatchV1().Jobs("ns").Get(context.TODO(), job.Name, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+	g.Expect(rd.Status.CurrentRun).Should(BeNil())
+	g.Expect(rd.Status.LastResult).Should(Equal(v1alpha1.RestoreDrillPassed))
+	g.Expect(rd.Status.LastSuccessfulTime).ShouldNot(BeNil())
+	g.Expect(rd.Status.History).Should(HaveLen(2))
+	g.Expect(rd.Status.History[0].Phase).Should(Equal(v1alpha1.RestoreDrillFinished))
+	g.Expect(rd.Status.History[0].Backup).Should(Equal("bs-2"))
+}
+
+func TestRestoreDrillRestoreFailed(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewSimpleClientDependencies()
+	stop := make(chan struct{})
+	defer close(stop)
+	deps.InformerFactory.Start(stop)
+	deps.KubeInformerFactory.Start(stop)
+	deps.InformerFactory.WaitForCacheSync(stop)
+	deps.KubeInformerFactory.WaitForCacheSync(stop)
+
+	m := &restoreDrillManager{deps: deps, now: time.Now}
+	rd := newRestoreDrill()
+	limit := int32(1)
+	rd.Spec.HistoryLimit = &limit
+	rd.Status.History = []v1alpha1.RestoreDrillRun{{Phase: v1alpha1.RestoreDrillFinished, Result: v1alpha1.RestoreDrillPassed}}
+	rd.Status.CurrentRun = &v1alpha1.RestoreDrillRun{
+		Phase:   v1alpha1.RestoreDrillRestoring,
+		Restore: "drill-restore",
+		Cluster: "drill-restore",
+	}
+	restore := &v1alpha1.Restore{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "drill-restore"},
+		Status: v1alpha1.RestoreStatus{
+			Conditions: []v1alpha1.RestoreCondition{{Type: v1alpha1.RestoreFailed, Status: corev1.ConditionTrue, Reason: "RestoreFailed"}},
+		},
+	}
+	_, err := deps.Clientset.PingcapV1alpha1().Restores("ns").Create(context.TODO(), restore, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Eventually(func() error {
+		_, err := deps.RestoreLister.Restores("ns").Get(restore.Name)
+		return err
+	}, time.Second*10).Should(Succeed())
+
+	// the failed restore fails the drill and the oldest history is dropped
+	g.Expect(m.Sync(rd)).Should(Succeed())
+	g.Expect(rd.Status.CurrentRun).Should(BeNil())
+	g.Expect(rd.Status.LastResult).Should(Equal(v1alpha1.RestoreDrillFailed))
+	g.Expect(rd.Status.LastSuccessfulTime).Should(BeNil())
+	g.Expect(rd.Status.History).Should(HaveLen(1))
+	g.Expect(rd.Status.History[0].Message).Should(ContainSubstring("restore drill-restore failed"))
+	_, err = deps.Clientset.PingcapV1alpha1().Restores("ns").Get(context.TODO(), restore.Name, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+}
+
+func TestRestoreDrillCheckJobCompleteWithoutResults(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewSimpleClientDependencies()
+	stop := make(chan struct{})
+	defer close(stop)
+	deps.InformerFactory.Start(stop)
+	deps.KubeInformerFactory.Start(stop)
+	deps.InformerFactory.WaitForCacheSync(stop)
+	deps.KubeInformerFactory.WaitForCacheSync(stop)
+
+	m := &restoreDrillManager{deps: deps, now: time.Now}
+	rd := newRestoreDrill()
+	rd.Status.CurrentRun = &v1alpha1.RestoreDrillRun{
+		Phase:   v1alpha1.RestoreDrillChecking,
+		Restore: "drill-restore",
+		Cluster: "drill-restore",
+	}
+	rd, err := deps.Clientset.PingcapV1alpha1().RestoreDrills("ns").Create(context.TODO(), rd, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: rd.GetCheckJobName("drill-restore")},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+		},
+	}
+	_, err = deps.KubeClientset.BatchV1().Jobs("ns").Create(context.TODO(), job, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Eventually(func() error {
+		_, err := deps.JobLister.Jobs("ns").Get(job.Name)
+		return err
+	}, time.Second*10).Should(Succeed())
+
+	// the drill waits if the results are recorded but not synced yet
+	recorded := rd.DeepCopy()
+	recorded.Status.CurrentRun.Phase = v1alpha1.RestoreDrillCleaningUp
+	recorded.Status.CurrentRun.Result = v1alpha1.RestoreDrillPassed
+	_, err = deps.Clientset.PingcapV1alpha1().RestoreDrills("ns").Update(context.TODO(), recorded, metav1.UpdateOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(controller.IsRequeueError(m.Sync(rd.DeepCopy()))).Should(BeTrue())
+
+	// the drill fails if the check job completes without recording the results
+	_, err = deps.Clientset.PingcapV1alpha1().RestoreDrills("ns").Update(context.TODO(), rd, metav1.UpdateOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Expect(m.Sync(rd)).Should(Succeed())
+	g.Expect(rd.Status.CurrentRun).Should(BeNil())
+	g.Expect(rd.Status.LastResult).Should(Equal(v1alpha1.RestoreDrillFailed))
+	g.Expect(rd.Status.History).Should(HaveLen(1))
+	g.Expect(rd.Status.History[0].Message).Should(ContainSubstring("completed without recording the results"))
+	_, err = deps.KubeClientset.BatchV1().Jobs("ns").Get(context.TODO(), job.Name, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+}
+
+func TestRestoreDrillDeleted(t *testing.T) {
+	g := NewGomegaWithT(t)
+	deps := controller.NewSimpleClientDependencies()
+	stop := make(chan struct{})
+	defer close(stop)
+	deps.InformerFactory.Start(stop)
+	deps.KubeInformerFactory.Start(stop)
+	deps.InformerFactory.WaitForCacheSync(stop)
+	deps.KubeInformerFactory.WaitForCacheSync(stop)
+
+	m := &restoreDrillManager{deps: deps, now: time.Now}
+	rd := newRestoreDrill()
+	rd.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	rd.Status.CurrentRun = &v1alpha1.RestoreDrillRun{
+		Phase:   v1alpha1.RestoreDrillRestoring,
+		Restore: "drill-restore",
+		Cluster: "drill-restore",
+	}
+	restore := &v1alpha1.Restore{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "drill-restore"}}
+	_, err := deps.Clientset.PingcapV1alpha1().Restores("ns").Create(context.TODO(), restore, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+	tc := &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "drill-restore",
+			Labels:    map[string]string{label.RestoreLabelKey: "drill-restore"},
+		},
+	}
+	_, err = deps.Clientset.PingcapV1alpha1().TidbClusters("ns").Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "tikv-drill-restore-tikv-0",
+			Labels:    label.New().Instance("drill-restore"),
+		},
+	}
+	_, err = deps.KubeClientset.CoreV1().PersistentVolumeClaims("ns").Create(context.TODO(), pvc, metav1.CreateOptions{})
+	g.Expect(err).Should(Succeed())
+	g.Eventually(func() error {
+		if _, err := deps.TiDBClusterLister.TidbClusters("ns").Get(tc.Name); err != nil {
+			return err
+		}
+		_, err := deps.PVCLister.PersistentVolumeClaims("ns").Get(pvc.Name)
+		return err
+	}, time.Second*10).Should(Succeed())
+
+	// the running drill is cleaned up when the restore drill is deleted
+	g.Expect(m.Sync(rd)).Should(Succeed())
+	g.Expect(rd.Status.CurrentRun).Should(BeNil())
+	g.Expect(rd.Status.LastResult).Should(Equal(v1alpha1.RestoreDrillFailed))
+	_, err = deps.Clientset.PingcapV1alpha1().Restores("ns").Get(context.TODO(), restore.Name, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+	_, err = deps.Clientset.PingcapV1alpha1().TidbClusters("ns").Get(context.TODO(), tc.Name, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+	_, err = deps.KubeClientset.CoreV1().PersistentVolumeClaims("ns").Get(context.TODO(), pvc.Name, metav1.GetOptions{})
+	g.Expect(errors.IsNotFound(err)).Should(BeTrue())
+}
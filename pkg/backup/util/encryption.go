@@ -0,0 +1,103 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	corelisterv1 "k8s.io/client-go/listers/core/v1"
+)
+
+const encryptionKeyVolumeName = "encryption-key"
+
+// GetEncryptionKey gets the encryption key from the secret referenced by the encryption,
+// and validates the key matches the crypter method
+func GetEncryptionKey(ns string, encryption *v1alpha1.BackupEncryption, secretLister corelisterv1.SecretLister) (string, string, error) {
+	secret, err := secretLister.Secrets(ns).Get(encryption.SecretName)
+	if err != nil {
+		return "", fmt.Sprintf("failed to get encryption secret %s/%s", ns, encryption.SecretName), err
+	}
+	secretKey := encryption.GetSecretKey()
+	key, ok := secret.Data[secretKey]
+	if !ok {
+		return "", "KeyNotExist", fmt.Errorf("key %s does not exist in encryption secret %s/%s", secretKey, ns, encryption.SecretName)
+	}
+	if errs := validation.ValidateBackupEncryptionKey(encryption, string(key), field.NewPath("spec", "encryption")); len(errs) > 0 {
+		return "", "InvalidEncryptionKey", fmt.Errorf("invalid key in encryption secret %s/%s: %v", ns, encryption.SecretName, errs.ToAggregate())
+	}
+	return string(key), "", nil
+}
+
+// GetEncryptionKeyFile returns the path of the encryption key file in the BR container
+func GetEncryptionKeyFile(encryption *v1alpha1.BackupEncryption) string {
+	return path.Join(constants.BREncryptionKeyPath, encryption.GetSecretKey())
+}
+
+// AppendEncryptionKeyVolume appends the volume and the volume mount of the encryption key secret,
+// nothing is appended if the encryption is not set.
+func AppendEncryptionKeyVolume(encryption *v1alpha1.BackupEncryption, volumes []corev1.Volume, volumeMounts []corev1.VolumeMount) ([]corev1.Volume, []corev1.VolumeMount) {
+	if encryption == nil {
+		return volumes, volumeMounts
+	}
+	volumes = append(volumes, corev1.Volume{
+		Name: encryptionKeyVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: encryption.SecretName,
+				Items: []corev1.KeyToPath{{
+					Key:  encryption.GetSecretKey(),
+					Path: encryption.GetSecretKey(),
+				}},
+			},
+		},
+	})
+	volumeMounts = append(volumeMounts, corev1.VolumeMount{
+		Name:      encryptionKeyVolumeName,
+		ReadOnly:  true,
+		MountPath: constants.BREncryptionKeyPath,
+	})
+	return volumes, volumeMounts
+}
+
+func validateBackupEncryption(backup *v1alpha1.Backup) error {
+	if backup.Spec.Encryption == nil {
+		return nil
+	}
+	if backup.Spec.Mode != "" && backup.Spec.Mode != v1alpha1.BackupModeSnapshot {
+		return fmt.Errorf("encryption is only supported by snapshot backup in spec of %s/%s", backup.Namespace, backup.Name)
+	}
+	if errs := validation.ValidateBackupEncryption(backup.Spec.Encryption, field.NewPath("spec", "encryption")); len(errs) > 0 {
+		return fmt.Errorf("invalid encryption in spec of %s/%s: %v", backup.Namespace, backup.Name, errs.ToAggregate())
+	}
+	return nil
+}
+
+func validateRestoreEncryption(restore *v1alpha1.Restore) error {
+	if restore.Spec.Encryption == nil {
+		return nil
+	}
+	if restore.Spec.Mode != "" && restore.Spec.Mode != v1alpha1.RestoreModeSnapshot && restore.Spec.Mode != v1alpha1.RestoreModePiTR {
+		return fmt.Errorf("encryption is only supported by snapshot and pitr restore in spec of %s/%s", restore.Namespace, restore.Name)
+	}
+	if errs := validation.ValidateBackupEncryption(restore.Spec.Encryption, field.NewPath("spec", "encryption")); len(errs) > 0 {
+		return fmt.Errorf("invalid encryption in spec of %s/%s: %v", restore.Namespace, restore.Name, errs.ToAggregate())
+	}
+	return nil
+}
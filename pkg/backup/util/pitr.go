@@ -0,0 +1,88 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
+)
+
+// GetLogBackupTSORange returns the range [start, end] of TSO covered by the log backup,
+// start is the larger one of the start ts and the truncated ts, end is the checkpoint ts.
+func GetLogBackupTSORange(logBackup *v1alpha1.Backup) (uint64, uint64, error) {
+	startTSO, err := config.ParseTSString(logBackup.Status.CommitTs)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parse commit ts of log backup %s/%s failed: %v", logBackup.Namespace, logBackup.Name, err)
+	}
+	truncateTSO, err := config.ParseTSString(logBackup.Status.LogSuccessTruncateUntil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parse truncate ts of log backup %s/%s failed: %v", logBackup.Namespace, logBackup.Name, err)
+	}
+	checkpointTSO, err := config.ParseTSString(logBackup.Status.LogCheckpointTs)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parse checkpoint ts of log backup %s/%s failed: %v", logBackup.Namespace, logBackup.Name, err)
+	}
+	if truncateTSO > startTSO {
+		startTSO = truncateTSO
+	}
+	return startTSO, checkpointTSO, nil
+}
+
+// IsPitrFullBackupCandidate returns whether the backup is a complete snapshot backup which can be
+// used as the full backup of pitr
+func IsPitrFullBackupCandidate(backup *v1alpha1.Backup) bool {
+	if backup.Spec.Mode != "" && backup.Spec.Mode != v1alpha1.BackupModeSnapshot {
+		return false
+	}
+	return v1alpha1.IsBackupComplete(backup) && backup.Status.CommitTs != ""
+}
+
+// GetPitrRecoverableWindow returns the range [start, end] of TSO that can be restored by pitr,
+// start is the commit ts of the earliest complete snapshot backup within the range of the log
+// backup, end is the checkpoint ts of the log backup. It returns 0, 0 if there is no such backup.
+func GetPitrRecoverableWindow(backups []*v1alpha1.Backup, logBackup *v1alpha1.Backup) (uint64, uint64, error) {
+	logStartTSO, logEndTSO, err := GetLogBackupTSORange(logBackup)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var startTSO uint64
+	for _, backup := range backups {
+		if !IsPitrFullBackupCandidate(backup) {
+			continue
+		}
+		commitTSO, err := config.ParseTSString(backup.Status.CommitTs)
+		if err != nil {
+			return 0, 0, fmt.Errorf("parse commit ts of backup %s/%s failed: %v", backup.Namespace, backup.Name, err)
+		}
+		if commitTSO < logStartTSO || commitTSO > logEndTSO {
+			continue
+		}
+		if startTSO == 0 || commitTSO < startTSO {
+			startTSO = commitTSO
+		}
+	}
+	if startTSO == 0 {
+		return 0, 0, nil
+	}
+	return startTSO, logEndTSO, nil
+}
+
+// FormatTSO formats the TSO as the RFC3339 time in UTC
+func FormatTSO(tso uint64) string {
+	return time.UnixMilli(int64(tso >> 18)).UTC().Format(time.RFC3339)
+}
@@ -41,6 +41,8 @@ import (
 	"gocloud.dev/gcerrors"
 	"gocloud.dev/gcp"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	corelisterv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -325,7 +327,85 @@ func BatchDeleteObjectsConcurrently(ctx context.Context, bucket *blob.Bucket, ob
 	return result
 }
 
+// CopyObjects copies all the objects in the storage to the destination storage concurrently,
+// the keys of the objects are relative to the prefixes of the storages. It returns the number
+// of the copied objects and the first error.
+func (b *StorageBackend) CopyObjects(ctx context.Context, dst *StorageBackend, concurrency int) (int, error) {
+	var keys []string
+	iter := b.List(nil)
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if !obj.IsDir {
+			keys = append(keys, obj.Key)
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		copied   int
+		firstErr error
+	)
+	workqueue.ParallelizeUntil(ctx, concurrency, len(keys), func(piece int) {
+		err := copyObject(ctx, b, dst, keys[piece])
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("copy object %s failed: %v", keys[piece], err)
+			}
+			return
+		}
+		copied++
+	})
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return copied, firstErr
+}
+
+func copyObject(ctx context.Context, src, dst *StorageBackend, key string) error {
+	reader, err := src.NewReader(ctx, key, nil)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// cancel the context to abort the write, so that the partial object is not committed
+	writeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	writer, err := dst.NewWriter(writeCtx, key, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(writer, reader); err != nil {
+		cancel()
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// GetStorageCredential returns the credential of the storage from the secret referenced by the provider
 func GetStorageCredential(ns string, provider v1alpha1.StorageProvider, secretLister corelisterv1.SecretLister) *StorageCredential {
+	return getStorageCredential(provider, secretLister.Secrets(ns).Get)
+}
+
+// GetStorageCredentialByClient is the same as GetStorageCredential, but gets the secret by the client.
+// It's used by the backup-manager, which doesn't watch secrets.
+func GetStorageCredentialByClient(ns string, provider v1alpha1.StorageProvider, kubeCli kubernetes.Interface) *StorageCredential {
+	return getStorageCredential(provider, func(name string) (*corev1.Secret, error) {
+		return kubeCli.CoreV1().Secrets(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	})
+}
+
+func getStorageCredential(provider v1alpha1.StorageProvider, getSecret func(name string) (*corev1.Secret, error)) *StorageCredential {
 	var err error
 	var secret *corev1.Secret
 	storageType := GetStorageType(provider)
@@ -334,7 +414,7 @@ func GetStorageCredential(ns string, provider v1alpha1.StorageProvider, secretLi
 	case v1alpha1.BackupStorageTypeS3:
 		s3SecretName := provider.S3.SecretName
 		if s3SecretName != "" {
-			secret, err = secretLister.Secrets(ns).Get(s3SecretName)
+			secret, err = getSecret(s3SecretName)
 			if err != nil {
 				klog.Errorf("Get the secret key failed.")
 				return &StorageCredential{}
@@ -390,6 +470,15 @@ func GenStorageArgsForFlag(provider v1alpha1.StorageProvider, flag string) ([]st
 	}
 }
 
+// GetStorageURL returns the URL of the storage used by BR, such as s3://bucket/prefix
+func GetStorageURL(provider v1alpha1.StorageProvider) (string, error) {
+	args, err := GenStorageArgsForFlag(provider, "")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(args[0], "--storage="), nil
+}
+
 // newLocalStorageOption constructs `--flag local://$PATH` arg for br
 func newLocalStorageOptionForFlag(conf *localConfig, flag string) ([]string, error) {
 	if flag != "" && flag != defaultStorageFlag {
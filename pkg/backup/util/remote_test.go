@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,6 +33,7 @@ import (
 	"github.com/onsi/gomega"
 	"gocloud.dev/blob"
 	"gocloud.dev/blob/driver"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 )
@@ -629,3 +632,60 @@ func objects(size int) []*blob.ListObject {
 	}
 	return objs
 }
+
+func TestStorageBackendCopyObjects(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	newLocalBackend := func(mountPath, prefix string) *StorageBackend {
+		backend, err := NewStorageBackend(v1alpha1.StorageProvider{
+			Local: &v1alpha1.LocalStorageProvider{
+				Volume:      corev1.Volume{Name: "local"},
+				VolumeMount: corev1.VolumeMount{Name: "local", MountPath: mountPath},
+				Prefix:      prefix,
+			},
+		}, &StorageCredential{})
+		g.Expect(err).Should(gomega.BeNil())
+		return backend
+	}
+
+	srcPath := t.TempDir()
+	contents := map[string]string{
+		"backupmeta":     "meta",
+		"1/1_2_3.sst":    "the first sst",
+		"1/4_5_6.sst":    "the second sst",
+		"2/7_8_9.sst":    "the third sst",
+		"checkpoint.log": "",
+	}
+	for key, content := range contents {
+		path := filepath.Join(srcPath, "backup-1", key)
+		g.Expect(os.MkdirAll(filepath.Dir(path), 0755)).Should(gomega.Succeed())
+		g.Expect(os.WriteFile(path, []byte(content), 0644)).Should(gomega.Succeed())
+	}
+	src := newLocalBackend(srcPath, "backup-1")
+	defer src.Close()
+
+	dstPath := t.TempDir()
+	g.Expect(os.MkdirAll(filepath.Join(dstPath, "dr/backup-1"), 0755)).Should(gomega.Succeed())
+	dst := newLocalBackend(dstPath, "dr/backup-1")
+	defer dst.Close()
+
+	copied, err := src.CopyObjects(context.Background(), dst, 2)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(copied).Should(gomega.Equal(len(contents)))
+	for key, content := range contents {
+		data, err := os.ReadFile(filepath.Join(dstPath, "dr/backup-1", key))
+		g.Expect(err).Should(gomega.BeNil())
+		g.Expect(string(data)).Should(gomega.Equal(content))
+	}
+
+	// copy again to overwrite the existing objects
+	copied, err = src.CopyObjects(context.Background(), dst, 2)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(copied).Should(gomega.Equal(len(contents)))
+
+	// the canceled context aborts the copy
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = src.CopyObjects(ctx, dst, 2)
+	g.Expect(err).ShouldNot(gomega.BeNil())
+}
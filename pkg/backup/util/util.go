@@ -26,6 +26,7 @@ import (
 
 	"github.com/Masterminds/semver"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
 	"github.com/pingcap/tidb-operator/pkg/apis/util/config"
 	"github.com/pingcap/tidb-operator/pkg/backup/constants"
 	corev1 "k8s.io/api/core/v1"
@@ -479,6 +480,9 @@ func ValidateBackup(backup *v1alpha1.Backup, tikvImage string, acrossK8s bool) e
 		if backup.Spec.StorageSize == "" {
 			return fmt.Errorf("missing StorageSize config in spec of %s/%s", ns, name)
 		}
+		if errs := validation.ValidateBackup(backup); len(errs) > 0 {
+			return fmt.Errorf("invalid spec of %s/%s: %v", ns, name, errs.ToAggregate())
+		}
 	} else {
 		if !canSkipSetGCLifeTime(tikvImage) {
 			if reason := validateAccessConfig(backup.Spec.From); reason != "" {
@@ -547,6 +551,15 @@ func ValidateBackup(backup *v1alpha1.Backup, tikvImage string, acrossK8s bool) e
 			}
 		}
 
+		// validate secondary storages
+		if err := validateSecondaryStorages(backup); err != nil {
+			return err
+		}
+
+		if err := validateBackupEncryption(backup); err != nil {
+			return err
+		}
+
 		if backup.Spec.BackoffRetryPolicy.MinRetryDuration != "" {
 			_, err := time.ParseDuration(backup.Spec.BackoffRetryPolicy.MinRetryDuration)
 			if err != nil {
@@ -575,6 +588,9 @@ func ValidateRestore(restore *v1alpha1.Restore, tikvImage string, acrossK8s bool
 		if restore.Spec.StorageSize == "" {
 			return fmt.Errorf("missing StorageSize config in spec of %s/%s", ns, name)
 		}
+		if errs := validation.ValidateRestore(restore); len(errs) > 0 {
+			return fmt.Errorf("invalid spec of %s/%s: %v", ns, name, errs.ToAggregate())
+		}
 	} else {
 		if !canSkipSetGCLifeTime(tikvImage) {
 			if reason := validateAccessConfig(restore.Spec.To); reason != "" {
@@ -621,6 +637,23 @@ func ValidateRestore(restore *v1alpha1.Restore, tikvImage string, acrossK8s bool
 				return errors.New("only support volume snapshot restore across k8s clusters")
 			}
 		}
+
+		if ref := restore.Spec.PitrBackupScheduleRef; ref != nil {
+			if restore.Spec.Mode != v1alpha1.RestoreModePiTR {
+				return fmt.Errorf("pitrBackupScheduleRef is only supported by pitr restore in spec of %s/%s", ns, name)
+			}
+			if ref.Name == "" {
+				return fmt.Errorf("name of pitrBackupScheduleRef should be configured in spec of %s/%s", ns, name)
+			}
+		}
+
+		if err := validateRestoreEncryption(restore); err != nil {
+			return err
+		}
+
+		if errs := validation.ValidateRestore(restore); len(errs) > 0 {
+			return fmt.Errorf("invalid spec of %s/%s: %v", ns, name, errs.ToAggregate())
+		}
 	}
 	return nil
 }
@@ -657,6 +690,14 @@ func validateGcs(ns, name string, gcs *v1alpha1.GcsStorageProvider) error {
 	return nil
 }
 
+func validateAzblob(ns, name string, azblob *v1alpha1.AzblobStorageProvider) error {
+	configuredForBR := fmt.Sprintf("configured for BR in spec of %s/%s", ns, name)
+	if azblob.Container == "" {
+		return fmt.Errorf("container should be %s", configuredForBR)
+	}
+	return nil
+}
+
 func validateLocal(ns, name string, local *v1alpha1.LocalStorageProvider) error {
 	configuredForBR := fmt.Sprintf("configured for BR in spec of %s/%s", ns, name)
 	if local.VolumeMount.Name != local.Volume.Name {
@@ -671,6 +712,63 @@ func validateLocal(ns, name string, local *v1alpha1.LocalStorageProvider) error
 	return nil
 }
 
+// AppendSecondaryStorageVolumes appends the volumes and the volume mounts of the local secondary storages of the backup,
+// the volumes with the same names as the existing ones are skipped.
+func AppendSecondaryStorageVolumes(backup *v1alpha1.Backup, volumes []corev1.Volume, volumeMounts []corev1.VolumeMount) ([]corev1.Volume, []corev1.VolumeMount) {
+	names := map[string]bool{}
+	for _, volume := range volumes {
+		names[volume.Name] = true
+	}
+	for _, storage := range backup.Spec.SecondaryStorages {
+		if storage.Local == nil || names[storage.Local.Volume.Name] {
+			continue
+		}
+		names[storage.Local.Volume.Name] = true
+		volumes = append(volumes, storage.Local.Volume)
+		volumeMounts = append(volumeMounts, storage.Local.VolumeMount)
+	}
+	return volumes, volumeMounts
+}
+
+func validateSecondaryStorages(backup *v1alpha1.Backup) error {
+	ns := backup.Namespace
+	name := backup.Name
+	if len(backup.Spec.SecondaryStorages) == 0 {
+		return nil
+	}
+	if backup.Spec.Mode != "" && backup.Spec.Mode != v1alpha1.BackupModeSnapshot {
+		return fmt.Errorf("secondary storages are only supported by snapshot backup in spec of %s/%s", ns, name)
+	}
+	names := map[string]bool{}
+	for _, storage := range backup.Spec.SecondaryStorages {
+		if storage.Name == "" {
+			return fmt.Errorf("name of secondary storage should be configured in spec of %s/%s", ns, name)
+		}
+		if names[storage.Name] {
+			return fmt.Errorf("duplicated secondary storage %s in spec of %s/%s", storage.Name, ns, name)
+		}
+		names[storage.Name] = true
+
+		var err error
+		switch GetStorageType(storage.StorageProvider) {
+		case v1alpha1.BackupStorageTypeS3:
+			err = validateS3(ns, name, storage.S3)
+		case v1alpha1.BackupStorageTypeGcs:
+			err = validateGcs(ns, name, storage.Gcs)
+		case v1alpha1.BackupStorageTypeAzblob:
+			err = validateAzblob(ns, name, storage.Azblob)
+		case v1alpha1.BackupStorageTypeLocal:
+			err = validateLocal(ns, name, storage.Local)
+		default:
+			err = fmt.Errorf("storage of secondary storage %s should be configured in spec of %s/%s", storage.Name, ns, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ParseImage returns the image name and the tag from the input image string
 func ParseImage(image string) (string, string) {
 	var name, tag string
@@ -490,6 +490,11 @@ func TestValidateBackup(t *testing.T) {
 	backup.Spec.StorageSize = "1m"
 	match("")
 
+	backup.Spec.Dumpling = &v1alpha1.DumplingConfig{FileType: "parquet"}
+	match("invalid spec of")
+	backup.Spec.Dumpling.FileType = v1alpha1.DumplingFileTypeCSV
+	match("")
+
 	// start BR != nil case
 	backup.Spec.BR = &v1alpha1.BRConfig{}
 	match("cluster should be configured for BR in spec")
@@ -521,6 +526,36 @@ func TestValidateBackup(t *testing.T) {
 
 	backup.Spec.S3.Endpoint = "s3://localhost:80"
 	match("")
+
+	// secondary storages
+	backup.Spec.SecondaryStorages = []v1alpha1.SecondaryStorage{{}}
+	match("name of secondary storage should be configured")
+
+	backup.Spec.SecondaryStorages[0].Name = "dr"
+	match("storage of secondary storage dr should be configured")
+
+	backup.Spec.SecondaryStorages[0].S3 = &v1alpha1.S3StorageProvider{}
+	match("bucket should be configured for BR in spec of")
+
+	backup.Spec.SecondaryStorages[0].S3.Bucket = "dr-bucket"
+	match("")
+
+	backup.Spec.SecondaryStorages = append(backup.Spec.SecondaryStorages, backup.Spec.SecondaryStorages[0])
+	match("duplicated secondary storage dr")
+
+	backup.Spec.SecondaryStorages[1] = v1alpha1.SecondaryStorage{Name: "dr-azure"}
+	backup.Spec.SecondaryStorages[1].Azblob = &v1alpha1.AzblobStorageProvider{}
+	match("container should be configured for BR in spec of")
+
+	backup.Spec.SecondaryStorages[1].Azblob.Container = "dr-container"
+	match("")
+
+	backup.Spec.SecondaryStorages = nil
+	backup.Spec.Encryption = &v1alpha1.BackupEncryption{Method: v1alpha1.BackupEncryptionMethodAES256CTR}
+	match("invalid encryption in spec of")
+
+	backup.Spec.Encryption.SecretName = "backup-key"
+	match("")
 }
 
 func TestValidateRestore(t *testing.T) {
@@ -581,6 +616,15 @@ func TestValidateRestore(t *testing.T) {
 
 	restore.Spec.S3.Endpoint = "s3://localhost:80"
 	match("")
+
+	restore.Spec.PitrBackupScheduleRef = &corev1.LocalObjectReference{}
+	match("pitrBackupScheduleRef is only supported by pitr restore")
+
+	restore.Spec.Mode = v1alpha1.RestoreModePiTR
+	match("name of pitrBackupScheduleRef should be configured")
+
+	restore.Spec.PitrBackupScheduleRef.Name = "bs"
+	match("")
 }
 
 func TestGetImageTag(t *testing.T) {
@@ -0,0 +1,113 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	kvbackup "github.com/pingcap/kvproto/pkg/backup"
+	"gocloud.dev/gcerrors"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// CorruptedFile is a backup file which is missing or corrupted
+type CorruptedFile struct {
+	Name   string
+	Reason string
+}
+
+// VerifyBackupFilesResult is the result of VerifyBackupFiles
+type VerifyBackupFilesResult struct {
+	// Verified is the number of the verified files
+	Verified int
+	// Corrupted are the missing or corrupted files, ordered by name
+	Corrupted []CorruptedFile
+}
+
+// VerifyBackupFiles verifies the files referenced by the backup meta concurrently.
+// A file is corrupted if it doesn't exist, or its size or sha256 checksum doesn't match the backup meta.
+// The size and checksum are not checked if they are not recorded in the backup meta, and the checksum is not
+// checked if skipChecksum is true, e.g. the files are encrypted while the checksum is computed over the plaintext.
+// The returned error is not nil only if the storage can not be accessed.
+func (b *StorageBackend) VerifyBackupFiles(ctx context.Context, files []*kvbackup.File, concurrency int, skipChecksum bool) (*VerifyBackupFilesResult, error) {
+	var (
+		mu     sync.Mutex
+		result = &VerifyBackupFilesResult{}
+		errs   []error
+	)
+
+	workqueue.ParallelizeUntil(ctx, concurrency, len(files), func(piece int) {
+		file := files[piece]
+		reason, err := b.verifyBackupFile(ctx, file, skipChecksum)
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("verify backup file %s failed: %v", file.Name, err))
+		case reason != "":
+			result.Corrupted = append(result.Corrupted, CorruptedFile{Name: file.Name, Reason: reason})
+		default:
+			result.Verified++
+		}
+	})
+
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(result.Corrupted, func(i, j int) bool {
+		return result.Corrupted[i].Name < result.Corrupted[j].Name
+	})
+	return result, nil
+}
+
+// verifyBackupFile returns the reason if the file is corrupted
+func (b *StorageBackend) verifyBackupFile(ctx context.Context, file *kvbackup.File, skipChecksum bool) (string, error) {
+	attrs, err := b.Attributes(ctx, file.Name)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return "file not found", nil
+		}
+		return "", err
+	}
+	if file.Size_ > 0 && uint64(attrs.Size) != file.Size_ {
+		return fmt.Sprintf("size %d doesn't match %d in backup meta", attrs.Size, file.Size_), nil
+	}
+	if skipChecksum || len(file.Sha256) == 0 {
+		return "", nil
+	}
+
+	reader, err := b.NewReader(ctx, file.Name, nil)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+	if !bytes.Equal(hash.Sum(nil), file.Sha256) {
+		return "sha256 checksum doesn't match backup meta", nil
+	}
+	return "", nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	kvbackup "github.com/pingcap/kvproto/pkg/backup"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+func TestStorageBackendVerifyBackupFiles(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	mountPath := t.TempDir()
+	prefix := "backup-1"
+	g.Expect(os.MkdirAll(filepath.Join(mountPath, prefix), 0755)).Should(gomega.Succeed())
+	contents := map[string][]byte{
+		"1_2_3.sst": []byte("the content of the first sst"),
+		"4_5_6.sst": []byte("the content of the second sst"),
+		"7_8_9.sst": []byte("the content of the third sst"),
+	}
+	var files []*kvbackup.File
+	for name, content := range contents {
+		g.Expect(os.WriteFile(filepath.Join(mountPath, prefix, name), content, 0644)).Should(gomega.Succeed())
+		checksum := sha256.Sum256(content)
+		files = append(files, &kvbackup.File{
+			Name:   name,
+			Sha256: checksum[:],
+			Size_:  uint64(len(content)),
+		})
+	}
+
+	backend, err := NewStorageBackend(v1alpha1.StorageProvider{
+		Local: &v1alpha1.LocalStorageProvider{
+			Volume:      corev1.Volume{Name: "local"},
+			VolumeMount: corev1.VolumeMount{Name: "local", MountPath: mountPath},
+			Prefix:      prefix,
+		},
+	}, &StorageCredential{})
+	g.Expect(err).Should(gomega.BeNil())
+	defer backend.Close()
+
+	ctx := context.Background()
+	result, err := backend.VerifyBackupFiles(ctx, files, 2, false)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(result.Verified).Should(gomega.Equal(3))
+	g.Expect(result.Corrupted).Should(gomega.BeEmpty())
+
+	// the size and checksum are not checked if they are not recorded
+	result, err = backend.VerifyBackupFiles(ctx, []*kvbackup.File{{Name: "1_2_3.sst"}}, 2, false)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(result.Verified).Should(gomega.Equal(1))
+
+	// corrupt the files
+	g.Expect(os.Remove(filepath.Join(mountPath, prefix, "1_2_3.sst"))).Should(gomega.Succeed())
+	g.Expect(os.WriteFile(filepath.Join(mountPath, prefix, "4_5_6.sst"), []byte("truncated"), 0644)).Should(gomega.Succeed())
+	g.Expect(os.WriteFile(filepath.Join(mountPath, prefix, "7_8_9.sst"), []byte("the content of the THIRD sst"), 0644)).Should(gomega.Succeed())
+
+	result, err = backend.VerifyBackupFiles(ctx, files, 2, false)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(result.Verified).Should(gomega.Equal(0))
+	g.Expect(result.Corrupted).Should(gomega.Equal([]CorruptedFile{
+		{Name: "1_2_3.sst", Reason: "file not found"},
+		{Name: "4_5_6.sst", Reason: "size 9 doesn't match 29 in backup meta"},
+		{Name: "7_8_9.sst", Reason: "sha256 checksum doesn't match backup meta"},
+	}))
+
+	// only the existence and size are checked if the checksum is skipped
+	result, err = backend.VerifyBackupFiles(ctx, files, 2, true)
+	g.Expect(err).Should(gomega.BeNil())
+	g.Expect(result.Verified).Should(gomega.Equal(1))
+	g.Expect(result.Corrupted).Should(gomega.Equal([]CorruptedFile{
+		{Name: "1_2_3.sst", Reason: "file not found"},
+		{Name: "4_5_6.sst", Reason: "size 9 doesn't match 29 in backup meta"},
+	}))
+}
@@ -0,0 +1,139 @@
+// Copyright PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeRestoreDrills implements RestoreDrillInterface
+type FakeRestoreDrills struct {
+	Fake *FakePingcapV1alpha1
+	ns   string
+}
+
+var restoredrillsResource = schema.GroupVersionResource{Group: "pingcap.com", Version: "v1alpha1", Resource: "restoredrills"}
+
+var restoredrillsKind = schema.GroupVersionKind{Group: "pingcap.com", Version: "v1alpha1", Kind: "RestoreDrill"}
+
+// Get takes name of the restoreDrill, and returns the corresponding restoreDrill object, and an error if there is any.
+func (c *FakeRestoreDrills) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RestoreDrill, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(restoredrillsResource, c.ns, name), &v1alpha1.RestoreDrill{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RestoreDrill), err
+}
+
+// List takes label and field selectors, and returns the list of RestoreDrills that match those selectors.
+func (c *FakeRestoreDrills) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RestoreDrillList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(restoredrillsResource, restoredrillsKind, c.ns, opts), &v1alpha1.RestoreDrillList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.RestoreDrillList{ListMeta: obj.(*v1alpha1.RestoreDrillList).ListMeta}
+	for _, item := range obj.(*v1alpha1.RestoreDrillList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested restoreDrills.
+func (c *FakeRestoreDrills) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(restoredrillsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a restoreDrill and creates it.  Returns the server's representation of the restoreDrill, and an error, if there is any.
+func (c *FakeRestoreDrills) Create(ctx context.Context, restoreDrill *v1alpha1.RestoreDrill, opts v1.CreateOptions) (result *v1alpha1.RestoreDrill, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(restoredrillsResource, c.ns, restoreDrill), &v1alpha1.RestoreDrill{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RestoreDrill), err
+}
+
+// Update takes the representation of a restoreDrill and updates it. Returns the server's representation of the restoreDrill, and an error, if there is any.
+func (c *FakeRestoreDrills) Update(ctx context.Context, restoreDrill *v1alpha1.RestoreDrill, opts v1.UpdateOptions) (result *v1alpha1.RestoreDrill, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(restoredrillsResource, c.ns, restoreDrill), &v1alpha1.RestoreDrill{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RestoreDrill), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeRestoreDrills) UpdateStatus(ctx context.Context, restoreDrill *v1alpha1.RestoreDrill, opts v1.UpdateOptions) (*v1alpha1.RestoreDrill, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(restoredrillsResource, "status", c.ns, restoreDrill), &v1alpha1.RestoreDrill{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RestoreDrill), err
+}
+
+// Delete takes name of the restoreDrill and deletes it. Returns an error if one occurs.
+func (c *FakeRestoreDrills) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(restoredrillsResource, c.ns, name), &v1alpha1.RestoreDrill{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeRestoreDrills) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(restoredrillsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.RestoreDrillList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched restoreDrill.
+func (c *FakeRestoreDrills) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RestoreDrill, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(restoredrillsResource, c.ns, name, pt, data, subresources...), &v1alpha1.RestoreDrill{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RestoreDrill), err
+}
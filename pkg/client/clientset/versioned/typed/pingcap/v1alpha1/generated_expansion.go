@@ -25,6 +25,8 @@ type DataResourceExpansion interface{}
 
 type RestoreExpansion interface{}
 
+type RestoreDrillExpansion interface{}
+
 type TidbClusterExpansion interface{}
 
 type TidbClusterAutoScalerExpansion interface{}
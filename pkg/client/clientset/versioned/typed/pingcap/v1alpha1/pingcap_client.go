@@ -28,6 +28,7 @@ type PingcapV1alpha1Interface interface {
 	DMClustersGetter
 	DataResourcesGetter
 	RestoresGetter
+	RestoreDrillsGetter
 	TidbClustersGetter
 	TidbClusterAutoScalersGetter
 	TidbDashboardsGetter
@@ -61,6 +62,10 @@ func (c *PingcapV1alpha1Client) Restores(namespace string) RestoreInterface {
 	return newRestores(c, namespace)
 }
 
+func (c *PingcapV1alpha1Client) RestoreDrills(namespace string) RestoreDrillInterface {
+	return newRestoreDrills(c, namespace)
+}
+
 func (c *PingcapV1alpha1Client) TidbClusters(namespace string) TidbClusterInterface {
 	return newTidbClusters(c, namespace)
 }
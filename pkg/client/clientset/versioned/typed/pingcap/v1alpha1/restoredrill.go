@@ -0,0 +1,192 @@
+// Copyright PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	scheme "github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// RestoreDrillsGetter has a method to return a RestoreDrillInterface.
+// A group's client should implement this interface.
+type RestoreDrillsGetter interface {
+	RestoreDrills(namespace string) RestoreDrillInterface
+}
+
+// RestoreDrillInterface has methods to work with RestoreDrill resources.
+type RestoreDrillInterface interface {
+	Create(ctx context.Context, restoreDrill *v1alpha1.RestoreDrill, opts v1.CreateOptions) (*v1alpha1.RestoreDrill, error)
+	Update(ctx context.Context, restoreDrill *v1alpha1.RestoreDrill, opts v1.UpdateOptions) (*v1alpha1.RestoreDrill, error)
+	UpdateStatus(ctx context.Context, restoreDrill *v1alpha1.RestoreDrill, opts v1.UpdateOptions) (*v1alpha1.RestoreDrill, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.RestoreDrill, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.RestoreDrillList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RestoreDrill, err error)
+	RestoreDrillExpansion
+}
+
+// restoreDrills implements RestoreDrillInterface
+type restoreDrills struct {
+	client rest.Interface
+	ns     string
+}
+
+// newRestoreDrills returns a RestoreDrills
+func newRestoreDrills(c *PingcapV1alpha1Client, namespace string) *restoreDrills {
+	return &restoreDrills{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the restoreDrill, and returns the corresponding restoreDrill object, and an error if there is any.
+func (c *restoreDrills) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RestoreDrill, err error) {
+	result = &v1alpha1.RestoreDrill{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("restoredrills").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of RestoreDrills that match those selectors.
+func (c *restoreDrills) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RestoreDrillList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.RestoreDrillList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("restoredrills").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested restoreDrills.
+func (c *restoreDrills) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("restoredrills").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a restoreDrill and creates it.  Returns the server's representation of the restoreDrill, and an error, if there is any.
+func (c *restoreDrills) Create(ctx context.Context, restoreDrill *v1alpha1.RestoreDrill, opts v1.CreateOptions) (result *v1alpha1.RestoreDrill, err error) {
+	result = &v1alpha1.RestoreDrill{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("restoredrills").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(restoreDrill).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a restoreDrill and updates it. Returns the server's representation of the restoreDrill, and an error, if there is any.
+func (c *restoreDrills) Update(ctx context.Context, restoreDrill *v1alpha1.RestoreDrill, opts v1.UpdateOptions) (result *v1alpha1.RestoreDrill, err error) {
+	result = &v1alpha1.RestoreDrill{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("restoredrills").
+		Name(restoreDrill.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(restoreDrill).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *restoreDrills) UpdateStatus(ctx context.Context, restoreDrill *v1alpha1.RestoreDrill, opts v1.UpdateOptions) (result *v1alpha1.RestoreDrill, err error) {
+	result = &v1alpha1.RestoreDrill{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("restoredrills").
+		Name(restoreDrill.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(restoreDrill).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the restoreDrill and deletes it. Returns an error if one occurs.
+func (c *restoreDrills) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("restoredrills").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *restoreDrills) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("restoredrills").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched restoreDrill.
+func (c *restoreDrills) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RestoreDrill, err error) {
+	result = &v1alpha1.RestoreDrill{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("restoredrills").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -55,6 +55,14 @@ type RestoreListerExpansion interface{}
 // RestoreNamespaceLister.
 type RestoreNamespaceListerExpansion interface{}
 
+// RestoreDrillListerExpansion allows custom methods to be added to
+// RestoreDrillLister.
+type RestoreDrillListerExpansion interface{}
+
+// RestoreDrillNamespaceListerExpansion allows custom methods to be added to
+// RestoreDrillNamespaceLister.
+type RestoreDrillNamespaceListerExpansion interface{}
+
 // TidbClusterListerExpansion allows custom methods to be added to
 // TidbClusterLister.
 type TidbClusterListerExpansion interface{}
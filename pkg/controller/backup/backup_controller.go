@@ -67,6 +67,23 @@ func NewController(deps *controller.Dependencies) *Controller {
 	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		DeleteFunc: c.deleteJob,
 	})
+	// record the metrics for all the changes, including the ones skipped by the sync
+	backupInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			metrics.ObserveBackup(nil, obj.(*v1alpha1.Backup))
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			metrics.ObserveBackup(old.(*v1alpha1.Backup), cur.(*v1alpha1.Backup))
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if backup, ok := obj.(*v1alpha1.Backup); ok {
+				metrics.ForgetBackup(backup)
+			}
+		},
+	})
 
 	return c
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
 	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
@@ -72,6 +73,10 @@ type BackupUpdateStatus struct {
 	RetryReason *string
 	// OriginalReason is the original reason of backup job or pod failed
 	OriginalReason *string
+
+	// SecondaryStorageStatus is the status of copying the backup to a secondary storage,
+	// it replaces the status with the same name.
+	SecondaryStorageStatus *v1alpha1.SecondaryStorageStatus
 }
 
 // BackupConditionUpdaterInterface enables updating Backup conditions.
@@ -182,9 +187,29 @@ func updateBackupStatus(status *v1alpha1.BackupStatus, newStatus *BackupUpdateSt
 		isUpdate = updateBackoffRetryStatus(status, newStatus)
 	}
 
+	if newStatus.SecondaryStorageStatus != nil {
+		isUpdate = updateSecondaryStorageStatus(status, newStatus.SecondaryStorageStatus) || isUpdate
+	}
+
 	return isUpdate
 }
 
+// updateSecondaryStorageStatus replaces the status of the secondary storage with the same name
+func updateSecondaryStorageStatus(status *v1alpha1.BackupStatus, storageStatus *v1alpha1.SecondaryStorageStatus) bool {
+	for i := range status.SecondaryStorageStatuses {
+		if status.SecondaryStorageStatuses[i].Name != storageStatus.Name {
+			continue
+		}
+		if apiequality.Semantic.DeepEqual(status.SecondaryStorageStatuses[i], *storageStatus) {
+			return false
+		}
+		status.SecondaryStorageStatuses[i] = *storageStatus
+		return true
+	}
+	status.SecondaryStorageStatuses = append(status.SecondaryStorageStatuses, *storageStatus)
+	return true
+}
+
 // updateSnapshotBackupStatus update snapshot mode backup status.
 func updateSnapshotBackupStatus(backup *v1alpha1.Backup, condition *v1alpha1.BackupCondition, newStatus *BackupUpdateStatus) bool {
 	var isStatusUpdate, isConditionUpdate bool
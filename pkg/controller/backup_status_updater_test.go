@@ -19,6 +19,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -57,6 +58,57 @@ func TestUpdateBackupStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateSnapshotBackupVerifyCondition(t *testing.T) {
+	g := NewGomegaWithT(t)
+	backup := &v1alpha1.Backup{}
+	backup.Spec.Verify = true
+
+	g.Expect(updateSnapshotBackupStatus(backup, &v1alpha1.BackupCondition{
+		Type:   v1alpha1.BackupComplete,
+		Status: corev1.ConditionTrue,
+	}, nil)).Should(BeTrue())
+	g.Expect(backup.Status.Phase).Should(Equal(v1alpha1.BackupComplete))
+
+	// the verification conditions don't change the phase
+	g.Expect(updateSnapshotBackupStatus(backup, &v1alpha1.BackupCondition{
+		Type:    v1alpha1.BackupCorrupted,
+		Status:  corev1.ConditionTrue,
+		Reason:  "BackupFilesCorrupted",
+		Message: "1 of 3 backup files are missing or corrupted: 1_2_3.sst: file not found",
+	}, nil)).Should(BeTrue())
+	g.Expect(backup.Status.Phase).Should(Equal(v1alpha1.BackupComplete))
+	g.Expect(v1alpha1.IsBackupComplete(backup)).Should(BeTrue())
+	g.Expect(v1alpha1.IsBackupCorrupted(backup)).Should(BeTrue())
+	g.Expect(v1alpha1.IsBackupVerified(backup)).Should(BeFalse())
+}
+
+func TestUpdateSecondaryStorageStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+	status := &v1alpha1.BackupStatus{}
+
+	g.Expect(updateBackupStatus(status, &BackupUpdateStatus{
+		SecondaryStorageStatus: &v1alpha1.SecondaryStorageStatus{Name: "dr-1", Phase: v1alpha1.SecondaryStorageCopying},
+	})).Should(BeTrue())
+	g.Expect(updateBackupStatus(status, &BackupUpdateStatus{
+		SecondaryStorageStatus: &v1alpha1.SecondaryStorageStatus{Name: "dr-2", Phase: v1alpha1.SecondaryStorageCopying},
+	})).Should(BeTrue())
+	g.Expect(status.SecondaryStorageStatuses).Should(HaveLen(2))
+
+	// the status of the same storage is replaced
+	g.Expect(updateBackupStatus(status, &BackupUpdateStatus{
+		SecondaryStorageStatus: &v1alpha1.SecondaryStorageStatus{Name: "dr-1", Phase: v1alpha1.SecondaryStorageFailed, RetryNum: 1, Message: "timeout"},
+	})).Should(BeTrue())
+	g.Expect(status.SecondaryStorageStatuses).Should(HaveLen(2))
+	g.Expect(status.SecondaryStorageStatuses[0].Phase).Should(Equal(v1alpha1.SecondaryStorageFailed))
+	g.Expect(status.SecondaryStorageStatuses[0].RetryNum).Should(Equal(1))
+	g.Expect(status.SecondaryStorageStatuses[1].Phase).Should(Equal(v1alpha1.SecondaryStorageCopying))
+
+	// no update if the status is not changed
+	g.Expect(updateBackupStatus(status, &BackupUpdateStatus{
+		SecondaryStorageStatus: &v1alpha1.SecondaryStorageStatus{Name: "dr-1", Phase: v1alpha1.SecondaryStorageFailed, RetryNum: 1, Message: "timeout"},
+	})).Should(BeFalse())
+}
+
 func newUpdateBackupStatus() *BackupUpdateStatus {
 	ts := "421762809912885269"
 	start, _ := time.Parse(time.RFC3339, "2020-12-25T21:46:59Z")
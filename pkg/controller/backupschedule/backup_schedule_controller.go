@@ -59,6 +59,16 @@ func NewController(deps *controller.Dependencies) *Controller {
 		},
 		DeleteFunc: c.enqueueBackupSchedule,
 	})
+	backupScheduleInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if bs, ok := obj.(*v1alpha1.BackupSchedule); ok {
+				metrics.ForgetBackupSchedule(bs.Namespace, bs.Name)
+			}
+		},
+	})
 
 	return c
 }
@@ -54,6 +54,9 @@ var (
 	// backupScheduleControllerKind contains the schema.GroupVersionKind for backupschedule controller type.
 	backupScheduleControllerKind = v1alpha1.SchemeGroupVersion.WithKind("BackupSchedule")
 
+	// restoreDrillControllerKind contains the schema.GroupVersionKind for restoredrill controller type.
+	restoreDrillControllerKind = v1alpha1.SchemeGroupVersion.WithKind("RestoreDrill")
+
 	// tidbMonitorControllerKind contains the schema.GroupVersionKind for TidbMonitor controller type.
 	tidbMonitorControllerKind = v1alpha1.SchemeGroupVersion.WithKind("TidbMonitor")
 
@@ -186,6 +189,25 @@ func GetBackupScheduleOwnerRef(bs *v1alpha1.BackupSchedule) metav1.OwnerReferenc
 	}
 }
 
+// SetRestoreDrillKind sets RestoreDrill's GroupVersionKind, the objects got from the lister don't have it
+func SetRestoreDrillKind(rd *v1alpha1.RestoreDrill) {
+	rd.SetGroupVersionKind(restoreDrillControllerKind)
+}
+
+// GetRestoreDrillOwnerRef returns RestoreDrill's OwnerReference
+func GetRestoreDrillOwnerRef(rd *v1alpha1.RestoreDrill) metav1.OwnerReference {
+	controller := true
+	blockOwnerDeletion := true
+	return metav1.OwnerReference{
+		APIVersion:         restoreDrillControllerKind.GroupVersion().String(),
+		Kind:               restoreDrillControllerKind.Kind,
+		Name:               rd.GetName(),
+		UID:                rd.GetUID(),
+		Controller:         &controller,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}
+}
+
 func GetTiDBMonitorOwnerRef(monitor *v1alpha1.TidbMonitor) metav1.OwnerReference {
 	controller := true
 	blockOwnerDeletion := true
@@ -367,6 +389,11 @@ func DiscoveryMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-discovery", clusterName)
 }
 
+// DiscoveryStateMemberName returns the name of the ConfigMap which persists the discovery state
+func DiscoveryStateMemberName(clusterName string) string {
+	return fmt.Sprintf("%s-discovery-state", clusterName)
+}
+
 // DMMasterMemberName returns dm-master member name
 func DMMasterMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-dm-master", clusterName)
@@ -231,6 +231,7 @@ type Dependencies struct {
 	BackupLister                listers.BackupLister
 	RestoreLister               listers.RestoreLister
 	BackupScheduleLister        listers.BackupScheduleLister
+	RestoreDrillLister          listers.RestoreDrillLister
 	TiDBInitializerLister       listers.TidbInitializerLister
 	TiDBMonitorLister           listers.TidbMonitorLister
 	TiDBNGMonitoringLister      listers.TidbNGMonitoringLister
@@ -376,6 +377,7 @@ func newDependencies(
 		BackupLister:                informerFactory.Pingcap().V1alpha1().Backups().Lister(),
 		RestoreLister:               informerFactory.Pingcap().V1alpha1().Restores().Lister(),
 		BackupScheduleLister:        informerFactory.Pingcap().V1alpha1().BackupSchedules().Lister(),
+		RestoreDrillLister:          informerFactory.Pingcap().V1alpha1().RestoreDrills().Lister(),
 		TiDBInitializerLister:       informerFactory.Pingcap().V1alpha1().TidbInitializers().Lister(),
 		TiDBMonitorLister:           informerFactory.Pingcap().V1alpha1().TidbMonitors().Lister(),
 		TiDBNGMonitoringLister:      informerFactory.Pingcap().V1alpha1().TidbNGMonitorings().Lister(),
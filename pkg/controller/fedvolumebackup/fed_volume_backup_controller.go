@@ -60,6 +60,11 @@ func NewController(deps *controller.BrFedDependencies) *Controller {
 		},
 		DeleteFunc: c.updateBackup,
 	})
+	volumeBackupInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			metrics.ObserveVolumeBackup(old.(*v1alpha1.VolumeBackup), cur.(*v1alpha1.VolumeBackup))
+		},
+	})
 
 	return c
 }
@@ -148,14 +148,14 @@ func (c *FakeJobControl) CreateJob(_ runtime.Object, job *batchv1.Job) error {
 	return c.JobIndexer.Add(job)
 }
 
-// DeleteJob deletes the job
-func (c *FakeJobControl) DeleteJob(_ runtime.Object, _ *batchv1.Job) error {
+// DeleteJob deletes the job from JobIndexer
+func (c *FakeJobControl) DeleteJob(_ runtime.Object, job *batchv1.Job) error {
 	defer c.deleteJobTracker.Inc()
 	if c.deleteJobTracker.ErrorReady() {
 		defer c.deleteJobTracker.Reset()
 		return c.deleteJobTracker.GetError()
 	}
-	return nil
+	return c.JobIndexer.Delete(job)
 }
 
 var _ JobControlInterface = &FakeJobControl{}
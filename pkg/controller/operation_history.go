@@ -0,0 +1,84 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the events emitted for the significant operations performed on the clusters
+const (
+	// EvictLeaderBeginReason is used when the operator begins to evict the leaders from a store before upgrade
+	EvictLeaderBeginReason = "EvictLeaderBegin"
+	// EvictLeaderEndReason is used when the operator stops evicting the leaders from a store after upgrade
+	EvictLeaderEndReason = "EvictLeaderEnd"
+	// PDLeaderTransferredReason is used when the operator transfers the PD leader to another member
+	PDLeaderTransferredReason = "PDLeaderTransferred"
+	// StoreDeletedReason is used when the operator deletes a store from PD to scale in
+	StoreDeletedReason = "StoreDeleted"
+	// MemberDeletedReason is used when the operator deletes a PD member to scale in
+	MemberDeletedReason = "MemberDeleted"
+	// FailoverPodCreatedReason is used when the operator marks a member as failure and creates a new pod to replace it
+	FailoverPodCreatedReason = "FailoverPodCreated"
+	// SuspendBeginReason is used when the operator begins to suspend a component
+	SuspendBeginReason = "SuspendBegin"
+	// SuspendEndReason is used when the operator ends the suspension of a component
+	SuspendEndReason = "SuspendEnd"
+	// ZoneUpgradeBeginReason is used when the operator deletes the TiKV pods of a zone to upgrade them together
+	ZoneUpgradeBeginReason = "ZoneUpgradeBegin"
+	// UpgradePausedReason is used when the upgrade of a component stops at a pause point of the upgrade policy
+	UpgradePausedReason = "UpgradePaused"
+	// UpgradeApprovedReason is used when a pause point of the upgrade policy is approved by the annotation
+	UpgradeApprovedReason = "UpgradeApproved"
+	// UpgradeHookFailedReason is used when the pre-upgrade or post-upgrade hook Job of a component fails
+	UpgradeHookFailedReason = "UpgradeHookFailed"
+)
+
+// RecordOperation emits an event for the operation performed on the cluster and appends it to
+// `status.operationHistory` if the cluster is a TidbCluster.
+// The status is persisted at the end of the sync of the cluster. The operation which is retried on requeue
+// is recorded only when it starts, i.e. it is not recorded if it is the last operation of the component.
+func RecordOperation(recorder record.EventRecorder, obj runtime.Object, component v1alpha1.MemberType, eventType, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	tc, ok := obj.(*v1alpha1.TidbCluster)
+	if ok && isLastOperation(tc, component, reason, message) {
+		return
+	}
+	recorder.Event(obj, eventType, reason, message)
+	if ok {
+		tc.AppendOperationRecord(v1alpha1.OperationRecord{
+			Time:      metav1.Now(),
+			Component: component,
+			Type:      eventType,
+			Reason:    reason,
+			Message:   message,
+		})
+	}
+}
+
+// isLastOperation returns whether the operation is the last one recorded for the component
+func isLastOperation(tc *v1alpha1.TidbCluster, component v1alpha1.MemberType, reason, message string) bool {
+	for i := len(tc.Status.OperationHistory) - 1; i >= 0; i-- {
+		record := tc.Status.OperationHistory[i]
+		if record.Component == component {
+			return record.Reason == reason && record.Message == message
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRecordOperation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	recorder := record.NewFakeRecorder(10)
+	tc := newTidbCluster()
+	RecordOperation(recorder, tc, v1alpha1.TiKVMemberType, corev1.EventTypeNormal, StoreDeletedReason, "delete store %d of pod %s to scale in", 1, "demo-tikv-2")
+
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(Equal("Normal StoreDeleted delete store 1 of pod demo-tikv-2 to scale in"))
+	g.Expect(tc.Status.OperationHistory).To(HaveLen(1))
+	g.Expect(tc.Status.OperationHistory[0].Component).To(Equal(v1alpha1.TiKVMemberType))
+	g.Expect(tc.Status.OperationHistory[0].Type).To(Equal(corev1.EventTypeNormal))
+	g.Expect(tc.Status.OperationHistory[0].Reason).To(Equal(StoreDeletedReason))
+	g.Expect(tc.Status.OperationHistory[0].Message).To(Equal("delete store 1 of pod demo-tikv-2 to scale in"))
+	g.Expect(tc.Status.OperationHistory[0].Time.IsZero()).To(BeFalse())
+
+	// the operation retried on requeue is recorded once
+	RecordOperation(recorder, tc, v1alpha1.TiKVMemberType, corev1.EventTypeNormal, StoreDeletedReason, "delete store %d of pod %s to scale in", 1, "demo-tikv-2")
+	g.Expect(recorder.Events).To(BeEmpty())
+	g.Expect(tc.Status.OperationHistory).To(HaveLen(1))
+	RecordOperation(recorder, tc, v1alpha1.PDMemberType, corev1.EventTypeNormal, PDLeaderTransferredReason, "transfer pd leader from %s to %s before upgrade", "demo-pd-2", "demo-pd-0")
+	RecordOperation(recorder, tc, v1alpha1.PDMemberType, corev1.EventTypeNormal, PDLeaderTransferredReason, "transfer pd leader from %s to %s before upgrade", "demo-pd-2", "demo-pd-0")
+	RecordOperation(recorder, tc, v1alpha1.TiKVMemberType, corev1.EventTypeNormal, StoreDeletedReason, "delete store %d of pod %s to scale in", 2, "demo-tikv-1")
+	g.Expect(recorder.Events).To(HaveLen(2))
+	g.Expect(tc.Status.OperationHistory).To(HaveLen(3))
+	g.Expect(tc.Status.OperationHistory[1].Component).To(Equal(v1alpha1.PDMemberType))
+	g.Expect(tc.Status.OperationHistory[2].Message).To(Equal("delete store 2 of pod demo-tikv-1 to scale in"))
+	for len(recorder.Events) > 0 {
+		<-recorder.Events
+	}
+
+	// Only the event is emitted for the other clusters
+	dc := &v1alpha1.DMCluster{}
+	RecordOperation(recorder, dc, v1alpha1.DMMasterMemberType, corev1.EventTypeNormal, SuspendBeginReason, "begin to suspend component %s", v1alpha1.DMMasterMemberType)
+	g.Expect(<-recorder.Events).To(Equal("Normal SuspendBegin begin to suspend component dm-master"))
+}
@@ -61,6 +61,11 @@ func NewController(deps *controller.Dependencies) *Controller {
 		},
 		DeleteFunc: c.enqueueRestore,
 	})
+	restoreInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			metrics.ObserveRestore(old.(*v1alpha1.Restore), cur.(*v1alpha1.Restore))
+		},
+	})
 	return c
 }
 
@@ -153,6 +158,13 @@ func (c *Controller) updateRestore(cur interface{}) {
 	ns := newRestore.GetNamespace()
 	name := newRestore.GetName()
 
+	if newRestore.Spec.ClusterTemplate != nil && newRestore.Status.ClusterPhase != "" && !v1alpha1.IsRestoreClusterFinished(newRestore) &&
+		(v1alpha1.IsRestoreComplete(newRestore) || v1alpha1.IsRestoreFailed(newRestore) || v1alpha1.IsRestoreInvalid(newRestore)) {
+		// the cluster created from the template is updated to the template or cleaned up after the restore
+		c.enqueueRestore(newRestore)
+		return
+	}
+
 	if v1alpha1.IsRestoreInvalid(newRestore) {
 		klog.V(4).Infof("restore %s/%s is Invalid, skipping.", ns, name)
 		return
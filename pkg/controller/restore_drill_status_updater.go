@@ -0,0 +1,113 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// RestoreDrillStatusUpdaterInterface is an interface used to update the RestoreDrillStatus associated with a RestoreDrill.
+// For any use other than testing, clients should create an instance using NewRealRestoreDrillStatusUpdater.
+type RestoreDrillStatusUpdaterInterface interface {
+	// UpdateRestoreDrillStatus sets the restoreDrill's Status to status. Implementations are required to retry on conflicts,
+	// but fail on other errors. If the returned error is nil restoreDrill's Status has been successfully set to status.
+	UpdateRestoreDrillStatus(*v1alpha1.RestoreDrill, *v1alpha1.RestoreDrillStatus, *v1alpha1.RestoreDrillStatus) error
+}
+
+// returns a RestoreDrillStatusUpdaterInterface that updates the Status of a RestoreDrill,
+// using the supplied client and rdLister.
+func NewRealRestoreDrillStatusUpdater(deps *Dependencies) RestoreDrillStatusUpdaterInterface {
+	return &realRestoreDrillStatusUpdater{
+		deps: deps,
+	}
+}
+
+type realRestoreDrillStatusUpdater struct {
+	deps *Dependencies
+}
+
+func (u *realRestoreDrillStatusUpdater) UpdateRestoreDrillStatus(
+	rd *v1alpha1.RestoreDrill,
+	newStatus *v1alpha1.RestoreDrillStatus,
+	oldStatus *v1alpha1.RestoreDrillStatus) error {
+
+	ns := rd.GetNamespace()
+	rdName := rd.GetName()
+	// don't wait due to limited number of clients, but backoff after the default number of steps
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, updateErr := u.deps.Clientset.PingcapV1alpha1().RestoreDrills(ns).Update(context.TODO(), rd, metav1.UpdateOptions{})
+		if updateErr == nil {
+			klog.Infof("RestoreDrill: [%s/%s] updated successfully", ns, rdName)
+			return nil
+		}
+		if updated, err := u.deps.RestoreDrillLister.RestoreDrills(ns).Get(rdName); err == nil {
+			// make a copy so we don't mutate the shared cache
+			rd = updated.DeepCopy()
+			rd.Status = *newStatus
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated restoreDrill %s/%s from lister: %v", ns, rdName, err))
+		}
+
+		return updateErr
+	})
+	return err
+}
+
+var _ RestoreDrillStatusUpdaterInterface = &realRestoreDrillStatusUpdater{}
+
+// FakeRestoreDrillStatusUpdater is a fake RestoreDrillStatusUpdaterInterface
+type FakeRestoreDrillStatusUpdater struct {
+	RdLister        listers.RestoreDrillLister
+	RdIndexer       cache.Indexer
+	updateRdTracker RequestTracker
+}
+
+// NewFakeRestoreDrillStatusUpdater returns a FakeRestoreDrillStatusUpdater
+func NewFakeRestoreDrillStatusUpdater(rdInformer informers.RestoreDrillInformer) *FakeRestoreDrillStatusUpdater {
+	return &FakeRestoreDrillStatusUpdater{
+		rdInformer.Lister(),
+		rdInformer.Informer().GetIndexer(),
+		RequestTracker{},
+	}
+}
+
+// SetUpdateRestoreDrillError sets the error attributes of updateRestoreDrillTracker
+func (u *FakeRestoreDrillStatusUpdater) SetUpdateRestoreDrillError(err error, after int) {
+	u.updateRdTracker.err = err
+	u.updateRdTracker.after = after
+	u.updateRdTracker.SetError(err).SetAfter(after)
+}
+
+// UpdateRestoreDrill updates the RestoreDrill
+func (u *FakeRestoreDrillStatusUpdater) UpdateRestoreDrillStatus(rd *v1alpha1.RestoreDrill, _ *v1alpha1.RestoreDrillStatus, _ *v1alpha1.RestoreDrillStatus) error {
+	defer u.updateRdTracker.Inc()
+	if u.updateRdTracker.ErrorReady() {
+		defer u.updateRdTracker.Reset()
+		return u.updateRdTracker.GetError()
+	}
+
+	return u.RdIndexer.Update(rd)
+}
+
+var _ RestoreDrillStatusUpdaterInterface = &FakeRestoreDrillStatusUpdater{}
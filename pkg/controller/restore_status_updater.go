@@ -45,6 +45,8 @@ type RestoreUpdateStatus struct {
 	Progress *float64
 	// ProgressUpdateTime is the progress update time.
 	ProgressUpdateTime *metav1.Time
+	// ClusterPhase is the phase of the TidbCluster created from the template.
+	ClusterPhase *v1alpha1.RestoreClusterPhase
 }
 
 // RestoreConditionUpdaterInterface enables updating Restore conditions.
@@ -127,6 +129,10 @@ func updateRestoreStatus(status *v1alpha1.RestoreStatus, newStatus *RestoreUpdat
 			isUpdate = true
 		}
 	}
+	if newStatus.ClusterPhase != nil && status.ClusterPhase != *newStatus.ClusterPhase {
+		status.ClusterPhase = *newStatus.ClusterPhase
+		isUpdate = true
+	}
 
 	return isUpdate
 }
@@ -0,0 +1,157 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restoredrill
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/util/slice"
+)
+
+// ControlInterface implements the control logic for updating RestoreDrill
+// It is implemented as an interface to allow for extensions that provide different semantics.
+// Currently, there is only one implementation.
+type ControlInterface interface {
+	// UpdateRestoreDrill implements the control logic for restore drill
+	UpdateRestoreDrill(restoreDrill *v1alpha1.RestoreDrill) error
+}
+
+// NewDefaultRestoreDrillControl returns a new instance of the default implementation ControlInterface that
+// implements the documented semantics for RestoreDrill.
+func NewDefaultRestoreDrillControl(cli versioned.Interface, statusUpdater controller.RestoreDrillStatusUpdaterInterface, rdManager backup.RestoreDrillManager) ControlInterface {
+	return &defaultRestoreDrillControl{
+		cli:           cli,
+		statusUpdater: statusUpdater,
+		rdManager:     rdManager,
+	}
+}
+
+type defaultRestoreDrillControl struct {
+	cli           versioned.Interface
+	statusUpdater controller.RestoreDrillStatusUpdaterInterface
+	rdManager     backup.RestoreDrillManager
+}
+
+// UpdateRestoreDrill executes the core logic loop for a RestoreDrill.
+func (c *defaultRestoreDrillControl) UpdateRestoreDrill(rd *v1alpha1.RestoreDrill) error {
+	if err := c.addProtectionFinalizer(rd); err != nil {
+		return err
+	}
+
+	if isDeletionCandidate(rd) {
+		// the running drill is cleaned up before the restore drill is deleted
+		if err := c.rdManager.Sync(rd); err != nil {
+			return err
+		}
+		return c.removeProtectionFinalizer(rd)
+	}
+
+	var errs []error
+	oldStatus := rd.Status.DeepCopy()
+
+	if err := c.rdManager.Sync(rd); err != nil {
+		errs = append(errs, err)
+	}
+	if apiequality.Semantic.DeepEqual(&rd.Status, oldStatus) {
+		return errorutils.NewAggregate(errs)
+	}
+	if err := c.statusUpdater.UpdateRestoreDrillStatus(rd.DeepCopy(), &rd.Status, oldStatus); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errorutils.NewAggregate(errs)
+}
+
+// addProtectionFinalizer will be called when the RestoreDrill CR is created
+func (c *defaultRestoreDrillControl) addProtectionFinalizer(rd *v1alpha1.RestoreDrill) error {
+	ns := rd.GetNamespace()
+	name := rd.GetName()
+
+	if needToAddFinalizer(rd) {
+		rd.Finalizers = append(rd.Finalizers, label.RestoreDrillProtectionFinalizer)
+		_, err := c.cli.PingcapV1alpha1().RestoreDrills(ns).Update(context.TODO(), rd, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("add restore drill %s/%s protection finalizers failed, err: %v", ns, name, err)
+		}
+	}
+	return nil
+}
+
+func (c *defaultRestoreDrillControl) removeProtectionFinalizer(rd *v1alpha1.RestoreDrill) error {
+	ns := rd.GetNamespace()
+	name := rd.GetName()
+
+	if rd.Status.CurrentRun == nil {
+		rd.Finalizers = slice.RemoveString(rd.Finalizers, label.RestoreDrillProtectionFinalizer, nil)
+		_, err := c.cli.PingcapV1alpha1().RestoreDrills(ns).Update(context.TODO(), rd, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("remove restore drill %s/%s protection finalizers failed, err: %v", ns, name, err)
+		}
+		klog.Infof("remove restore drill %s/%s protection finalizers success", ns, name)
+	}
+	return nil
+}
+
+func needToAddFinalizer(rd *v1alpha1.RestoreDrill) bool {
+	return rd.DeletionTimestamp == nil && !slice.ContainsString(rd.Finalizers, label.RestoreDrillProtectionFinalizer, nil)
+}
+
+func isDeletionCandidate(rd *v1alpha1.RestoreDrill) bool {
+	return rd.DeletionTimestamp != nil && slice.ContainsString(rd.Finalizers, label.RestoreDrillProtectionFinalizer, nil)
+}
+
+var _ ControlInterface = &defaultRestoreDrillControl{}
+
+// FakeRestoreDrillControl is a fake ControlInterface
+type FakeRestoreDrillControl struct {
+	rdIndexer       cache.Indexer
+	updateRdTracker controller.RequestTracker
+}
+
+// NewFakeRestoreDrillControl returns a FakeRestoreDrillControl
+func NewFakeRestoreDrillControl(rdInformer informers.RestoreDrillInformer) *FakeRestoreDrillControl {
+	return &FakeRestoreDrillControl{
+		rdIndexer: rdInformer.Informer().GetIndexer(),
+	}
+}
+
+// SetUpdateRestoreDrillError sets the error attributes of updateRdTracker
+func (c *FakeRestoreDrillControl) SetUpdateRestoreDrillError(err error, after int) {
+	c.updateRdTracker.SetError(err).SetAfter(after)
+}
+
+// UpdateRestoreDrill adds the restore drill to rdIndexer
+func (c *FakeRestoreDrillControl) UpdateRestoreDrill(rd *v1alpha1.RestoreDrill) error {
+	defer c.updateRdTracker.Inc()
+	if c.updateRdTracker.ErrorReady() {
+		defer c.updateRdTracker.Reset()
+		return c.updateRdTracker.GetError()
+	}
+
+	return c.rdIndexer.Add(rd)
+}
+
+var _ ControlInterface = &FakeRestoreDrillControl{}
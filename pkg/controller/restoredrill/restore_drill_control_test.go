@@ -0,0 +1,160 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restoredrill
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/restoredrill"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/util/slice"
+	"k8s.io/utils/pointer"
+)
+
+func TestRestoreDrillControlUpdateRestoreDrill(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name             string
+		update           func(rd *v1alpha1.RestoreDrill)
+		syncRdManagerErr bool
+		updateStatusErr  bool
+		errExpectFn      func(*GomegaWithT, error)
+		expectFinalizer  *bool
+	}
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		rd := &v1alpha1.RestoreDrill{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "test-drill"},
+		}
+		if test.update != nil {
+			test.update(rd)
+		}
+		control, cli, rdManager, rdStatusUpdater := newFakeRestoreDrillControl()
+		_, err := cli.PingcapV1alpha1().RestoreDrills(rd.Namespace).Create(context.TODO(), rd, metav1.CreateOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		if test.syncRdManagerErr {
+			rdManager.SetSyncError(fmt.Errorf("restore drill sync error"))
+		}
+		if test.updateStatusErr {
+			rdStatusUpdater.SetUpdateRestoreDrillError(fmt.Errorf("update restoreDrill status error"), 0)
+		}
+
+		err = control.UpdateRestoreDrill(rd)
+		if test.errExpectFn != nil {
+			test.errExpectFn(g, err)
+		}
+		if test.expectFinalizer != nil {
+			got, err := cli.PingcapV1alpha1().RestoreDrills(rd.Namespace).Get(context.TODO(), rd.Name, metav1.GetOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(slice.ContainsString(got.Finalizers, label.RestoreDrillProtectionFinalizer, nil)).To(Equal(*test.expectFinalizer))
+		}
+	}
+	tests := []testcase{
+		{
+			name:             "restore drill sync error",
+			syncRdManagerErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "restore drill sync error")).To(Equal(true))
+			},
+		},
+		{
+			name: "restore drill status is not updated",
+			// the status is not updated, so the error of the status updater is not returned
+			updateStatusErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name: "restore drill status update failed",
+			update: func(rd *v1alpha1.RestoreDrill) {
+				rd.Status.LastScheduleTime = &metav1.Time{Time: time.Now()}
+			},
+			updateStatusErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "update restoreDrill status error")).To(Equal(true))
+			},
+		},
+		{
+			name: "normal",
+			update: func(rd *v1alpha1.RestoreDrill) {
+				rd.Status.LastScheduleTime = &metav1.Time{Time: time.Now()}
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name: "add protection finalizer",
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFinalizer: pointer.BoolPtr(true),
+		},
+		{
+			name: "keep protection finalizer if the deleted drill is not cleaned up",
+			update: func(rd *v1alpha1.RestoreDrill) {
+				rd.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				rd.Finalizers = []string{label.RestoreDrillProtectionFinalizer}
+				rd.Status.CurrentRun = &v1alpha1.RestoreDrillRun{Phase: v1alpha1.RestoreDrillCleaningUp}
+			},
+			syncRdManagerErr: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+			},
+			expectFinalizer: pointer.BoolPtr(true),
+		},
+		{
+			name: "remove protection finalizer after the deleted drill is cleaned up",
+			update: func(rd *v1alpha1.RestoreDrill) {
+				rd.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				rd.Finalizers = []string{label.RestoreDrillProtectionFinalizer}
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFinalizer: pointer.BoolPtr(false),
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func newFakeRestoreDrillControl() (ControlInterface, versioned.Interface, *restoredrill.FakeRestoreDrillManager, *controller.FakeRestoreDrillStatusUpdater) {
+	cli := fake.NewSimpleClientset()
+	rdInformer := informers.NewSharedInformerFactory(cli, 0).Pingcap().V1alpha1().RestoreDrills()
+	statusUpdater := controller.NewFakeRestoreDrillStatusUpdater(rdInformer)
+	rdManager := restoredrill.NewFakeRestoreDrillManager()
+	control := NewDefaultRestoreDrillControl(cli, statusUpdater, rdManager)
+
+	return control, cli, rdManager, statusUpdater
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restoredrill
+
+import (
+	"fmt"
+	"time"
+
+	perrors "github.com/pingcap/errors"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/restoredrill"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// Controller controls restoreDrills.
+type Controller struct {
+	deps *controller.Dependencies
+	// control returns an interface capable of syncing a restore drill.
+	// Abstracted out for testing.
+	control ControlInterface
+	// restoreDrills that need to be synced.
+	queue workqueue.RateLimitingInterface
+}
+
+// NewController creates a restoreDrill controller.
+func NewController(deps *controller.Dependencies) *Controller {
+	c := &Controller{
+		deps:    deps,
+		control: NewDefaultRestoreDrillControl(deps.Clientset, controller.NewRealRestoreDrillStatusUpdater(deps), restoredrill.NewRestoreDrillManager(deps)),
+		queue: workqueue.NewNamedRateLimitingQueue(
+			controller.NewControllerRateLimiter(1*time.Second, 100*time.Second),
+			"restoreDrill",
+		),
+	}
+
+	restoreDrillInformer := deps.InformerFactory.Pingcap().V1alpha1().RestoreDrills()
+	restoreDrillInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueRestoreDrill,
+		UpdateFunc: func(old, cur interface{}) {
+			c.enqueueRestoreDrill(cur)
+		},
+		DeleteFunc: c.enqueueRestoreDrill,
+	})
+	// the running drill moves forward when its restore is updated
+	deps.InformerFactory.Pingcap().V1alpha1().Restores().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			c.enqueueOwnerRestoreDrill(cur)
+		},
+	})
+
+	return c
+}
+
+// Name returns restore drill controller name.
+func (c *Controller) Name() string {
+	return "restore-drill"
+}
+
+// Run runs the restore drill controller.
+func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Info("Starting restore drill controller")
+	defer klog.Info("Shutting down restore drill controller")
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// worker runs a worker goroutine that invokes processNextWorkItem until the the controller's queue is closed
+func (c *Controller) worker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem dequeues items, processes them, and marks them done. It enforces that the syncHandler is never
+// invoked concurrently with the same key.
+func (c *Controller) processNextWorkItem() bool {
+	metrics.ActiveWorkers.WithLabelValues(c.Name()).Add(1)
+	defer metrics.ActiveWorkers.WithLabelValues(c.Name()).Add(-1)
+
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+	if err := c.sync(key.(string)); err != nil {
+		if perrors.Find(err, controller.IsRequeueError) != nil {
+			klog.Infof("RestoreDrill: %v, still need sync: %v, requeuing", key.(string), err)
+			c.queue.AddRateLimited(key)
+		} else if perrors.Find(err, controller.IsIgnoreError) != nil {
+			klog.V(4).Infof("RestoreDrill: %v, ignore err: %v, waiting for the next sync", key.(string), err)
+		} else {
+			utilruntime.HandleError(fmt.Errorf("RestoreDrill: %v, sync failed, err: %v, requeuing", key.(string), err))
+			c.queue.AddRateLimited(key)
+		}
+	} else {
+		c.queue.Forget(key)
+	}
+	return true
+}
+
+// sync syncs the given restoreDrill.
+func (c *Controller) sync(key string) error {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime)
+		metrics.ReconcileTime.WithLabelValues(c.Name()).Observe(duration.Seconds())
+		klog.V(4).Infof("Finished syncing RestoreDrill %q (%v)", key, duration)
+	}()
+
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	rd, err := c.deps.RestoreDrillLister.RestoreDrills(ns).Get(name)
+	if errors.IsNotFound(err) {
+		klog.Infof("RestoreDrill has been deleted %v", key)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.control.UpdateRestoreDrill(rd.DeepCopy())
+}
+
+// enqueueRestoreDrill enqueues the given restore drill in the work queue.
+func (c *Controller) enqueueRestoreDrill(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("cound't get key for object %+v: %v", obj, err))
+		return
+	}
+	c.queue.Add(key)
+}
+
+// enqueueOwnerRestoreDrill enqueues the restore drill which created the given restore
+func (c *Controller) enqueueOwnerRestoreDrill(obj interface{}) {
+	restore, ok := obj.(*v1alpha1.Restore)
+	if !ok {
+		return
+	}
+	name, ok := restore.Labels[label.RestoreDrillLabelKey]
+	if !ok {
+		return
+	}
+	c.queue.Add(fmt.Sprintf("%s/%s", restore.Namespace, name))
+}
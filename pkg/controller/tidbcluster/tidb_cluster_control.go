@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/manager/volumes"
 	"github.com/pingcap/tidb-operator/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // ControlInterface implements the control logic for updating TidbClusters and their children StatefulSets.
@@ -118,6 +119,7 @@ func (c *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster)
 	if err := c.conditionUpdater.Update(tc); err != nil {
 		errs = append(errs, err)
 	}
+	c.recordStatusMetrics(tc)
 
 	if apiequality.Semantic.DeepEqual(&tc.Status, oldStatus) {
 		return errorutils.NewAggregate(errs)
@@ -322,6 +324,63 @@ func (c *defaultTidbClusterControl) recordMetrics(tc *v1alpha1.TidbCluster) {
 	}
 }
 
+// allPhases are the phases exported in the phase metrics of the components
+var allPhases = []v1alpha1.MemberPhase{v1alpha1.NormalPhase, v1alpha1.UpgradePhase, v1alpha1.ScalePhase, v1alpha1.SuspendPhase}
+
+func (c *defaultTidbClusterControl) recordStatusMetrics(tc *v1alpha1.TidbCluster) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	var samples []metrics.ClusterStatusSample
+	add := func(gauge *prometheus.GaugeVec, value float64, labels ...string) {
+		samples = append(samples, metrics.ClusterStatusSample{Gauge: gauge, Labels: labels, Value: value})
+	}
+
+	for _, status := range tc.AllComponentStatus() {
+		component := status.MemberType().String()
+		phase := status.GetPhase()
+		if phase != "" {
+			for _, p := range allPhases {
+				value := 0.0
+				if p == phase {
+					value = 1
+				}
+				add(metrics.ClusterComponentPhase, value, component, string(p))
+			}
+			duration := metrics.ClusterStatus.PhaseDuration(ns, tcName, component, string(phase))
+			add(metrics.ClusterComponentPhaseDuration, duration.Seconds(), component, string(phase))
+		}
+		for volName, vol := range status.GetVolumes() {
+			add(metrics.ClusterVolumeBoundCount, float64(vol.BoundCount), component, string(volName))
+			add(metrics.ClusterVolumeModifiedCount, float64(vol.ModifiedCount), component, string(volName))
+		}
+	}
+
+	if tc.Spec.PD != nil {
+		add(metrics.ClusterFailureMembers, float64(len(tc.Status.PD.FailureMembers)), v1alpha1.PDMemberType.String())
+	}
+	if tc.Spec.TiDB != nil {
+		add(metrics.ClusterFailureMembers, float64(len(tc.Status.TiDB.FailureMembers)), v1alpha1.TiDBMemberType.String())
+	}
+	if tc.Spec.TiKV != nil {
+		component := v1alpha1.TiKVMemberType.String()
+		add(metrics.ClusterFailureMembers, float64(len(tc.Status.TiKV.FailureStores)), component)
+		add(metrics.ClusterTombstoneStores, float64(len(tc.Status.TiKV.TombstoneStores)), component)
+		for _, store := range tc.Status.TiKV.Stores {
+			add(metrics.ClusterStoreLeaderCount, float64(store.LeaderCount), component, store.ID, store.PodName)
+		}
+	}
+	if tc.Spec.TiFlash != nil {
+		component := v1alpha1.TiFlashMemberType.String()
+		add(metrics.ClusterFailureMembers, float64(len(tc.Status.TiFlash.FailureStores)), component)
+		add(metrics.ClusterTombstoneStores, float64(len(tc.Status.TiFlash.TombstoneStores)), component)
+		for _, store := range tc.Status.TiFlash.Stores {
+			add(metrics.ClusterStoreLeaderCount, float64(store.LeaderCount), component, store.ID, store.PodName)
+		}
+	}
+
+	metrics.ClusterStatus.Record(ns, tcName, samples)
+}
+
 var _ ControlInterface = &defaultTidbClusterControl{}
 
 type FakeTidbClusterControlInterface struct {
@@ -25,6 +25,8 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	mm "github.com/pingcap/tidb-operator/pkg/manager/member"
 	"github.com/pingcap/tidb-operator/pkg/manager/meta"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -294,6 +296,46 @@ func TestTidbClusterStatusEquality(t *testing.T) {
 	g.Expect(apiequality.Semantic.DeepEqual(&tcStatus, tcStatusCopy)).To(Equal(false))
 }
 
+func TestTidbClusterControlRecordStatusMetrics(t *testing.T) {
+	g := NewGomegaWithT(t)
+	control, _, _, _, _, _, _, _, _ := newFakeTidbClusterControl()
+	tc := newTidbClusterForTidbClusterControl()
+	tc.Name = "test-metrics"
+	tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1", PodName: "test-metrics-tikv-0", LeaderCount: 10},
+		"2": {ID: "2", PodName: "test-metrics-tikv-1", LeaderCount: 20},
+	}
+	tc.Status.TiKV.TombstoneStores = map[string]v1alpha1.TiKVStore{
+		"3": {ID: "3", PodName: "test-metrics-tikv-2"},
+	}
+	tc.Status.TiKV.FailureStores = map[string]v1alpha1.TiKVFailureStore{
+		"2": {PodName: "test-metrics-tikv-1", StoreID: "2"},
+	}
+	tc.Status.TiKV.Volumes = map[v1alpha1.StorageVolumeName]*v1alpha1.StorageVolumeStatus{
+		"tikv": {
+			Name:                        "tikv",
+			ObservedStorageVolumeStatus: v1alpha1.ObservedStorageVolumeStatus{BoundCount: 3, ModifiedCount: 1},
+		},
+	}
+
+	control.(*defaultTidbClusterControl).recordStatusMetrics(tc)
+	g.Expect(testutil.ToFloat64(metrics.ClusterComponentPhase.WithLabelValues("default", "test-metrics", "tikv", "Upgrade"))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(metrics.ClusterComponentPhase.WithLabelValues("default", "test-metrics", "tikv", "Normal"))).To(Equal(0.0))
+	g.Expect(testutil.ToFloat64(metrics.ClusterStoreLeaderCount.WithLabelValues("default", "test-metrics", "tikv", "2", "test-metrics-tikv-1"))).To(Equal(20.0))
+	g.Expect(testutil.ToFloat64(metrics.ClusterTombstoneStores.WithLabelValues("default", "test-metrics", "tikv"))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(metrics.ClusterFailureMembers.WithLabelValues("default", "test-metrics", "tikv"))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(metrics.ClusterVolumeModifiedCount.WithLabelValues("default", "test-metrics", "tikv", "tikv"))).To(Equal(1.0))
+
+	// The series of the removed store are deleted
+	delete(tc.Status.TiKV.Stores, "2")
+	control.(*defaultTidbClusterControl).recordStatusMetrics(tc)
+	g.Expect(metrics.ClusterStoreLeaderCount.DeleteLabelValues("default", "test-metrics", "tikv", "2", "test-metrics-tikv-1")).To(BeFalse())
+
+	metrics.ClusterStatus.Delete(tc.Namespace, tc.Name)
+	g.Expect(metrics.ClusterStoreLeaderCount.DeleteLabelValues("default", "test-metrics", "tikv", "1", "test-metrics-tikv-0")).To(BeFalse())
+}
+
 func newFakeTidbClusterControl() (
 	ControlInterface,
 	*meta.FakeReclaimPolicyManager,
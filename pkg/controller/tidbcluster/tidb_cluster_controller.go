@@ -164,6 +164,7 @@ func (c *Controller) sync(key string) error {
 	tc, err := c.deps.TiDBClusterLister.TidbClusters(ns).Get(name)
 	if errors.IsNotFound(err) {
 		klog.Infof("TidbCluster has been deleted %v", key)
+		metrics.ClusterStatus.Delete(ns, name)
 		return nil
 	}
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,6 +37,10 @@ type TiDBDiscovery interface {
 	Discover(string) (string, error)
 	DiscoverDM(string) (string, error)
 	VerifyPDEndpoint(string) (string, error)
+	// Topology returns the topology of the PD cluster of the TidbCluster
+	Topology(tcName string) (*ClusterTopology, error)
+	// DMTopology returns the topology of the dm-master cluster of the DMCluster
+	DMTopology(dcName string) (*ClusterTopology, error)
 }
 
 type tidbDiscovery struct {
@@ -45,11 +50,19 @@ type tidbDiscovery struct {
 	dmClusters    map[string]*clusterInfo
 	pdControl     pdapi.PDControlInterface
 	masterControl dmapi.MasterControlInterface
+	// store persists the clusters, nil if the clusters are only kept in memory
+	store ClusterStore
+	// registrations are the last registration attempts of the pods, keyed by the store key of the cluster
+	registrations map[string]map[string]*Registration
+	// initialClusters are the initial-cluster arguments generated for the clusters, keyed by the store key
+	initialClusters map[string]string
 }
 
 type clusterInfo struct {
 	resourceVersion string
 	peers           map[string]struct{}
+	// revision is the revision of the cluster in the store
+	revision string
 }
 
 type pdEndpointURL struct {
@@ -59,18 +72,22 @@ type pdEndpointURL struct {
 	tcName       string
 }
 
-// NewTiDBDiscovery returns a TiDBDiscovery
-func NewTiDBDiscovery(pdControl pdapi.PDControlInterface, masterControl dmapi.MasterControlInterface, cli versioned.Interface, kubeCli kubernetes.Interface) TiDBDiscovery {
+// NewTiDBDiscovery returns a TiDBDiscovery, the discovery state is persisted in the store
+// if it is not nil, otherwise the state is only kept in memory
+func NewTiDBDiscovery(pdControl pdapi.PDControlInterface, masterControl dmapi.MasterControlInterface, cli versioned.Interface, kubeCli kubernetes.Interface, store ClusterStore) TiDBDiscovery {
 	return &tidbDiscovery{
-		cli:           cli,
-		pdControl:     pdControl,
-		masterControl: masterControl,
-		clusters:      map[string]*clusterInfo{},
-		dmClusters:    map[string]*clusterInfo{},
+		cli:             cli,
+		pdControl:       pdControl,
+		masterControl:   masterControl,
+		clusters:        map[string]*clusterInfo{},
+		dmClusters:      map[string]*clusterInfo{},
+		store:           store,
+		registrations:   map[string]map[string]*Registration{},
+		initialClusters: map[string]string{},
 	}
 }
 
-func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
+func (d *tidbDiscovery) Discover(advertisePeerUrl string) (result string, err error) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
@@ -97,20 +114,26 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		return "", err
 	}
 	keyName := fmt.Sprintf("%s/%s", ns, tcName)
+	storeKey := fmt.Sprintf("pd/%s", keyName)
+	defer func() {
+		d.recordRegistration(storeKey, podName, advertisePeerUrl, result, err)
+	}()
 
-	currentCluster := d.clusters[keyName]
-	if currentCluster == nil || currentCluster.resourceVersion != tc.ResourceVersion {
-		d.clusters[keyName] = &clusterInfo{
-			resourceVersion: tc.ResourceVersion,
-			peers:           map[string]struct{}{},
-		}
+	currentCluster, err := d.loadCluster(d.clusters, storeKey, keyName, tc.ResourceVersion)
+	if err != nil {
+		return "", err
 	}
-	currentCluster = d.clusters[keyName]
 	currentCluster.peers[podName] = struct{}{}
+	if err := d.saveCluster(d.clusters, storeKey, keyName); err != nil {
+		return "", err
+	}
 
 	// Should take failover replicas into consideration
 	if len(currentCluster.peers) == int(tc.PDStsDesiredReplicas()) && tc.Spec.Cluster == nil {
 		delete(currentCluster.peers, podName)
+		if err := d.saveCluster(d.clusters, storeKey, keyName); err != nil {
+			return "", err
+		}
 		pdAddresses := tc.Spec.PDAddresses
 		// Join an existing PD cluster if tc.Spec.PDAddresses is set
 		if len(pdAddresses) != 0 {
@@ -124,34 +147,8 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		return fmt.Sprintf("--initial-cluster=%s=%s://%s", podName, tc.Scheme(), advertisePeerUrl), nil
 	}
 
-	var pdClients []pdapi.PDClient
-
-	if tc.Spec.PD != nil {
-		// connect to pd of current cluster
-		pdClients = append(pdClients, d.pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.IsTLSClusterEnabled()))
-	}
-
-	if tc.Heterogeneous() {
-		// connect to pd of other cluster and use own cert
-		namespace := tc.Spec.Cluster.Namespace
-		if len(namespace) == 0 {
-			namespace = tc.GetNamespace()
-		}
-		pdClients = append(pdClients,
-			d.pdControl.GetPDClient(pdapi.Namespace(namespace), tc.Spec.Cluster.Name, tc.IsTLSClusterEnabled(),
-				pdapi.TLSCertFromTC(pdapi.Namespace(tc.GetNamespace()), tc.GetName()),
-				pdapi.ClusterRef(tc.Spec.Cluster.ClusterDomain),
-				pdapi.UseHeadlessService(tc.Spec.AcrossK8s),
-			),
-		)
-	}
-
-	for _, pdMember := range tc.Status.PD.PeerMembers {
-		pdClients = append(pdClients, d.pdControl.GetPDClient(pdapi.Namespace(ns), tc.Name, tc.IsTLSClusterEnabled(), pdapi.SpecifyClient(pdMember.ClientURL, pdMember.Name)))
-	}
-
 	var membersInfo *pdapi.MembersInfo
-	for _, client := range pdClients {
+	for _, client := range d.getPDClients(tc) {
 		membersInfo, err = client.GetMembers()
 		if err == nil {
 			break
@@ -180,10 +177,13 @@ func (d *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		membersArr = append(membersArr, memberURL)
 	}
 	delete(currentCluster.peers, podName)
+	if err := d.saveCluster(d.clusters, storeKey, keyName); err != nil {
+		return "", err
+	}
 	return fmt.Sprintf("--join=%s", strings.Join(membersArr, ",")), nil
 }
 
-func (d *tidbDiscovery) DiscoverDM(advertisePeerUrl string) (string, error) {
+func (d *tidbDiscovery) DiscoverDM(advertisePeerUrl string) (result string, err error) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
@@ -210,19 +210,25 @@ func (d *tidbDiscovery) DiscoverDM(advertisePeerUrl string) (string, error) {
 		return "", err
 	}
 	keyName := fmt.Sprintf("%s/%s", ns, dcName)
+	storeKey := fmt.Sprintf("dm/%s", keyName)
+	defer func() {
+		d.recordRegistration(storeKey, podName, advertisePeerUrl, result, err)
+	}()
 
-	currentCluster := d.dmClusters[keyName]
-	if currentCluster == nil || currentCluster.resourceVersion != dc.ResourceVersion {
-		d.dmClusters[keyName] = &clusterInfo{
-			resourceVersion: dc.ResourceVersion,
-			peers:           map[string]struct{}{},
-		}
+	currentCluster, err := d.loadCluster(d.dmClusters, storeKey, keyName, dc.ResourceVersion)
+	if err != nil {
+		return "", err
 	}
-	currentCluster = d.dmClusters[keyName]
 	currentCluster.peers[podName] = struct{}{}
+	if err := d.saveCluster(d.dmClusters, storeKey, keyName); err != nil {
+		return "", err
+	}
 
 	if len(currentCluster.peers) == int(dc.MasterStsDesiredReplicas()) {
 		delete(currentCluster.peers, podName)
+		if err := d.saveCluster(d.dmClusters, storeKey, keyName); err != nil {
+			return "", err
+		}
 		return fmt.Sprintf("--initial-cluster=%s=%s://%s", podName, dc.Scheme(), advertisePeerUrl), nil
 	}
 
@@ -244,9 +250,102 @@ func (d *tidbDiscovery) DiscoverDM(advertisePeerUrl string) (string, error) {
 		mastersArr = append(mastersArr, memberURL)
 	}
 	delete(currentCluster.peers, podName)
+	if err := d.saveCluster(d.dmClusters, storeKey, keyName); err != nil {
+		return "", err
+	}
 	return fmt.Sprintf("--join=%s", strings.Join(mastersArr, ",")), nil
 }
 
+// getPDClients returns the clients of the PD members which the PD of the TidbCluster can join
+func (d *tidbDiscovery) getPDClients(tc *v1alpha1.TidbCluster) []pdapi.PDClient {
+	var pdClients []pdapi.PDClient
+
+	if tc.Spec.PD != nil {
+		// connect to pd of current cluster
+		pdClients = append(pdClients, d.pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.IsTLSClusterEnabled()))
+	}
+
+	if tc.Heterogeneous() {
+		// connect to pd of other cluster and use own cert
+		namespace := tc.Spec.Cluster.Namespace
+		if len(namespace) == 0 {
+			namespace = tc.GetNamespace()
+		}
+		pdClients = append(pdClients,
+			d.pdControl.GetPDClient(pdapi.Namespace(namespace), tc.Spec.Cluster.Name, tc.IsTLSClusterEnabled(),
+				pdapi.TLSCertFromTC(pdapi.Namespace(tc.GetNamespace()), tc.GetName()),
+				pdapi.ClusterRef(tc.Spec.Cluster.ClusterDomain),
+				pdapi.UseHeadlessService(tc.Spec.AcrossK8s),
+			),
+		)
+	}
+
+	for _, pdMember := range tc.Status.PD.PeerMembers {
+		pdClients = append(pdClients, d.pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.Name, tc.IsTLSClusterEnabled(), pdapi.SpecifyClient(pdMember.ClientURL, pdMember.Name)))
+	}
+	return pdClients
+}
+
+// loadCluster returns the cluster of keyName, which is loaded from the store if the store is set.
+// The registered peers are reset if the cluster object is changed since they registered.
+func (d *tidbDiscovery) loadCluster(clusters map[string]*clusterInfo, storeKey, keyName, resourceVersion string) (*clusterInfo, error) {
+	if d.store != nil {
+		state, err := d.store.Load(storeKey)
+		if err != nil {
+			klog.Errorf("failed to load the discovery state of %s, err: %v", storeKey, err)
+			return nil, err
+		}
+		peers := make(map[string]struct{}, len(state.Peers))
+		for _, peer := range state.Peers {
+			peers[peer] = struct{}{}
+		}
+		clusters[keyName] = &clusterInfo{
+			resourceVersion: state.ResourceVersion,
+			peers:           peers,
+			revision:        state.Revision,
+		}
+	}
+
+	currentCluster := clusters[keyName]
+	if currentCluster == nil || currentCluster.resourceVersion != resourceVersion {
+		var revision string
+		if currentCluster != nil {
+			revision = currentCluster.revision
+		}
+		clusters[keyName] = &clusterInfo{
+			resourceVersion: resourceVersion,
+			peers:           map[string]struct{}{},
+			revision:        revision,
+		}
+	}
+	return clusters[keyName], nil
+}
+
+// saveCluster saves the cluster of keyName to the store if the store is set.
+// The cluster is removed from memory if it fails to be saved, so it is reloaded from the store next time.
+func (d *tidbDiscovery) saveCluster(clusters map[string]*clusterInfo, storeKey, keyName string) error {
+	if d.store == nil {
+		return nil
+	}
+	currentCluster := clusters[keyName]
+	state := &ClusterState{
+		ResourceVersion: currentCluster.resourceVersion,
+		Peers:           make([]string, 0, len(currentCluster.peers)),
+		Revision:        currentCluster.revision,
+	}
+	for peer := range currentCluster.peers {
+		state.Peers = append(state.Peers, peer)
+	}
+	sort.Strings(state.Peers)
+	if err := d.store.Save(storeKey, state); err != nil {
+		klog.Errorf("failed to save the discovery state of %s, err: %v", storeKey, err)
+		delete(clusters, keyName)
+		return err
+	}
+	currentCluster.revision = state.Revision
+	return nil
+}
+
 func (d *tidbDiscovery) VerifyPDEndpoint(pdURL string) (string, error) {
 	pdEndpoint := parsePDURL(pdURL)
 	klog.Infof("Get PD endpoint URL: %s, scheme is %s, pdMemberName is %s, pdMemberPort is %s, tcName is %s", pdURL, pdEndpoint.scheme, pdEndpoint.pdMemberName, pdEndpoint.pdMemberPort, pdEndpoint.tcName)
@@ -60,7 +60,7 @@ func TestDiscoveryDiscovery(t *testing.T) {
 			return test.getMembersFn()
 		})
 
-		td := NewTiDBDiscovery(fakePDControl, fakeMasterControl, cli, kubeCli)
+		td := NewTiDBDiscovery(fakePDControl, fakeMasterControl, cli, kubeCli, nil)
 		td.(*tidbDiscovery).clusters = test.clusters
 
 		os.Setenv("MY_POD_NAMESPACE", test.ns)
@@ -562,7 +562,7 @@ func TestDiscoveryDMDiscovery(t *testing.T) {
 			return test.getMastersFn()
 		})
 
-		td := NewTiDBDiscovery(fakePDControl, fakeMasterControl, cli, kubeCli)
+		td := NewTiDBDiscovery(fakePDControl, fakeMasterControl, cli, kubeCli, nil)
 		td.(*tidbDiscovery).dmClusters = test.dmClusters
 
 		os.Setenv("MY_POD_NAMESPACE", test.ns)
@@ -977,7 +977,7 @@ func TestDiscoveryVerifyPDEndpoint(t *testing.T) {
 		}
 
 		cli.PingcapV1alpha1().TidbClusters(ns).Create(context.TODO(), tc, metav1.CreateOptions{})
-		td := NewTiDBDiscovery(fakePDControl, fakeMasterControl, cli, kubeCli)
+		td := NewTiDBDiscovery(fakePDControl, fakeMasterControl, cli, kubeCli, nil)
 
 		os.Setenv("MY_POD_NAMESPACE", test.ns)
 		re, err := td.VerifyPDEndpoint(test.url)
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	"github.com/pingcap/tidb-operator/pkg/discovery"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
@@ -35,10 +36,10 @@ type server struct {
 	container *restful.Container
 }
 
-// NewServer creates a new server.
-func NewServer(pdControl pdapi.PDControlInterface, masterControl dmapi.MasterControlInterface, cli versioned.Interface, kubeCli kubernetes.Interface) Server {
+// NewServer creates a new server, the discovery state is persisted in the store if it is not nil.
+func NewServer(pdControl pdapi.PDControlInterface, masterControl dmapi.MasterControlInterface, cli versioned.Interface, kubeCli kubernetes.Interface, store discovery.ClusterStore) Server {
 	s := &server{
-		discovery: discovery.NewTiDBDiscovery(pdControl, masterControl, cli, kubeCli),
+		discovery: discovery.NewTiDBDiscovery(pdControl, masterControl, cli, kubeCli, store),
 		container: restful.NewContainer(),
 	}
 	s.registerHandlers()
@@ -50,6 +51,8 @@ func (s *server) registerHandlers() {
 	ws.Route(ws.GET("/new/{advertise-peer-url}").To(s.newHandler))
 	ws.Route(ws.GET("/new/{advertise-peer-url}/{register-type}").To(s.newHandler))
 	ws.Route(ws.GET("/verify/{pd-url}").To(s.newVerifyHandler))
+	ws.Route(ws.GET("/topology/pd/{cluster-name}").To(s.newTopologyHandler).Produces(restful.MIME_JSON))
+	ws.Route(ws.GET("/topology/dm/{cluster-name}").To(s.newDMTopologyHandler).Produces(restful.MIME_JSON))
 	s.container.Add(ws)
 }
 
@@ -131,3 +134,31 @@ func (s *server) newVerifyHandler(req *restful.Request, resp *restful.Response)
 		klog.Errorf("failed to writeString: %s, %v", result, err)
 	}
 }
+
+// newTopologyHandler returns the topology of the PD cluster in JSON for debugging
+func (s *server) newTopologyHandler(req *restful.Request, resp *restful.Response) {
+	writeTopology(resp, req.PathParameter("cluster-name"), s.discovery.Topology)
+}
+
+// newDMTopologyHandler returns the topology of the dm-master cluster in JSON for debugging
+func (s *server) newDMTopologyHandler(req *restful.Request, resp *restful.Response) {
+	writeTopology(resp, req.PathParameter("cluster-name"), s.discovery.DMTopology)
+}
+
+func writeTopology(resp *restful.Response, clusterName string, getTopology func(string) (*discovery.ClusterTopology, error)) {
+	topology, err := getTopology(clusterName)
+	if err != nil {
+		klog.Errorf("failed to get the topology of cluster %s, %v", clusterName, err)
+		status := http.StatusInternalServerError
+		if errors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		if werr := resp.WriteError(status, err); werr != nil {
+			klog.Errorf("failed to writeError: %v", werr)
+		}
+		return
+	}
+	if err := resp.WriteAsJson(topology); err != nil {
+		klog.Errorf("failed to write the topology of cluster %s, %v", clusterName, err)
+	}
+}
@@ -16,6 +16,7 @@ package server
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -30,6 +31,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	"github.com/pingcap/tidb-operator/pkg/discovery"
 	"github.com/pingcap/tidb-operator/pkg/dmapi"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"golang.org/x/sync/errgroup"
@@ -74,7 +76,7 @@ func TestServer(t *testing.T) {
 	fakePDControl := pdapi.NewFakePDControl(informer.Core().V1().Secrets().Lister())
 	faleMasterControl := dmapi.NewFakeMasterControl(informer.Core().V1().Secrets().Lister())
 	pdClient := pdapi.NewFakePDClient()
-	s := NewServer(fakePDControl, faleMasterControl, cli, kubeCli)
+	s := NewServer(fakePDControl, faleMasterControl, cli, kubeCli, nil)
 	httpServer := httptest.NewServer(s.(*server).container.ServeMux)
 	defer httpServer.Close()
 
@@ -149,6 +151,13 @@ func TestServer(t *testing.T) {
 	if join != 2 {
 		t.Errorf("join expects 2, got %d", join)
 	}
+
+	checkTopology(t, httpServer.URL+"/topology/pd/foo", 3)
+	if resp, err := http.Get(httpServer.URL + "/topology/pd/bar"); err != nil {
+		t.Errorf("get topology failed: %v", err)
+	} else if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status code expects %d, got %d", http.StatusNotFound, resp.StatusCode)
+	}
 }
 
 func TestDMServer(t *testing.T) {
@@ -160,7 +169,7 @@ func TestDMServer(t *testing.T) {
 	fakePDControl := pdapi.NewFakePDControl(informer.Core().V1().Secrets().Lister())
 	faleMasterControl := dmapi.NewFakeMasterControl(informer.Core().V1().Secrets().Lister())
 	masterClient := dmapi.NewFakeMasterClient()
-	s := NewServer(fakePDControl, faleMasterControl, cli, kubeCli)
+	s := NewServer(fakePDControl, faleMasterControl, cli, kubeCli, nil)
 	httpServer := httptest.NewServer(s.(*server).container.ServeMux)
 	defer httpServer.Close()
 
@@ -233,6 +242,40 @@ func TestDMServer(t *testing.T) {
 	if join != 2 {
 		t.Errorf("join expects 2, got %d", join)
 	}
+
+	checkTopology(t, httpServer.URL+"/topology/dm/foo", 3)
+}
+
+func checkTopology(t *testing.T, url string, members int) {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("get topology failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status code expects %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	topology := &discovery.ClusterTopology{}
+	if err := json.NewDecoder(resp.Body).Decode(topology); err != nil {
+		t.Fatalf("decode topology failed: %v", err)
+	}
+	if len(topology.Members) != members {
+		t.Errorf("members expects %d, got %d", members, len(topology.Members))
+	}
+	if !strings.HasPrefix(topology.InitialCluster, "foo-") {
+		t.Errorf("initial cluster expects to be generated, got %q", topology.InitialCluster)
+	}
+	if len(topology.PendingPeers) != 0 {
+		t.Errorf("pending peers expects empty, got %v", topology.PendingPeers)
+	}
+	if len(topology.Registrations) != members {
+		t.Errorf("registrations expects %d, got %d", members, len(topology.Registrations))
+	}
+	for _, registration := range topology.Registrations {
+		if registration.Error != "" || registration.Result == "" {
+			t.Errorf("the last registration of %s expects to succeed, got %+v", registration.Pod, registration)
+		}
+	}
 }
 
 func TestVerifyServer(t *testing.T) {
@@ -242,7 +285,7 @@ func TestVerifyServer(t *testing.T) {
 	informer := informers.NewSharedInformerFactory(kubeCli, 0)
 	fakePDControl := pdapi.NewFakePDControl(informer.Core().V1().Secrets().Lister())
 	fakeMasterControl := dmapi.NewFakeMasterControl(informer.Core().V1().Secrets().Lister())
-	s := NewServer(fakePDControl, fakeMasterControl, cli, kubeCli)
+	s := NewServer(fakePDControl, fakeMasterControl, cli, kubeCli, nil)
 
 	httpServer := httptest.NewServer(s.(*server).container.ServeMux)
 
@@ -0,0 +1,109 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ClusterState is the discovery state of a cluster in the bootstrap phase
+type ClusterState struct {
+	// ResourceVersion is the resource version of the cluster object when the peers registered
+	ResourceVersion string `json:"resourceVersion"`
+	// Peers are the pods which registered and are waiting for the cluster to be initialized
+	Peers []string `json:"peers"`
+	// Revision is the revision of the state in the store, which is used to detect concurrent modifications
+	Revision string `json:"-"`
+}
+
+// ClusterStore persists the discovery state of the clusters, so that the state survives
+// the restart of the discovery service.
+type ClusterStore interface {
+	// Load returns the state of the cluster, the ResourceVersion of the state is empty if not found
+	Load(key string) (*ClusterState, error)
+	// Save saves the state of the cluster only if the state in the store is not modified since
+	// it is loaded, and sets the new revision to the state
+	Save(key string, state *ClusterState) error
+}
+
+type configMapStore struct {
+	kubeCli   kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewConfigMapStore returns a ClusterStore which stores the states in the given ConfigMap.
+// The ConfigMap is created by the operator with the discovery service.
+func NewConfigMapStore(kubeCli kubernetes.Interface, namespace, name string) ClusterStore {
+	return &configMapStore{
+		kubeCli:   kubeCli,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+func (s *configMapStore) Load(key string) (*ClusterState, error) {
+	cm, err := s.kubeCli.CoreV1().ConfigMaps(s.namespace).Get(context.TODO(), s.name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return &ClusterState{}, nil
+		}
+		return nil, err
+	}
+	state := &ClusterState{}
+	if data, ok := cm.Data[configMapKey(key)]; ok {
+		if err := json.Unmarshal([]byte(data), state); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal the discovery state of %s in configmap %s/%s: %v", key, s.namespace, s.name, err)
+		}
+	}
+	state.Revision = cm.ResourceVersion
+	return state, nil
+}
+
+func (s *configMapStore) Save(key string, state *ClusterState) error {
+	cm, err := s.kubeCli.CoreV1().ConfigMaps(s.namespace).Get(context.TODO(), s.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if len(state.Revision) > 0 && cm.ResourceVersion != state.Revision {
+		return fmt.Errorf("the discovery state of %s in configmap %s/%s is modified concurrently", key, s.namespace, s.name)
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[configMapKey(key)] = string(data)
+	// The update fails with conflict if the ConfigMap is modified after the get
+	updated, err := s.kubeCli.CoreV1().ConfigMaps(s.namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	state.Revision = updated.ResourceVersion
+	return nil
+}
+
+// configMapKey converts the key to a valid key of the ConfigMap data
+func configMapKey(key string) string {
+	return strings.ReplaceAll(key, "/", ".")
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	"github.com/pingcap/tidb-operator/pkg/dmapi"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigMapStore(t *testing.T) {
+	g := NewGomegaWithT(t)
+	kubeCli := kubefake.NewSimpleClientset()
+	store := NewConfigMapStore(kubeCli, metav1.NamespaceDefault, "demo-discovery-state")
+
+	// The state is empty if the ConfigMap does not exist
+	state, err := store.Load("pd/default/demo")
+	g.Expect(err).To(Succeed())
+	g.Expect(state.ResourceVersion).To(BeEmpty())
+	g.Expect(state.Peers).To(BeEmpty())
+	g.Expect(store.Save("pd/default/demo", state)).NotTo(Succeed())
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "demo-discovery-state",
+			Namespace:       metav1.NamespaceDefault,
+			ResourceVersion: "1",
+		},
+	}
+	_, err = kubeCli.CoreV1().ConfigMaps(cm.Namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+	g.Expect(err).To(Succeed())
+
+	state, err = store.Load("pd/default/demo")
+	g.Expect(err).To(Succeed())
+	g.Expect(state.Revision).To(Equal("1"))
+	state.ResourceVersion = "10"
+	state.Peers = []string{"demo-pd-0", "demo-pd-1"}
+	g.Expect(store.Save("pd/default/demo", state)).To(Succeed())
+
+	loaded, err := store.Load("pd/default/demo")
+	g.Expect(err).To(Succeed())
+	g.Expect(loaded.ResourceVersion).To(Equal("10"))
+	g.Expect(loaded.Peers).To(Equal([]string{"demo-pd-0", "demo-pd-1"}))
+	cm, err = kubeCli.CoreV1().ConfigMaps(cm.Namespace).Get(context.TODO(), cm.Name, metav1.GetOptions{})
+	g.Expect(err).To(Succeed())
+	g.Expect(cm.Data).To(HaveKey("pd.default.demo"))
+
+	// The other clusters are kept in the same ConfigMap
+	other, err := store.Load("dm/default/demo")
+	g.Expect(err).To(Succeed())
+	g.Expect(other.Peers).To(BeEmpty())
+
+	// Saving a stale state fails if the ConfigMap is modified by others
+	cm.ResourceVersion = "2"
+	_, err = kubeCli.CoreV1().ConfigMaps(cm.Namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	g.Expect(err).To(Succeed())
+	err = store.Save("pd/default/demo", loaded)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(strings.Contains(err.Error(), "modified concurrently")).To(BeTrue())
+}
+
+func TestDiscoveryRestartWithStore(t *testing.T) {
+	g := NewGomegaWithT(t)
+	os.Setenv("MY_POD_NAMESPACE", metav1.NamespaceDefault)
+
+	tc := newTC()
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	_, err := cli.PingcapV1alpha1().TidbClusters(tc.Namespace).Create(context.TODO(), tc, metav1.CreateOptions{})
+	g.Expect(err).To(Succeed())
+	_, err = kubeCli.CoreV1().ConfigMaps(tc.Namespace).Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-discovery-state", Namespace: tc.Namespace},
+	}, metav1.CreateOptions{})
+	g.Expect(err).To(Succeed())
+
+	informer := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	fakePDControl := pdapi.NewFakePDControl(informer.Core().V1().Secrets().Lister())
+	fakeMasterControl := dmapi.NewFakeMasterControl(informer.Core().V1().Secrets().Lister())
+	pdClient := pdapi.NewFakePDClient()
+	fakePDControl.SetPDClient(pdapi.Namespace(tc.Namespace), tc.Name, pdClient)
+	var members []string
+	pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+		if len(members) == 0 {
+			return nil, fmt.Errorf("there are no pd members")
+		}
+		return newMembersInfo(members), nil
+	})
+	newDiscovery := func() TiDBDiscovery {
+		store := NewConfigMapStore(kubeCli, tc.Namespace, "demo-discovery-state")
+		return NewTiDBDiscovery(fakePDControl, fakeMasterControl, cli, kubeCli, store)
+	}
+
+	td := newDiscovery()
+	for _, pod := range []string{"demo-pd-0", "demo-pd-1"} {
+		_, err := td.Discover(fmt.Sprintf("%s.demo-pd-peer.default.svc:2380", pod))
+		g.Expect(err).To(HaveOccurred())
+	}
+
+	// The registered peers survive the restart of the discovery service
+	td = newDiscovery()
+	re, err := td.Discover("demo-pd-2.demo-pd-peer.default.svc:2380")
+	g.Expect(err).To(Succeed())
+	g.Expect(re).To(Equal("--initial-cluster=demo-pd-2=http://demo-pd-2.demo-pd-peer.default.svc:2380"))
+	members = []string{"demo-pd-2"}
+
+	// The other peers join the initialized cluster after another restart
+	td = newDiscovery()
+	for _, pod := range []string{"demo-pd-0", "demo-pd-1"} {
+		re, err := td.Discover(fmt.Sprintf("%s.demo-pd-peer.default.svc:2380", pod))
+		g.Expect(err).To(Succeed())
+		g.Expect(re).To(Equal("--join=demo-pd-2.demo-pd-peer.default.svc:2379"))
+	}
+	state, err := NewConfigMapStore(kubeCli, tc.Namespace, "demo-discovery-state").Load("pd/default/demo")
+	g.Expect(err).To(Succeed())
+	g.Expect(state.Peers).To(BeEmpty())
+}
+
+func newMembersInfo(names []string) *pdapi.MembersInfo {
+	members := &pdapi.MembersInfo{}
+	for _, name := range names {
+		members.Members = append(members.Members, &pdpb.Member{
+			Name:     name,
+			PeerUrls: []string{fmt.Sprintf("%s.demo-pd-peer.default.svc:2380", name)},
+		})
+	}
+	return members
+}
@@ -0,0 +1,192 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const initialClusterArgPrefix = "--initial-cluster="
+
+// MemberStatus is the status of a PD or dm-master member
+type MemberStatus struct {
+	Name       string   `json:"name"`
+	PeerURLs   []string `json:"peerURLs,omitempty"`
+	ClientURLs []string `json:"clientURLs,omitempty"`
+	Healthy    bool     `json:"healthy"`
+}
+
+// Registration is a registration attempt of a pod
+type Registration struct {
+	Pod              string      `json:"pod"`
+	AdvertisePeerURL string      `json:"advertisePeerURL"`
+	Result           string      `json:"result,omitempty"`
+	Error            string      `json:"error,omitempty"`
+	Timestamp        metav1.Time `json:"timestamp"`
+}
+
+// ClusterTopology is the topology of a PD or dm-master cluster seen by the discovery service
+type ClusterTopology struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Members are the current members of the cluster
+	Members []MemberStatus `json:"members"`
+	// MembersError is the error to get the members, the cluster may not be initialized yet
+	MembersError string `json:"membersError,omitempty"`
+	// InitialCluster is the initial-cluster argument generated to bootstrap the cluster
+	InitialCluster string `json:"initialCluster,omitempty"`
+	// PendingPeers are the pods which registered and are waiting for the cluster to be initialized
+	PendingPeers []string `json:"pendingPeers"`
+	// Registrations are the last registration attempts of the pods
+	Registrations []Registration `json:"registrations"`
+}
+
+func (d *tidbDiscovery) Topology(tcName string) (*ClusterTopology, error) {
+	ns := os.Getenv("MY_POD_NAMESPACE")
+	tc, err := d.cli.PingcapV1alpha1().TidbClusters(ns).Get(context.TODO(), tcName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	keyName := fmt.Sprintf("%s/%s", ns, tcName)
+	topology, err := d.clusterTopology(d.clusters, fmt.Sprintf("pd/%s", keyName), keyName, tc.ResourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	topology.Namespace, topology.Name = ns, tcName
+
+	var membersInfo *pdapi.MembersInfo
+	var pdClient pdapi.PDClient
+	for _, client := range d.getPDClients(tc) {
+		membersInfo, err = client.GetMembers()
+		if err == nil {
+			pdClient = client
+			break
+		}
+	}
+	if err != nil {
+		topology.MembersError = err.Error()
+		return topology, nil
+	}
+	healthy := map[string]bool{}
+	if healthInfo, err := pdClient.GetHealth(); err != nil {
+		klog.Warningf("failed to get the health of the pd members of %s, err: %v", keyName, err)
+	} else {
+		for _, health := range healthInfo.Healths {
+			healthy[health.Name] = health.Health
+		}
+	}
+	for _, member := range membersInfo.Members {
+		topology.Members = append(topology.Members, MemberStatus{
+			Name:       member.Name,
+			PeerURLs:   member.PeerUrls,
+			ClientURLs: member.ClientUrls,
+			Healthy:    healthy[member.Name],
+		})
+	}
+	return topology, nil
+}
+
+func (d *tidbDiscovery) DMTopology(dcName string) (*ClusterTopology, error) {
+	ns := os.Getenv("MY_POD_NAMESPACE")
+	dc, err := d.cli.PingcapV1alpha1().DMClusters(ns).Get(context.TODO(), dcName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	keyName := fmt.Sprintf("%s/%s", ns, dcName)
+	topology, err := d.clusterTopology(d.dmClusters, fmt.Sprintf("dm/%s", keyName), keyName, dc.ResourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	topology.Namespace, topology.Name = ns, dcName
+
+	masterClient := d.masterControl.GetMasterClient(dc.GetNamespace(), dc.GetName(), dc.IsTLSClusterEnabled())
+	mastersInfos, err := masterClient.GetMasters()
+	if err != nil {
+		topology.MembersError = err.Error()
+		return topology, nil
+	}
+	for _, master := range mastersInfos {
+		topology.Members = append(topology.Members, MemberStatus{
+			Name:       master.Name,
+			PeerURLs:   master.PeerURLs,
+			ClientURLs: master.ClientURLs,
+			Healthy:    master.Alive,
+		})
+	}
+	return topology, nil
+}
+
+// clusterTopology returns the topology of the cluster kept by the discovery service
+func (d *tidbDiscovery) clusterTopology(clusters map[string]*clusterInfo, storeKey, keyName, resourceVersion string) (*ClusterTopology, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	topology := &ClusterTopology{
+		Members:        []MemberStatus{},
+		InitialCluster: d.initialClusters[storeKey],
+		PendingPeers:   []string{},
+		Registrations:  []Registration{},
+	}
+	if d.store != nil {
+		state, err := d.store.Load(storeKey)
+		if err != nil {
+			return nil, err
+		}
+		if state.ResourceVersion == resourceVersion {
+			topology.PendingPeers = append(topology.PendingPeers, state.Peers...)
+		}
+	} else if cluster := clusters[keyName]; cluster != nil && cluster.resourceVersion == resourceVersion {
+		for peer := range cluster.peers {
+			topology.PendingPeers = append(topology.PendingPeers, peer)
+		}
+	}
+	sort.Strings(topology.PendingPeers)
+
+	for _, registration := range d.registrations[storeKey] {
+		topology.Registrations = append(topology.Registrations, *registration)
+	}
+	sort.Slice(topology.Registrations, func(i, j int) bool {
+		return topology.Registrations[i].Pod < topology.Registrations[j].Pod
+	})
+	return topology, nil
+}
+
+// recordRegistration records the registration attempt of the pod, it must be called with the lock held
+func (d *tidbDiscovery) recordRegistration(storeKey, podName, advertisePeerURL, result string, err error) {
+	registration := &Registration{
+		Pod:              podName,
+		AdvertisePeerURL: advertisePeerURL,
+		Result:           result,
+		Timestamp:        metav1.NewTime(time.Now()),
+	}
+	if err != nil {
+		registration.Error = err.Error()
+	}
+	if d.registrations[storeKey] == nil {
+		d.registrations[storeKey] = map[string]*Registration{}
+	}
+	d.registrations[storeKey][podName] = registration
+	if strings.HasPrefix(result, initialClusterArgPrefix) {
+		d.initialClusters[storeKey] = strings.TrimPrefix(result, initialClusterArgPrefix)
+	}
+}
@@ -152,6 +152,8 @@ func (sf *commonStoreFailover) tryMarkAStoreAsFailure(tc *v1alpha1.TidbCluster)
 					})
 					msg := fmt.Sprintf("store[%s] is Down", store.ID)
 					sf.deps.Recorder.Event(tc, corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, sf.storeAccess.GetMemberType(), podName, msg))
+					controller.RecordOperation(sf.deps.Recorder, tc, sf.storeAccess.GetMemberType(), corev1.EventTypeWarning, controller.FailoverPodCreatedReason,
+						"mark store %s of pod %s as failure store and create a new pod for failover", store.ID, podName)
 				}
 			}
 		}
@@ -147,6 +147,8 @@ func (f *pdFailover) tryToMarkAPeerAsFailure(tc *v1alpha1.TidbCluster) error {
 			MemberDeleted: false,
 			CreatedAt:     metav1.Now(),
 		}
+		controller.RecordOperation(f.deps.Recorder, tc, v1alpha1.PDMemberType, apiv1.EventTypeWarning, controller.FailoverPodCreatedReason,
+			"mark pd member %s of pod %s as failure member and create a new pod for failover", pdMember.Name, podName)
 		return controller.RequeueErrorf("marking Pod: %s/%s pd member: %s as failure", ns, podName, pdMember.Name)
 	}
 
@@ -308,9 +308,12 @@ func TestPDFailoverFailover(t *testing.T) {
 				g.Expect(failureMembers.PVCUIDSet).To(HaveKey(types.UID("pvc-1-uid-2")))
 				g.Expect(failureMembers.MemberDeleted).To(BeFalse())
 				events := collectEvents(recorder.Events)
-				g.Expect(events).To(HaveLen(2))
+				g.Expect(events).To(HaveLen(3))
 				g.Expect(events[0]).To(ContainSubstring("test-pd-1(12891273174085095651) is unhealthy"))
 				g.Expect(events[1]).To(ContainSubstring("PDMemberUnhealthy default/test-pd-1(12891273174085095651) is unhealthy"))
+				g.Expect(events[2]).To(ContainSubstring("FailoverPodCreated mark pd member test-pd-1 of pod test-pd-1 as failure member"))
+				g.Expect(tc.Status.OperationHistory).To(HaveLen(1))
+				g.Expect(tc.Status.OperationHistory[0].Reason).To(Equal(controller.FailoverPodCreatedReason))
 			},
 		},
 		{
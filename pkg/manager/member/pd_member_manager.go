@@ -254,10 +254,15 @@ func (m *pdMemberManager) syncPDStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 	}
 
 	if !templateEqual(newPDSet, oldPDSet) || tc.Status.PD.Phase == v1alpha1.UpgradePhase {
-		if err := m.upgrader.Upgrade(tc, oldPDSet, newPDSet); err != nil {
+		if err := upgradeWithPolicy(m.deps, tc, v1alpha1.PDMemberType, oldPDSet, newPDSet, func() error {
+			return m.upgrader.Upgrade(tc, oldPDSet, newPDSet)
+		}); err != nil {
 			return err
 		}
 	}
+	if err := syncPostUpgradeHook(m.deps, tc, v1alpha1.PDMemberType, oldPDSet, newPDSet); err != nil {
+		return err
+	}
 
 	return mngerutils.UpdateStatefulSetWithPrecheck(m.deps, tc, "FailedUpdatePDSTS", newPDSet, oldPDSet)
 }
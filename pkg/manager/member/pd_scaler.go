@@ -115,14 +115,15 @@ func (s *pdScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet
 				targetOrdinal = minOrdinal
 			}
 			targetPdName := PdName(tcName, targetOrdinal, tc.Namespace, tc.Spec.ClusterDomain, tc.Spec.AcrossK8s)
-			if _, exist := tc.Status.PD.Members[targetPdName]; exist {
-				err = pdClient.TransferPDLeader(targetPdName)
-			} else {
-				err = pdClient.TransferPDLeader(PdPodName(tcName, targetOrdinal))
+			if _, exist := tc.Status.PD.Members[targetPdName]; !exist {
+				targetPdName = PdPodName(tcName, targetOrdinal)
 			}
+			err = pdClient.TransferPDLeader(targetPdName)
 			if err != nil {
 				return err
 			}
+			controller.RecordOperation(s.deps.Recorder, tc, v1alpha1.PDMemberType, v1.EventTypeNormal, controller.PDLeaderTransferredReason,
+				"transfer pd leader from %s to %s before scale in", memberName, targetPdName)
 		} else {
 			for _, member := range tc.Status.PD.PeerMembers {
 				if member.Health && member.Name != memberName {
@@ -130,6 +131,8 @@ func (s *pdScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet
 					if err != nil {
 						return err
 					}
+					controller.RecordOperation(s.deps.Recorder, tc, v1alpha1.PDMemberType, v1.EventTypeNormal, controller.PDLeaderTransferredReason,
+						"transfer pd leader from %s to peer member %s before scale in", memberName, member.Name)
 					return controller.RequeueErrorf("tc[%s/%s]'s pd pod[%s/%s] is transferring pd leader,can't scale-in now", ns, tcName, ns, memberName)
 				}
 			}
@@ -142,6 +145,8 @@ func (s *pdScaler) ScaleIn(meta metav1.Object, oldSet *apps.StatefulSet, newSet
 		return err
 	}
 	klog.Infof("pdScaler.ScaleIn: delete member %s successfully", memberName)
+	controller.RecordOperation(s.deps.Recorder, tc, v1alpha1.PDMemberType, v1.EventTypeNormal, controller.MemberDeletedReason,
+		"delete pd member %s to scale in", memberName)
 
 	pod, err := s.deps.PodLister.Pods(ns).Get(pdPodName)
 	if err != nil {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 )
@@ -83,10 +84,18 @@ func (u *pdUpgrader) gracefulUpgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stat
 		return nil
 	}
 
+	limit, err := getUpgradePartitionLimit(tc, v1alpha1.PDMemberType, oldSet, newSet)
+	if err != nil {
+		return err
+	}
 	mngerutils.SetUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
+		if i < limit {
+			// the pod is held by the next pause point of the upgrade policy
+			return nil
+		}
 		podName := PdPodName(tcName, i)
 		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
 		if err != nil {
@@ -151,6 +160,8 @@ func (u *pdUpgrader) upgradePDPod(tc *v1alpha1.TidbCluster, ordinal int32, newSe
 				return err
 			}
 			klog.Infof("pd upgrader: transfer pd leader to: %s successfully", targetName)
+			controller.RecordOperation(u.deps.Recorder, tc, v1alpha1.PDMemberType, corev1.EventTypeNormal, controller.PDLeaderTransferredReason,
+				"transfer pd leader from %s to %s before upgrade", upgradePdName, targetName)
 			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s pd member: [%s] is transferring leader to pd member: [%s]", ns, tcName, upgradePdName, targetName)
 		} else {
 			klog.Warningf("pd upgrader: skip to transfer pd leader, because can not find a suitable pd")
@@ -219,10 +219,15 @@ func (m *ticdcMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 	}
 
 	if !templateEqual(newSts, oldSts) || tc.Status.TiCDC.Phase == v1alpha1.UpgradePhase {
-		if err := m.ticdcUpgrader.Upgrade(tc, oldSts, newSts); err != nil {
+		if err := upgradeWithPolicy(m.deps, tc, v1alpha1.TiCDCMemberType, oldSts, newSts, func() error {
+			return m.ticdcUpgrader.Upgrade(tc, oldSts, newSts)
+		}); err != nil {
 			return err
 		}
 	}
+	if err := syncPostUpgradeHook(m.deps, tc, v1alpha1.TiCDCMemberType, oldSts, newSts); err != nil {
+		return err
+	}
 
 	return mngerutils.UpdateStatefulSetWithPrecheck(m.deps, tc, "FailedUpdateTiCDCSTS", newSts, oldSts)
 }
@@ -18,10 +18,11 @@ import (
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
-	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
 
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 )
@@ -75,7 +76,8 @@ func (u *ticdcUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulS
 		return nil
 	}
 
-	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
+	if !isUpgradingInBatches(oldSet) &&
+		(oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil) {
 		// Manually bypass tidb-operator to modify statefulset directly, such as modify ticdc statefulset's RollingUpdate strategy to OnDelete strategy,
 		// or set RollingUpdate to nil, skip tidb-operator's rolling update logic in order to speed up the upgrade in the test environment occasionally.
 		// If we encounter this situation, we will let the native statefulset controller do the upgrade completely, which may be unsafe for upgrading tidb.
@@ -85,8 +87,22 @@ func (u *ticdcUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulS
 		return nil
 	}
 
-	mngerutils.SetUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	limit, err := getUpgradePartitionLimit(tc, v1alpha1.TiCDCMemberType, oldSet, newSet)
+	if err != nil {
+		return err
+	}
+	keepUpgradeStrategy(oldSet, newSet)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	maxUnavailable, err := tc.Spec.TiCDC.GetMaxUnavailable()
+	if err != nil {
+		return err
+	}
+	if switchToBatchUpgrade(tc, v1alpha1.TiCDCMemberType, oldSet, newSet, maxUnavailable) {
+		return nil
+	}
+	var pending []int32
+	unavailable := sets.NewInt32()
+	pods := map[int32]*corev1.Pod{}
 	for i := len(podOrdinals) - 1; i >= 0; i-- {
 		ordinal := podOrdinals[i]
 		podName := ticdcPodName(tcName, ordinal)
@@ -109,36 +125,75 @@ func (u *ticdcUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulS
 			continue
 		}
 
+		pending = append(pending, ordinal)
+		pods[ordinal] = pod
+		if _, exist := tc.Status.TiCDC.Captures[podName]; !exist || !podutil.IsPodReady(pod) {
+			unavailable.Insert(ordinal)
+		}
+	}
+	if len(pending) == 0 {
+		finishBatchUpgrade(oldSet, newSet)
+		return nil
+	}
+
+	batch := nextUpgradeBatch(pending, getUpgradeBatchPartition(oldSet, pending, pods), limit, unavailable, maxUnavailable)
+	if len(batch) == 0 {
+		// the next pod is held by the next pause point of the upgrade policy
+		return nil
+	}
+	// Drain all pods of the batch at the same time, the pods are not upgraded
+	// until all of them complete graceful shutdown.
+	var drainErr error
+	var drained *corev1.Pod
+	for _, ordinal := range batch {
+		pod := pods[ordinal]
+		if pod.DeletionTimestamp != nil {
+			// the pod is drained and being upgraded
+			continue
+		}
+		podName := pod.GetName()
 		support, err := isTiCDCPodSupportGracefulUpgrade(tc, u.deps.CDCControl, u.deps.PodControl, pod, ordinal, "Upgrade")
 		if err != nil {
-			return err
+			if drainErr == nil {
+				drainErr = err
+			}
+			continue
+		}
+		if !support {
+			continue
+		}
+		err = gracefulDrainTiCDC(tc, u.deps.CDCControl, u.deps.PodControl, pod, ordinal, "Upgrade")
+		if err != nil {
+			if drainErr == nil {
+				drainErr = err
+			}
+			continue
 		}
-		if support {
-			err = gracefulDrainTiCDC(tc, u.deps.CDCControl, u.deps.PodControl, pod, ordinal, "Upgrade")
+		klog.Infof("ticdcUpgrade.Upgrade: %s graceful drain TiCDC complete in cluster %s/%s", podName, tc.GetNamespace(), tc.GetName())
+		drained = pod
+	}
+	if drainErr != nil {
+		return drainErr
+	}
+
+	if drained != nil {
+		// To prevent TiCDC service disruption, we need to resign owner
+		// gracefully from the next pod that is going to be upgraded.
+		// If the current batch is the last one to upgrade or the next pod is
+		// held by a pause point, skip resign owner.
+		hasNext := len(pending) > len(batch) && pending[len(batch)] >= limit
+		if hasNext {
+			nextOrd := pending[len(batch)]
+			nextPodName := ticdcPodName(tcName, nextOrd)
+			klog.Infof("ticdcUpgrade.Upgrade: try to graceful resign owner from the next ticdc pod %s in cluster %s/%s", nextPodName, tc.GetNamespace(), tc.GetName())
+			err = gracefulResignOwnerTiCDC(tc, u.deps.CDCControl, u.deps.PodControl, drained, nextPodName, nextOrd, "Upgrade")
 			if err != nil {
 				return err
 			}
-			klog.Infof("ticdcUpgrade.Upgrade: %s graceful drain TiCDC complete in cluster %s/%s", podName, tc.GetNamespace(), tc.GetName())
-			// To prevent TiCDC service disruption, we need to resign owner
-			// gracefully from the next pod that is going to be upgraded.
-			// If the current pod is the last one to upgrade, skip resign owner.
-			hasNext := i-1 >= 0
-			if hasNext {
-				nextOrd := podOrdinals[i-1]
-				nextPodName := ticdcPodName(tcName, nextOrd)
-				klog.Infof("ticdcUpgrade.Upgrade: try to graceful resign owner from the next ticdc pod %s in cluster %s/%s", nextPodName, tc.GetNamespace(), tc.GetName())
-				err = gracefulResignOwnerTiCDC(tc, u.deps.CDCControl, u.deps.PodControl, pod, nextPodName, nextOrd, "Upgrade")
-				if err != nil {
-					return err
-				}
-				klog.Infof("ticdcUpgrade.Upgrade: %s graceful resign owner complete in cluster %s/%s", nextPodName, tc.GetNamespace(), tc.GetName())
-			}
-			klog.Infof("ticdcUpgrade.Upgrade: %s graceful shutdown complete in cluster %s/%s", podName, tc.GetNamespace(), tc.GetName())
+			klog.Infof("ticdcUpgrade.Upgrade: %s graceful resign owner complete in cluster %s/%s", nextPodName, tc.GetNamespace(), tc.GetName())
 		}
-
-		mngerutils.SetUpgradePartition(newSet, ordinal)
-		return nil
+		klog.Infof("ticdcUpgrade.Upgrade: pods %v graceful shutdown complete in cluster %s/%s", batch, tc.GetNamespace(), tc.GetName())
 	}
 
-	return nil
+	return upgradeBatch(u.deps, tc, v1alpha1.TiCDCMemberType, oldSet, newSet, batch, pods)
 }
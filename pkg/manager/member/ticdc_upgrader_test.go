@@ -14,6 +14,7 @@
 package member
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
@@ -24,8 +25,10 @@ import (
 	. "github.com/onsi/gomega"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	podinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/utils/pointer"
 )
@@ -421,6 +424,142 @@ func TestTiCDCUpgrader_Upgrade(t *testing.T) {
 
 }
 
+func TestTiCDCUpgraderUpgradeBatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name           string
+		maxUnavailable int
+		drainErr       int32
+		resignRetry    int32
+		errorExpect    bool
+		switching      bool
+		drained        []int32
+		resigned       []int32
+		deleted        []int32
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+		upgrader, podInformer := newTiCDCUpgrader()
+
+		tc := newTidbClusterForTiCDCUpgrader()
+		version := ticdcCrossUpgradeVersion
+		tc.Spec.TiCDC.Version = &version
+		tc.Spec.TiCDC.Replicas = 4
+		tc.Spec.TiCDC.MaxUnavailable = &intstr.IntOrString{Type: intstr.Int, IntVal: int32(test.maxUnavailable)}
+		tc.Status.TiCDC.StatefulSet = &apps.StatefulSetStatus{CurrentRevision: "1", UpdateRevision: "2", Replicas: 4, CurrentReplicas: 4}
+		tc.Status.TiCDC.Captures = map[string]v1alpha1.TiCDCCapture{}
+		for ordinal := int32(0); ordinal < 4; ordinal++ {
+			podName := ticdcPodName(upgradeTcName, ordinal)
+			tc.Status.TiCDC.Captures[podName] = v1alpha1.TiCDCCapture{PodName: podName}
+			pod := getTiCDCPods()[0]
+			pod.Name = podName
+			podInformer.Informer().GetIndexer().Add(pod)
+		}
+
+		var drained, resigned []int32
+		cdcControl := upgrader.(*ticdcUpgrader).deps.CDCControl.(*controller.FakeTiCDCControl)
+		cdcControl.GetStatusFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (*controller.CaptureStatus, error) {
+			return &controller.CaptureStatus{Version: ticdcCrossUpgradeVersion}, nil
+		}
+		cdcControl.ResignOwnerFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+			if ordinal == test.resignRetry {
+				return false, nil
+			}
+			resigned = append(resigned, ordinal)
+			return true, nil
+		}
+		cdcControl.DrainCaptureFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (int, bool, error) {
+			if ordinal == test.drainErr {
+				return 0, false, fmt.Errorf("failed to drain capture")
+			}
+			drained = append(drained, ordinal)
+			return 0, false, nil
+		}
+		cdcControl.IsHealthyFn = func(tc *v1alpha1.TidbCluster, ordinal int32) (bool, error) {
+			return true, nil
+		}
+
+		oldSet := newStatefulSetForTiCDCUpgrader()
+		oldSet.Spec.Replicas = pointer.Int32Ptr(4)
+		oldSet.Spec.UpdateStrategy.RollingUpdate.Partition = pointer.Int32Ptr(4)
+		newSet := oldSet.DeepCopy()
+		if !test.switching {
+			setBatchUpgradeStrategy(oldSet)
+		}
+		mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		if test.errorExpect {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		g.Expect(drained).To(Equal(test.drained))
+		g.Expect(resigned).To(Equal(test.resigned))
+		g.Expect(isUpgradingInBatches(newSet)).To(BeTrue())
+		var deleted []int32
+		for ordinal := int32(3); ordinal >= 0; ordinal-- {
+			_, err := podInformer.Lister().Pods(corev1.NamespaceDefault).Get(ticdcPodName(upgradeTcName, ordinal))
+			if errors.IsNotFound(err) {
+				deleted = append(deleted, ordinal)
+			}
+		}
+		g.Expect(deleted).To(Equal(test.deleted))
+	}
+
+	tests := []*testcase{
+		{
+			name:           "switch to the OnDelete strategy before draining the batch",
+			maxUnavailable: 2,
+			drainErr:       -1,
+			resignRetry:    -1,
+			switching:      true,
+		},
+		{
+			name:           "drain the batch and resign owner from the first pod of the next batch",
+			maxUnavailable: 2,
+			drainErr:       -1,
+			resignRetry:    -1,
+			drained:        []int32{3, 2},
+			resigned:       []int32{3, 2, 1},
+			deleted:        []int32{3, 2},
+		},
+		{
+			name:           "drain error in the middle of the batch",
+			maxUnavailable: 3,
+			drainErr:       2,
+			resignRetry:    -1,
+			errorExpect:    true,
+			drained:        []int32{3, 1},
+			resigned:       []int32{3, 2, 1},
+		},
+		{
+			name:           "retry resigning owner from the first pod of the next batch",
+			maxUnavailable: 2,
+			drainErr:       -1,
+			resignRetry:    1,
+			errorExpect:    true,
+			drained:        []int32{3, 2},
+			resigned:       []int32{3, 2},
+		},
+		{
+			name:           "skip resigning owner for the last batch",
+			maxUnavailable: 4,
+			drainErr:       -1,
+			resignRetry:    -1,
+			drained:        []int32{3, 2, 1, 0},
+			resigned:       []int32{3, 2, 1, 0},
+			deleted:        []int32{3, 2, 1, 0},
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test)
+	}
+}
+
 func newTiCDCUpgrader() (Upgrader, podinformers.PodInformer) {
 	fakeDeps := controller.NewFakeDependencies()
 	upgrader := &ticdcUpgrader{fakeDeps}
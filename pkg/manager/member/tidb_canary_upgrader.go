@@ -0,0 +1,262 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/autoscaler/autoscaler/calculate"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+)
+
+const (
+	// annoKeyTiDBCanaryBaseConfig is the annotation of the TiDB StatefulSet which keeps the last applied
+	// configuration before the canary upgrade, the canaries are rolled back to it if a health gate fails.
+	annoKeyTiDBCanaryBaseConfig = "tidb.pingcap.com/tidb-canary-base-configuration"
+	// canaryPodsPlaceholder is replaced by the regular expression matching the canaries in the error rate query
+	canaryPodsPlaceholder = "$CANARY_PODS"
+)
+
+// hashPodSpec returns the hash of the pod spec, it is used to find out whether the template
+// rolled back by the canary upgrade is changed
+func hashPodSpec(spec *corev1.PodSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return v1alpha1.HashContents(data), nil
+}
+
+// keepCanaryBaseConfig keeps the last applied configuration of the StatefulSet before the upgrade starts
+// in the annotation of the new StatefulSet
+func keepCanaryBaseConfig(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) {
+	base, ok := oldSet.Annotations[annoKeyTiDBCanaryBaseConfig]
+	stsStatus := tc.Status.TiDB.StatefulSet
+	if !templateEqual(newSet, oldSet) && (stsStatus == nil || stsStatus.UpdateRevision == stsStatus.CurrentRevision) {
+		base, ok = oldSet.Annotations[LastAppliedConfigAnnotation]
+	}
+	if !ok {
+		return
+	}
+	if newSet.Annotations == nil {
+		newSet.Annotations = map[string]string{}
+	}
+	newSet.Annotations[annoKeyTiDBCanaryBaseConfig] = base
+}
+
+// keepCanaryRolledBack keeps the TiDB StatefulSet rolled back by the canary upgrade until the spec of TiDB is changed,
+// it returns true if the StatefulSet is kept rolled back.
+func keepCanaryRolledBack(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) (bool, error) {
+	status := tc.Status.TiDB.CanaryUpgrade
+	if status == nil || status.Phase != v1alpha1.TiDBCanaryRolledBack {
+		return false, nil
+	}
+	hash, err := hashPodSpec(&newSet.Spec.Template.Spec)
+	if err != nil {
+		return false, err
+	}
+	if hash != status.TemplateHash {
+		klog.Infof("tidbcluster: [%s/%s]'s tidb spec is changed after the canary upgrade is rolled back, start a new upgrade", tc.Namespace, tc.Name)
+		tc.Status.TiDB.CanaryUpgrade = nil
+		return false, nil
+	}
+
+	_, podSpec, err := GetLastAppliedConfig(oldSet)
+	if err != nil {
+		return false, err
+	}
+	newSet.Spec.Template.Spec = *podSpec
+	mngerutils.SetUpgradePartition(newSet, 0)
+	return true, nil
+}
+
+// canaryUpgrade upgrades the canaries and evaluates the health gates after the bake time,
+// it returns true if the health gates pass and the rest pods can be upgraded.
+func (u *tidbUpgrader) canaryUpgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet, podOrdinals []int32, limit int32, minReadySeconds int) (bool, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	canary := tc.Spec.TiDB.CanaryUpgrade
+	updateRevision := tc.Status.TiDB.StatefulSet.UpdateRevision
+
+	status := tc.Status.TiDB.CanaryUpgrade
+	if status == nil || status.Revision != updateRevision {
+		replicas, err := canary.GetReplicas(int32(len(podOrdinals)))
+		if err != nil {
+			return false, fmt.Errorf("tidbcluster: [%s/%s] invalid replicas of the tidb canary upgrade: %v", ns, tcName, err)
+		}
+		hash, err := hashPodSpec(&newSet.Spec.Template.Spec)
+		if err != nil {
+			return false, err
+		}
+		status = &v1alpha1.TiDBCanaryUpgradeStatus{
+			Phase:        v1alpha1.TiDBCanaryUpgrading,
+			Revision:     updateRevision,
+			TemplateHash: hash,
+		}
+		// the pods are upgraded from the highest ordinal, so the canaries are the pods of the highest ordinals
+		for i := len(podOrdinals) - 1; i >= len(podOrdinals)-int(replicas); i-- {
+			status.Canaries = append(status.Canaries, tidbPodName(tcName, podOrdinals[i]))
+		}
+		tc.Status.TiDB.CanaryUpgrade = status
+		setCanaryCondition(tc, corev1.ConditionUnknown, utiltidbcluster.CanaryUpgrading,
+			fmt.Sprintf("upgrading canaries %v to revision %s", status.Canaries, updateRevision))
+	}
+	if status.Phase == v1alpha1.TiDBCanaryPassed {
+		return true, nil
+	}
+	if status.StartTime == nil {
+		status.StartTime = &metav1.Time{Time: time.Now()}
+	}
+
+	canaries := sets.NewString(status.Canaries...)
+	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
+		i := podOrdinals[_i]
+		podName := tidbPodName(tcName, i)
+		if !canaries.Has(podName) {
+			continue
+		}
+		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
+		if err != nil {
+			return false, fmt.Errorf("tidbUpgrader.canaryUpgrade: failed to get pods %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+		}
+		revision, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
+		if !exist {
+			return false, controller.RequeueErrorf("tidbcluster: [%s/%s]'s tidb pod: [%s] has no label: %s", ns, tcName, podName, apps.ControllerRevisionHashLabelKey)
+		}
+		if revision != updateRevision {
+			if i < limit {
+				// the canary is held by the next pause point of the upgrade policy
+				return false, nil
+			}
+			return false, u.upgradeTiDBPod(tc, i, newSet)
+		}
+
+		if restarts := getPodRestarts(pod); restarts > canary.GetMaxRestarts() {
+			return false, u.rollbackCanary(tc, oldSet, newSet, fmt.Sprintf("canary %s restarted %d times", podName, restarts))
+		}
+		member, exist := tc.Status.TiDB.Members[podName]
+		healthy := exist && member.Health && podutil.IsPodAvailable(pod, int32(minReadySeconds), metav1.Now())
+		if healthy {
+			continue
+		}
+		if status.Phase == v1alpha1.TiDBCanaryBaking {
+			return false, u.rollbackCanary(tc, oldSet, newSet, fmt.Sprintf("canary %s is unhealthy during the bake time", podName))
+		}
+		if deadline := canary.GetProgressDeadline(); time.Since(status.StartTime.Time) > deadline {
+			// e.g. the image of the canary fails to be pulled or the canary is never ready
+			return false, u.rollbackCanary(tc, oldSet, newSet, fmt.Sprintf("canary %s is not healthy within the progress deadline %s", podName, deadline))
+		}
+		return false, controller.RequeueErrorf("tidbcluster: [%s/%s]'s tidb canary: [%s] is not healthy", ns, tcName, podName)
+	}
+
+	if status.Phase == v1alpha1.TiDBCanaryUpgrading {
+		status.Phase = v1alpha1.TiDBCanaryBaking
+		status.BakeStartTime = &metav1.Time{Time: time.Now()}
+		setCanaryCondition(tc, corev1.ConditionUnknown, utiltidbcluster.CanaryBaking,
+			fmt.Sprintf("canaries %v are healthy, baking for %s", status.Canaries, canary.GetBakeTime()))
+	}
+	if left := canary.GetBakeTime() - time.Since(status.BakeStartTime.Time); left > 0 {
+		return false, controller.RequeueErrorf("tidbcluster: [%s/%s]'s tidb canaries are baking, %s left", ns, tcName, left.Round(time.Second))
+	}
+
+	if canary.ErrorRate != nil {
+		exceeded, err := evaluateCanaryErrorRate(canary.ErrorRate, status.Canaries)
+		if err != nil {
+			return false, fmt.Errorf("tidbcluster: [%s/%s] evaluate the error rate of the tidb canaries failed: %v", ns, tcName, err)
+		}
+		if exceeded != "" {
+			return false, u.rollbackCanary(tc, oldSet, newSet, exceeded)
+		}
+	}
+
+	status.Phase = v1alpha1.TiDBCanaryPassed
+	setCanaryCondition(tc, corev1.ConditionTrue, utiltidbcluster.CanaryPassed,
+		fmt.Sprintf("canaries %v passed the health gates, upgrading the rest pods", status.Canaries))
+	u.deps.Recorder.Eventf(tc, corev1.EventTypeNormal, "TiDBCanaryPassed", "canaries %v of revision %s passed the health gates", status.Canaries, updateRevision)
+	return true, nil
+}
+
+// rollbackCanary rolls back the canaries to the template before the upgrade
+func (u *tidbUpgrader) rollbackCanary(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet, reason string) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	base, ok := oldSet.Annotations[annoKeyTiDBCanaryBaseConfig]
+	if !ok {
+		return fmt.Errorf("tidbcluster: [%s/%s]'s tidb canary upgrade failed: %s, but the template before the upgrade is not found", ns, tcName, reason)
+	}
+	baseSet := &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   oldSet.Namespace,
+			Name:        oldSet.Name,
+			Annotations: map[string]string{LastAppliedConfigAnnotation: base},
+		},
+	}
+	_, podSpec, err := GetLastAppliedConfig(baseSet)
+	if err != nil {
+		return err
+	}
+	newSet.Spec.Template.Spec = *podSpec
+	// the canaries are recreated by the template before the upgrade
+	mngerutils.SetUpgradePartition(newSet, 0)
+
+	status := tc.Status.TiDB.CanaryUpgrade
+	status.Phase = v1alpha1.TiDBCanaryRolledBack
+	status.Message = reason
+	setCanaryCondition(tc, corev1.ConditionFalse, utiltidbcluster.CanaryRolledBack, reason)
+	klog.Warningf("tidbcluster: [%s/%s]'s tidb canary upgrade is rolled back: %s", ns, tcName, reason)
+	u.deps.Recorder.Eventf(tc, corev1.EventTypeWarning, "TiDBCanaryRolledBack", "canaries %v are rolled back: %s", status.Canaries, reason)
+	return nil
+}
+
+// evaluateCanaryErrorRate returns the reason if the error rate of the canaries exceeds the threshold
+func evaluateCanaryErrorRate(errorRate *v1alpha1.TiDBCanaryErrorRate, canaries []string) (string, error) {
+	query := strings.ReplaceAll(errorRate.Query, canaryPodsPlaceholder, strings.Join(canaries, "|"))
+	values, err := calculate.QueryValues(nil, errorRate.PrometheusAddress, query)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range values {
+		if v > errorRate.Threshold {
+			return fmt.Sprintf("error rate %v of canaries exceeds the threshold %v", v, errorRate.Threshold), nil
+		}
+	}
+	return "", nil
+}
+
+// getPodRestarts returns the total restarts of the containers of the pod
+func getPodRestarts(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return restarts
+}
+
+func setCanaryCondition(tc *v1alpha1.TidbCluster, status corev1.ConditionStatus, reason, message string) {
+	cond := utiltidbcluster.NewTidbClusterCondition(v1alpha1.TiDBCanaryUpgradeCondition, status, reason, message)
+	utiltidbcluster.SetTidbClusterCondition(&tc.Status, *cond)
+}
@@ -0,0 +1,227 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+	utiltidbcluster "github.com/pingcap/tidb-operator/pkg/util/tidbcluster"
+
+	. "github.com/onsi/gomega"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+)
+
+func newCanaryStatefulSets(g *GomegaWithT) (*apps.StatefulSet, *apps.StatefulSet) {
+	oldSet := newStatefulSetForTiDBUpgrader()
+	baseSet := oldSet.DeepCopy()
+	baseSet.Spec.Template.Spec.Containers[0].Image = "tidb-old-image"
+	base, err := json.Marshal(baseSet.Spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	oldSet.Annotations = map[string]string{annoKeyTiDBCanaryBaseConfig: string(base)}
+	newSet := oldSet.DeepCopy()
+	newSet.Annotations = nil
+	mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+	return oldSet, newSet
+}
+
+func newPrometheusServer(value string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"instance":"upgrader-tidb-1"},"value":[1700000000,"%s"]}]}}`, value)
+	}))
+}
+
+func TestTiDBCanaryUpgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+	upgrader, _, podInformer := newTiDBUpgrader()
+	for _, pod := range getTiDBPods() {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+	server := newPrometheusServer("0.5")
+	defer server.Close()
+
+	tc := newTidbClusterForTiDBUpgrader()
+	tc.Spec.TiDB.CanaryUpgrade = &v1alpha1.TiDBCanaryUpgrade{
+		BakeTime: &metav1.Duration{Duration: time.Hour},
+		ErrorRate: &v1alpha1.TiDBCanaryErrorRate{
+			PrometheusAddress: server.URL,
+			Query:             `rate(tidb_server_execute_error_total{instance=~"$CANARY_PODS"}[1m])`,
+			Threshold:         0.1,
+		},
+	}
+
+	// the upgraded canary is healthy and starts baking
+	oldSet, newSet := newCanaryStatefulSets(g)
+	err := upgrader.Upgrade(tc, oldSet, newSet)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	status := tc.Status.TiDB.CanaryUpgrade
+	g.Expect(status.Phase).To(Equal(v1alpha1.TiDBCanaryBaking))
+	g.Expect(status.Canaries).To(Equal([]string{"upgrader-tidb-1"}))
+	g.Expect(status.Revision).To(Equal("2"))
+	g.Expect(newSet.Annotations).To(HaveKeyWithValue(annoKeyTiDBCanaryBaseConfig, oldSet.Annotations[annoKeyTiDBCanaryBaseConfig]))
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TiDBCanaryUpgradeCondition)
+	g.Expect(cond.Status).To(Equal(corev1.ConditionUnknown))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.CanaryBaking))
+
+	// the canary is rolled back after the error rate exceeds the threshold
+	status.BakeStartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+	oldSet, newSet = newCanaryStatefulSets(g)
+	g.Expect(upgrader.Upgrade(tc, oldSet, newSet)).To(Succeed())
+	g.Expect(status.Phase).To(Equal(v1alpha1.TiDBCanaryRolledBack))
+	g.Expect(status.Message).To(ContainSubstring("exceeds the threshold"))
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-old-image"))
+	g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+	cond = utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TiDBCanaryUpgradeCondition)
+	g.Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(cond.Reason).To(Equal(utiltidbcluster.CanaryRolledBack))
+
+	// the template is kept rolled back until the spec is changed
+	rolledBackSet := newStatefulSetForTiDBUpgrader()
+	rolledBackSet.Spec.Template.Spec.Containers[0].Image = "tidb-old-image"
+	mngerutils.SetStatefulSetLastAppliedConfigAnnotation(rolledBackSet)
+	tc.Status.TiDB.Phase = v1alpha1.NormalPhase
+	newSet = newStatefulSetForTiDBUpgrader()
+	g.Expect(upgrader.Upgrade(tc, rolledBackSet, newSet)).To(Succeed())
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-old-image"))
+	g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.NormalPhase))
+
+	newSet = newStatefulSetForTiDBUpgrader()
+	newSet.Spec.Template.Spec.Containers[0].Image = "tidb-new-image"
+	g.Expect(upgrader.Upgrade(tc, rolledBackSet, newSet)).To(Succeed())
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-new-image"))
+	g.Expect(tc.Status.TiDB.CanaryUpgrade).To(BeNil())
+	g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.UpgradePhase))
+}
+
+func TestTiDBCanaryUpgradePassed(t *testing.T) {
+	g := NewGomegaWithT(t)
+	upgrader, _, podInformer := newTiDBUpgrader()
+	for _, pod := range getTiDBPods() {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+	server := newPrometheusServer("0.01")
+	defer server.Close()
+
+	tc := newTidbClusterForTiDBUpgrader()
+	tc.Spec.TiDB.CanaryUpgrade = &v1alpha1.TiDBCanaryUpgrade{
+		ErrorRate: &v1alpha1.TiDBCanaryErrorRate{
+			PrometheusAddress: server.URL,
+			Query:             `rate(tidb_server_execute_error_total{instance=~"$CANARY_PODS"}[1m])`,
+			Threshold:         0.1,
+		},
+	}
+	tc.Status.TiDB.CanaryUpgrade = &v1alpha1.TiDBCanaryUpgradeStatus{
+		Phase:         v1alpha1.TiDBCanaryBaking,
+		Revision:      "2",
+		Canaries:      []string{"upgrader-tidb-1"},
+		BakeStartTime: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+	}
+
+	// the rest pods are upgraded after the health gates pass
+	oldSet, newSet := newCanaryStatefulSets(g)
+	g.Expect(upgrader.Upgrade(tc, oldSet, newSet)).To(Succeed())
+	g.Expect(tc.Status.TiDB.CanaryUpgrade.Phase).To(Equal(v1alpha1.TiDBCanaryPassed))
+	g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+	cond := utiltidbcluster.GetTidbClusterCondition(tc.Status, v1alpha1.TiDBCanaryUpgradeCondition)
+	g.Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+}
+
+func TestTiDBCanaryUpgradeRestarts(t *testing.T) {
+	g := NewGomegaWithT(t)
+	upgrader, _, podInformer := newTiDBUpgrader()
+	pods := getTiDBPods()
+	pods[1].Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "tidb", RestartCount: 2}}
+	for _, pod := range pods {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+
+	tc := newTidbClusterForTiDBUpgrader()
+	tc.Spec.TiDB.CanaryUpgrade = &v1alpha1.TiDBCanaryUpgrade{MaxRestarts: pointer.Int32Ptr(1)}
+
+	// the canary restarted more than the max restarts is rolled back
+	oldSet, newSet := newCanaryStatefulSets(g)
+	g.Expect(upgrader.Upgrade(tc, oldSet, newSet)).To(Succeed())
+	g.Expect(tc.Status.TiDB.CanaryUpgrade.Phase).To(Equal(v1alpha1.TiDBCanaryRolledBack))
+	g.Expect(tc.Status.TiDB.CanaryUpgrade.Message).To(Equal("canary upgrader-tidb-1 restarted 2 times"))
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-old-image"))
+}
+
+func TestTiDBCanaryUpgradeProgressDeadline(t *testing.T) {
+	g := NewGomegaWithT(t)
+	upgrader, _, podInformer := newTiDBUpgrader()
+	pods := getTiDBPods()
+	pods[1].Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}
+	pods[1].Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "tidb",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: ImagePullBackOff}},
+	}}
+	for _, pod := range pods {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+
+	tc := newTidbClusterForTiDBUpgrader()
+	tc.Spec.TiDB.CanaryUpgrade = &v1alpha1.TiDBCanaryUpgrade{ProgressDeadline: &metav1.Duration{Duration: 10 * time.Minute}}
+
+	// wait for the canary to become healthy before the progress deadline
+	oldSet, newSet := newCanaryStatefulSets(g)
+	err := upgrader.Upgrade(tc, oldSet, newSet)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	status := tc.Status.TiDB.CanaryUpgrade
+	g.Expect(status.Phase).To(Equal(v1alpha1.TiDBCanaryUpgrading))
+	g.Expect(status.StartTime).NotTo(BeNil())
+
+	// the canary failing to pull the image is rolled back after the progress deadline
+	status.StartTime = &metav1.Time{Time: time.Now().Add(-20 * time.Minute)}
+	oldSet, newSet = newCanaryStatefulSets(g)
+	g.Expect(upgrader.Upgrade(tc, oldSet, newSet)).To(Succeed())
+	g.Expect(status.Phase).To(Equal(v1alpha1.TiDBCanaryRolledBack))
+	g.Expect(status.Message).To(Equal("canary upgrader-tidb-1 is not healthy within the progress deadline 10m0s"))
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-old-image"))
+	g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+}
+
+func TestTiDBCanaryUpgradePausePoint(t *testing.T) {
+	g := NewGomegaWithT(t)
+	upgrader, _, podInformer := newTiDBUpgrader()
+	for _, pod := range getTiDBPods() {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+
+	tc := newTidbClusterForTiDBUpgrader()
+	tc.Spec.TiDB.CanaryUpgrade = &v1alpha1.TiDBCanaryUpgrade{Replicas: &intstr.IntOrString{Type: intstr.Int, IntVal: 2}}
+	tc.Spec.UpgradePolicy = &v1alpha1.UpgradePolicy{
+		PausePoints: []v1alpha1.UpgradePausePoint{
+			{Name: "tidb-one", Component: v1alpha1.TiDBMemberType, AfterPods: 1},
+		},
+	}
+
+	// the canary beyond the pause point is not upgraded until the pause point is approved
+	oldSet, newSet := newCanaryStatefulSets(g)
+	g.Expect(upgrader.Upgrade(tc, oldSet, newSet)).To(Succeed())
+	status := tc.Status.TiDB.CanaryUpgrade
+	g.Expect(status.Phase).To(Equal(v1alpha1.TiDBCanaryUpgrading))
+	g.Expect(status.Canaries).To(Equal([]string{"upgrader-tidb-1", "upgrader-tidb-0"}))
+	g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(1)))
+}
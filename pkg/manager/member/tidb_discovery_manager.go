@@ -21,6 +21,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/klog/v2"
@@ -86,6 +87,7 @@ func (m *realTidbDiscoveryManager) Reconcile(obj client.Object) error {
 	}
 
 	meta, _ := getDiscoveryMeta(metaObj, controller.DiscoveryMemberName)
+	stateMeta, _ := getDiscoveryMeta(metaObj, controller.DiscoveryStateMemberName)
 	// Ensure RBAC
 	_, err := m.deps.TypedControl.CreateOrUpdateRole(obj, &rbacv1.Role{
 		ObjectMeta: meta,
@@ -96,6 +98,12 @@ func (m *realTidbDiscoveryManager) Reconcile(obj client.Object) error {
 				Resources: []string{"secrets"},
 				Verbs:     []string{"get", "list", "watch"},
 			},
+			{
+				APIGroups:     []string{corev1.GroupName},
+				Resources:     []string{"configmaps"},
+				ResourceNames: []string{stateMeta.Name},
+				Verbs:         []string{"get", "update"},
+			},
 		},
 	})
 	if err != nil {
@@ -122,6 +130,16 @@ func (m *realTidbDiscoveryManager) Reconcile(obj client.Object) error {
 	if err != nil {
 		return controller.RequeueErrorf("error creating or updating discovery rolebinding: %v", err)
 	}
+	// The ConfigMap persists the discovery state, it is only created here and updated by the discovery service
+	exist, err := m.deps.TypedControl.Exist(client.ObjectKey{Namespace: stateMeta.Namespace, Name: stateMeta.Name}, &corev1.ConfigMap{})
+	if err != nil {
+		return controller.RequeueErrorf("error checking discovery state configmap: %v", err)
+	}
+	if !exist {
+		if err := m.deps.TypedControl.Create(obj, &corev1.ConfigMap{ObjectMeta: stateMeta}); err != nil && !errors.IsAlreadyExists(err) {
+			return controller.RequeueErrorf("error creating discovery state configmap: %v", err)
+		}
+	}
 	d, err := m.getTidbDiscoveryDeployment(metaObj)
 	if err != nil {
 		return controller.RequeueErrorf("error generating discovery deployment: %v", err)
@@ -191,6 +209,7 @@ func (m *realTidbDiscoveryManager) getTidbDiscoveryDeployment(obj metav1.Object)
 	}
 
 	meta, l := getDiscoveryMeta(obj, controller.DiscoveryMemberName)
+	stateMeta, _ := getDiscoveryMeta(obj, controller.DiscoveryStateMemberName)
 
 	envs := []corev1.EnvVar{
 		{
@@ -209,6 +228,10 @@ func (m *realTidbDiscoveryManager) getTidbDiscoveryDeployment(obj metav1.Object)
 			Name:  "TC_NAME",
 			Value: obj.GetName(), // for DmCluster, we still name it as TC_NAME because only ProxyServer use it now.
 		},
+		{
+			Name:  "DISCOVERY_STATE_CONFIGMAP",
+			Value: stateMeta.Name,
+		},
 	}
 	envs = util.AppendEnv(envs, baseSpec.Env())
 	volMounts := []corev1.VolumeMount{}
@@ -91,6 +91,8 @@ func (f *tidbFailover) Failover(tc *v1alpha1.TidbCluster) error {
 			}
 			msg := fmt.Sprintf("tidb[%s] is unhealthy", tidbMember.Name)
 			f.deps.Recorder.Event(tc, corev1.EventTypeWarning, unHealthEventReason, fmt.Sprintf(unHealthEventMsgPattern, "tidb", tidbMember.Name, msg))
+			controller.RecordOperation(f.deps.Recorder, tc, v1alpha1.TiDBMemberType, corev1.EventTypeWarning, controller.FailoverPodCreatedReason,
+				"mark pod %s as failure member and create a new pod for failover", tidbMember.Name)
 			break
 		}
 	}
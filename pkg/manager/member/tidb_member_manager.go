@@ -306,10 +306,15 @@ func (m *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbC
 	}
 
 	if !templateEqual(newTiDBSet, oldTiDBSet) || tc.Status.TiDB.Phase == v1alpha1.UpgradePhase {
-		if err := m.tidbUpgrader.Upgrade(tc, oldTiDBSet, newTiDBSet); err != nil {
+		if err := upgradeWithPolicy(m.deps, tc, v1alpha1.TiDBMemberType, oldTiDBSet, newTiDBSet, func() error {
+			return m.tidbUpgrader.Upgrade(tc, oldTiDBSet, newTiDBSet)
+		}); err != nil {
 			return err
 		}
 	}
+	if err := syncPostUpgradeHook(m.deps, tc, v1alpha1.TiDBMemberType, oldTiDBSet, newTiDBSet); err != nil {
+		return err
+	}
 
 	return mngerutils.UpdateStatefulSetWithPrecheck(m.deps, tc, "FailedUpdateTiDBSTS", newTiDBSet, oldTiDBSet)
 }
@@ -25,6 +25,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 )
@@ -56,6 +57,18 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
+	// the limit is got before the template of the new statefulset is changed by the canary upgrade
+	limit, err := getUpgradePartitionLimit(tc, v1alpha1.TiDBMemberType, oldSet, newSet)
+	if err != nil {
+		return err
+	}
+
+	if tc.Spec.TiDB.CanaryUpgrade != nil {
+		keepCanaryBaseConfig(tc, oldSet, newSet)
+	} else {
+		tc.Status.TiDB.CanaryUpgrade = nil
+	}
+
 	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.Status.PD.Phase == v1alpha1.ScalePhase ||
 		tc.Status.TiKV.Phase == v1alpha1.UpgradePhase || tc.Status.TiKV.Phase == v1alpha1.ScalePhase ||
 		tc.Status.TiFlash.Phase == v1alpha1.UpgradePhase || tc.Status.TiFlash.Phase == v1alpha1.ScalePhase ||
@@ -75,6 +88,10 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 		return nil
 	}
 
+	if rolledBack, err := keepCanaryRolledBack(tc, oldSet, newSet); err != nil || rolledBack {
+		return err
+	}
+
 	tc.Status.TiDB.Phase = v1alpha1.UpgradePhase
 	if !templateEqual(newSet, oldSet) {
 		return nil
@@ -84,7 +101,8 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 		return nil
 	}
 
-	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
+	if !isUpgradingInBatches(oldSet) &&
+		(oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil) {
 		// Manually bypass tidb-operator to modify statefulset directly, such as modify tidb statefulset's RollingUpdate strategy to OnDelete strategy,
 		// or set RollingUpdate to nil, skip tidb-operator's rolling update logic in order to speed up the upgrade in the test environment occasionally.
 		// If we encounter this situation, we will let the native statefulset controller do the upgrade completely, which may be unsafe for upgrading tidb.
@@ -105,8 +123,24 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 		}
 	}
 
-	mngerutils.SetUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	keepUpgradeStrategy(oldSet, newSet)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	if tc.Spec.TiDB.CanaryUpgrade != nil {
+		if passed, err := u.canaryUpgrade(tc, oldSet, newSet, podOrdinals, limit, minReadySeconds); err != nil || !passed {
+			return err
+		}
+	}
+
+	maxUnavailable, err := tc.Spec.TiDB.GetMaxUnavailable()
+	if err != nil {
+		return err
+	}
+	if switchToBatchUpgrade(tc, v1alpha1.TiDBMemberType, oldSet, newSet, maxUnavailable) {
+		return nil
+	}
+	var pending []int32
+	unavailable := sets.NewInt32()
+	pods := map[int32]*corev1.Pod{}
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
 		podName := tidbPodName(tcName, i)
@@ -133,10 +167,26 @@ func (u *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 			}
 			continue
 		}
-		return u.upgradeTiDBPod(tc, i, newSet)
+
+		pending = append(pending, i)
+		pods[i] = pod
+		// the pod to be gracefully shut down by user is taken as unavailable
+		if member, exist := tc.Status.TiDB.Members[podName]; !exist || !member.Health || !podutil.IsPodReady(pod) ||
+			pod.Annotations[v1alpha1.TiDBGracefulShutdownAnnKey] != "" {
+			unavailable.Insert(i)
+		}
+	}
+	if len(pending) == 0 {
+		finishBatchUpgrade(oldSet, newSet)
+		return nil
 	}
 
-	return nil
+	batch := nextUpgradeBatch(pending, getUpgradeBatchPartition(oldSet, pending, pods), limit, unavailable, maxUnavailable)
+	if len(batch) == 0 {
+		// the next pod is held by the next pause point of the upgrade policy
+		return nil
+	}
+	return upgradeBatch(u.deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet, batch, pods)
 }
 
 func (u *tidbUpgrader) upgradeTiDBPod(tc *v1alpha1.TidbCluster, ordinal int32, newSet *apps.StatefulSet) error {
@@ -15,6 +15,7 @@ package member
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/label"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -24,8 +25,10 @@ import (
 	. "github.com/onsi/gomega"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	podinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/utils/pointer"
 )
@@ -256,6 +259,110 @@ func TestTiDBUpgrader_Upgrade(t *testing.T) {
 
 }
 
+func TestTiDBUpgraderBatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	upgrader, _, podInformer := newTiDBUpgrader()
+	tc := newTidbClusterForTiDBUpgrader()
+	tc.Spec.TiDB.Replicas = 5
+	maxUnavailable := intstr.FromString("40%")
+	tc.Spec.TiDB.MaxUnavailable = &maxUnavailable
+	pods := getTiDBPods()
+	for i := int32(2); i < 5; i++ {
+		pod := pods[0].DeepCopy()
+		pod.Name = tidbPodName(upgradeTcName, i)
+		pods = append(pods, pod)
+		tc.Status.TiDB.Members[pod.Name] = v1alpha1.TiDBMember{Name: pod.Name, Health: true}
+	}
+	pods[4].Annotations = map[string]string{v1alpha1.TiDBGracefulShutdownAnnKey: v1alpha1.TiDBPodDeletionDeletePod}
+	for _, pod := range pods {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+
+	oldSet := newStatefulSetForTiDBUpgrader()
+	oldSet.Spec.Replicas = pointer.Int32Ptr(5)
+	oldSet.Spec.UpdateStrategy.RollingUpdate.Partition = pointer.Int32Ptr(5)
+	mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+	getPod := func(ordinal int32) (*corev1.Pod, error) {
+		return podInformer.Lister().Pods(corev1.NamespaceDefault).Get(tidbPodName(upgradeTcName, ordinal))
+	}
+	upgrade := func() (*apps.StatefulSet, error) {
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.UpdateStrategy = apps.StatefulSetUpdateStrategy{
+			Type:          apps.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{Partition: pointer.Int32Ptr(5)},
+		}
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		return newSet, err
+	}
+
+	// the statefulset is switched to the OnDelete strategy before any pod is deleted
+	newSet, err := upgrade()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(newSet.Spec.UpdateStrategy.Type).To(Equal(apps.OnDeleteStatefulSetStrategyType))
+	g.Expect(isUpgradingInBatches(newSet)).To(BeTrue())
+	for ordinal := int32(0); ordinal < 5; ordinal++ {
+		_, err := getPod(ordinal)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	oldSet = newSet
+
+	// pod 1 is upgraded, pod 4 to be gracefully shut down takes up the quota of the batch with pod 3,
+	// both of them are deleted to be replaced at the same time
+	newSet, err = upgrade()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(isUpgradingInBatches(newSet)).To(BeTrue())
+	for _, ordinal := range []int32{3, 4} {
+		_, err := getPod(ordinal)
+		g.Expect(errors.IsNotFound(err)).To(BeTrue())
+	}
+	for _, ordinal := range []int32{0, 2} {
+		_, err := getPod(ordinal)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	// wait for the batch in flight
+	pods[4].Labels[apps.ControllerRevisionHashLabelKey] = "2"
+	pods[4].Annotations = nil
+	podInformer.Informer().GetIndexer().Add(pods[4])
+	terminating := pods[3].DeepCopy()
+	terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	podInformer.Informer().GetIndexer().Add(terminating)
+	_, err = upgrade()
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = getPod(2)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// wait for all pods of the batch to be healthy
+	pods[3].Labels[apps.ControllerRevisionHashLabelKey] = "2"
+	podInformer.Informer().GetIndexer().Update(pods[3])
+	tc.Status.TiDB.Members[pods[3].Name] = v1alpha1.TiDBMember{Name: pods[3].Name, Health: false}
+	_, err = upgrade()
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	_, err = getPod(2)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// pods 2 and 0 are replaced in the next batch at the same time
+	tc.Status.TiDB.Members[pods[3].Name] = v1alpha1.TiDBMember{Name: pods[3].Name, Health: true}
+	_, err = upgrade()
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, ordinal := range []int32{0, 2} {
+		_, err := getPod(ordinal)
+		g.Expect(errors.IsNotFound(err)).To(BeTrue())
+	}
+
+	// the statefulset is switched back to the RollingUpdate strategy after all pods are upgraded
+	for _, ordinal := range []int32{0, 2} {
+		pods[ordinal].Labels[apps.ControllerRevisionHashLabelKey] = "2"
+		podInformer.Informer().GetIndexer().Add(pods[ordinal])
+	}
+	newSet, err = upgrade()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(newSet.Spec.UpdateStrategy.Type).To(Equal(apps.RollingUpdateStatefulSetStrategyType))
+	g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+	g.Expect(newSet.Annotations).NotTo(HaveKey(annoKeyBatchUpgrade))
+}
+
 func newTiDBUpgrader() (Upgrader, *controller.FakeTiDBControl, podinformers.PodInformer) {
 	fakeDeps := controller.NewFakeDependencies()
 	upgrader := &tidbUpgrader{fakeDeps}
@@ -285,10 +285,15 @@ func (m *tiflashMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 	}
 
 	if !templateEqual(newSet, oldSet) || tc.Status.TiFlash.Phase == v1alpha1.UpgradePhase {
-		if err := m.upgrader.Upgrade(tc, oldSet, newSet); err != nil {
+		if err := upgradeWithPolicy(m.deps, tc, v1alpha1.TiFlashMemberType, oldSet, newSet, func() error {
+			return m.upgrader.Upgrade(tc, oldSet, newSet)
+		}); err != nil {
 			return err
 		}
 	}
+	if err := syncPostUpgradeHook(m.deps, tc, v1alpha1.TiFlashMemberType, oldSet, newSet); err != nil {
+		return err
+	}
 
 	return mngerutils.UpdateStatefulSetWithPrecheck(m.deps, tc, "FailedUpdateTiFlashSTS", newSet, oldSet)
 }
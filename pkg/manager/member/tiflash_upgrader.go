@@ -105,10 +105,18 @@ func (u *tiflashUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Statefu
 		}
 	}
 
+	limit, err := getUpgradePartitionLimit(tc, v1alpha1.TiFlashMemberType, oldSet, newSet)
+	if err != nil {
+		return err
+	}
 	mngerutils.SetUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
+		if i < limit {
+			// the pod is held by the next pause point of the upgrade policy
+			return nil
+		}
 		store := getTiFlashStoreByOrdinal(tc.GetName(), tc.Status.TiFlash, i)
 		if store == nil {
 			mngerutils.SetUpgradePartition(newSet, i)
@@ -267,10 +267,15 @@ func (m *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbClust
 	}
 
 	if !templateEqual(newSet, oldSet) || tc.Status.TiKV.Phase == v1alpha1.UpgradePhase {
-		if err := m.upgrader.Upgrade(tc, oldSet, newSet); err != nil {
+		if err := upgradeWithPolicy(m.deps, tc, v1alpha1.TiKVMemberType, oldSet, newSet, func() error {
+			return m.upgrader.Upgrade(tc, oldSet, newSet)
+		}); err != nil {
 			return err
 		}
 	}
+	if err := syncPostUpgradeHook(m.deps, tc, v1alpha1.TiKVMemberType, oldSet, newSet); err != nil {
+		return err
+	}
 
 	return mngerutils.UpdateStatefulSetWithPrecheck(m.deps, tc, "FailedUpdateTiKVSTS", newSet, oldSet)
 }
@@ -201,6 +201,8 @@ func (s *tikvScaler) scaleInOne(tc *v1alpha1.TidbCluster, skipPreCheck bool, upT
 					return deletedUpStore, err
 				}
 				klog.Infof("tikvScaler.ScaleIn: delete store %d for tikv %s/%s successfully", id, ns, podName)
+				controller.RecordOperation(s.deps.Recorder, tc, v1alpha1.TiKVMemberType, v1.EventTypeNormal, controller.StoreDeletedReason,
+					"delete store %d of pod %s to scale in", id, podName)
 				if state == v1alpha1.TiKVStateUp {
 					deletedUpStore++
 				}
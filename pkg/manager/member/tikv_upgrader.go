@@ -110,7 +110,16 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 		return nil
 	}
 
-	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
+	// The statefulset uses the OnDelete update strategy during the upgrade by zone,
+	// unless it is configured by the spec of TiKV.
+	upgradeByZone := tc.Spec.TiKV.ShouldUpgradeByZone() && newSet.Spec.UpdateStrategy.Type != apps.OnDeleteStatefulSetStrategyType
+	if !upgradeByZone && isUpgradingByZone(oldSet) && newSet.Spec.UpdateStrategy.Type != apps.OnDeleteStatefulSetStrategyType {
+		// the zone-aware upgrade is turned off during the upgrade, the OnDelete strategy set by the upgrader is not
+		// taken as modified manually, otherwise no pod would be upgraded any more
+		stopUpgradeByZone(tc, oldSet, newSet)
+		return nil
+	}
+	if !upgradeByZone && (oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil) {
 		// Manually bypass tidb-operator to modify statefulset directly, such as modify tikv statefulset's RollingUpdate strategy to OnDelete strategy,
 		// or set RollingUpdate to nil, skip tidb-operator's rolling update logic in order to speed up the upgrade in the test environment occasionally.
 		// If we encounter this situation, we will let the native statefulset controller do the upgrade completely, which may be unsafe for upgrading tikv.
@@ -131,10 +140,22 @@ func (u *tikvUpgrader) Upgrade(meta metav1.Object, oldSet *apps.StatefulSet, new
 		}
 	}
 
+	if upgradeByZone {
+		return u.upgradeByZone(tc, oldSet, newSet, minReadySeconds)
+	}
+
+	limit, err := getUpgradePartitionLimit(tc, v1alpha1.TiKVMemberType, oldSet, newSet)
+	if err != nil {
+		return err
+	}
 	mngerutils.SetUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
+		if i < limit {
+			// the pod is held by the next pause point of the upgrade policy
+			return nil
+		}
 		store := getStoreByOrdinal(meta.GetName(), *status, i)
 		if store == nil {
 			mngerutils.SetUpgradePartition(newSet, i)
@@ -393,6 +414,8 @@ func (u *tikvUpgrader) beginEvictLeader(tc *v1alpha1.TidbCluster, storeID uint64
 
 	klog.Infof("beginEvictLeader: set pod %s/%s annotation to record info successfully, annos:%v",
 		ns, podName, annosToRecordInfo)
+	controller.RecordOperation(u.deps.Recorder, tc, v1alpha1.TiKVMemberType, corev1.EventTypeNormal, controller.EvictLeaderBeginReason,
+		"begin to evict leaders from store %d of pod %s before upgrade", storeID, podName)
 	return nil
 }
 
@@ -418,6 +441,8 @@ func (u *tikvUpgrader) endEvictLeader(tc *v1alpha1.TidbCluster, storeID uint64,
 				ns, podName, annoKeyEvictLeaderEndTime, err)
 			return fmt.Errorf("end evict leader for store %d failed: %v", storeID, err)
 		}
+		controller.RecordOperation(u.deps.Recorder, tc, v1alpha1.TiKVMemberType, corev1.EventTypeNormal, controller.EvictLeaderEndReason,
+			"end evicting leaders from store %d of pod %s after upgrade", storeID, podName)
 	}
 
 	return nil
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/tikvapi"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -284,6 +285,41 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(1)))
 			},
 		},
+		{
+			name: "end leader eviction of pod 2 and hold pod 1 by the pause point",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.UpgradePolicy = &v1alpha1.UpgradePolicy{
+					PausePoints: []v1alpha1.UpgradePausePoint{
+						{Name: "tikv-one", Component: v1alpha1.TiKVMemberType, AfterPods: 1},
+					},
+				}
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+				tc.Status.TiKV.Synced = true
+				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
+				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
+				store := tc.Status.TiKV.Stores["3"]
+				store.LeaderCountBeforeUpgrade = pointer.Int32Ptr(100)
+				tc.Status.TiKV.Stores["3"] = store
+			},
+			changeOldSet: func(oldSet *apps.StatefulSet) {
+				mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+				oldSet.Status.CurrentReplicas = 2
+				oldSet.Status.UpdatedReplicas = 1
+				oldSet.Spec.UpdateStrategy.RollingUpdate.Partition = pointer.Int32Ptr(2)
+			},
+			beginEvictLeaderErr: false,
+			endEvictLeaderErr:   false,
+			updatePodErr:        false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+				g.Expect(pods[TikvPodName(upgradeTcName, 2)].Annotations).To(HaveKey(annoKeyEvictLeaderEndTime))
+				g.Expect(pods[TikvPodName(upgradeTcName, 1)].Annotations).NotTo(HaveKey(annoKeyEvictLeaderBeginTime))
+			},
+		},
 		{
 			name: "newSet template changed",
 			changeFn: func(tc *v1alpha1.TidbCluster) {
@@ -1085,6 +1121,234 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 	}
 }
 
+func TestTiKVUpgraderUpgradeByZone(t *testing.T) {
+	g := NewGomegaWithT(t)
+	zones := []string{"a", "b", "a"}
+	upgrader, tc, oldSet, evicting, getPod, recreatePods := newTiKVZoneUpgradeTest(zones)
+
+	// the statefulset is updated only if the upgrade succeeds, which is the same as the member manager
+	upgrade := func(persist bool) (*apps.StatefulSet, error) {
+		newSet := newStatefulSetForTiKVUpgrader()
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		if err == nil && persist {
+			oldSet.Spec = *newSet.Spec.DeepCopy()
+			keepZoneUpgradeAnnotation(oldSet, newSet)
+		}
+		return newSet, err
+	}
+
+	// switch to the OnDelete strategy without touching any pod
+	newSet, err := upgrade(false)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(newSet.Spec.UpdateStrategy).To(Equal(apps.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType}))
+	g.Expect(*evicting).To(BeEmpty())
+
+	// no pod is deleted if the statefulset failed to be updated
+	newSet, err = upgrade(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(newSet.Spec.UpdateStrategy).To(Equal(apps.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType}))
+	g.Expect(*evicting).To(BeEmpty())
+	for ordinal := range zones {
+		_, err := getPod(int32(ordinal))
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	// begin to evict leaders from all stores of zone a after the OnDelete strategy is persisted
+	_, err = upgrade(true)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(*evicting).To(ConsistOf(uint64(1), uint64(3)))
+
+	// delete the pods of zone a after leaders are evicted
+	_, err = upgrade(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, ordinal := range []int32{0, 2} {
+		_, err := getPod(ordinal)
+		g.Expect(err).To(HaveOccurred())
+	}
+	pod, err := getPod(1)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pod.Annotations).NotTo(HaveKey(annoKeyEvictLeaderBeginTime))
+
+	// zone b is not touched until the pods of zone a are healthy
+	recreatePods(0, 2)
+	tc.Status.TiKV.Stores["3"] = v1alpha1.TiKVStore{ID: "3", PodName: TikvPodName(upgradeTcName, 2), State: v1alpha1.TiKVStateDown}
+	_, err = upgrade(true)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(*evicting).To(HaveLen(2))
+
+	tc.Status.TiKV.Stores["3"] = v1alpha1.TiKVStore{ID: "3", PodName: TikvPodName(upgradeTcName, 2), State: v1alpha1.TiKVStateUp}
+	_, err = upgrade(true)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(*evicting).To(HaveLen(3))
+	g.Expect((*evicting)[2]).To(Equal(uint64(2)))
+
+	_, err = upgrade(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = getPod(1)
+	g.Expect(err).To(HaveOccurred())
+
+	// the statefulset completes the upgrade after all pods are upgraded
+	recreatePods(1)
+	newSet, err = upgrade(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(newSet.Spec.UpdateStrategy.Type).To(Equal(apps.RollingUpdateStatefulSetStrategyType))
+	g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+}
+func TestTiKVUpgraderUpgradeByZoneWithPausePoint(t *testing.T) {
+	g := NewGomegaWithT(t)
+	upgrader, tc, oldSet, evicting, getPod, recreatePods := newTiKVZoneUpgradeTest([]string{"a", "b", "a"})
+	tc.Spec.UpgradePolicy = &v1alpha1.UpgradePolicy{
+		PausePoints: []v1alpha1.UpgradePausePoint{
+			{Name: "tikv-one", Component: v1alpha1.TiKVMemberType, AfterPods: 1},
+		},
+	}
+	upgrade := func() error {
+		newSet := newStatefulSetForTiKVUpgrader()
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		if err == nil {
+			oldSet.Spec = *newSet.Spec.DeepCopy()
+			keepZoneUpgradeAnnotation(oldSet, newSet)
+		}
+		return err
+	}
+
+	g.Expect(upgrade()).To(Succeed())
+	g.Expect(*evicting).To(BeEmpty())
+
+	// only one pod of zone a is upgraded before the pause point is approved
+	g.Expect(controller.IsRequeueError(upgrade())).To(BeTrue())
+	g.Expect(*evicting).To(ConsistOf(uint64(3)))
+	g.Expect(upgrade()).To(Succeed())
+	_, err := getPod(2)
+	g.Expect(err).To(HaveOccurred())
+	pod, err := getPod(0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pod.Annotations).NotTo(HaveKey(annoKeyEvictLeaderBeginTime))
+
+	recreatePods(2)
+	g.Expect(upgrade()).To(Succeed())
+	g.Expect(*evicting).To(HaveLen(1))
+	_, err = getPod(0)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestTiKVUpgraderStopUpgradeByZone(t *testing.T) {
+	g := NewGomegaWithT(t)
+	upgrader, tc, oldSet, evicting, getPod, recreatePods := newTiKVZoneUpgradeTest([]string{"a", "b", "a"})
+	upgrade := func() (*apps.StatefulSet, error) {
+		newSet := newStatefulSetForTiKVUpgrader()
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		if err == nil {
+			oldSet.Spec = *newSet.Spec.DeepCopy()
+			keepZoneUpgradeAnnotation(oldSet, newSet)
+		}
+		return newSet, err
+	}
+
+	// upgrade the pods of zone a
+	_, err := upgrade()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(isUpgradingByZone(oldSet)).To(BeTrue())
+	_, err = upgrade()
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	_, err = upgrade()
+	g.Expect(err).NotTo(HaveOccurred())
+	recreatePods(0, 2)
+
+	// the statefulset is switched back to the RollingUpdate strategy without releasing any pod
+	tc.Spec.TiKV.ZoneAwareUpgrade = pointer.BoolPtr(false)
+	newSet, err := upgrade()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(newSet.Spec.UpdateStrategy.Type).To(Equal(apps.RollingUpdateStatefulSetStrategyType))
+	g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(3)))
+	g.Expect(newSet.Annotations).NotTo(HaveKey(annoKeyZoneUpgrade))
+	g.Expect(isUpgradingByZone(oldSet)).To(BeFalse())
+
+	// the rest pod is upgraded one by one
+	_, err = upgrade()
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(*evicting).To(HaveLen(3))
+	g.Expect((*evicting)[2]).To(Equal(uint64(2)))
+	_, err = getPod(1)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+// keepZoneUpgradeAnnotation persists the annotation of the zone-aware upgrade without touching the other annotations
+func keepZoneUpgradeAnnotation(oldSet *apps.StatefulSet, newSet *apps.StatefulSet) {
+	if v, ok := newSet.Annotations[annoKeyZoneUpgrade]; ok {
+		oldSet.Annotations[annoKeyZoneUpgrade] = v
+		return
+	}
+	delete(oldSet.Annotations, annoKeyZoneUpgrade)
+}
+
+// newTiKVZoneUpgradeTest returns a tikv upgrader for the tidbcluster whose tikv stores are in the zones, the stores
+// evicting leaders are recorded.
+func newTiKVZoneUpgradeTest(zones []string) (TiKVUpgrader, *v1alpha1.TidbCluster, *apps.StatefulSet, *[]uint64,
+	func(int32) (*corev1.Pod, error), func(...int32)) {
+	upgrader, pdControl, _, podInformer, tikvControl, _ := newTiKVUpgrader()
+
+	tc := newTidbClusterForTiKVUpgrader()
+	tc.Spec.TiKV.ZoneAwareUpgrade = pointer.BoolPtr(true)
+
+	pdClient := controller.NewFakePDClient(pdControl, tc)
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.PDConfigFromAPI{
+			Replication: &pdapi.PDReplicationConfig{LocationLabels: []string{"zone", "host"}},
+		}, nil
+	})
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		storesInfo := &pdapi.StoresInfo{}
+		for i, zone := range zones {
+			storesInfo.Stores = append(storesInfo.Stores, &pdapi.StoreInfo{
+				Store: &pdapi.MetaStore{
+					Store: &metapb.Store{
+						Id:      uint64(i + 1),
+						Address: fmt.Sprintf("%s.upgrader-tikv-peer.default.svc:20160", TikvPodName(upgradeTcName, int32(i))),
+						Labels:  []*metapb.StoreLabel{{Key: "zone", Value: zone}},
+					},
+					StateName: v1alpha1.TiKVStateUp,
+				},
+				Status: &pdapi.StoreStatus{},
+			})
+		}
+		return storesInfo, nil
+	})
+	evicting := &[]uint64{}
+	pdClient.AddReaction(pdapi.BeginEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+		*evicting = append(*evicting, action.ID)
+		return nil, nil
+	})
+	pdClient.AddReaction(pdapi.EndEvictLeaderActionType, func(action *pdapi.Action) (interface{}, error) {
+		return nil, nil
+	})
+	for i := range zones {
+		tikvClient := controller.NewFakeTiKVClient(tikvControl, tc, TikvPodName(upgradeTcName, int32(i)))
+		tikvClient.AddReaction(tikvapi.GetLeaderCountActionType, func(action *tikvapi.Action) (interface{}, error) {
+			return 0, nil
+		})
+	}
+
+	oldSet := oldStatefulSetForTiKVUpgrader()
+	mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+	pods := getTiKVPods(oldSet)
+	for _, pod := range pods {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+	getPod := func(ordinal int32) (*corev1.Pod, error) {
+		return podInformer.Lister().Pods(corev1.NamespaceDefault).Get(TikvPodName(upgradeTcName, ordinal))
+	}
+	recreatePods := func(ordinals ...int32) {
+		for _, ordinal := range ordinals {
+			pod := pods[ordinal].DeepCopy()
+			pod.Labels[apps.ControllerRevisionHashLabelKey] = "2"
+			podInformer.Informer().GetIndexer().Add(pod)
+		}
+	}
+
+	return upgrader, tc, oldSet, evicting, getPod, recreatePods
+}
+
 func newTiKVUpgrader() (TiKVUpgrader, *pdapi.FakePDControl, *controller.FakePodControl, podinformers.PodInformer, *tikvapi.FakeTiKVControl, *volumes.FakePodVolumeModifier) {
 	fakeDeps := controller.NewFakeDependencies()
 	pdControl := fakeDeps.PDControl.(*pdapi.FakePDControl)
@@ -0,0 +1,282 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/features"
+
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// annoKeyZoneUpgrade is the annotation of the tikv statefulset whose OnDelete update strategy is set by the
+	// upgrader to upgrade the pods zone by zone, it tells the strategy apart from the OnDelete strategy modified
+	// manually or configured by the spec of TiKV.
+	annoKeyZoneUpgrade = "tidb.pingcap.com/zone-upgrade"
+)
+
+// upgradeByZone upgrades the TiKV pods zone by zone. The statefulset uses the OnDelete update strategy during
+// the upgrade, the pods of a zone are deleted together after leaders are evicted from all of their stores, and
+// the next zone is not touched until all pods of the zone are upgraded and healthy. No pod is touched before
+// the OnDelete strategy is persisted.
+func (u *tikvUpgrader) upgradeByZone(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet, minReadySeconds int) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	status := &tc.Status.TiKV
+
+	zones, err := u.getStoreZones(tc)
+	if err != nil {
+		return err
+	}
+
+	newSet.Spec.UpdateStrategy = apps.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType}
+	if newSet.Annotations == nil {
+		newSet.Annotations = map[string]string{}
+	}
+	newSet.Annotations[annoKeyZoneUpgrade] = "true"
+	pending := map[string][]*corev1.Pod{}
+	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
+		i := podOrdinals[_i]
+		podName := TikvPodName(tcName, i)
+		pod, err := u.deps.PodLister.Pods(ns).Get(podName)
+		if err != nil {
+			return fmt.Errorf("tikvUpgrader.upgradeByZone: failed to get pods %s for cluster %s/%s, error: %s", podName, ns, tcName, err)
+		}
+		revision, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
+		if !exist {
+			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tikv pod: [%s] has no label: %s", ns, tcName, podName, apps.ControllerRevisionHashLabelKey)
+		}
+		store := getStoreByOrdinal(tcName, *status, i)
+
+		if revision == status.StatefulSet.UpdateRevision {
+			if store == nil {
+				continue
+			}
+			if !podutil.IsPodAvailable(pod, int32(minReadySeconds), metav1.Now()) {
+				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not available", ns, tcName, podName)
+			}
+			if store.State != v1alpha1.TiKVStateUp {
+				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not all ready", ns, tcName, podName)
+			}
+			done, err := u.endEvictLeaderAfterUpgrade(tc, pod)
+			if err != nil {
+				return err
+			}
+			if !done {
+				return controller.RequeueErrorf("waiting to end evict leader of pod %s for tc %s/%s", podName, ns, tcName)
+			}
+			continue
+		}
+
+		// the pod whose store has no zone is upgraded alone
+		zone, ok := zones[podName]
+		if !ok {
+			zone = podName
+		}
+		pending[zone] = append(pending[zone], pod)
+	}
+
+	if len(pending) == 0 {
+		// all pods are upgraded, the statefulset completes the upgrade with the RollingUpdate strategy
+		newSet.Spec.UpdateStrategy = apps.StatefulSetUpdateStrategy{
+			Type: apps.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
+				Partition: pointer.Int32Ptr(0),
+			},
+		}
+		delete(newSet.Annotations, annoKeyZoneUpgrade)
+		return nil
+	}
+
+	if oldSet.Spec.UpdateStrategy.Type != apps.OnDeleteStatefulSetStrategyType {
+		// the pods are deleted after the OnDelete strategy is persisted, otherwise they may be recreated
+		// with the old revision if the statefulset fails to be updated
+		klog.Infof("upgradeByZone: switch the update strategy of tikv statefulset to OnDelete for tc %s/%s", ns, tcName)
+		return nil
+	}
+
+	zone := nextUpgradeZone(pending)
+	pods := pending[zone]
+	limit, limited, err := getUpgradeLimit(tc, v1alpha1.TiKVMemberType, oldSet, newSet)
+	if err != nil {
+		return err
+	}
+	if limited {
+		// keep at most `afterPods` pods upgraded before the next pause point is approved,
+		// so the zone may be upgraded partially
+		upgraded := int32(len(podOrdinals))
+		for _, zonePods := range pending {
+			upgraded -= int32(len(zonePods))
+		}
+		if upgraded >= limit {
+			klog.Infof("upgradeByZone: pods of zone %s are held by the pause point for tc %s/%s", zone, ns, tcName)
+			return nil
+		}
+		if int(limit-upgraded) < len(pods) {
+			pods = limitZoneUpgradePods(pods, int(limit-upgraded))
+		}
+	}
+	if unstableReason := u.isClusterStable(tc); unstableReason != "" {
+		return controller.RequeueErrorf("cluster is unstable: %s", unstableReason)
+	}
+
+	// evict leaders from all stores of the zone at the same time
+	var waiting []string
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			waiting = append(waiting, pod.Name)
+			continue
+		}
+		if _, err := TiKVStoreIDFromStatus(tc, pod.Name); err != nil {
+			continue
+		}
+		done, err := u.evictLeaderBeforeUpgrade(tc, pod)
+		if err != nil {
+			return fmt.Errorf("upgradeByZone: failed to evict leader of pod %s for tc %s/%s, error: %s", pod.Name, ns, tcName, err)
+		}
+		if !done {
+			waiting = append(waiting, pod.Name)
+			continue
+		}
+		if features.DefaultFeatureGate.Enabled(features.VolumeModifying) {
+			done, err = u.modifyVolumesBeforeUpgrade(tc, pod)
+			if err != nil {
+				return fmt.Errorf("upgradeByZone: failed to modify volumes of pod %s for tc %s/%s, error: %s", pod.Name, ns, tcName, err)
+			}
+			if !done {
+				waiting = append(waiting, pod.Name)
+			}
+		}
+	}
+	if len(waiting) > 0 {
+		return controller.RequeueErrorf("upgradeByZone: waiting for pods %v of zone %s to be ready to upgrade for tc %s/%s", waiting, zone, ns, tcName)
+	}
+
+	podNames := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		if err := u.deps.PodControl.DeletePod(tc, pod); err != nil {
+			return fmt.Errorf("upgradeByZone: failed to delete pod %s for tc %s/%s, error: %s", pod.Name, ns, tcName, err)
+		}
+		podNames = append(podNames, pod.Name)
+	}
+	klog.Infof("upgradeByZone: delete pods %v of zone %s to upgrade for tc %s/%s", podNames, zone, ns, tcName)
+	controller.RecordOperation(u.deps.Recorder, tc, v1alpha1.TiKVMemberType, corev1.EventTypeNormal, controller.ZoneUpgradeBeginReason,
+		"delete pods %v of zone %s to upgrade", podNames, zone)
+	return nil
+}
+
+// getStoreZones returns the zones of the TiKV pods, the zone is the value of the top-level location label of PD.
+func (u *tikvUpgrader) getStoreZones(tc *v1alpha1.TidbCluster) (map[string]string, error) {
+	pdCli := controller.GetPDClient(u.deps.PDControl, tc)
+	config, err := pdCli.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	zones := map[string]string{}
+	if config.Replication == nil || len(config.Replication.LocationLabels) == 0 {
+		klog.Warningf("tikvUpgrader.getStoreZones: location labels of pd are not set, upgrade tikv one by one for tc %s/%s", tc.Namespace, tc.Name)
+		return zones, nil
+	}
+	zoneLabel := config.Replication.LocationLabels[0]
+
+	storesInfo, err := pdCli.GetStores()
+	if err != nil {
+		return nil, err
+	}
+	for _, store := range storesInfo.Stores {
+		status := getTiKVStore(store)
+		if status == nil {
+			continue
+		}
+		for _, label := range store.Store.Labels {
+			if label.GetKey() == zoneLabel && label.GetValue() != "" {
+				zones[status.PodName] = label.GetValue()
+				break
+			}
+		}
+	}
+	return zones, nil
+}
+
+// nextUpgradeZone returns the zone to upgrade, the zone in upgrade has pods evicting leaders or terminating,
+// otherwise the zones are upgraded in the order of their names.
+func nextUpgradeZone(pending map[string][]*corev1.Pod) string {
+	zones := make([]string, 0, len(pending))
+	for zone := range pending {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	for _, zone := range zones {
+		for _, pod := range pending[zone] {
+			if isZoneUpgradePodInFlight(pod) {
+				return zone
+			}
+		}
+	}
+	return zones[0]
+}
+
+// limitZoneUpgradePods returns at most n pods of the zone to upgrade, the pods evicting leaders or terminating
+// are returned first.
+func limitZoneUpgradePods(pods []*corev1.Pod, n int) []*corev1.Pod {
+	limited := make([]*corev1.Pod, len(pods))
+	copy(limited, pods)
+	sort.SliceStable(limited, func(i, j int) bool {
+		return isZoneUpgradePodInFlight(limited[i]) && !isZoneUpgradePodInFlight(limited[j])
+	})
+	return limited[:n]
+}
+
+func isZoneUpgradePodInFlight(pod *corev1.Pod) bool {
+	_, evicting := pod.Annotations[annoKeyEvictLeaderBeginTime]
+	return evicting || pod.DeletionTimestamp != nil
+}
+
+// isUpgradingByZone returns whether the tikv statefulset is switched to the OnDelete update strategy by the upgrader
+// to upgrade the pods zone by zone.
+func isUpgradingByZone(set *apps.StatefulSet) bool {
+	_, ok := set.Annotations[annoKeyZoneUpgrade]
+	return ok && set.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType
+}
+
+// stopUpgradeByZone switches the tikv statefulset back to the RollingUpdate strategy if the zone-aware upgrade is
+// turned off during the upgrade. No pod is released by the partition, the rest pods are upgraded one by one after
+// the strategy is persisted.
+func stopUpgradeByZone(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) {
+	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	partition := int32(0)
+	if len(podOrdinals) > 0 {
+		partition = podOrdinals[len(podOrdinals)-1] + 1
+	}
+	newSet.Spec.UpdateStrategy = apps.StatefulSetUpdateStrategy{
+		Type: apps.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
+			Partition: pointer.Int32Ptr(partition),
+		},
+	}
+	delete(newSet.Annotations, annoKeyZoneUpgrade)
+	klog.Infof("stopUpgradeByZone: switch the update strategy of tikv statefulset back to RollingUpdate for tc %s/%s", tc.Namespace, tc.Name)
+}
@@ -237,10 +237,15 @@ func (m *tiproxyMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 	}
 
 	if !templateEqual(newSts, oldStatefulSet) || tc.Status.TiProxy.Phase == v1alpha1.UpgradePhase {
-		if err := m.upgrader.Upgrade(tc, oldStatefulSet, newSts); err != nil {
+		if err := upgradeWithPolicy(m.deps, tc, v1alpha1.TiProxyMemberType, oldStatefulSet, newSts, func() error {
+			return m.upgrader.Upgrade(tc, oldStatefulSet, newSts)
+		}); err != nil {
 			return err
 		}
 	}
+	if err := syncPostUpgradeHook(m.deps, tc, v1alpha1.TiProxyMemberType, oldStatefulSet, newSts); err != nil {
+		return err
+	}
 
 	return mngerutils.UpdateStatefulSetWithPrecheck(m.deps, tc, "FailedUpdateTiProxySTS", newSts, oldStatefulSet)
 }
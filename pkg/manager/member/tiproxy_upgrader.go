@@ -19,9 +19,10 @@ import (
 	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
-	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
 
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 )
@@ -63,8 +64,22 @@ func (u *tiproxyUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Statefu
 		return nil
 	}
 
-	mngerutils.SetUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	limit, err := getUpgradePartitionLimit(tc, v1alpha1.TiProxyMemberType, oldSet, newSet)
+	if err != nil {
+		return err
+	}
+	keepUpgradeStrategy(oldSet, newSet)
 	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	maxUnavailable, err := tc.Spec.TiProxy.GetMaxUnavailable()
+	if err != nil {
+		return err
+	}
+	if switchToBatchUpgrade(tc, v1alpha1.TiProxyMemberType, oldSet, newSet, maxUnavailable) {
+		return nil
+	}
+	var pending []int32
+	unavailable := sets.NewInt32()
+	pods := map[int32]*corev1.Pod{}
 	for _i := len(podOrdinals) - 1; _i >= 0; _i-- {
 		i := podOrdinals[_i]
 		podName := fmt.Sprintf("%s-%d", controller.TiProxyMemberName(tcName), i)
@@ -85,8 +100,21 @@ func (u *tiproxyUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Statefu
 			continue
 		}
 
-		mngerutils.SetUpgradePartition(newSet, i)
+		pending = append(pending, i)
+		pods[i] = pod
+		if !podutil.IsPodReady(pod) {
+			unavailable.Insert(i)
+		}
+	}
+	if len(pending) == 0 {
+		finishBatchUpgrade(oldSet, newSet)
+		return nil
+	}
+
+	batch := nextUpgradeBatch(pending, getUpgradeBatchPartition(oldSet, pending, pods), limit, unavailable, maxUnavailable)
+	if len(batch) == 0 {
+		// the next pod is held by the next pause point of the upgrade policy
 		return nil
 	}
-	return nil
+	return upgradeBatch(u.deps, tc, v1alpha1.TiProxyMemberType, oldSet, newSet, batch, pods)
 }
@@ -0,0 +1,231 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+
+	. "github.com/onsi/gomega"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+)
+
+func TestTiProxyUpgraderUpgradeBatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name              string
+		maxUnavailable    int
+		partition         int32
+		inBatches         bool
+		upgraded          []int32
+		terminating       []int32
+		notReady          []int32
+		changeFn          func(*v1alpha1.TidbCluster)
+		errorExpect       bool
+		expectedPartition *int32
+		expectedDeleted   []int32
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+		deps := controller.NewFakeDependencies()
+		upgrader := NewTiProxyUpgrader(deps)
+
+		tc := &v1alpha1.TidbCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: upgradeTcName, Namespace: corev1.NamespaceDefault},
+			Spec: v1alpha1.TidbClusterSpec{
+				TiProxy: &v1alpha1.TiProxySpec{
+					Replicas:       4,
+					MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: int32(test.maxUnavailable)},
+				},
+			},
+			Status: v1alpha1.TidbClusterStatus{
+				TiProxy: v1alpha1.TiProxyStatus{
+					Synced:      true,
+					Phase:       v1alpha1.NormalPhase,
+					StatefulSet: &apps.StatefulSetStatus{CurrentRevision: "1", UpdateRevision: "2", Replicas: 4},
+				},
+			},
+		}
+		if test.changeFn != nil {
+			test.changeFn(tc)
+		}
+
+		revisions := map[int32]string{}
+		for _, ordinal := range test.upgraded {
+			revisions[ordinal] = "2"
+		}
+		notReady := map[int32]bool{}
+		for _, ordinal := range test.notReady {
+			notReady[ordinal] = true
+		}
+		terminating := map[int32]bool{}
+		for _, ordinal := range test.terminating {
+			terminating[ordinal] = true
+		}
+		for ordinal := int32(0); ordinal < 4; ordinal++ {
+			l := label.New().Instance(upgradeInstanceName).TiProxy().Labels()
+			l[apps.ControllerRevisionHashLabelKey] = "1"
+			if revision, ok := revisions[ordinal]; ok {
+				l[apps.ControllerRevisionHashLabelKey] = revision
+			}
+			ready := corev1.ConditionTrue
+			if notReady[ordinal] {
+				ready = corev1.ConditionFalse
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-%d", controller.TiProxyMemberName(upgradeTcName), ordinal),
+					Namespace: corev1.NamespaceDefault,
+					Labels:    l,
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+				},
+			}
+			if terminating[ordinal] {
+				pod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			}
+			deps.KubeInformerFactory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+		}
+
+		oldSet := &apps.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: controller.TiProxyMemberName(upgradeTcName), Namespace: corev1.NamespaceDefault},
+			Spec: apps.StatefulSetSpec{
+				Replicas: pointer.Int32Ptr(4),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "tiproxy", Image: "tiproxy-test-image"}},
+					},
+				},
+				UpdateStrategy: apps.StatefulSetUpdateStrategy{
+					Type:          apps.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{Partition: pointer.Int32Ptr(test.partition)},
+				},
+			},
+		}
+		newSet := oldSet.DeepCopy()
+		if test.inBatches {
+			setBatchUpgradeStrategy(oldSet)
+		}
+		mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		if test.errorExpect {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		g.Expect(tc.Status.TiProxy.Phase).To(Equal(v1alpha1.UpgradePhase))
+		if test.expectedPartition != nil {
+			g.Expect(newSet.Spec.UpdateStrategy.Type).To(Equal(apps.RollingUpdateStatefulSetStrategyType))
+			g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(test.expectedPartition))
+		} else {
+			g.Expect(isUpgradingInBatches(newSet)).To(BeTrue())
+		}
+		var deleted []int32
+		for ordinal := int32(3); ordinal >= 0; ordinal-- {
+			_, err := deps.PodLister.Pods(corev1.NamespaceDefault).Get(fmt.Sprintf("%s-%d", controller.TiProxyMemberName(upgradeTcName), ordinal))
+			if errors.IsNotFound(err) {
+				deleted = append(deleted, ordinal)
+			}
+		}
+		g.Expect(deleted).To(Equal(test.expectedDeleted))
+	}
+
+	tests := []*testcase{
+		{
+			name:              "upgrade one by one",
+			maxUnavailable:    1,
+			partition:         4,
+			expectedPartition: pointer.Int32Ptr(3),
+		},
+		{
+			name:           "switch to the OnDelete strategy before upgrading a batch",
+			maxUnavailable: 2,
+			partition:      4,
+		},
+		{
+			name:            "replace the pods of a batch at the same time",
+			maxUnavailable:  2,
+			inBatches:       true,
+			expectedDeleted: []int32{3, 2},
+		},
+		{
+			name:           "wait for the batch in flight",
+			maxUnavailable: 2,
+			inBatches:      true,
+			upgraded:       []int32{3},
+			terminating:    []int32{2},
+		},
+		{
+			name:            "upgrade the next batch",
+			maxUnavailable:  2,
+			inBatches:       true,
+			upgraded:        []int32{3, 2},
+			expectedDeleted: []int32{1, 0},
+		},
+		{
+			name:            "unavailable pods out of the batch take up the quota",
+			maxUnavailable:  2,
+			inBatches:       true,
+			notReady:        []int32{0},
+			expectedDeleted: []int32{3},
+		},
+		{
+			name:           "upgraded pods are not ready",
+			maxUnavailable: 2,
+			inBatches:      true,
+			upgraded:       []int32{3, 2},
+			notReady:       []int32{2},
+			errorExpect:    true,
+		},
+		{
+			name:           "limited by the pause point",
+			maxUnavailable: 3,
+			inBatches:      true,
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.UpgradePolicy = &v1alpha1.UpgradePolicy{
+					PausePoints: []v1alpha1.UpgradePausePoint{
+						{Name: "tiproxy-half", Component: v1alpha1.TiProxyMemberType, AfterPods: 2},
+					},
+				}
+			},
+			expectedDeleted: []int32{3, 2},
+		},
+		{
+			name:              "switch back to the RollingUpdate strategy after all pods are upgraded",
+			maxUnavailable:    2,
+			inBatches:         true,
+			upgraded:          []int32{3, 2, 1, 0},
+			expectedPartition: pointer.Int32Ptr(0),
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test)
+	}
+}
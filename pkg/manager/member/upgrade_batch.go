@@ -0,0 +1,164 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// annoKeyBatchUpgrade is the annotation of the statefulset whose OnDelete update strategy is set by the upgrader
+	// to upgrade the pods in batches, it tells the strategy apart from the OnDelete strategy modified manually.
+	annoKeyBatchUpgrade = "tidb.pingcap.com/batch-upgrade"
+)
+
+// nextUpgradeBatch returns the ordinals of the pods upgraded in the next batch, the pending ordinals are the pods
+// not upgraded yet in the upgrade order. The pending pods at or above the partition are the batch in flight and
+// are returned alone until all of them are upgraded. The unavailable pods out of the batch take up the quota of
+// maxUnavailable, while at least one pod is upgraded to make progress. The pods whose ordinals are less than the
+// limit are not upgraded, so the batch is empty if the next pod is out of the limit.
+func nextUpgradeBatch(pending []int32, partition int32, limit int32, unavailable sets.Int32, maxUnavailable int) []int32 {
+	var batch []int32
+	for _, ordinal := range pending {
+		if ordinal >= partition {
+			batch = append(batch, ordinal)
+		}
+	}
+	if len(batch) > 0 {
+		return batch
+	}
+
+	quota := maxUnavailable - unavailable.Len()
+	for _, ordinal := range pending {
+		if ordinal < limit {
+			break
+		}
+		if unavailable.Has(ordinal) {
+			// the pod is unavailable already, upgrading it takes up no more quota
+			batch = append(batch, ordinal)
+			continue
+		}
+		if quota <= 0 && len(batch) > 0 {
+			break
+		}
+		batch = append(batch, ordinal)
+		quota--
+	}
+	return batch
+}
+
+// isUpgradingInBatches returns whether the statefulset is switched to the OnDelete update strategy by the upgrader
+// to upgrade the pods in batches.
+func isUpgradingInBatches(set *apps.StatefulSet) bool {
+	_, ok := set.Annotations[annoKeyBatchUpgrade]
+	return ok && set.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType
+}
+
+// keepUpgradeStrategy keeps the update strategy of the old statefulset during the upgrade, the OnDelete strategy of
+// the upgrade in batches is kept until all pods are upgraded, otherwise the partition is kept.
+func keepUpgradeStrategy(oldSet *apps.StatefulSet, newSet *apps.StatefulSet) {
+	if isUpgradingInBatches(oldSet) {
+		setBatchUpgradeStrategy(newSet)
+		return
+	}
+	mngerutils.SetUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+}
+
+// switchToBatchUpgrade switches the statefulset to the OnDelete update strategy if more than one pod is allowed to
+// be unavailable, because the statefulset controller replaces the pods released by the partition one by one. It
+// returns true if the statefulset is being switched, no pod is touched before the strategy is persisted, otherwise
+// the deleted pods may be recreated with the old revision.
+func switchToBatchUpgrade(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, oldSet *apps.StatefulSet, newSet *apps.StatefulSet, maxUnavailable int) bool {
+	if maxUnavailable <= 1 || isUpgradingInBatches(oldSet) {
+		return false
+	}
+	setBatchUpgradeStrategy(newSet)
+	klog.Infof("switchToBatchUpgrade: switch the update strategy of %s statefulset to OnDelete for tc %s/%s", memberType, tc.Namespace, tc.Name)
+	return true
+}
+
+// finishBatchUpgrade switches the statefulset back to the RollingUpdate strategy after all pods are upgraded in
+// batches, so that the statefulset controller completes the upgrade.
+func finishBatchUpgrade(oldSet *apps.StatefulSet, newSet *apps.StatefulSet) {
+	if !isUpgradingInBatches(oldSet) {
+		return
+	}
+	newSet.Spec.UpdateStrategy = apps.StatefulSetUpdateStrategy{
+		Type: apps.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
+			Partition: pointer.Int32Ptr(0),
+		},
+	}
+	delete(newSet.Annotations, annoKeyBatchUpgrade)
+}
+
+func setBatchUpgradeStrategy(set *apps.StatefulSet) {
+	set.Spec.UpdateStrategy = apps.StatefulSetUpdateStrategy{Type: apps.OnDeleteStatefulSetStrategyType}
+	if set.Annotations == nil {
+		set.Annotations = map[string]string{}
+	}
+	set.Annotations[annoKeyBatchUpgrade] = "true"
+}
+
+// getUpgradeBatchPartition returns the partition of the batch in flight for nextUpgradeBatch. In the upgrade in
+// batches the terminating pods are the batch in flight, otherwise it's the partition of the statefulset.
+func getUpgradeBatchPartition(oldSet *apps.StatefulSet, pending []int32, pods map[int32]*corev1.Pod) int32 {
+	if !isUpgradingInBatches(oldSet) {
+		return *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+	partition := int32(math.MaxInt32)
+	for _, ordinal := range pending {
+		if pods[ordinal].DeletionTimestamp != nil && ordinal < partition {
+			partition = ordinal
+		}
+	}
+	return partition
+}
+
+// upgradeBatch upgrades the pods of the batch. In the upgrade in batches the pods of the batch are deleted together
+// and recreated with the new revision by the statefulset controller, otherwise the partition is lowered to the last
+// pod of the batch.
+func upgradeBatch(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType,
+	oldSet *apps.StatefulSet, newSet *apps.StatefulSet, batch []int32, pods map[int32]*corev1.Pod) error {
+	if !isUpgradingInBatches(oldSet) {
+		mngerutils.SetUpgradePartition(newSet, batch[len(batch)-1])
+		return nil
+	}
+	var deleted []string
+	for _, ordinal := range batch {
+		pod := pods[ordinal]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if err := deps.PodControl.DeletePod(tc, pod); err != nil {
+			return fmt.Errorf("upgradeBatch: failed to delete pod %s for tc %s/%s, error: %s", pod.Name, tc.Namespace, tc.Name, err)
+		}
+		deleted = append(deleted, pod.Name)
+	}
+	if len(deleted) > 0 {
+		klog.Infof("upgradeBatch: delete %s pods %v to upgrade for tc %s/%s", memberType, deleted, tc.Namespace, tc.Name)
+	}
+	return nil
+}
@@ -0,0 +1,349 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	apps "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// annoKeyUpgradeHookTemplateHash is the annotation of the StatefulSet which keeps the hash of the pod template
+	// whose pre-upgrade hook completes, the post-upgrade hook of the template is pending until it is removed.
+	annoKeyUpgradeHookTemplateHash = "tidb.pingcap.com/upgrade-hook-template-hash"
+	// annoKeyUpgradeHookStatefulSet and annoKeyUpgradeHookType are the annotations of the hook Job which keep the
+	// StatefulSet and the type of the hook, the hash of the pod template is kept by annoKeyUpgradeHookTemplateHash.
+	annoKeyUpgradeHookStatefulSet = "tidb.pingcap.com/upgrade-hook-statefulset"
+	annoKeyUpgradeHookType        = "tidb.pingcap.com/upgrade-hook-type"
+
+	preUpgradeHook  = "pre-upgrade"
+	postUpgradeHook = "post-upgrade"
+)
+
+type upgradeHookState int
+
+const (
+	upgradeHookRunning upgradeHookState = iota
+	upgradeHookSucceeded
+	upgradeHookFailed
+)
+
+// upgradeAfterPreHook upgrades the component after the pre-upgrade hook Job of the new pod template completes,
+// the pods keep the old template when the Job is running or fails.
+func upgradeAfterPreHook(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType,
+	oldSet *apps.StatefulSet, newSet *apps.StatefulSet, upgrade func() error) error {
+	hooks := getUpgradeHooks(tc, memberType)
+	if hooks == nil || templateEqual(newSet, oldSet) {
+		return upgrade()
+	}
+	keepUpgradeHookTemplateHash(oldSet, newSet)
+	hash, err := hashPodSpec(&newSet.Spec.Template.Spec)
+	if err != nil {
+		return err
+	}
+	if newSet.Annotations[annoKeyUpgradeHookTemplateHash] == hash {
+		return upgrade()
+	}
+
+	if hooks.PreUpgrade != nil {
+		state, err := syncUpgradeHookJob(deps, tc, memberType, preUpgradeHook, hooks.PreUpgrade, newSet.Name, hash)
+		if err != nil || state != upgradeHookSucceeded {
+			tc.ComponentStatus(memberType).SetPhase(v1alpha1.UpgradePhase)
+			_, podSpec, lastErr := GetLastAppliedConfig(oldSet)
+			if lastErr != nil {
+				return lastErr
+			}
+			newSet.Spec.Template.Spec = *podSpec
+			if err == nil && state == upgradeHookRunning {
+				err = controller.RequeueErrorf("tidbcluster: [%s/%s]'s %s is waiting for the pre-upgrade hook",
+					tc.GetNamespace(), tc.GetName(), memberType)
+			}
+			// if the Job fails, the upgrade stops until the Job is deleted or the spec is changed
+			return err
+		}
+	}
+
+	if newSet.Annotations == nil {
+		newSet.Annotations = map[string]string{}
+	}
+	newSet.Annotations[annoKeyUpgradeHookTemplateHash] = hash
+	return upgrade()
+}
+
+// syncPostUpgradeHook runs the post-upgrade hook Job after all pods are upgraded to the pod template whose
+// pre-upgrade hook completes, the component stays in the upgrade phase until the Job completes.
+func syncPostUpgradeHook(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType,
+	oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	if err := cleanUpgradeHookJobs(deps, tc, oldSet); err != nil {
+		return err
+	}
+	keepUpgradeHookTemplateHash(oldSet, newSet)
+	hash, ok := newSet.Annotations[annoKeyUpgradeHookTemplateHash]
+	if !ok {
+		return nil
+	}
+	hooks := getUpgradeHooks(tc, memberType)
+	if hooks == nil || hooks.PostUpgrade == nil {
+		delete(newSet.Annotations, annoKeyUpgradeHookTemplateHash)
+		return nil
+	}
+
+	if !templateEqual(newSet, oldSet) {
+		return nil
+	}
+	current, err := hashPodSpec(&newSet.Spec.Template.Spec)
+	if err != nil {
+		return err
+	}
+	if current != hash {
+		return nil
+	}
+	status := tc.ComponentStatus(memberType)
+	stsStatus := status.GetStatefulSet()
+	if stsStatus == nil || stsStatus.ObservedGeneration < oldSet.Generation ||
+		stsStatus.UpdateRevision != stsStatus.CurrentRevision || stsStatus.ReadyReplicas != stsStatus.Replicas {
+		return nil
+	}
+
+	state, err := syncUpgradeHookJob(deps, tc, memberType, postUpgradeHook, hooks.PostUpgrade, newSet.Name, hash)
+	if err != nil {
+		return err
+	}
+	if state == upgradeHookSucceeded {
+		delete(newSet.Annotations, annoKeyUpgradeHookTemplateHash)
+		return nil
+	}
+	status.SetPhase(v1alpha1.UpgradePhase)
+	return nil
+}
+
+func getUpgradeHooks(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType) *v1alpha1.UpgradeHooks {
+	spec := tc.ComponentSpec(memberType)
+	if spec == nil {
+		return nil
+	}
+	hooks := spec.UpgradeHooks()
+	if hooks == nil || (hooks.PreUpgrade == nil && hooks.PostUpgrade == nil) {
+		return nil
+	}
+	return hooks
+}
+
+// keepUpgradeHookTemplateHash keeps the annotation of the pending post-upgrade hook in the new StatefulSet
+func keepUpgradeHookTemplateHash(oldSet *apps.StatefulSet, newSet *apps.StatefulSet) {
+	hash, ok := oldSet.Annotations[annoKeyUpgradeHookTemplateHash]
+	if !ok {
+		return
+	}
+	if _, ok := newSet.Annotations[annoKeyUpgradeHookTemplateHash]; ok {
+		return
+	}
+	if newSet.Annotations == nil {
+		newSet.Annotations = map[string]string{}
+	}
+	newSet.Annotations[annoKeyUpgradeHookTemplateHash] = hash
+}
+
+// syncUpgradeHookJob creates the hook Job of the pod template if it does not exist and returns the state of the Job,
+// the failure of the Job is reported by the condition and the event of the component
+func syncUpgradeHookJob(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType,
+	hookType string, hook *v1alpha1.UpgradeHook, setName string, hash string) (upgradeHookState, error) {
+	ns := tc.GetNamespace()
+	jobName := upgradeHookJobName(setName, hookType, hash)
+	status := tc.ComponentStatus(memberType)
+
+	job, err := deps.JobLister.Jobs(ns).Get(jobName)
+	if errors.IsNotFound(err) {
+		job, err = newUpgradeHookJob(deps, tc, memberType, hookType, hook, jobName, setName, hash)
+		if err != nil {
+			return upgradeHookRunning, err
+		}
+		if err := deps.JobControl.CreateJob(tc, job); err != nil && !errors.IsAlreadyExists(err) {
+			return upgradeHookRunning, fmt.Errorf("tidbcluster: [%s/%s], create %s hook job %s failed, err: %v",
+				ns, tc.GetName(), hookType, jobName, err)
+		}
+		klog.Infof("tidbcluster: [%s/%s]'s %s %s hook job %s is created", ns, tc.GetName(), memberType, hookType, jobName)
+		return upgradeHookRunning, nil
+	} else if err != nil {
+		return upgradeHookRunning, fmt.Errorf("tidbcluster: [%s/%s], get %s hook job %s failed, err: %v",
+			ns, tc.GetName(), hookType, jobName, err)
+	}
+
+	state := getUpgradeHookJobState(job)
+
+	cond := meta.FindStatusCondition(status.GetConditions(), v1alpha1.ComponentUpgradeHookFailed)
+	failed := cond != nil && cond.Status == metav1.ConditionTrue
+	if state == upgradeHookFailed {
+		message := fmt.Sprintf("%s hook job %s failed, delete it to retry", hookType, jobName)
+		if !failed || cond.Message != message {
+			controller.RecordOperation(deps.Recorder, tc, memberType, corev1.EventTypeWarning, controller.UpgradeHookFailedReason,
+				"%s %s", memberType, message)
+		}
+		status.SetCondition(metav1.Condition{
+			Type:    v1alpha1.ComponentUpgradeHookFailed,
+			Status:  metav1.ConditionTrue,
+			Reason:  "HookJobFailed",
+			Message: message,
+		})
+	} else if failed {
+		status.SetCondition(metav1.Condition{
+			Type:    v1alpha1.ComponentUpgradeHookFailed,
+			Status:  metav1.ConditionFalse,
+			Reason:  "HookJobNotFailed",
+			Message: fmt.Sprintf("%s hook job %s does not fail", hookType, jobName),
+		})
+	}
+	return state, nil
+}
+
+func getUpgradeHookJobState(job *batchv1.Job) upgradeHookState {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
+			return upgradeHookSucceeded
+		}
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return upgradeHookFailed
+		}
+	}
+	return upgradeHookRunning
+}
+
+// upgradeHookJobName returns the name of the hook Job of the pod template. The name is set as a label value to the
+// pods of the Job, so the name of the StatefulSet is truncated if the name is longer than 63 characters, and the hash
+// of the full name keeps the names of the Jobs apart.
+func upgradeHookJobName(setName string, hookType string, hash string) string {
+	name := fmt.Sprintf("%s-%s-%s", setName, hookType, hash)
+	if len(name) <= validation.DNS1123LabelMaxLength {
+		return name
+	}
+	suffix := fmt.Sprintf("-%s-%s", hookType, v1alpha1.HashContents([]byte(name)))
+	return strings.TrimRight(setName[:validation.DNS1123LabelMaxLength-len(suffix)], "-") + suffix
+}
+
+// cleanUpgradeHookJobs deletes the succeeded hook Jobs of the StatefulSet after their results are persisted, that is,
+// the pre-upgrade hook is kept until the StatefulSet is annotated with the hash of its pod template, and the
+// post-upgrade hook is kept until the annotation is removed. The Jobs are not deleted earlier, otherwise they would
+// run again if the StatefulSet fails to be updated.
+func cleanUpgradeHookJobs(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	selector, err := label.New().Instance(tc.GetInstanceName()).Component(label.UpgradeHookJobLabelVal).Selector()
+	if err != nil {
+		return err
+	}
+	jobs, err := deps.JobLister.Jobs(ns).List(selector)
+	if err != nil {
+		return fmt.Errorf("tidbcluster: [%s/%s], list upgrade hook jobs failed, err: %v", ns, tc.GetName(), err)
+	}
+	pending, hasPending := oldSet.Annotations[annoKeyUpgradeHookTemplateHash]
+	for _, job := range jobs {
+		if job.Annotations[annoKeyUpgradeHookStatefulSet] != oldSet.Name || job.DeletionTimestamp != nil ||
+			getUpgradeHookJobState(job) != upgradeHookSucceeded {
+			continue
+		}
+		hash := job.Annotations[annoKeyUpgradeHookTemplateHash]
+		switch job.Annotations[annoKeyUpgradeHookType] {
+		case preUpgradeHook:
+			if !hasPending || pending != hash {
+				continue
+			}
+		case postUpgradeHook:
+			if hasPending && pending == hash {
+				continue
+			}
+		default:
+			continue
+		}
+		if err := deps.JobControl.DeleteJob(tc, job); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("tidbcluster: [%s/%s], delete succeeded hook job %s failed, err: %v", ns, tc.GetName(), job.Name, err)
+		}
+		klog.Infof("tidbcluster: [%s/%s]'s succeeded hook job %s is deleted", ns, tc.GetName(), job.Name)
+	}
+	return nil
+}
+
+// newUpgradeHookJob builds the hook Job from the template in the ConfigMap
+func newUpgradeHookJob(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType,
+	hookType string, hook *v1alpha1.UpgradeHook, jobName string, setName string, hash string) (*batchv1.Job, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	cm, err := deps.KubeClientset.CoreV1().ConfigMaps(ns).Get(context.TODO(), hook.JobTemplate.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("tidbcluster: [%s/%s], get job template configmap %s of %s hook failed, err: %v",
+			ns, tcName, hook.JobTemplate.Name, hookType, err)
+	}
+	data, ok := cm.Data[hook.JobTemplate.Key]
+	if !ok {
+		return nil, fmt.Errorf("tidbcluster: [%s/%s], key %s is not found in job template configmap %s of %s hook",
+			ns, tcName, hook.JobTemplate.Key, hook.JobTemplate.Name, hookType)
+	}
+	job := &batchv1.Job{}
+	if err := yaml.Unmarshal([]byte(data), job); err != nil {
+		return nil, fmt.Errorf("tidbcluster: [%s/%s], unmarshal job template in configmap %s of %s hook failed, err: %v",
+			ns, tcName, hook.JobTemplate.Name, hookType, err)
+	}
+
+	jobLabels := label.New().Instance(tc.GetInstanceName()).Component(label.UpgradeHookJobLabelVal)
+	jobAnnotations := map[string]string{
+		annoKeyUpgradeHookStatefulSet:  setName,
+		annoKeyUpgradeHookType:         hookType,
+		annoKeyUpgradeHookTemplateHash: hash,
+	}
+	job.ObjectMeta = metav1.ObjectMeta{
+		Name:            jobName,
+		Namespace:       ns,
+		Labels:          util.CombineStringMap(job.Labels, jobLabels),
+		Annotations:     util.CombineStringMap(jobAnnotations, job.Annotations),
+		OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+	}
+	job.Spec.Template.Labels = util.CombineStringMap(job.Spec.Template.Labels, jobLabels)
+	if job.Spec.Template.Spec.RestartPolicy == "" {
+		job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "CLUSTER_NAME", Value: tcName},
+		{Name: "NAMESPACE", Value: ns},
+		{Name: "COMPONENT", Value: memberType.String()},
+		{Name: "UPGRADE_HOOK", Value: hookType},
+		{Name: "PD_ADDR", Value: fmt.Sprintf("%s://%s.%s:%d", tc.Scheme(), controller.PDMemberName(tcName), ns, v1alpha1.DefaultPDClientPort)},
+		{Name: "TC_TLS_ENABLED", Value: strconv.FormatBool(tc.IsTLSClusterEnabled())},
+	}
+	if tc.Spec.TiDB != nil {
+		env = append(env,
+			corev1.EnvVar{Name: "TIDB_HOST", Value: fmt.Sprintf("%s.%s", controller.TiDBMemberName(tcName), ns)},
+			corev1.EnvVar{Name: "TIDB_PORT", Value: strconv.Itoa(int(tc.Spec.TiDB.GetServicePort()))},
+		)
+	}
+	for i := range job.Spec.Template.Spec.Containers {
+		container := &job.Spec.Template.Spec.Containers[i]
+		container.Env = util.AppendOverwriteEnv(append([]corev1.EnvVar{}, env...), container.Env)
+	}
+	return job, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+
+	. "github.com/onsi/gomega"
+	apps "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const upgradeHookJobTemplate = `
+spec:
+  backoffLimit: 0
+  template:
+    spec:
+      containers:
+      - name: check
+        image: busybox
+        env:
+        - name: CHECK_SQL
+          value: select 1
+`
+
+func TestUpgradeHooks(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	tc := newTidbClusterForTiDBUpgrader()
+	_, err := deps.KubeClientset.CoreV1().ConfigMaps(tc.Namespace).Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade-hooks", Namespace: tc.Namespace},
+		Data:       map[string]string{"job": upgradeHookJobTemplate},
+	}, metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	hook := &v1alpha1.UpgradeHook{JobTemplate: corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "upgrade-hooks"},
+		Key:                  "job",
+	}}
+	tc.Spec.TiDB.UpgradeHooks = &v1alpha1.UpgradeHooks{PreUpgrade: hook, PostUpgrade: hook}
+	tc.Status.TiDB.StatefulSet = &apps.StatefulSetStatus{CurrentRevision: "1", UpdateRevision: "1", Replicas: 2, ReadyReplicas: 2}
+
+	oldSet := newStatefulSetForTiDBUpgrader()
+	mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+	newTiDBSet := func() *apps.StatefulSet {
+		set := oldSet.DeepCopy()
+		set.Spec.Template.Spec.Containers[0].Image = "tidb-new-image"
+		delete(set.Annotations, mngerutils.LastAppliedConfigAnnotation)
+		return set
+	}
+	upgraded := false
+	upgrade := func() error {
+		upgraded = true
+		return nil
+	}
+	getJob := func() *batchv1.Job {
+		jobs, err := deps.JobLister.Jobs(tc.Namespace).List(labels.Everything())
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(jobs).To(HaveLen(1))
+		return jobs[0]
+	}
+	finishJob := func(job *batchv1.Job, typ batchv1.JobConditionType) {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: typ, Status: corev1.ConditionTrue}}
+		g.Expect(deps.KubeInformerFactory.Batch().V1().Jobs().Informer().GetIndexer().Update(job)).To(Succeed())
+	}
+	hookFailed := func() bool {
+		cond := meta.FindStatusCondition(tc.Status.TiDB.Conditions, v1alpha1.ComponentUpgradeHookFailed)
+		return cond != nil && cond.Status == metav1.ConditionTrue
+	}
+
+	// wait for the pre-upgrade hook
+	newSet := newTiDBSet()
+	err = upgradeAfterPreHook(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet, upgrade)
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(upgraded).To(BeFalse())
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-test-image"))
+	g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.UpgradePhase))
+	job := getJob()
+	g.Expect(job.Labels[label.ComponentLabelKey]).To(Equal(label.UpgradeHookJobLabelVal))
+	g.Expect(job.OwnerReferences).To(HaveLen(1))
+	env := job.Spec.Template.Spec.Containers[0].Env
+	g.Expect(env).To(ContainElement(corev1.EnvVar{Name: "CLUSTER_NAME", Value: "upgrader"}))
+	g.Expect(env).To(ContainElement(corev1.EnvVar{Name: "UPGRADE_HOOK", Value: preUpgradeHook}))
+	g.Expect(env).To(ContainElement(corev1.EnvVar{Name: "PD_ADDR", Value: "http://upgrader-pd.default:2379"}))
+	g.Expect(env).To(ContainElement(corev1.EnvVar{Name: "TIDB_HOST", Value: "upgrader-tidb.default"}))
+	g.Expect(env).To(ContainElement(corev1.EnvVar{Name: "CHECK_SQL", Value: "select 1"}))
+
+	// the upgrade stops if the pre-upgrade hook fails
+	finishJob(job, batchv1.JobFailed)
+	newSet = newTiDBSet()
+	g.Expect(upgradeAfterPreHook(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet, upgrade)).To(Succeed())
+	g.Expect(upgraded).To(BeFalse())
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-test-image"))
+	g.Expect(hookFailed()).To(BeTrue())
+
+	// upgrade after the pre-upgrade hook completes
+	finishJob(job, batchv1.JobComplete)
+	newSet = newTiDBSet()
+	g.Expect(upgradeAfterPreHook(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet, upgrade)).To(Succeed())
+	g.Expect(upgraded).To(BeTrue())
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-new-image"))
+	g.Expect(newSet.Annotations).To(HaveKey(annoKeyUpgradeHookTemplateHash))
+	g.Expect(hookFailed()).To(BeFalse())
+	g.Expect(job.Annotations).To(HaveKeyWithValue(annoKeyUpgradeHookType, preUpgradeHook))
+	g.Expect(job.Annotations).To(HaveKeyWithValue(annoKeyUpgradeHookTemplateHash, newSet.Annotations[annoKeyUpgradeHookTemplateHash]))
+	// the succeeded pre-upgrade hook is kept until the hash of the pod template is persisted
+	g.Expect(syncPostUpgradeHook(deps, tc, v1alpha1.TiDBMemberType, oldSet, newTiDBSet())).To(Succeed())
+	getJob()
+
+	// the succeeded pre-upgrade hook is deleted, and wait for all pods to be upgraded before the post-upgrade hook
+	oldSet = newSet.DeepCopy()
+	mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+	tc.Status.TiDB.Phase = v1alpha1.NormalPhase
+	tc.Status.TiDB.StatefulSet = &apps.StatefulSetStatus{CurrentRevision: "1", UpdateRevision: "2", Replicas: 2, ReadyReplicas: 2}
+	newSet = newTiDBSet()
+	g.Expect(syncPostUpgradeHook(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet)).To(Succeed())
+	g.Expect(newSet.Annotations).To(HaveKey(annoKeyUpgradeHookTemplateHash))
+	jobs, err := deps.JobLister.Jobs(tc.Namespace).List(labels.Everything())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(jobs).To(BeEmpty())
+
+	// the component stays in the upgrade phase until the post-upgrade hook completes
+	tc.Status.TiDB.StatefulSet = &apps.StatefulSetStatus{CurrentRevision: "2", UpdateRevision: "2", Replicas: 2, ReadyReplicas: 2}
+	newSet = newTiDBSet()
+	g.Expect(syncPostUpgradeHook(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet)).To(Succeed())
+	g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.UpgradePhase))
+	job = getJob()
+	g.Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "UPGRADE_HOOK", Value: postUpgradeHook}))
+
+	finishJob(job, batchv1.JobFailed)
+	tc.Status.TiDB.Phase = v1alpha1.NormalPhase
+	newSet = newTiDBSet()
+	g.Expect(syncPostUpgradeHook(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet)).To(Succeed())
+	g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.UpgradePhase))
+	g.Expect(hookFailed()).To(BeTrue())
+
+	finishJob(job, batchv1.JobComplete)
+	tc.Status.TiDB.Phase = v1alpha1.NormalPhase
+	newSet = newTiDBSet()
+	g.Expect(syncPostUpgradeHook(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet)).To(Succeed())
+	g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.NormalPhase))
+	g.Expect(newSet.Annotations).NotTo(HaveKey(annoKeyUpgradeHookTemplateHash))
+	g.Expect(hookFailed()).To(BeFalse())
+	getJob()
+
+	// the succeeded post-upgrade hook is deleted after the annotation is removed
+	oldSet = newSet.DeepCopy()
+	mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+	g.Expect(syncPostUpgradeHook(deps, tc, v1alpha1.TiDBMemberType, oldSet, newTiDBSet())).To(Succeed())
+	jobs, err = deps.JobLister.Jobs(tc.Namespace).List(labels.Everything())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(jobs).To(BeEmpty())
+}
+
+func TestUpgradeHookJobName(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(upgradeHookJobName("basic-tidb", preUpgradeHook, "5d8f9c7b4")).To(Equal("basic-tidb-pre-upgrade-5d8f9c7b4"))
+
+	// the name longer than 63 characters is truncated, and the names of different statefulsets are kept apart
+	tidb := upgradeHookJobName(strings.Repeat("a", 50)+"-tidb", postUpgradeHook, "5d8f9c7b4")
+	tikv := upgradeHookJobName(strings.Repeat("a", 50)+"-tikv", postUpgradeHook, "5d8f9c7b4")
+	g.Expect(len(tidb)).To(BeNumerically("<=", 63))
+	g.Expect(len(tikv)).To(BeNumerically("<=", 63))
+	g.Expect(tidb).To(HavePrefix(strings.Repeat("a", 30)))
+	g.Expect(tidb).NotTo(Equal(tikv))
+	g.Expect(validation.IsDNS1123Label(tidb)).To(BeEmpty())
+
+	name := upgradeHookJobName(strings.Repeat("a", 30)+"-"+strings.Repeat("b", 30), preUpgradeHook, "5d8f9c7b4")
+	g.Expect(validation.IsDNS1123Label(name)).To(BeEmpty())
+}
@@ -0,0 +1,258 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/advanced-statefulset/client/apis/apps/v1/helper"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// upgradePausedReason is the reason of the upgrade paused condition when the upgrade stops at a pause point
+	upgradePausedReason = "PausePointReached"
+	// upgradeResumedReason is the reason of the upgrade paused condition when no pause point blocks the upgrade
+	upgradeResumedReason = "UpgradeResumed"
+)
+
+// upgradeWithPolicy upgrades the component by the upgrade function and stops the upgrade at the pause points of
+// `spec.upgradePolicy` until they are approved by the annotation `tidb.pingcap.com/upgrade-approve`.
+//
+// The upgrade stops at a pause point when the number of the upgraded pods reaches `afterPods` of the pause point,
+// the pods are not upgraded any more and the component stays in the upgrade phase, so that the components upgraded
+// after it wait too. The upgraders don't touch the pods out of the limit of the next pause point before upgrading
+// them, see getUpgradePartitionLimit. Before the upgrade stops, the upgrade function still runs to finish the upgrade
+// of the upgraded pods, e.g. waits for them to be healthy and ends the leader eviction of TiKV stores.
+// The component is upgraded after its pre-upgrade hook completes, see upgradeAfterPreHook.
+func upgradeWithPolicy(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType,
+	oldSet *apps.StatefulSet, newSet *apps.StatefulSet, upgrade func() error) error {
+	status := tc.ComponentStatus(memberType)
+	points := getUpgradePausePoints(tc, memberType)
+	if status == nil || len(points) == 0 {
+		resumeUpgrade(status)
+		return upgradeAfterPreHook(deps, tc, memberType, oldSet, newSet, upgrade)
+	}
+
+	hash, err := hashPodSpec(&newSet.Spec.Template.Spec)
+	if err != nil {
+		return err
+	}
+	replicas := int32(helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).Len())
+	updated := getUpgradedReplicas(status, oldSet, newSet, replicas)
+
+	for i := range points {
+		point := &points[i]
+		if point.AfterPods >= replicas || isUpgradePausePointApproved(tc, point, hash) {
+			continue
+		}
+		if point.AfterPods > updated || updated >= replicas {
+			break
+		}
+		if approveUpgradePausePoint(deps, tc, point, hash) {
+			continue
+		}
+		if updated > 0 {
+			if err := upgradeAfterPreHook(deps, tc, memberType, oldSet, newSet, upgrade); err != nil {
+				return err
+			}
+		}
+		return pauseUpgrade(deps, tc, memberType, point, updated, oldSet, newSet)
+	}
+
+	resumeUpgrade(status)
+	return upgradeAfterPreHook(deps, tc, memberType, oldSet, newSet, upgrade)
+}
+
+// getUpgradeLimit returns the max number of the pods that can be upgraded to the template of the new statefulset
+// before the next pause point of the component is approved, it returns false if no pause point limits the upgrade.
+func getUpgradeLimit(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) (int32, bool, error) {
+	points := getUpgradePausePoints(tc, memberType)
+	if len(points) == 0 {
+		return 0, false, nil
+	}
+	hash, err := hashPodSpec(&newSet.Spec.Template.Spec)
+	if err != nil {
+		return 0, false, err
+	}
+	replicas := int32(helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).Len())
+	for i := range points {
+		point := &points[i]
+		if point.AfterPods >= replicas || isUpgradePausePointApproved(tc, point, hash) {
+			continue
+		}
+		return point.AfterPods, true, nil
+	}
+	return 0, false, nil
+}
+
+// getUpgradePartitionLimit returns the min partition the statefulset can be upgraded to before the next pause point
+// of the component is approved, the upgraders must not touch the pods whose ordinals are less than it. The pods
+// released by the partition of the old statefulset are not limited.
+func getUpgradePartitionLimit(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) (int32, error) {
+	limit, ok, err := getUpgradeLimit(tc, memberType, oldSet, newSet)
+	if err != nil || !ok {
+		return 0, err
+	}
+	podOrdinals := helper.GetPodOrdinals(*oldSet.Spec.Replicas, oldSet).List()
+	replicas := int32(len(podOrdinals))
+	// the pods whose ordinals are not less than the partition are upgraded, keep at most `afterPods`
+	// pods upgraded before the pause point is approved
+	partition := podOrdinals[replicas-1] + 1
+	if limit > 0 {
+		partition = podOrdinals[replicas-limit]
+	}
+	if oldSet.Spec.UpdateStrategy.RollingUpdate != nil && oldSet.Spec.UpdateStrategy.RollingUpdate.Partition != nil &&
+		*oldSet.Spec.UpdateStrategy.RollingUpdate.Partition < partition {
+		partition = *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+	return partition, nil
+}
+
+// getUpgradePausePoints returns the pause points of the component sorted by `afterPods`
+func getUpgradePausePoints(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType) []v1alpha1.UpgradePausePoint {
+	if tc.Spec.UpgradePolicy == nil {
+		return nil
+	}
+	points := []v1alpha1.UpgradePausePoint{}
+	for _, point := range tc.Spec.UpgradePolicy.PausePoints {
+		if point.Component == memberType {
+			points = append(points, point)
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].AfterPods < points[j].AfterPods
+	})
+	return points
+}
+
+// getUpgradedReplicas returns the number of pods upgraded to the new template
+func getUpgradedReplicas(status v1alpha1.ComponentStatus, oldSet *apps.StatefulSet, newSet *apps.StatefulSet, replicas int32) int32 {
+	if !templateEqual(newSet, oldSet) {
+		return 0
+	}
+	stsStatus := status.GetStatefulSet()
+	if stsStatus == nil || stsStatus.UpdateRevision == stsStatus.CurrentRevision {
+		return replicas
+	}
+	return stsStatus.UpdatedReplicas
+}
+
+func isUpgradePausePointApproved(tc *v1alpha1.TidbCluster, point *v1alpha1.UpgradePausePoint, hash string) bool {
+	for _, approved := range tc.Status.UpgradePausePoints {
+		if approved.Name == point.Name {
+			return approved.TemplateHash == hash
+		}
+	}
+	return false
+}
+
+// approveUpgradePausePoint consumes the approval of the pause point in the annotation and records the approval
+// of the current template in the status, it returns false if the pause point is not approved
+func approveUpgradePausePoint(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, point *v1alpha1.UpgradePausePoint, hash string) bool {
+	names := []string{}
+	approved := false
+	for _, name := range strings.Split(tc.Annotations[label.AnnUpgradeApprove], ",") {
+		name = strings.TrimSpace(name)
+		if name == point.Name {
+			approved = true
+		} else if name != "" {
+			names = append(names, name)
+		}
+	}
+	if !approved {
+		return false
+	}
+
+	if len(names) == 0 {
+		delete(tc.Annotations, label.AnnUpgradeApprove)
+	} else {
+		tc.Annotations[label.AnnUpgradeApprove] = strings.Join(names, ",")
+	}
+	record := v1alpha1.UpgradePausePointStatus{
+		Name:         point.Name,
+		TemplateHash: hash,
+		ApprovedTime: metav1.Now(),
+	}
+	found := false
+	for i := range tc.Status.UpgradePausePoints {
+		if tc.Status.UpgradePausePoints[i].Name == point.Name {
+			tc.Status.UpgradePausePoints[i] = record
+			found = true
+		}
+	}
+	if !found {
+		tc.Status.UpgradePausePoints = append(tc.Status.UpgradePausePoints, record)
+	}
+	controller.RecordOperation(deps.Recorder, tc, point.Component, corev1.EventTypeNormal, controller.UpgradeApprovedReason,
+		"pause point %s of %s upgrade is approved", point.Name, point.Component)
+	return true
+}
+
+// pauseUpgrade stops the upgrade of the component at the pause point, the pods which are not upgraded keep the old template
+func pauseUpgrade(deps *controller.Dependencies, tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType,
+	point *v1alpha1.UpgradePausePoint, updated int32, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	status := tc.ComponentStatus(memberType)
+	status.SetPhase(v1alpha1.UpgradePhase)
+	if !templateEqual(newSet, oldSet) {
+		_, podSpec, err := GetLastAppliedConfig(oldSet)
+		if err != nil {
+			return err
+		}
+		newSet.Spec.Template.Spec = *podSpec
+	}
+	newSet.Spec.UpdateStrategy = *oldSet.Spec.UpdateStrategy.DeepCopy()
+
+	message := fmt.Sprintf("upgrade stops at pause point %s with %d upgraded pods, add it to annotation %s to approve",
+		point.Name, updated, label.AnnUpgradeApprove)
+	cond := meta.FindStatusCondition(status.GetConditions(), v1alpha1.ComponentUpgradePaused)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Message != message {
+		controller.RecordOperation(deps.Recorder, tc, memberType, corev1.EventTypeNormal, controller.UpgradePausedReason,
+			"%s %s", memberType, message)
+	}
+	status.SetCondition(metav1.Condition{
+		Type:    v1alpha1.ComponentUpgradePaused,
+		Status:  metav1.ConditionTrue,
+		Reason:  upgradePausedReason,
+		Message: message,
+	})
+	klog.Infof("tidbcluster: [%s/%s]'s %s %s", tc.GetNamespace(), tc.GetName(), memberType, message)
+	return nil
+}
+
+// resumeUpgrade updates the upgrade paused condition if the upgrade stopped at a pause point before
+func resumeUpgrade(status v1alpha1.ComponentStatus) {
+	if status == nil {
+		return
+	}
+	cond := meta.FindStatusCondition(status.GetConditions(), v1alpha1.ComponentUpgradePaused)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		return
+	}
+	status.SetCondition(metav1.Condition{
+		Type:    v1alpha1.ComponentUpgradePaused,
+		Status:  metav1.ConditionFalse,
+		Reason:  upgradeResumedReason,
+		Message: "No pause point blocks the upgrade",
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tidb-operator/pkg/manager/utils"
+
+	. "github.com/onsi/gomega"
+	apps "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestUpgradeWithPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deps := controller.NewFakeDependencies()
+	tc := newTidbClusterForTiDBUpgrader()
+	tc.Spec.TiDB.Replicas = 4
+	tc.Spec.UpgradePolicy = &v1alpha1.UpgradePolicy{
+		PausePoints: []v1alpha1.UpgradePausePoint{
+			{Name: "tidb-half", Component: v1alpha1.TiDBMemberType, AfterPods: 2},
+			{Name: "tidb-before", Component: v1alpha1.TiDBMemberType},
+			{Name: "tikv-before", Component: v1alpha1.TiKVMemberType},
+		},
+	}
+	tc.Status.TiDB.StatefulSet = &apps.StatefulSetStatus{CurrentRevision: "1", UpdateRevision: "1", Replicas: 4, UpdatedReplicas: 4}
+
+	oldSet := newStatefulSetForTiDBUpgrader()
+	oldSet.Spec.Replicas = pointer.Int32Ptr(4)
+	oldSet.Spec.UpdateStrategy.RollingUpdate.Partition = pointer.Int32Ptr(4)
+	mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+	newTiDBSet := func(image string) *apps.StatefulSet {
+		set := oldSet.DeepCopy()
+		set.Spec.Template.Spec.Containers[0].Image = image
+		return set
+	}
+	// the fake upgrade upgrades all pods in the limit of the pause points at a time
+	upgraded := false
+	var upgradeErr error
+	upgrade := func(newSet *apps.StatefulSet) func() error {
+		upgraded = false
+		return func() error {
+			upgraded = true
+			if upgradeErr != nil {
+				return upgradeErr
+			}
+			limit, err := getUpgradePartitionLimit(tc, v1alpha1.TiDBMemberType, oldSet, newSet)
+			if err != nil {
+				return err
+			}
+			mngerutils.SetUpgradePartition(newSet, limit)
+			return nil
+		}
+	}
+	paused := func() bool {
+		cond := meta.FindStatusCondition(tc.Status.TiDB.Conditions, v1alpha1.ComponentUpgradePaused)
+		return cond != nil && cond.Status == metav1.ConditionTrue
+	}
+
+	// stop before any pod is upgraded
+	newSet := newTiDBSet("tidb-new-image")
+	g.Expect(upgradeWithPolicy(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet, upgrade(newSet))).To(Succeed())
+	g.Expect(upgraded).To(BeFalse())
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-test-image"))
+	g.Expect(tc.Status.TiDB.Phase).To(Equal(v1alpha1.UpgradePhase))
+	g.Expect(paused()).To(BeTrue())
+
+	// the approval is consumed and the partition is limited by the next pause point
+	tc.Annotations = map[string]string{label.AnnUpgradeApprove: "tikv-before, tidb-before"}
+	newSet = newTiDBSet("tidb-new-image")
+	g.Expect(upgradeWithPolicy(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet, upgrade(newSet))).To(Succeed())
+	g.Expect(upgraded).To(BeTrue())
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-new-image"))
+	g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(2)))
+	g.Expect(tc.Annotations[label.AnnUpgradeApprove]).To(Equal("tikv-before"))
+	g.Expect(tc.Status.UpgradePausePoints).To(HaveLen(1))
+	g.Expect(tc.Status.UpgradePausePoints[0].Name).To(Equal("tidb-before"))
+	g.Expect(paused()).To(BeFalse())
+
+	// don't stop until the upgraded pods are healthy
+	oldSet = newSet.DeepCopy()
+	mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSet)
+	tc.Status.TiDB.StatefulSet = &apps.StatefulSetStatus{CurrentRevision: "1", UpdateRevision: "2", Replicas: 4, UpdatedReplicas: 2}
+	upgradeErr = controller.RequeueErrorf("upgraded pod is not ready")
+	newSet = newTiDBSet("tidb-new-image")
+	err := upgradeWithPolicy(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet, upgrade(newSet))
+	g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+	g.Expect(paused()).To(BeFalse())
+
+	// stop after 2 pods are upgraded, the upgrade function still runs for the upgraded pods
+	upgradeErr = nil
+	newSet = newTiDBSet("tidb-new-image")
+	g.Expect(upgradeWithPolicy(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet, upgrade(newSet))).To(Succeed())
+	g.Expect(upgraded).To(BeTrue())
+	g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(2)))
+	g.Expect(paused()).To(BeTrue())
+
+	tc.Annotations[label.AnnUpgradeApprove] = "tidb-half"
+	newSet = newTiDBSet("tidb-new-image")
+	g.Expect(upgradeWithPolicy(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet, upgrade(newSet))).To(Succeed())
+	g.Expect(upgraded).To(BeTrue())
+	g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(pointer.Int32Ptr(0)))
+	g.Expect(tc.Annotations).NotTo(HaveKey(label.AnnUpgradeApprove))
+	g.Expect(paused()).To(BeFalse())
+
+	// the approvals don't take effect for the next upgrade
+	tc.Status.TiDB.StatefulSet = &apps.StatefulSetStatus{CurrentRevision: "2", UpdateRevision: "2", Replicas: 4, UpdatedReplicas: 4}
+	newSet = newTiDBSet("tidb-newer-image")
+	g.Expect(upgradeWithPolicy(deps, tc, v1alpha1.TiDBMemberType, oldSet, newSet, upgrade(newSet))).To(Succeed())
+	g.Expect(upgraded).To(BeFalse())
+	g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-new-image"))
+	g.Expect(paused()).To(BeTrue())
+}
@@ -28,6 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	kubeinformers "k8s.io/client-go/informers"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 )
@@ -724,6 +725,87 @@ func TestCombineAnnotations(t *testing.T) {
 	}
 }
 
+func TestNextUpgradeBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		pending        []int32
+		partition      int32
+		limit          int32
+		unavailable    []int32
+		maxUnavailable int
+		expected       []int32
+	}{
+		{
+			name:           "one by one",
+			pending:        []int32{3, 2, 1, 0},
+			partition:      4,
+			maxUnavailable: 1,
+			expected:       []int32{3},
+		},
+		{
+			name:           "batch",
+			pending:        []int32{3, 2, 1, 0},
+			partition:      4,
+			maxUnavailable: 3,
+			expected:       []int32{3, 2, 1},
+		},
+		{
+			name:           "batch in flight",
+			pending:        []int32{3, 1, 0},
+			partition:      2,
+			maxUnavailable: 3,
+			expected:       []int32{3},
+		},
+		{
+			name:           "unavailable pods out of the batch take up the quota",
+			pending:        []int32{3, 2, 1, 0},
+			partition:      4,
+			unavailable:    []int32{0},
+			maxUnavailable: 3,
+			expected:       []int32{3, 2},
+		},
+		{
+			name:           "unavailable pods in the batch take up no more quota",
+			pending:        []int32{3, 2, 1, 0},
+			partition:      4,
+			unavailable:    []int32{2},
+			maxUnavailable: 2,
+			expected:       []int32{3, 2},
+		},
+		{
+			name:           "at least one pod",
+			pending:        []int32{3, 2, 1, 0},
+			partition:      4,
+			unavailable:    []int32{0, 1},
+			maxUnavailable: 1,
+			expected:       []int32{3},
+		},
+		{
+			name:           "limited by the pause point",
+			pending:        []int32{3, 2, 1, 0},
+			partition:      4,
+			limit:          2,
+			maxUnavailable: 3,
+			expected:       []int32{3, 2},
+		},
+		{
+			name:           "held by the pause point",
+			pending:        []int32{1, 0},
+			partition:      2,
+			limit:          2,
+			maxUnavailable: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextUpgradeBatch(tt.pending, tt.partition, tt.limit, sets.NewInt32(tt.unavailable...), tt.maxUnavailable)
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Errorf("unexpected (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
 func TestMemberPodName(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -17,8 +17,10 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	errutil "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
@@ -191,6 +193,7 @@ func (s *suspender) begin(ctx *suspendComponentCtx) error {
 	klog.Infof("begin to suspend component %s and transfer phase from %s to %s",
 		ctx.ComponentID(), status.GetPhase(), phase)
 	ctx.status.SetPhase(phase)
+	s.recordOperation(ctx, controller.SuspendBeginReason, "begin to suspend component %s", ctx.component)
 	return nil
 }
 
@@ -200,9 +203,19 @@ func (s *suspender) end(ctx *suspendComponentCtx) error {
 	klog.Infof("end to suspend component %s and transfer phase from %s to %s",
 		ctx.ComponentID(), status.GetPhase(), phase)
 	ctx.status.SetPhase(phase)
+	s.recordOperation(ctx, controller.SuspendEndReason, "end the suspension of component %s", ctx.component)
 	return nil
 }
 
+// recordOperation emits an event for the suspension of the component and records it in the operation history
+func (s *suspender) recordOperation(ctx *suspendComponentCtx, reason, messageFmt string, args ...interface{}) {
+	obj, ok := ctx.cluster.(runtime.Object)
+	if !ok {
+		return
+	}
+	controller.RecordOperation(s.deps.Recorder, obj, ctx.component, corev1.EventTypeNormal, reason, messageFmt, args...)
+}
+
 // needsSuspendComponent returns whether suspender needs to to suspend the component
 func needsSuspendComponent(cluster v1alpha1.Cluster, comp v1alpha1.MemberType) bool {
 	spec := cluster.ComponentSpec(comp)
@@ -0,0 +1,355 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	fedv1alpha1 "github.com/pingcap/tidb-operator/pkg/apis/federation/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Label constants of the backup and restore metrics.
+const (
+	LabelMode     = "mode"
+	LabelSchedule = "schedule"
+	LabelReason   = "reason"
+)
+
+var (
+	// durationBuckets range from 1 minute to about 34 hours
+	durationBuckets = prometheus.ExponentialBuckets(60, 2, 12)
+	// sizeBuckets range from 1MiB to 64TiB
+	sizeBuckets = prometheus.ExponentialBuckets(1<<20, 4, 14)
+)
+
+var (
+	BackupPhaseTransitions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "backup",
+			Name:      "phase_transitions_total",
+			Help:      "Number of Backups transitioned to each phase",
+		}, []string{LabelNamespace, LabelMode, LabelSchedule, LabelPhase})
+
+	BackupFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "backup",
+			Name:      "failures_total",
+			Help:      "Number of failed Backups by the reason",
+		}, []string{LabelNamespace, LabelMode, LabelSchedule, LabelReason})
+
+	BackupRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "backup",
+			Name:      "retries_total",
+			Help:      "Number of retries of Backups after the backup job or pod failed, by the reason",
+		}, []string{LabelNamespace, LabelMode, LabelSchedule, LabelReason})
+
+	BackupDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "backup",
+			Name:      "duration_seconds",
+			Help:      "Time taken by the complete Backups",
+			Buckets:   durationBuckets,
+		}, []string{LabelNamespace, LabelMode, LabelSchedule})
+
+	BackupSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "backup",
+			Name:      "size_bytes",
+			Help:      "Data size of the complete Backups",
+			Buckets:   sizeBuckets,
+		}, []string{LabelNamespace, LabelMode, LabelSchedule})
+
+	RestorePhaseTransitions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "restore",
+			Name:      "phase_transitions_total",
+			Help:      "Number of Restores transitioned to each phase",
+		}, []string{LabelNamespace, LabelMode, LabelPhase})
+
+	RestoreFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "restore",
+			Name:      "failures_total",
+			Help:      "Number of failed Restores by the reason",
+		}, []string{LabelNamespace, LabelMode, LabelReason})
+
+	RestoreDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "restore",
+			Name:      "duration_seconds",
+			Help:      "Time taken by the complete Restores",
+			Buckets:   durationBuckets,
+		}, []string{LabelNamespace, LabelMode})
+
+	VolumeBackupPhaseTransitions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "fed_volume_backup",
+			Name:      "phase_transitions_total",
+			Help:      "Number of federation VolumeBackups transitioned to each phase",
+		}, []string{LabelNamespace, LabelPhase})
+
+	VolumeBackupFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "fed_volume_backup",
+			Name:      "failures_total",
+			Help:      "Number of failed federation VolumeBackups by the reason",
+		}, []string{LabelNamespace, LabelReason})
+
+	VolumeBackupDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "fed_volume_backup",
+			Name:      "duration_seconds",
+			Help:      "Time taken by the complete federation VolumeBackups",
+			Buckets:   durationBuckets,
+		}, []string{LabelNamespace})
+
+	VolumeBackupSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "fed_volume_backup",
+			Name:      "size_bytes",
+			Help:      "Data size of the complete federation VolumeBackups",
+			Buckets:   sizeBuckets,
+		}, []string{LabelNamespace})
+
+	// BackupStatus collects the metrics derived from the latest observed status of the Backups
+	BackupStatus = newBackupStatusCollector()
+)
+
+// ObserveBackup records the metrics of the Backup when it is observed to be added or updated.
+// The old Backup is nil when it is added, the transitions are only counted when it is updated,
+// so the existing Backups are not counted again after the controller-manager restarts.
+func ObserveBackup(old, cur *v1alpha1.Backup) {
+	BackupStatus.observeBackup(cur)
+	if old == nil {
+		return
+	}
+
+	ns := cur.Namespace
+	mode := string(backupMode(cur))
+	schedule := cur.Labels[label.BackupScheduleLabelKey]
+	if len(cur.Status.BackoffRetryStatus) > len(old.Status.BackoffRetryStatus) {
+		for _, record := range cur.Status.BackoffRetryStatus[len(old.Status.BackoffRetryStatus):] {
+			BackupRetries.WithLabelValues(ns, mode, schedule, record.RetryReason).Inc()
+		}
+	}
+	if old.Status.Phase == cur.Status.Phase || cur.Status.Phase == "" {
+		return
+	}
+
+	BackupPhaseTransitions.WithLabelValues(ns, mode, schedule, string(cur.Status.Phase)).Inc()
+	switch cur.Status.Phase {
+	case v1alpha1.BackupComplete:
+		if d, ok := elapsed(cur.Status.TimeStarted.Time, cur.Status.TimeCompleted.Time); ok {
+			BackupDuration.WithLabelValues(ns, mode, schedule).Observe(d.Seconds())
+		}
+		if cur.Status.BackupSize > 0 {
+			BackupSize.WithLabelValues(ns, mode, schedule).Observe(float64(cur.Status.BackupSize))
+		}
+	case v1alpha1.BackupFailed:
+		var reason string
+		if _, condition := v1alpha1.GetBackupCondition(&cur.Status, v1alpha1.BackupFailed); condition != nil {
+			reason = condition.Reason
+		}
+		BackupFailures.WithLabelValues(ns, mode, schedule, reason).Inc()
+	}
+}
+
+// ForgetBackup deletes the metrics of the deleted Backup
+func ForgetBackup(backup *v1alpha1.Backup) {
+	BackupStatus.forgetBackup(backup)
+}
+
+// ForgetBackupSchedule deletes the metrics of the deleted BackupSchedule
+func ForgetBackupSchedule(ns, name string) {
+	BackupStatus.forgetBackupSchedule(ns, name)
+}
+
+// ObserveRestore records the metrics of the Restore when it is updated
+func ObserveRestore(old, cur *v1alpha1.Restore) {
+	if old == nil || old.Status.Phase == cur.Status.Phase || cur.Status.Phase == "" {
+		return
+	}
+
+	ns := cur.Namespace
+	mode := string(cur.Spec.Mode)
+	if mode == "" {
+		mode = string(v1alpha1.RestoreModeSnapshot)
+	}
+	RestorePhaseTransitions.WithLabelValues(ns, mode, string(cur.Status.Phase)).Inc()
+	switch cur.Status.Phase {
+	case v1alpha1.RestoreComplete:
+		if d, ok := elapsed(cur.Status.TimeStarted.Time, cur.Status.TimeCompleted.Time); ok {
+			RestoreDuration.WithLabelValues(ns, mode).Observe(d.Seconds())
+		}
+	case v1alpha1.RestoreFailed:
+		var reason string
+		if _, condition := v1alpha1.GetRestoreCondition(&cur.Status, v1alpha1.RestoreFailed); condition != nil {
+			reason = condition.Reason
+		}
+		RestoreFailures.WithLabelValues(ns, mode, reason).Inc()
+	}
+}
+
+// ObserveVolumeBackup records the metrics of the federation VolumeBackup when it is updated
+func ObserveVolumeBackup(old, cur *fedv1alpha1.VolumeBackup) {
+	if old == nil || old.Status.Phase == cur.Status.Phase || cur.Status.Phase == "" {
+		return
+	}
+
+	ns := cur.Namespace
+	VolumeBackupPhaseTransitions.WithLabelValues(ns, string(cur.Status.Phase)).Inc()
+	switch cur.Status.Phase {
+	case fedv1alpha1.VolumeBackupComplete:
+		if d, ok := elapsed(cur.Status.TimeStarted.Time, cur.Status.TimeCompleted.Time); ok {
+			VolumeBackupDuration.WithLabelValues(ns).Observe(d.Seconds())
+		}
+		if cur.Status.BackupSize > 0 {
+			VolumeBackupSize.WithLabelValues(ns).Observe(float64(cur.Status.BackupSize))
+		}
+	case fedv1alpha1.VolumeBackupFailed:
+		var reason string
+		if _, condition := fedv1alpha1.GetVolumeBackupCondition(&cur.Status, fedv1alpha1.VolumeBackupFailed); condition != nil {
+			reason = condition.Reason
+		}
+		VolumeBackupFailures.WithLabelValues(ns, reason).Inc()
+	}
+}
+
+// backupStatusCollector collects the gauges which are derived from the latest status of the Backups,
+// the log backup checkpoint lag is calculated when it is collected so it keeps growing if the checkpoint is stuck.
+type backupStatusCollector struct {
+	lock        sync.Mutex
+	lastSuccess map[types.NamespacedName]time.Time
+	checkpoints map[types.NamespacedName]time.Time
+	now         func() time.Time
+
+	lastSuccessDesc   *prometheus.Desc
+	checkpointLagDesc *prometheus.Desc
+}
+
+func newBackupStatusCollector() *backupStatusCollector {
+	return &backupStatusCollector{
+		lastSuccess: map[types.NamespacedName]time.Time{},
+		checkpoints: map[types.NamespacedName]time.Time{},
+		now:         time.Now,
+		lastSuccessDesc: prometheus.NewDesc(
+			"tidb_operator_backup_schedule_last_success_timestamp_seconds",
+			"Completion time of the last successful Backup of each BackupSchedule in unix seconds",
+			[]string{LabelNamespace, LabelName}, nil),
+		checkpointLagDesc: prometheus.NewDesc(
+			"tidb_operator_log_backup_checkpoint_lag_seconds",
+			"Seconds between now and the checkpoint of each running log Backup",
+			[]string{LabelNamespace, LabelName}, nil),
+	}
+}
+
+func (c *backupStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.lastSuccessDesc
+	ch <- c.checkpointLagDesc
+}
+
+func (c *backupStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	for key, t := range c.lastSuccess {
+		ch <- prometheus.MustNewConstMetric(c.lastSuccessDesc, prometheus.GaugeValue, float64(t.Unix()), key.Namespace, key.Name)
+	}
+	for key, t := range c.checkpoints {
+		ch <- prometheus.MustNewConstMetric(c.checkpointLagDesc, prometheus.GaugeValue, now.Sub(t).Seconds(), key.Namespace, key.Name)
+	}
+}
+
+func (c *backupStatusCollector) observeBackup(backup *v1alpha1.Backup) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := types.NamespacedName{Namespace: backup.Namespace, Name: backup.Name}
+	if backupMode(backup) == v1alpha1.BackupModeLog {
+		checkpoint, ok := tsToTime(backup.Status.LogCheckpointTs)
+		if ok && backup.Status.Phase == v1alpha1.BackupRunning {
+			c.checkpoints[key] = checkpoint
+		} else {
+			delete(c.checkpoints, key)
+		}
+		return
+	}
+
+	schedule := backup.Labels[label.BackupScheduleLabelKey]
+	if schedule == "" || backup.Status.Phase != v1alpha1.BackupComplete || backup.Status.TimeCompleted.IsZero() {
+		return
+	}
+	scheduleKey := types.NamespacedName{Namespace: backup.Namespace, Name: schedule}
+	if completed := backup.Status.TimeCompleted.Time; completed.After(c.lastSuccess[scheduleKey]) {
+		c.lastSuccess[scheduleKey] = completed
+	}
+}
+
+func (c *backupStatusCollector) forgetBackup(backup *v1alpha1.Backup) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.checkpoints, types.NamespacedName{Namespace: backup.Namespace, Name: backup.Name})
+}
+
+func (c *backupStatusCollector) forgetBackupSchedule(ns, name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.lastSuccess, types.NamespacedName{Namespace: ns, Name: name})
+}
+
+func backupMode(backup *v1alpha1.Backup) v1alpha1.BackupMode {
+	if backup.Spec.Mode == "" {
+		return v1alpha1.BackupModeSnapshot
+	}
+	return backup.Spec.Mode
+}
+
+// elapsed returns the duration between the start and the end if both of them are set
+func elapsed(start, end time.Time) (time.Duration, bool) {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
+
+// tsToTime converts the TSO to the physical time
+func tsToTime(ts string) (time.Time, bool) {
+	tso, err := strconv.ParseUint(ts, 10, 64)
+	if err != nil || tso == 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(tso >> 18)), true
+}
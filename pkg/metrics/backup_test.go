@@ -0,0 +1,117 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/label"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestObserveBackup(t *testing.T) {
+	g := NewGomegaWithT(t)
+	start := time.Now().Add(-time.Hour)
+	backup := &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "observe-backup",
+			Name:      "daily-1",
+			Labels:    map[string]string{label.BackupScheduleLabelKey: "daily"},
+		},
+		Status: v1alpha1.BackupStatus{Phase: v1alpha1.BackupRunning},
+	}
+
+	// The existing backups are not counted when they are added
+	ObserveBackup(nil, backup)
+	g.Expect(testutil.ToFloat64(BackupPhaseTransitions.WithLabelValues("observe-backup", "snapshot", "daily", "Running"))).To(BeZero())
+
+	complete := backup.DeepCopy()
+	complete.Status.Phase = v1alpha1.BackupComplete
+	complete.Status.TimeStarted = metav1.NewTime(start)
+	complete.Status.TimeCompleted = metav1.NewTime(start.Add(30 * time.Minute))
+	complete.Status.BackupSize = 1 << 30
+	complete.Status.BackoffRetryStatus = []v1alpha1.BackoffRetryRecord{{RetryNum: 1, RetryReason: "Evicted"}}
+	ObserveBackup(backup, complete)
+	g.Expect(testutil.ToFloat64(BackupPhaseTransitions.WithLabelValues("observe-backup", "snapshot", "daily", "Complete"))).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(BackupRetries.WithLabelValues("observe-backup", "snapshot", "daily", "Evicted"))).To(Equal(1.0))
+	g.Expect(testutil.CollectAndCount(BackupDuration)).To(Equal(1))
+	g.Expect(testutil.CollectAndCount(BackupSize)).To(Equal(1))
+
+	// Updates without phase changes are not counted
+	ObserveBackup(complete, complete)
+	g.Expect(testutil.ToFloat64(BackupPhaseTransitions.WithLabelValues("observe-backup", "snapshot", "daily", "Complete"))).To(Equal(1.0))
+
+	failed := backup.DeepCopy()
+	failed.Name = "daily-2"
+	failed.Status.Phase = v1alpha1.BackupFailed
+	failed.Status.Conditions = []v1alpha1.BackupCondition{{Type: v1alpha1.BackupFailed, Reason: "BackupJobFailed"}}
+	ObserveBackup(backup, failed)
+	g.Expect(testutil.ToFloat64(BackupFailures.WithLabelValues("observe-backup", "snapshot", "daily", "BackupJobFailed"))).To(Equal(1.0))
+}
+
+func TestBackupStatusCollector(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now := time.Now()
+	c := newBackupStatusCollector()
+	c.now = func() time.Time { return now }
+
+	completed := now.Add(-time.Hour).Truncate(time.Second)
+	backup := &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "daily-1",
+			Labels:    map[string]string{label.BackupScheduleLabelKey: "daily"},
+		},
+		Status: v1alpha1.BackupStatus{
+			Phase:         v1alpha1.BackupComplete,
+			TimeCompleted: metav1.NewTime(completed),
+		},
+	}
+	c.observeBackup(backup)
+	g.Expect(testutil.ToFloat64(c)).To(Equal(float64(completed.Unix())))
+
+	// An older backup does not override the last success
+	older := backup.DeepCopy()
+	older.Status.TimeCompleted = metav1.NewTime(completed.Add(-time.Hour))
+	c.observeBackup(older)
+	g.Expect(testutil.ToFloat64(c)).To(Equal(float64(completed.Unix())))
+	c.forgetBackupSchedule("ns", "daily")
+	g.Expect(testutil.CollectAndCount(c)).To(BeZero())
+
+	checkpoint := now.Add(-5 * time.Minute).Truncate(time.Millisecond)
+	logBackup := &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "log"},
+		Spec:       v1alpha1.BackupSpec{Mode: v1alpha1.BackupModeLog},
+		Status: v1alpha1.BackupStatus{
+			Phase:           v1alpha1.BackupRunning,
+			LogCheckpointTs: fmt.Sprint(uint64(checkpoint.UnixMilli()) << 18),
+		},
+	}
+	c.observeBackup(logBackup)
+	g.Expect(testutil.ToFloat64(c)).To(Equal(now.Sub(checkpoint).Seconds()))
+
+	// The lag keeps growing if the checkpoint is not updated
+	now = now.Add(time.Minute)
+	g.Expect(testutil.ToFloat64(c)).To(Equal(now.Sub(checkpoint).Seconds()))
+
+	stopped := logBackup.DeepCopy()
+	stopped.Status.Phase = v1alpha1.BackupStopped
+	c.observeBackup(stopped)
+	g.Expect(testutil.CollectAndCount(c)).To(BeZero())
+}
@@ -22,6 +22,10 @@ const (
 	LabelNamespace = "namespace"
 	LabelName      = "name"
 	LabelComponent = "component"
+	LabelPhase     = "phase"
+	LabelStore     = "store"
+	LabelPod       = "pod"
+	LabelVolume    = "volume"
 )
 
 var (
@@ -75,5 +79,26 @@ func init() {
 
 		ClusterSpecReplicas,
 		ClusterUpdateErrors,
+		ClusterComponentPhase,
+		ClusterComponentPhaseDuration,
+		ClusterFailureMembers,
+		ClusterTombstoneStores,
+		ClusterStoreLeaderCount,
+		ClusterVolumeBoundCount,
+		ClusterVolumeModifiedCount,
+
+		BackupPhaseTransitions,
+		BackupFailures,
+		BackupRetries,
+		BackupDuration,
+		BackupSize,
+		RestorePhaseTransitions,
+		RestoreFailures,
+		RestoreDuration,
+		VolumeBackupPhaseTransitions,
+		VolumeBackupFailures,
+		VolumeBackupDuration,
+		VolumeBackupSize,
+		BackupStatus,
 	)
 }
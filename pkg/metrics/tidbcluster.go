@@ -14,6 +14,11 @@
 package metrics
 
 import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -34,3 +39,152 @@ var (
 			Help:      "Number of errors generated in each stage when updating TiDB Clusters",
 		}, []string{LabelNamespace, LabelName, LabelComponent})
 )
+
+var (
+	ClusterComponentPhase = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "cluster",
+			Name:      "component_phase",
+			Help:      "Phase of each component in TidbCluster, 1 for the current phase and 0 for the others",
+		}, []string{LabelNamespace, LabelName, LabelComponent, LabelPhase})
+
+	ClusterComponentPhaseDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "cluster",
+			Name:      "component_phase_duration_seconds",
+			Help:      "Seconds each component in TidbCluster has been in the current phase since the controller-manager observed the transition",
+		}, []string{LabelNamespace, LabelName, LabelComponent, LabelPhase})
+
+	ClusterFailureMembers = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "cluster",
+			Name:      "failure_members",
+			Help:      "Number of failure members or stores of each component in TidbCluster",
+		}, []string{LabelNamespace, LabelName, LabelComponent})
+
+	ClusterTombstoneStores = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "cluster",
+			Name:      "tombstone_stores",
+			Help:      "Number of tombstone stores of each component in TidbCluster",
+		}, []string{LabelNamespace, LabelName, LabelComponent})
+
+	ClusterStoreLeaderCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "cluster",
+			Name:      "store_leader_count",
+			Help:      "Leader count of each store in TidbCluster",
+		}, []string{LabelNamespace, LabelName, LabelComponent, LabelStore, LabelPod})
+
+	ClusterVolumeBoundCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "cluster",
+			Name:      "volume_bound_count",
+			Help:      "Number of bound volumes of each component in TidbCluster",
+		}, []string{LabelNamespace, LabelName, LabelComponent, LabelVolume})
+
+	ClusterVolumeModifiedCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb_operator",
+			Subsystem: "cluster",
+			Name:      "volume_modified_count",
+			Help:      "Number of volumes modified to the desired capacity and storage class of each component in TidbCluster",
+		}, []string{LabelNamespace, LabelName, LabelComponent, LabelVolume})
+)
+
+// ClusterStatus records the metrics derived from the status of TidbClusters
+var ClusterStatus = NewClusterStatusRecorder()
+
+// ClusterStatusSample is a sample of a gauge, the labels exclude the namespace and name of the cluster
+type ClusterStatusSample struct {
+	Gauge  *prometheus.GaugeVec
+	Labels []string
+	Value  float64
+}
+
+type clusterPhase struct {
+	phase string
+	since time.Time
+}
+
+// ClusterStatusRecorder sets the gauges derived from the status of TidbClusters. It remembers the series
+// of each cluster to delete the stale ones of the removed components, stores and clusters, and the
+// time each component is observed to enter its current phase.
+type ClusterStatusRecorder struct {
+	lock   sync.Mutex
+	series map[string][]ClusterStatusSample
+	phases map[string]clusterPhase
+	now    func() time.Time
+}
+
+// NewClusterStatusRecorder returns a ClusterStatusRecorder
+func NewClusterStatusRecorder() *ClusterStatusRecorder {
+	return &ClusterStatusRecorder{
+		series: map[string][]ClusterStatusSample{},
+		phases: map[string]clusterPhase{},
+		now:    time.Now,
+	}
+}
+
+// PhaseDuration returns how long the component has been in the phase, the time is reset when the phase changes
+func (r *ClusterStatusRecorder) PhaseDuration(ns, name, component, phase string) time.Duration {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := fmt.Sprintf("%s/%s/%s", ns, name, component)
+	now := r.now()
+	current, ok := r.phases[key]
+	if !ok || current.phase != phase {
+		current = clusterPhase{phase: phase, since: now}
+		r.phases[key] = current
+	}
+	return now.Sub(current.since)
+}
+
+// Record sets the samples of the cluster and deletes the series which are not in the samples
+func (r *ClusterStatusRecorder) Record(ns, name string, samples []ClusterStatusSample) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := fmt.Sprintf("%s/%s", ns, name)
+	current := map[string]struct{}{}
+	for _, sample := range samples {
+		labels := append([]string{ns, name}, sample.Labels...)
+		sample.Gauge.WithLabelValues(labels...).Set(sample.Value)
+		current[seriesKey(sample)] = struct{}{}
+	}
+	for _, sample := range r.series[key] {
+		if _, ok := current[seriesKey(sample)]; !ok {
+			sample.Gauge.DeleteLabelValues(append([]string{ns, name}, sample.Labels...)...)
+		}
+	}
+	r.series[key] = samples
+}
+
+// Delete deletes all the series of the cluster
+func (r *ClusterStatusRecorder) Delete(ns, name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := fmt.Sprintf("%s/%s", ns, name)
+	for _, sample := range r.series[key] {
+		sample.Gauge.DeleteLabelValues(append([]string{ns, name}, sample.Labels...)...)
+	}
+	delete(r.series, key)
+	prefix := key + "/"
+	for phaseKey := range r.phases {
+		if strings.HasPrefix(phaseKey, prefix) {
+			delete(r.phases, phaseKey)
+		}
+	}
+}
+
+func seriesKey(sample ClusterStatusSample) string {
+	return fmt.Sprintf("%p/%s", sample.Gauge, strings.Join(sample.Labels, "/"))
+}
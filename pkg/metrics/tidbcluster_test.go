@@ -0,0 +1,70 @@
+// Copyright 2023 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestClusterStatusRecorder(t *testing.T) {
+	g := NewGomegaWithT(t)
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test"}, []string{LabelNamespace, LabelName, LabelComponent, LabelStore})
+	r := NewClusterStatusRecorder()
+
+	r.Record("ns", "demo", []ClusterStatusSample{
+		{Gauge: gauge, Labels: []string{"tikv", "1"}, Value: 10},
+		{Gauge: gauge, Labels: []string{"tikv", "2"}, Value: 20},
+	})
+	r.Record("ns", "other", []ClusterStatusSample{
+		{Gauge: gauge, Labels: []string{"tikv", "1"}, Value: 30},
+	})
+	g.Expect(testutil.CollectAndCount(gauge)).To(Equal(3))
+
+	// The stale series of the removed store is deleted
+	r.Record("ns", "demo", []ClusterStatusSample{
+		{Gauge: gauge, Labels: []string{"tikv", "1"}, Value: 15},
+	})
+	g.Expect(testutil.CollectAndCount(gauge)).To(Equal(2))
+	g.Expect(testutil.ToFloat64(gauge.WithLabelValues("ns", "demo", "tikv", "1"))).To(Equal(15.0))
+
+	r.Delete("ns", "demo")
+	g.Expect(testutil.CollectAndCount(gauge)).To(Equal(1))
+	g.Expect(testutil.ToFloat64(gauge.WithLabelValues("ns", "other", "tikv", "1"))).To(Equal(30.0))
+}
+
+func TestClusterStatusRecorderPhaseDuration(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now := time.Now()
+	r := NewClusterStatusRecorder()
+	r.now = func() time.Time { return now }
+
+	g.Expect(r.PhaseDuration("ns", "demo", "tikv", "Upgrade")).To(BeZero())
+	now = now.Add(time.Minute)
+	g.Expect(r.PhaseDuration("ns", "demo", "tikv", "Upgrade")).To(Equal(time.Minute))
+	g.Expect(r.PhaseDuration("ns", "demo", "pd", "Normal")).To(BeZero())
+
+	// The duration is reset when the phase changes
+	now = now.Add(time.Minute)
+	g.Expect(r.PhaseDuration("ns", "demo", "tikv", "Normal")).To(BeZero())
+	now = now.Add(time.Minute)
+	g.Expect(r.PhaseDuration("ns", "demo", "tikv", "Normal")).To(Equal(time.Minute))
+
+	r.Delete("ns", "demo")
+	g.Expect(r.phases).To(BeEmpty())
+}
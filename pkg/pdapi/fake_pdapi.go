@@ -44,6 +44,7 @@ const (
 	TransferPDLeaderActionType                  ActionType = "TransferPDLeader"
 	GetAutoscalingPlansActionType               ActionType = "GetAutoscalingPlans"
 	GetRecoveringMarkActionType                 ActionType = "GetRecoveringMark"
+	GetPlacementRulesActionType                 ActionType = "GetPlacementRules"
 )
 
 type NotFoundReaction struct {
@@ -281,3 +282,12 @@ func (c *FakePDClient) GetRecoveringMark() (bool, error) {
 
 	return true, nil
 }
+
+func (c *FakePDClient) GetPlacementRules(group string) ([]*PlacementRule, error) {
+	action := &Action{Name: group}
+	result, err := c.fakeAPI(GetPlacementRulesActionType, action)
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*PlacementRule), nil
+}
@@ -94,6 +94,8 @@ type PDClient interface {
 	GetAutoscalingPlans(strategy Strategy) ([]Plan, error)
 	// GetRecoveringMark return the pd recovering mark
 	GetRecoveringMark() (bool, error)
+	// GetPlacementRules returns the placement rules of the given rule group
+	GetPlacementRules(group string) ([]*PlacementRule, error)
 }
 
 var (
@@ -112,6 +114,7 @@ var (
 	evictLeaderSchedulerConfigPrefix = "pd/api/v1/scheduler-config/evict-leader-scheduler/list"
 	autoscalingPrefix                = "autoscaling"
 	recoveringMarkPrefix             = "pd/api/v1/admin/cluster/markers/snapshot-recovering"
+	placementRulesGroupPrefix        = "pd/api/v1/config/rules/group"
 )
 
 // pdClient is default implementation of PDClient
@@ -243,6 +246,14 @@ type Plan struct {
 	Labels       map[string]string `json:"labels"`
 }
 
+// PlacementRule is the placement rule returned from PD, only the fields used by operator are defined
+type PlacementRule struct {
+	GroupID string `json:"group_id"`
+	ID      string `json:"id"`
+	Role    string `json:"role"`
+	Count   int    `json:"count"`
+}
+
 type schedulerInfo struct {
 	Name    string `json:"name"`
 	StoreID uint64 `json:"store_id"`
@@ -747,6 +758,20 @@ func (c *pdClient) GetAutoscalingPlans(strategy Strategy) ([]Plan, error) {
 	return plans, nil
 }
 
+func (c *pdClient) GetPlacementRules(group string) ([]*PlacementRule, error) {
+	apiURL := fmt.Sprintf("%s/%s/%s", c.url, placementRulesGroupPrefix, group)
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*PlacementRule
+	err = json.Unmarshal(body, &rules)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
 func getLeaderEvictSchedulerInfo(storeID uint64) *schedulerInfo {
 	return &schedulerInfo{"evict-leader-scheduler", storeID}
 }
@@ -866,6 +866,27 @@ func TestGeneric(t *testing.T) {
 			wantPath:    fmt.Sprintf("/%s/%s", pdLeaderTransferPrefix, "foo"),
 			checkResult: checkNoError,
 		},
+		{
+			name:   "GetPlacementRules",
+			method: "GetPlacementRules",
+			args: []reflect.Value{
+				reflect.ValueOf("tiflash"),
+			},
+			resp: []byte(`
+[
+	{
+		"group_id": "tiflash",
+		"id": "table-45-r",
+		"role": "learner",
+		"count": 2
+	}
+]
+`),
+			statusCode:  http.StatusOK,
+			wantMethod:  "GET",
+			wantPath:    fmt.Sprintf("/%s/%s", placementRulesGroupPrefix, "tiflash"),
+			checkResult: checkNoError,
+		},
 	}
 
 	for _, tt := range tests {
@@ -36,6 +36,17 @@ const (
 	TiFlashStoreNotUp = "TiFlashStoreNotUp"
 	// TiCDCCaptureNotReady is added when one of ticdc capture is not ready.
 	TiCDCCaptureNotReady = "TiCDCCaptureNotReady"
+
+	// TiDBCanaryUpgrade
+
+	// CanaryUpgrading is added when the canaries are being upgraded.
+	CanaryUpgrading = "CanaryUpgrading"
+	// CanaryBaking is added when the canaries are being observed for the bake time.
+	CanaryBaking = "CanaryBaking"
+	// CanaryPassed is added when all health gates of the canaries pass.
+	CanaryPassed = "CanaryPassed"
+	// CanaryRolledBack is added when a health gate fails and the canaries are rolled back.
+	CanaryRolledBack = "CanaryRolledBack"
 )
 
 // NewTidbClusterCondition creates a new tidbcluster condition.